@@ -0,0 +1,212 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xc9973/go-tmdb-crawler/dto"
+	"github.com/xc9973/go-tmdb-crawler/repositories"
+	"github.com/xc9973/go-tmdb-crawler/services"
+	"github.com/xc9973/go-tmdb-crawler/utils"
+	"gorm.io/gorm"
+)
+
+// Feed window bounds in days around today
+const (
+	defaultFeedPastDays = 7
+	defaultFeedDays     = 90
+	maxFeedDays         = 365
+)
+
+// CalendarAPI handles the iCalendar feed endpoints and their tokens
+type CalendarAPI struct {
+	feedService    *services.CalendarFeedService
+	showRepo       repositories.ShowRepository
+	tokenRepo      repositories.FeedTokenRepository
+	timezoneHelper *utils.TimezoneHelper
+}
+
+// NewCalendarAPI creates a new calendar API instance
+func NewCalendarAPI(
+	feedService *services.CalendarFeedService,
+	showRepo repositories.ShowRepository,
+	tokenRepo repositories.FeedTokenRepository,
+	timezoneHelper *utils.TimezoneHelper,
+) *CalendarAPI {
+	return &CalendarAPI{
+		feedService:    feedService,
+		showRepo:       showRepo,
+		tokenRepo:      tokenRepo,
+		timezoneHelper: timezoneHelper,
+	}
+}
+
+// GetFeed handles GET /api/v1/calendar.ics?token=
+// Episodes of all shows that are not archived, filtered by status, genre and uploaded.
+func (api *CalendarAPI) GetFeed(c *gin.Context) {
+	if !api.authorizeFeed(c) {
+		return
+	}
+
+	filter, err := feedFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		return
+	}
+
+	api.writeFeed(c, "TMDB 剧集更新", "episodes.ics", filter)
+}
+
+// GetShowFeed handles GET /api/v1/shows/:id/calendar.ics?token=
+func (api *CalendarAPI) GetShowFeed(c *gin.Context) {
+	if !api.authorizeFeed(c) {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest("Invalid show ID"))
+		return
+	}
+	show, err := api.showRepo.WithContext(c.Request.Context()).GetByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.NotFound("Show not found"))
+		return
+	}
+
+	filter, err := feedFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		return
+	}
+	filter.ShowID = show.ID
+
+	name := show.Name
+	if lang := requestLanguage(c); lang != "" {
+		name = show.Localized(lang).Name
+	}
+	api.writeFeed(c, name, fmt.Sprintf("show-%d.ics", show.ID), filter)
+}
+
+// writeFeed renders the calendar of the episodes matching filter.
+// The window is past_days before today through days after today (defaults 7 and 90).
+func (api *CalendarAPI) writeFeed(c *gin.Context, name, filename string, filter repositories.EpisodeFilter) {
+	pastDays, err := feedDays(c, "past_days", defaultFeedPastDays)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		return
+	}
+	days, err := feedDays(c, "days", defaultFeedDays)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		return
+	}
+
+	today := api.timezoneHelper.TodayInLocation()
+	calendar, err := api.feedService.GenerateFeed(c.Request.Context(), name,
+		today.AddDate(0, 0, -pastDays), today.AddDate(0, 0, days), filter, requestLanguage(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, filename))
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(calendar))
+}
+
+// authorizeFeed checks the feed token in ?token= and writes 401 when it is missing or revoked
+func (api *CalendarAPI) authorizeFeed(c *gin.Context) bool {
+	if _, err := api.feedService.ValidateToken(c.Request.Context(), c.Query("token")); err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.Error(401, err.Error()))
+		return false
+	}
+	return true
+}
+
+// feedFilter reads the status, genre and uploaded (true/false) query parameters
+func feedFilter(c *gin.Context) (repositories.EpisodeFilter, error) {
+	filter := repositories.EpisodeFilter{
+		Status: strings.TrimSpace(c.Query("status")),
+		Genre:  strings.TrimSpace(c.Query("genre")),
+	}
+	if uploadedStr := c.Query("uploaded"); uploadedStr != "" {
+		uploaded, err := strconv.ParseBool(uploadedStr)
+		if err != nil {
+			return filter, fmt.Errorf("uploaded must be true or false")
+		}
+		filter.Uploaded = &uploaded
+	}
+	return filter, nil
+}
+
+// feedDays reads a number of days between 0 and maxFeedDays from the query parameter key
+func feedDays(c *gin.Context, key string, defaultDays int) (int, error) {
+	value := c.Query(key)
+	if value == "" {
+		return defaultDays, nil
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 || days > maxFeedDays {
+		return 0, fmt.Errorf("%s must be between 0 and %d", key, maxFeedDays)
+	}
+	return days, nil
+}
+
+// ListFeedTokens handles GET /api/v1/feed-tokens
+func (api *CalendarAPI) ListFeedTokens(c *gin.Context) {
+	tokens, err := api.tokenRepo.WithContext(c.Request.Context()).ListAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(tokens))
+}
+
+// CreateFeedToken handles POST /api/v1/feed-tokens
+// The token is only returned here; it cannot be shown again.
+func (api *CalendarAPI) CreateFeedToken(c *gin.Context) {
+	var req struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		return
+	}
+
+	feedToken, token, err := api.feedService.CreateToken(c.Request.Context(), req.Name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessWithMessage("Feed token created, store it now: it is not shown again", gin.H{
+		"feed_token": feedToken,
+		"token":      token,
+	}))
+}
+
+// RevokeFeedToken handles DELETE /api/v1/feed-tokens/:id
+// Revoked tokens are kept so their last use stays visible.
+func (api *CalendarAPI) RevokeFeedToken(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest("Invalid feed token ID"))
+		return
+	}
+
+	if err := api.tokenRepo.WithContext(c.Request.Context()).Revoke(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dto.NotFound("Feed token not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessWithMessage("Feed token revoked", nil))
+}
@@ -95,7 +95,7 @@ func (api *CorrectionAPI) RefreshShow(c *gin.Context) {
 		return
 	}
 
-	if err := api.correction.RefreshShow(uint(id), show.TmdbID); err != nil {
+	if err := api.correction.RefreshShow(c.Request.Context(), uint(id), show.TmdbID); err != nil {
 		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
 		return
 	}
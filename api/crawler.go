@@ -1,8 +1,11 @@
 package api
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"time"
@@ -22,8 +25,11 @@ type CrawlerAPI struct {
 	showRepo    repositories.ShowRepository
 	logRepo     repositories.CrawlLogRepository
 	episodeRepo repositories.EpisodeRepository
+	changeRepo  repositories.EpisodeChangeRepository
 	taskManager *services.TaskManager
 	logger      *utils.Logger
+	// timezoneHelper is the configured timezone, used when a request sets none
+	timezoneHelper *utils.TimezoneHelper
 }
 
 // NewCrawlerAPI creates a new crawler API instance
@@ -32,16 +38,20 @@ func NewCrawlerAPI(
 	showRepo repositories.ShowRepository,
 	logRepo repositories.CrawlLogRepository,
 	episodeRepo repositories.EpisodeRepository,
+	changeRepo repositories.EpisodeChangeRepository,
 	taskManager *services.TaskManager,
 	logger *utils.Logger,
+	timezoneHelper *utils.TimezoneHelper,
 ) *CrawlerAPI {
 	return &CrawlerAPI{
-		crawler:     crawler,
-		showRepo:    showRepo,
-		logRepo:     logRepo,
-		episodeRepo: episodeRepo,
-		taskManager: taskManager,
-		logger:      logger,
+		crawler:        crawler,
+		showRepo:       showRepo,
+		logRepo:        logRepo,
+		episodeRepo:    episodeRepo,
+		changeRepo:     changeRepo,
+		taskManager:    taskManager,
+		logger:         logger,
+		timezoneHelper: timezoneHelper,
 	}
 }
 
@@ -57,7 +67,7 @@ func (api *CrawlerAPI) SearchTMDB(c *gin.Context) {
 	var tmdbID int
 	if _, err := fmt.Sscanf(query, "%d", &tmdbID); err == nil {
 		// 通过TMDB ID获取详情
-		tmdbShow, err := api.crawler.GetTMDBService().GetShowDetails(tmdbID)
+		tmdbShow, err := api.crawler.GetTMDBService().GetShowDetails(c.Request.Context(), tmdbID)
 		if err != nil {
 			c.JSON(http.StatusNotFound, dto.InternalError("TMDB搜索失败: "+err.Error()))
 			return
@@ -94,7 +104,7 @@ func (api *CrawlerAPI) SearchTMDB(c *gin.Context) {
 		}
 	}
 
-	searchResult, err := api.crawler.GetTMDBService().SearchShow(query, page)
+	searchResult, err := api.crawler.GetTMDBService().SearchShow(c.Request.Context(), query, page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.InternalError("TMDB搜索失败: "+err.Error()))
 		return
@@ -114,7 +124,7 @@ func (api *CrawlerAPI) CrawlShow(c *gin.Context) {
 
 	api.logger.Infof("[CrawlShow] 开始爬取 TMDB ID: %d", tmdbID)
 
-	if err := api.crawler.CrawlShow(tmdbID); err != nil {
+	if err := api.crawler.CrawlShow(c.Request.Context(), tmdbID); err != nil {
 		api.logger.Errorf("[CrawlShow] 爬取失败: %v", err)
 		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
 		return
@@ -149,8 +159,14 @@ func (api *CrawlerAPI) RefreshAll(c *gin.Context) {
 
 // GetTodayUpdates handles GET /api/v1/crawler/today-updates
 func (api *CrawlerAPI) GetTodayUpdates(c *gin.Context) {
+	tzHelper, err := requestTimezone(c, api.timezoneHelper)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		return
+	}
+
 	// Get episodes with upload status
-	episodes, err := api.episodeRepo.GetTodayUpdatesWithUploadStatus()
+	episodes, err := api.episodeRepo.WithTimezone(tzHelper).GetTodayUpdatesWithUploadStatus()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
 		return
@@ -277,12 +293,19 @@ func (api *CrawlerAPI) GetUpdatesByDateRange(c *gin.Context) {
 		return
 	}
 
+	tzHelper, err := requestTimezone(c, api.timezoneHelper)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		return
+	}
+
 	// Get episodes in date range
-	episodes, err := api.episodeRepo.GetByDateRange(startDate, endDate)
+	episodes, err := api.episodeRepo.WithTimezone(tzHelper).GetByDateRange(startDate, endDate)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
 		return
 	}
+	episodes = services.LocalizeEpisodes(episodes, requestLanguage(c))
 
 	// Build response with show information
 	type EpisodeWithShow struct {
@@ -290,15 +313,18 @@ func (api *CrawlerAPI) GetUpdatesByDateRange(c *gin.Context) {
 		ShowName   string `json:"show_name"`
 		PosterPath string `json:"poster_path"`
 		ShowStatus string `json:"show_status"`
+		// LocalAirDate is the day the episode airs on in the requested timezone
+		LocalAirDate string `json:"local_air_date"`
 	}
 
 	result := make([]EpisodeWithShow, 0)
 	for _, episode := range episodes {
 		item := EpisodeWithShow{
-			Episode:    episode,
-			ShowName:   episode.Show.Name,
-			PosterPath: episode.Show.PosterPath,
-			ShowStatus: episode.Show.Status,
+			Episode:      episode,
+			ShowName:     episode.Show.Name,
+			PosterPath:   episode.Show.PosterPath,
+			ShowStatus:   episode.Show.Status,
+			LocalAirDate: tzHelper.FormatAirDate(episode.AirAt, episode.AirDate),
 		}
 		result = append(result, item)
 	}
@@ -401,6 +427,30 @@ func (api *CrawlerAPI) GetHealthStatus(c *gin.Context) {
 	}))
 }
 
+// TaskResponse is a crawl task with its progress and decoded result summary
+type TaskResponse struct {
+	*models.CrawlTask
+	Progress   int             `json:"progress"`              // Percentage of processed shows
+	ETASeconds *int64          `json:"eta_seconds,omitempty"` // Estimated seconds until the task finishes
+	Summary    json.RawMessage `json:"summary,omitempty"`
+}
+
+// newTaskResponse builds the API representation of a task
+func newTaskResponse(task *models.CrawlTask) TaskResponse {
+	response := TaskResponse{CrawlTask: task, Progress: task.GetProgressPercent()}
+	if task.EstimatedFinishAt != nil && !task.IsCompleted() {
+		eta := int64(time.Until(*task.EstimatedFinishAt).Seconds())
+		if eta < 0 {
+			eta = 0
+		}
+		response.ETASeconds = &eta
+	}
+	if task.Summary != "" {
+		response.Summary = json.RawMessage(task.Summary)
+	}
+	return response
+}
+
 // GetTask handles GET /api/v1/crawler/tasks/:id
 func (api *CrawlerAPI) GetTask(c *gin.Context) {
 	if api.taskManager == nil {
@@ -425,5 +475,203 @@ func (api *CrawlerAPI) GetTask(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.Success(task))
+	c.JSON(http.StatusOK, dto.Success(newTaskResponse(task)))
+}
+
+// ListRunningTasks handles GET /api/v1/crawler/tasks/running
+func (api *CrawlerAPI) ListRunningTasks(c *gin.Context) {
+	if api.taskManager == nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError("task manager not available"))
+		return
+	}
+
+	tasks, err := api.taskManager.ListRunning()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	responses := make([]TaskResponse, 0, len(tasks))
+	for _, task := range tasks {
+		responses = append(responses, newTaskResponse(task))
+	}
+
+	c.JSON(http.StatusOK, dto.Success(responses))
+}
+
+// taskEventInterval is how often the task event stream checks for progress
+const taskEventInterval = time.Second
+
+// StreamTaskEvents handles GET /api/v1/crawler/tasks/:id/events
+// Streams the task as Server-Sent Events: a "progress" event whenever it
+// changes and a final "done" event once it has completed.
+func (api *CrawlerAPI) StreamTaskEvents(c *gin.Context) {
+	if api.taskManager == nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError("task manager not available"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest("Invalid task ID"))
+		return
+	}
+
+	if _, err := api.taskManager.GetTask(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dto.NotFound("Task not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	ticker := time.NewTicker(taskEventInterval)
+	defer ticker.Stop()
+
+	var last []byte
+	c.Stream(func(w io.Writer) bool {
+		task, err := api.taskManager.GetTask(uint(id))
+		if err != nil {
+			c.SSEvent("error", gin.H{"message": err.Error()})
+			return false
+		}
+
+		response := newTaskResponse(task)
+		if task.IsCompleted() {
+			c.SSEvent("done", response)
+			return false
+		}
+
+		// Only send progress that changed since the last event
+		if data, err := json.Marshal(response); err == nil && !bytes.Equal(data, last) {
+			last = data
+			c.SSEvent("progress", response)
+		}
+
+		select {
+		case <-ticker.C:
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// CancelTask handles POST /api/v1/crawler/tasks/:id/cancel
+func (api *CrawlerAPI) CancelTask(c *gin.Context) {
+	if api.taskManager == nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError("task manager not available"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest("Invalid task ID"))
+		return
+	}
+
+	if _, err := api.taskManager.GetTask(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dto.NotFound("Task not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	if err := api.taskManager.CancelTask(uint(id)); err != nil {
+		if errors.Is(err, services.ErrTaskNotRunning) {
+			c.JSON(http.StatusConflict, dto.Error(409, "Task is not running"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	// The task stops asynchronously; poll GET /crawler/tasks/:id for the final status
+	c.JSON(http.StatusAccepted, dto.SuccessWithMessage("Cancellation requested", gin.H{"task_id": id}))
+}
+
+// GetShowChanges handles GET /api/v1/shows/:id/changes
+func (api *CrawlerAPI) GetShowChanges(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest("Invalid show ID"))
+		return
+	}
+
+	page, pageSize := parseChangePagination(c)
+	changes, total, err := api.changeRepo.GetByShowID(uint(id), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(newChangeListResponse(changes, total, page, pageSize)))
+}
+
+// GetEpisodeChanges handles GET /api/v1/crawler/changes
+func (api *CrawlerAPI) GetEpisodeChanges(c *gin.Context) {
+	startDateStr := c.Query("start_date")
+	endDateStr := c.Query("end_date")
+
+	if startDateStr == "" || endDateStr == "" {
+		c.JSON(http.StatusBadRequest, dto.BadRequest("start_date and end_date are required"))
+		return
+	}
+
+	startDate, err := time.ParseInLocation("2006-01-02", startDateStr, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest("Invalid start_date format. Use YYYY-MM-DD"))
+		return
+	}
+
+	endDate, err := time.ParseInLocation("2006-01-02", endDateStr, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest("Invalid end_date format. Use YYYY-MM-DD"))
+		return
+	}
+
+	// end_date is inclusive
+	page, pageSize := parseChangePagination(c)
+	changes, total, err := api.changeRepo.GetByDateRange(startDate, endDate.AddDate(0, 0, 1).Add(-time.Nanosecond), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(newChangeListResponse(changes, total, page, pageSize)))
+}
+
+func parseChangePagination(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "50"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 200 {
+		pageSize = 50
+	}
+	return page, pageSize
+}
+
+func newChangeListResponse(changes []*models.EpisodeChange, total int64, page, pageSize int) dto.ListResponse {
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return dto.ListResponse{
+		Items:      changes,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}
 }
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xc9973/go-tmdb-crawler/dto"
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/repositories"
+	"github.com/xc9973/go-tmdb-crawler/services"
+	"gorm.io/gorm"
+)
+
+// DiscoveryAPI handles the discovery review queue endpoints
+type DiscoveryAPI struct {
+	discovery     *services.DiscoveryService
+	candidateRepo repositories.DiscoveryCandidateRepository
+	cache         services.CacheService
+}
+
+// NewDiscoveryAPI creates a new discovery API instance
+func NewDiscoveryAPI(
+	discovery *services.DiscoveryService,
+	candidateRepo repositories.DiscoveryCandidateRepository,
+	cache services.CacheService,
+) *DiscoveryAPI {
+	return &DiscoveryAPI{
+		discovery:     discovery,
+		candidateRepo: candidateRepo,
+		cache:         cache,
+	}
+}
+
+// ListCandidates handles GET /api/v1/discovery/candidates
+// Defaults to the pending candidates; status=all lists every candidate.
+func (api *DiscoveryAPI) ListCandidates(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	status := c.DefaultQuery("status", models.DiscoveryStatusPending)
+	switch status {
+	case "all":
+		status = ""
+	case models.DiscoveryStatusPending, models.DiscoveryStatusApproved, models.DiscoveryStatusRejected:
+	default:
+		c.JSON(http.StatusBadRequest, dto.BadRequest("status must be pending, approved, rejected or all"))
+		return
+	}
+
+	candidates, total, err := api.candidateRepo.WithContext(c.Request.Context()).List(status, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	c.JSON(http.StatusOK, dto.Success(dto.ListResponse{
+		Items:      candidates,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}))
+}
+
+// ApproveCandidate handles POST /api/v1/discovery/candidates/:id/approve
+// The show is crawled and followed before the response is sent.
+func (api *DiscoveryAPI) ApproveCandidate(c *gin.Context) {
+	api.reviewCandidate(c, api.discovery.Approve, "Candidate approved")
+}
+
+// RejectCandidate handles POST /api/v1/discovery/candidates/:id/reject
+func (api *DiscoveryAPI) RejectCandidate(c *gin.Context) {
+	api.reviewCandidate(c, api.discovery.Reject, "Candidate rejected")
+}
+
+// reviewCandidate applies an approve or reject action to the candidate in the path
+func (api *DiscoveryAPI) reviewCandidate(
+	c *gin.Context,
+	action func(ctx context.Context, id uint) (*models.DiscoveryCandidate, error),
+	message string,
+) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest("Invalid candidate ID"))
+		return
+	}
+
+	candidate, err := action(c.Request.Context(), uint(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, dto.NotFound("Candidate not found"))
+		case errors.Is(err, services.ErrCandidateNotPending):
+			c.JSON(http.StatusConflict, dto.Error(409, fmt.Sprintf("Candidate is already %s", candidate.Status)))
+		default:
+			c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		}
+		return
+	}
+
+	if candidate.ShowID != nil {
+		api.cache.InvalidatePattern(context.Background(), "show:list*")
+	}
+	c.JSON(http.StatusOK, dto.SuccessWithMessage(message, candidate))
+}
+
+// RunDiscovery handles POST /api/v1/discovery/run
+func (api *DiscoveryAPI) RunDiscovery(c *gin.Context) {
+	result, err := api.discovery.Run(c.Request.Context())
+	if err != nil {
+		if errors.Is(err, services.ErrDiscoveryRunning) {
+			c.JSON(http.StatusConflict, dto.Error(409, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	if result.Followed > 0 {
+		api.cache.InvalidatePattern(context.Background(), "show:list*")
+	}
+	c.JSON(http.StatusOK, dto.SuccessWithMessage(
+		fmt.Sprintf("Discovery complete: %d matched, %d queued, %d followed", result.Matched, result.Queued, result.Followed),
+		result,
+	))
+}
+
+// GetRules handles GET /api/v1/discovery/rules
+func (api *DiscoveryAPI) GetRules(c *gin.Context) {
+	counts, err := api.candidateRepo.WithContext(c.Request.Context()).CountByStatus()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(gin.H{
+		"rules":      api.discovery.GetRules(),
+		"candidates": counts,
+	}))
+}
@@ -0,0 +1,340 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xc9973/go-tmdb-crawler/dto"
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/repositories"
+	"github.com/xc9973/go-tmdb-crawler/services"
+	"github.com/xc9973/go-tmdb-crawler/utils"
+	"gorm.io/gorm"
+)
+
+// MovieAPI handles movie-related API endpoints
+type MovieAPI struct {
+	movieRepo      repositories.MovieRepository
+	movieService   *services.MovieService
+	cache          services.CacheService
+	releaseFilter  repositories.MovieReleaseFilter
+	timezoneHelper *utils.TimezoneHelper
+}
+
+// NewMovieAPI creates a new movie API instance
+// releaseFilter is the default filter of the release list
+func NewMovieAPI(
+	movieRepo repositories.MovieRepository,
+	movieService *services.MovieService,
+	cache services.CacheService,
+	releaseFilter repositories.MovieReleaseFilter,
+	timezoneHelper *utils.TimezoneHelper,
+) *MovieAPI {
+	return &MovieAPI{
+		movieRepo:      movieRepo,
+		movieService:   movieService,
+		cache:          cache,
+		releaseFilter:  releaseFilter,
+		timezoneHelper: timezoneHelper,
+	}
+}
+
+// ListMovies handles GET /api/v1/movies
+func (api *MovieAPI) ListMovies(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	search := c.Query("search")
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	cacheKey := services.MovieCacheKeyBuilder.Build("list", fmt.Sprintf("p%d_s%d", page, pageSize))
+
+	var response dto.ListResponse
+	ctx := context.Background()
+
+	// Only cache unfiltered list requests
+	if search == "" {
+		if err := api.cache.Get(ctx, cacheKey, &response); err == nil {
+			c.JSON(http.StatusOK, dto.Success(response))
+			return
+		}
+	}
+
+	movies, total, err := api.movieRepo.WithContext(c.Request.Context()).List(search, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	response = dto.ListResponse{
+		Items:      movies,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}
+
+	if search == "" {
+		api.cache.Set(ctx, cacheKey, response, services.CacheTTLMedium)
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// GetMovie handles GET /api/v1/movies/:id
+func (api *MovieAPI) GetMovie(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest("Invalid movie ID"))
+		return
+	}
+
+	cacheKey := services.MovieCacheKeyBuilder.Build("detail", idStr)
+	ctx := context.Background()
+
+	var movie models.Movie
+	if err := api.cache.Get(ctx, cacheKey, &movie); err == nil {
+		c.JSON(http.StatusOK, dto.Success(movie))
+		return
+	}
+
+	movieData, err := api.movieRepo.WithContext(c.Request.Context()).GetByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.NotFound("Movie not found"))
+		return
+	}
+
+	movie = *movieData
+	api.cache.Set(ctx, cacheKey, movie, services.CacheTTLLong)
+
+	c.JSON(http.StatusOK, dto.Success(movie))
+}
+
+// CreateMovie handles POST /api/v1/movies
+// The movie and its release dates are crawled before the response is sent.
+func (api *MovieAPI) CreateMovie(c *gin.Context) {
+	var req struct {
+		TmdbID int `json:"tmdb_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		return
+	}
+
+	if _, err := api.movieRepo.WithContext(c.Request.Context()).GetByTmdbID(req.TmdbID); err == nil {
+		c.JSON(http.StatusConflict, dto.Error(409, "Movie already exists"))
+		return
+	}
+
+	movie, err := api.movieService.CrawlMovie(c.Request.Context(), req.TmdbID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	api.invalidateCaches("")
+	c.JSON(http.StatusCreated, dto.SuccessWithMessage("Movie created successfully", movie))
+}
+
+// UpdateMovie handles PUT /api/v1/movies/:id
+func (api *MovieAPI) UpdateMovie(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest("Invalid movie ID"))
+		return
+	}
+
+	var req models.Movie
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		return
+	}
+
+	movieRepo := api.movieRepo.WithContext(c.Request.Context())
+	movie, err := movieRepo.GetByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.NotFound("Movie not found"))
+		return
+	}
+
+	// Release dates come from TMDB and are not changed here
+	req.ID = uint(id)
+	req.TmdbID = movie.TmdbID // Keep original TMDB ID
+	req.CreatedAt = movie.CreatedAt
+	req.ReleaseDates = movie.ReleaseDates
+
+	if err := movieRepo.Update(&req); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	api.invalidateCaches(idStr)
+	c.JSON(http.StatusOK, dto.SuccessWithMessage("Movie updated successfully", &req))
+}
+
+// DeleteMovie handles DELETE /api/v1/movies/:id
+func (api *MovieAPI) DeleteMovie(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest("Invalid movie ID"))
+		return
+	}
+
+	if err := api.movieRepo.WithContext(c.Request.Context()).Delete(uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	api.invalidateCaches(idStr)
+	c.JSON(http.StatusOK, dto.SuccessWithMessage("Movie deleted successfully", nil))
+}
+
+// RefreshMovie handles POST /api/v1/movies/:id/refresh
+func (api *MovieAPI) RefreshMovie(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest("Invalid movie ID"))
+		return
+	}
+
+	movie, err := api.movieRepo.WithContext(c.Request.Context()).GetByID(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dto.NotFound("Movie not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		}
+		return
+	}
+
+	updated, err := api.movieService.CrawlMovie(c.Request.Context(), movie.TmdbID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	api.invalidateCaches(idStr)
+	c.JSON(http.StatusOK, dto.SuccessWithMessage("Movie refreshed successfully", updated))
+}
+
+// RefreshAll handles POST /api/v1/movies/refresh-all
+func (api *MovieAPI) RefreshAll(c *gin.Context) {
+	result, err := api.movieService.RefreshAll(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	api.cache.InvalidatePattern(context.Background(), "movie*")
+	c.JSON(http.StatusOK, dto.SuccessWithMessage(
+		fmt.Sprintf("Movie refresh completed: %d/%d successful", result.Refreshed, result.Total),
+		result,
+	))
+}
+
+// ListReleases handles GET /api/v1/movies/releases
+// Defaults to the next 7 days in the viewer timezone (?tz=); region=US,GB and type=3,4
+// override the configured filter.
+func (api *MovieAPI) ListReleases(c *gin.Context) {
+	tzHelper, err := requestTimezone(c, api.timezoneHelper)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		return
+	}
+
+	startDate := tzHelper.TodayInLocation()
+	endDate := startDate.AddDate(0, 0, 7)
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
+		parsed, err := time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.BadRequest("Invalid start_date format. Use YYYY-MM-DD"))
+			return
+		}
+		startDate = parsed
+	}
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
+		parsed, err := time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.BadRequest("Invalid end_date format. Use YYYY-MM-DD"))
+			return
+		}
+		endDate = parsed
+	}
+
+	filter := api.releaseFilter
+	if regions := c.Query("region"); regions != "" {
+		filter.Regions = strings.Split(regions, ",")
+	}
+	if types := c.Query("type"); types != "" {
+		filter.Types = nil
+		for _, part := range strings.Split(types, ",") {
+			releaseType, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || !models.IsValidReleaseType(releaseType) {
+				c.JSON(http.StatusBadRequest, dto.BadRequest("type must be release types between 1 and 6"))
+				return
+			}
+			filter.Types = append(filter.Types, releaseType)
+		}
+	}
+
+	releases, err := api.movieRepo.WithContext(c.Request.Context()).WithTimezone(tzHelper).GetReleasesByDateRange(startDate, endDate, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(releases))
+}
+
+// SearchTMDB handles GET /api/v1/movies/search/tmdb
+func (api *MovieAPI) SearchTMDB(c *gin.Context) {
+	query := c.Query("query")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, dto.BadRequest("query parameter is required"))
+		return
+	}
+
+	page := 1
+	if pageStr := c.Query("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			page = p
+		}
+	}
+
+	searchResult, err := api.movieService.GetTMDBService().SearchMovie(c.Request.Context(), query, page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError("TMDB搜索失败: "+err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(searchResult))
+}
+
+// invalidateCaches drops the cached movie lists and, when idStr is set, the movie's detail
+func (api *MovieAPI) invalidateCaches(idStr string) {
+	ctx := context.Background()
+	if idStr != "" {
+		api.cache.Delete(ctx, services.MovieCacheKeyBuilder.Build("detail", idStr))
+	}
+	api.cache.InvalidatePattern(ctx, "movie:list*")
+}
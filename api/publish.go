@@ -1,35 +1,45 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/xc9973/go-tmdb-crawler/dto"
+	"github.com/xc9973/go-tmdb-crawler/repositories"
 	"github.com/xc9973/go-tmdb-crawler/services"
+	"gorm.io/gorm"
 )
 
 // PublishAPI handles publishing endpoints
 type PublishAPI struct {
-	publisher *services.PublisherService
-	markdown  *services.MarkdownService
+	publisher  *services.PublisherService
+	markdown   *services.MarkdownService
+	recordRepo repositories.PublishRecordRepository
+	postRepo   repositories.TelegraphPostRepository
 }
 
 // NewPublishAPI creates a new publish API instance
 func NewPublishAPI(
 	publisher *services.PublisherService,
 	markdown *services.MarkdownService,
+	recordRepo repositories.PublishRecordRepository,
+	postRepo repositories.TelegraphPostRepository,
 ) *PublishAPI {
 	return &PublishAPI{
-		publisher: publisher,
-		markdown:  markdown,
+		publisher:  publisher,
+		markdown:   markdown,
+		recordRepo: recordRepo,
+		postRepo:   postRepo,
 	}
 }
 
 // PublishTodayUpdates handles POST /api/v1/publish/today
 func (api *PublishAPI) PublishTodayUpdates(c *gin.Context) {
-	result, err := api.publisher.PublishTodayUpdates()
+	result, err := api.publisher.WithLanguage(requestLanguage(c)).PublishTodayUpdates()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
 		return
@@ -68,7 +78,7 @@ func (api *PublishAPI) PublishDateRange(c *gin.Context) {
 		return
 	}
 
-	result, err := api.publisher.PublishDateRange(startDate, endDate)
+	result, err := api.publisher.WithLanguage(requestLanguage(c)).PublishDateRange(startDate, endDate)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
 		return
@@ -91,7 +101,7 @@ func (api *PublishAPI) PublishShow(c *gin.Context) {
 		return
 	}
 
-	result, err := api.publisher.PublishShow(showID)
+	result, err := api.publisher.WithLanguage(requestLanguage(c)).PublishShow(showID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
 		return
@@ -107,7 +117,7 @@ func (api *PublishAPI) PublishShow(c *gin.Context) {
 
 // PublishWeekly handles POST /api/v1/publish/weekly
 func (api *PublishAPI) PublishWeekly(c *gin.Context) {
-	result, err := api.publisher.PublishWeeklyUpdates()
+	result, err := api.publisher.WithLanguage(requestLanguage(c)).PublishWeeklyUpdates()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
 		return
@@ -123,7 +133,7 @@ func (api *PublishAPI) PublishWeekly(c *gin.Context) {
 
 // PublishMonthly handles POST /api/v1/publish/monthly
 func (api *PublishAPI) PublishMonthly(c *gin.Context) {
-	result, err := api.publisher.PublishMonthlyUpdates()
+	result, err := api.publisher.WithLanguage(requestLanguage(c)).PublishMonthlyUpdates()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
 		return
@@ -139,7 +149,13 @@ func (api *PublishAPI) PublishMonthly(c *gin.Context) {
 
 // GenerateMarkdownToday handles GET /api/v1/publish/markdown/today
 func (api *PublishAPI) GenerateMarkdownToday(c *gin.Context) {
-	markdown, err := api.markdown.GenerateTodayUpdates()
+	tzHelper, err := requestTimezone(c, nil)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		return
+	}
+
+	markdown, err := api.markdown.WithLanguage(requestLanguage(c)).WithTimezone(tzHelper).GenerateTodayUpdates()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
 		return
@@ -158,7 +174,13 @@ func (api *PublishAPI) GenerateMarkdownShow(c *gin.Context) {
 		return
 	}
 
-	markdown, err := api.markdown.GenerateShowDetail(showID)
+	tzHelper, err := requestTimezone(c, nil)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		return
+	}
+
+	markdown, err := api.markdown.WithLanguage(requestLanguage(c)).WithTimezone(tzHelper).GenerateShowDetail(showID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
 		return
@@ -192,7 +214,13 @@ func (api *PublishAPI) GenerateMarkdownRange(c *gin.Context) {
 	}
 
 	// Generate markdown
-	markdown, err := api.markdown.GenerateDateRange(startDate, endDate)
+	tzHelper, err := requestTimezone(c, nil)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		return
+	}
+
+	markdown, err := api.markdown.WithLanguage(requestLanguage(c)).WithTimezone(tzHelper).GenerateDateRange(startDate, endDate)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
 		return
@@ -204,7 +232,13 @@ func (api *PublishAPI) GenerateMarkdownRange(c *gin.Context) {
 
 // GenerateMarkdownWeekly handles GET /api/v1/publish/markdown/weekly
 func (api *PublishAPI) GenerateMarkdownWeekly(c *gin.Context) {
-	markdown, err := api.markdown.GenerateWeeklyUpdates()
+	tzHelper, err := requestTimezone(c, nil)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		return
+	}
+
+	markdown, err := api.markdown.WithLanguage(requestLanguage(c)).WithTimezone(tzHelper).GenerateWeeklyUpdates()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
 		return
@@ -214,6 +248,51 @@ func (api *PublishAPI) GenerateMarkdownWeekly(c *gin.Context) {
 	c.String(http.StatusOK, markdown)
 }
 
+// ListPublishRecords handles GET /api/v1/publish/records?target=&limit=
+// The result of each publish target, newest first.
+func (api *PublishAPI) ListPublishRecords(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	records, err := api.recordRepo.WithContext(c.Request.Context()).ListRecent(c.Query("target"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(gin.H{
+		"targets": api.publisher.TargetNames(),
+		"records": records,
+	}))
+}
+
+// GetTelegraphSlot handles GET /api/v1/publish/telegraph/:slot
+// The Telegraph page of a publish slot with the history of its versions.
+func (api *PublishAPI) GetTelegraphSlot(c *gin.Context) {
+	post, err := api.postRepo.GetBySlot(c.Param("slot"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dto.NotFound("No Telegraph page for this slot"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	revisions, err := api.postRepo.ListRevisions(post.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+	for _, revision := range revisions {
+		post.Revisions = append(post.Revisions, *revision)
+	}
+
+	c.JSON(http.StatusOK, dto.Success(post))
+}
+
 // parseID parses a string ID to uint
 func parseID(idStr string) (uint, error) {
 	var id uint
@@ -50,7 +50,7 @@ func (api *SchedulerAPI) StopScheduler(c *gin.Context) {
 
 // RunCrawlNow handles POST /api/v1/scheduler/crawl-now
 func (api *SchedulerAPI) RunCrawlNow(c *gin.Context) {
-	if err := api.scheduler.RunCrawlNow(); err != nil {
+	if err := api.scheduler.RunCrawlNow(c.Request.Context()); err != nil {
 		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
 		return
 	}
@@ -77,7 +77,7 @@ func (api *SchedulerAPI) RunManualCrawl(c *gin.Context) {
 		return
 	}
 
-	if err := api.scheduler.RunManualCrawl(req.ID); err != nil {
+	if err := api.scheduler.RunManualCrawl(c.Request.Context(), req.ID); err != nil {
 		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
 		return
 	}
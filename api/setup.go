@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"log"
 	"strings"
 	"time"
@@ -28,9 +29,11 @@ func mustOpenDB(cfg *config.Config) *gorm.DB {
 	return db
 }
 
-// SetupRouter creates and configures the Gin router
-func SetupRouter(cfg *config.Config) *gin.Engine {
-	router := gin.Default()
+// SetupRouter creates and configures the Gin router.
+// Background work started here, such as the Telegram command poller, stops when ctx is done.
+func SetupRouter(ctx context.Context, cfg *config.Config) *gin.Engine {
+	router := gin.New()
+	router.Use(middleware.RequestLogger(), gin.Recovery())
 
 	// CORS middleware
 	router.Use(corsMiddleware(cfg))
@@ -74,11 +77,14 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 	episodeRepo := repositories.NewEpisodeRepository(db)
 	crawlLogRepo := repositories.NewCrawlLogRepository(db)
 	crawlTaskRepo := repositories.NewCrawlTaskRepository(db)
+	episodeChangeRepo := repositories.NewEpisodeChangeRepository(db)
 	telegraphPostRepo := repositories.NewTelegraphPostRepository(db)
 	uploadedEpisodeRepo := repositories.NewUploadedEpisodeRepository(db)
+	movieRepo := repositories.NewMovieRepository(db)
 
-	// Set timezone helper for episode repository
+	// Set timezone helper for episode and movie repositories
 	episodeRepo.SetTimezoneHelper(timezoneHelper)
+	movieRepo.SetTimezoneHelper(timezoneHelper)
 
 	// Auto migrate database tables
 	// Note: Episode table structure is managed by SQL migrations (see migrations/001_init_schema.sql)
@@ -86,15 +92,45 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 	// We only AutoMigrate tables that don't have complex constraints
 	if err := db.AutoMigrate(
 		&models.Show{},
+		&models.Genre{},
+		&models.ShowGenre{},
 		// &models.Episode{}, // Skip - managed by SQL migrations
 		&models.CrawlLog{},
 		&models.CrawlTask{},
+		&models.EpisodeChange{},
 		&models.TelegraphPost{},
+		&models.TelegraphPostRevision{},
 		&models.Session{},
+		&models.TMDBCacheEntry{},
+		&models.DiscoveryCandidate{},
+		&models.TMDBList{},
+		&models.Movie{},
+		&models.MovieReleaseDate{},
+		&models.FeedToken{},
+		&models.PublishRecord{},
+		&models.WebhookEndpoint{},
+		&models.WebhookDelivery{},
 		// &models.UploadedEpisode{}, // Skip - managed by SQL migrations
 	); err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
+	// Episode columns added after the initial schema (see migrations/013, 014 and 020)
+	if db.Migrator().HasTable(&models.Episode{}) {
+		for _, column := range []string{"Translations", "ImdbID", "TvdbID", "AirAt"} {
+			if db.Migrator().HasColumn(&models.Episode{}, column) {
+				continue
+			}
+			if err := db.Migrator().AddColumn(&models.Episode{}, column); err != nil {
+				log.Fatalf("Failed to add episodes column %s: %v", column, err)
+			}
+		}
+	}
+	// Genres used to be a JSON string on shows (see migrations/016)
+	if converted, err := showRepo.ConvertLegacyGenres(); err != nil {
+		log.Fatalf("Failed to convert legacy show genres: %v", err)
+	} else if converted > 0 {
+		log.Printf("Converted genres of %d shows", converted)
+	}
 	log.Println("Database migration completed successfully")
 
 	// 初始化认证服务
@@ -111,10 +147,56 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 
 	telegraph := services.NewTelegraphService(cfg.Telegraph.Token, cfg.Telegraph.ShortName, cfg.Telegraph.AuthorName, cfg.Telegraph.AuthorURL)
 	publisher := services.NewPublisherService(telegraph, showRepo, episodeRepo, telegraphPostRepo, timezoneHelper)
-	tmdb := services.MustTMDBService(cfg.TMDB.APIKey, cfg.TMDB.BaseURL, cfg.TMDB.Language)
-	crawler := services.NewCrawlerService(tmdb, showRepo, episodeRepo, crawlLogRepo, crawlTaskRepo)
+	releaseFilter := repositories.MovieReleaseFilter{Regions: cfg.Movies.ReleaseRegions, Types: cfg.Movies.ReleaseTypes}
+	publisher.SetMovieReleases(movieRepo, releaseFilter)
+	publishTargets, err := services.NewPublishTargets(cfg.Publish.Targets, services.NewPublishTargetOptions(cfg, telegraph, telegraphPostRepo))
+	if err != nil {
+		log.Fatalf("Failed to initialize publish targets: %v", err)
+	}
+	publisher.SetTargets(publishTargets)
+	publishRecordRepo := repositories.NewPublishRecordRepository(db)
+	publisher.SetPublishRecords(publishRecordRepo)
+	templateService := services.NewTemplateService(cfg.Publish.TemplatesDir)
+	publisher.SetTemplates(templateService)
+	var tmdb *services.TMDBService
+	if cfg.TMDB.Mode == services.TMDBModeReplay {
+		// Replay mode serves fixtures and needs no API key
+		tmdb = services.NewTMDBService(cfg.TMDB.APIKey, cfg.TMDB.BaseURL, cfg.TMDB.Language)
+	} else {
+		tmdb = services.MustTMDBService(cfg.TMDB.APIKey, cfg.TMDB.BaseURL, cfg.TMDB.Language)
+	}
+	tmdb.SetRateLimit(cfg.TMDB.RateLimit)
+	tmdb.SetAccessToken(cfg.TMDB.ReadAccessToken)
+	if err := tmdb.SetMode(cfg.TMDB.Mode, cfg.TMDB.FixturesDir); err != nil {
+		log.Fatalf("Failed to set TMDB mode: %v", err)
+	}
+	if cfg.TMDB.Mode != services.TMDBModeLive {
+		log.Printf("TMDB %s mode, fixtures in %s", cfg.TMDB.Mode, cfg.TMDB.FixturesDir)
+	}
+	tmdbCache, err := services.NewTMDBCacheStore(cfg.TMDB.CacheBackend, cfg.Paths.Data, repositories.NewTMDBCacheRepository(db))
+	if err != nil {
+		log.Fatalf("Failed to initialize TMDB cache: %v", err)
+	}
+	tmdb.SetCacheStore(tmdbCache)
+	tmdb.SetCacheTTLs(services.TMDBCacheTTLs{
+		Show:    cfg.TMDB.CacheTTLShow,
+		Season:  cfg.TMDB.CacheTTLSeason,
+		Search:  cfg.TMDB.CacheTTLSearch,
+		Changes: cfg.TMDB.CacheTTLChanges,
+	})
+	crawler := services.NewCrawlerService(tmdb, showRepo, episodeRepo, crawlLogRepo, crawlTaskRepo, episodeChangeRepo)
+	crawler.SetConcurrency(cfg.Crawler.Concurrency)
+	crawler.SetFallbackLanguages(cfg.TMDB.FallbackLanguages)
+	crawler.SetEpisodeExternalIDs(cfg.TMDB.EpisodeExternalIDs)
 	taskManager := services.NewTaskManager(crawlTaskRepo, crawler)
 
+	// Recover tasks left running by a previous process
+	if resumed, interrupted, err := taskManager.RecoverInterrupted(cfg.Crawler.ResumeInterrupted); err != nil {
+		log.Printf("Failed to recover interrupted crawl tasks: %v", err)
+	} else if resumed+interrupted > 0 {
+		log.Printf("Recovered interrupted crawl tasks: %d resumed, %d marked interrupted", resumed, interrupted)
+	}
+
 	// Initialize correction service (needed by scheduler)
 	correctionService := correction.NewService(showRepo, episodeRepo, crawlTaskRepo, crawler, location)
 
@@ -122,14 +204,59 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 	logger := utils.NewLogger(cfg.App.LogLevel, cfg.Paths.Log)
 	scheduler := services.NewScheduler(crawler, publisher, correctionService, logger)
 
+	// Initialize event webhooks (endpoints are registered through the admin API)
+	webhookRepo := repositories.NewWebhookRepository(db)
+	webhookService := services.NewWebhookService(webhookRepo, logger)
+	webhookService.SetRetry(cfg.Webhooks.MaxAttempts, cfg.Webhooks.RetryBackoff)
+	crawler.SetEventEmitter(webhookService)
+	publisher.SetEventEmitter(webhookService)
+	correctionService.SetEventEmitter(webhookService)
+
+	// Initialize the Telegram bot (publish summaries and commands are both opt-in)
+	if cfg.Telegram.BotToken != "" && (len(cfg.Telegram.NotifyChatIDs) > 0 || cfg.Telegram.Commands) {
+		telegramBot := services.NewTelegramBot(services.NewTelegramClient(cfg.Telegram.BotToken, cfg.Telegram.APIURL),
+			showRepo, episodeRepo, crawler, timezoneHelper, logger)
+		telegramBot.SetNotifyChats(cfg.Telegram.NotifyChatIDs)
+		telegramBot.SetAdminUsers(cfg.Telegram.AdminUserIDs)
+		scheduler.SetTelegramBot(telegramBot)
+		// Telegram allows one poller per token, so only the configured process polls
+		if cfg.Telegram.Commands && cfg.Telegram.CommandsProcess == "server" {
+			go telegramBot.Run(ctx)
+		}
+	}
+
+	// Initialize discovery (the scheduled job is opt-in, the review queue is always available)
+	discoveryCandidateRepo := repositories.NewDiscoveryCandidateRepository(db)
+	discoveryService := services.NewDiscoveryService(crawler, showRepo, discoveryCandidateRepo)
+	discoveryService.SetRules(discoveryRules(cfg))
+	if cfg.Discovery.Enabled {
+		scheduler.SetDiscovery(discoveryService, cfg.Discovery.Cron)
+	}
+
+	// Initialize TMDB list sync (lists can always be synced by hand)
+	tmdbListRepo := repositories.NewTMDBListRepository(db)
+	listSyncService := services.NewListSyncService(crawler, showRepo, tmdbListRepo)
+	if cfg.ListSync.Enabled {
+		scheduler.SetListSync(listSyncService, cfg.ListSync.Cron)
+	}
+
+	// Initialize movie refresh (movies can always be refreshed by hand)
+	movieService := services.NewMovieService(tmdb, movieRepo)
+	if cfg.Movies.RefreshCron != "" {
+		scheduler.SetMovieRefresh(movieService, cfg.Movies.RefreshCron)
+	}
+
 	// Initialize cache service (after logger is available)
 	cacheService := services.NewMemoryCacheService(15*time.Minute, logger)
 
 	showAPI := NewShowAPI(showRepo, episodeRepo, crawler, cacheService)
-	crawlerAPI := NewCrawlerAPI(crawler, showRepo, crawlLogRepo, episodeRepo, taskManager, logger)
+	crawlerAPI := NewCrawlerAPI(crawler, showRepo, crawlLogRepo, episodeRepo, episodeChangeRepo, taskManager, logger, timezoneHelper)
 	markdownService := services.NewMarkdownService(episodeRepo, showRepo)
 	markdownService.SetTimezoneHelper(timezoneHelper)
-	publishAPI := NewPublishAPI(publisher, markdownService)
+	markdownService.SetMovieReleases(movieRepo, releaseFilter)
+	markdownService.SetTemplates(templateService)
+	publishAPI := NewPublishAPI(publisher, markdownService, publishRecordRepo, telegraphPostRepo)
+	templateAPI := NewTemplateAPI(templateService, markdownService)
 	schedulerAPI := NewSchedulerAPI(scheduler)
 
 	// Initialize backup service
@@ -138,6 +265,12 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 	uploadedEpisodeAPI := NewUploadedEpisodeAPI(episodeRepo, uploadedEpisodeRepo)
 
 	correctionAPI := NewCorrectionAPI(correctionService, showRepo)
+	discoveryAPI := NewDiscoveryAPI(discoveryService, discoveryCandidateRepo, cacheService)
+	tmdbListAPI := NewTMDBListAPI(listSyncService, tmdbListRepo, crawlLogRepo, cacheService)
+	movieAPI := NewMovieAPI(movieRepo, movieService, cacheService, releaseFilter, timezoneHelper)
+	feedTokenRepo := repositories.NewFeedTokenRepository(db)
+	webhookAPI := NewWebhookAPI(webhookService, webhookRepo)
+	calendarAPI := NewCalendarAPI(services.NewCalendarFeedService(episodeRepo, feedTokenRepo), showRepo, feedTokenRepo, timezoneHelper)
 
 	// API routes
 	api := router.Group("/api/v1")
@@ -155,8 +288,19 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 		// Shows (只读)
 		api.GET("/shows", showAPI.ListShows)
 		api.GET("/shows/:id", showAPI.GetShow)
+		api.GET("/shows/lookup", showAPI.LookupShow)
 		api.GET("/shows/:id/episodes", showAPI.GetShowEpisodes)
 
+		// Calendar feeds (authorized by a feed token in ?token=, which the request log redacts)
+		api.GET("/calendar.ics", calendarAPI.GetFeed)
+		api.GET("/shows/:id/calendar.ics", calendarAPI.GetShowFeed)
+
+		// Movies (只读)
+		api.GET("/movies", movieAPI.ListMovies)
+		api.GET("/movies/releases", movieAPI.ListReleases)
+		api.GET("/movies/search/tmdb", movieAPI.SearchTMDB)
+		api.GET("/movies/:id", movieAPI.GetMovie)
+
 		// Calendar (只读)
 		api.GET("/calendar/today", crawlerAPI.GetTodayUpdates)
 		api.GET("/crawler/updates", crawlerAPI.GetUpdatesByDateRange)
@@ -179,6 +323,13 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 		admin.DELETE("/shows/:id", showAPI.DeleteShow)
 		admin.POST("/shows/:id/refresh", showAPI.RefreshShow)
 
+		// Movies (写操作)
+		admin.POST("/movies", movieAPI.CreateMovie)
+		admin.POST("/movies/refresh-all", movieAPI.RefreshAll)
+		admin.PUT("/movies/:id", movieAPI.UpdateMovie)
+		admin.DELETE("/movies/:id", movieAPI.DeleteMovie)
+		admin.POST("/movies/:id/refresh", movieAPI.RefreshMovie)
+
 		// Crawler (写操作和日志)
 		admin.POST("/crawler/show/:tmdb_id", crawlerAPI.CrawlShow)
 		admin.POST("/crawler/refresh-all", crawlerAPI.RefreshAll)
@@ -186,7 +337,12 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 		admin.GET("/crawler/logs", crawlerAPI.GetCrawlLogs)
 		admin.DELETE("/crawler/logs/old", crawlerAPI.DeleteOldLogs)
 		admin.GET("/crawler/health", crawlerAPI.GetHealthStatus)
+		admin.GET("/crawler/tasks/running", crawlerAPI.ListRunningTasks)
 		admin.GET("/crawler/tasks/:id", crawlerAPI.GetTask)
+		admin.GET("/crawler/tasks/:id/events", crawlerAPI.StreamTaskEvents)
+		admin.POST("/crawler/tasks/:id/cancel", crawlerAPI.CancelTask)
+		admin.GET("/crawler/changes", crawlerAPI.GetEpisodeChanges)
+		admin.GET("/shows/:id/changes", crawlerAPI.GetShowChanges)
 
 		// Publish
 		admin.POST("/publish/today", publishAPI.PublishTodayUpdates)
@@ -194,10 +350,17 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 		admin.POST("/publish/show/:id", publishAPI.PublishShow)
 		admin.POST("/publish/weekly", publishAPI.PublishWeekly)
 		admin.POST("/publish/monthly", publishAPI.PublishMonthly)
+		admin.GET("/publish/records", publishAPI.ListPublishRecords)
+		admin.GET("/publish/telegraph/:slot", publishAPI.GetTelegraphSlot)
 		admin.GET("/publish/markdown/today", publishAPI.GenerateMarkdownToday)
 		admin.GET("/publish/markdown/show/:id", publishAPI.GenerateMarkdownShow)
 		admin.GET("/publish/markdown/range", publishAPI.GenerateMarkdownRange)
 		admin.GET("/publish/markdown/weekly", publishAPI.GenerateMarkdownWeekly)
+		admin.GET("/publish/templates", templateAPI.ListTemplates)
+		admin.GET("/publish/templates/:name", templateAPI.GetTemplate)
+		admin.PUT("/publish/templates/:name", templateAPI.SaveTemplate)
+		admin.DELETE("/publish/templates/:name", templateAPI.ResetTemplate)
+		admin.POST("/publish/templates/:name/preview", templateAPI.PreviewTemplate)
 
 		// Scheduler
 		admin.GET("/scheduler/status", schedulerAPI.GetStatus)
@@ -226,6 +389,35 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 		admin.POST("/correction/:id/refresh", correctionAPI.RefreshShow)
 		admin.DELETE("/correction/:id/stale", correctionAPI.ClearStaleFlag)
 		admin.PUT("/correction/:id/threshold", correctionAPI.SetThreshold)
+
+		// Discovery
+		admin.GET("/discovery/candidates", discoveryAPI.ListCandidates)
+		admin.POST("/discovery/candidates/:id/approve", discoveryAPI.ApproveCandidate)
+		admin.POST("/discovery/candidates/:id/reject", discoveryAPI.RejectCandidate)
+		admin.POST("/discovery/run", discoveryAPI.RunDiscovery)
+		admin.GET("/discovery/rules", discoveryAPI.GetRules)
+
+		// TMDB list sync
+		admin.GET("/lists", tmdbListAPI.ListLists)
+		admin.POST("/lists", tmdbListAPI.CreateList)
+		admin.PUT("/lists/:id", tmdbListAPI.UpdateList)
+		admin.DELETE("/lists/:id", tmdbListAPI.DeleteList)
+		admin.GET("/lists/:id/history", tmdbListAPI.GetHistory)
+		admin.POST("/lists/:id/sync", tmdbListAPI.SyncList)
+		admin.POST("/lists/sync", tmdbListAPI.SyncAll)
+
+		// Event webhooks
+		admin.GET("/webhooks", webhookAPI.ListWebhooks)
+		admin.POST("/webhooks", webhookAPI.CreateWebhook)
+		admin.PUT("/webhooks/:id", webhookAPI.UpdateWebhook)
+		admin.DELETE("/webhooks/:id", webhookAPI.DeleteWebhook)
+		admin.POST("/webhooks/:id/test", webhookAPI.TestWebhook)
+		admin.GET("/webhooks/:id/deliveries", webhookAPI.ListDeliveries)
+
+		// Calendar feed tokens
+		admin.GET("/feed-tokens", calendarAPI.ListFeedTokens)
+		admin.POST("/feed-tokens", calendarAPI.CreateFeedToken)
+		admin.DELETE("/feed-tokens/:id", calendarAPI.RevokeFeedToken)
 	}
 
 	// Start scheduler if enabled
@@ -273,3 +465,17 @@ func corsMiddleware(cfg *config.Config) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// discoveryRules converts the discovery configuration
+func discoveryRules(cfg *config.Config) services.DiscoveryRules {
+	return services.DiscoveryRules{
+		Sources:       cfg.Discovery.Sources,
+		Pages:         cfg.Discovery.Pages,
+		Languages:     cfg.Discovery.Languages,
+		Countries:     cfg.Discovery.Countries,
+		GenreIDs:      cfg.Discovery.GenreIDs,
+		MinPopularity: cfg.Discovery.MinPopularity,
+		MinVoteCount:  cfg.Discovery.MinVoteCount,
+		AutoFollow:    cfg.Discovery.AutoFollow,
+	}
+}
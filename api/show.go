@@ -2,16 +2,21 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
 	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/xc9973/go-tmdb-crawler/dto"
 	"github.com/xc9973/go-tmdb-crawler/models"
 	"github.com/xc9973/go-tmdb-crawler/repositories"
 	"github.com/xc9973/go-tmdb-crawler/services"
+	"github.com/xc9973/go-tmdb-crawler/utils"
 )
 
 // ShowAPI handles show-related API endpoints
@@ -44,6 +49,11 @@ func (api *ShowAPI) ListShows(c *gin.Context) {
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
 	status := c.Query("status")
 	search := c.Query("search")
+	network := c.Query("network")
+	country := c.Query("country")
+	genre := c.Query("genre")
+	lang := requestLanguage(c)
+	filtered := search != "" || status != "" || network != "" || country != "" || genre != ""
 
 	// Validate page and pageSize
 	if page < 1 {
@@ -54,13 +64,13 @@ func (api *ShowAPI) ListShows(c *gin.Context) {
 	}
 
 	// Build cache key
-	cacheKey := services.ShowCacheKeyBuilder.Build("list", fmt.Sprintf("p%d_s%d_%s_%s", page, pageSize, status, search))
+	cacheKey := services.ShowCacheKeyBuilder.Build("list", fmt.Sprintf("p%d_s%d_%s_%s_%s", page, pageSize, status, search, lang))
 
 	var response dto.ListResponse
 	ctx := context.Background()
 
 	// Try cache first
-	if search == "" && status == "" {
+	if !filtered {
 		// Only cache unfiltered list requests
 		if err := api.cache.Get(ctx, cacheKey, &response); err == nil {
 			c.JSON(http.StatusOK, dto.Success(response))
@@ -73,8 +83,15 @@ func (api *ShowAPI) ListShows(c *gin.Context) {
 	var err error
 
 	// Handle different query types
-	if search != "" || status != "" {
-		shows, total, err = api.showRepo.ListFiltered(status, search, page, pageSize)
+	filter := repositories.ShowFilter{
+		Status:  status,
+		Search:  search,
+		Network: network,
+		Country: country,
+		Genre:   genre,
+	}
+	if filtered {
+		shows, total, err = api.showRepo.ListByFilter(filter, page, pageSize)
 	} else {
 		shows, total, err = api.showRepo.List(page, pageSize)
 	}
@@ -84,6 +101,12 @@ func (api *ShowAPI) ListShows(c *gin.Context) {
 		return
 	}
 
+	genreCounts, err := api.showRepo.CountGenres(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
 	// Build response
 	totalPages := int(total) / pageSize
 	if int(total)%pageSize > 0 {
@@ -91,15 +114,16 @@ func (api *ShowAPI) ListShows(c *gin.Context) {
 	}
 
 	response = dto.ListResponse{
-		Items:      shows,
+		Items:      services.LocalizeShows(shows, lang),
 		Total:      total,
 		Page:       page,
 		PageSize:   pageSize,
 		TotalPages: totalPages,
+		Facets:     map[string]interface{}{"genres": genreCounts},
 	}
 
 	// Cache unfiltered list responses
-	if search == "" && status == "" {
+	if !filtered {
 		api.cache.Set(ctx, cacheKey, response, services.CacheTTLMedium)
 	}
 
@@ -122,8 +146,10 @@ func (api *ShowAPI) GetShow(c *gin.Context) {
 	var show models.Show
 
 	// Try cache first
+	// The cached show keeps all translations; ?lang= is applied per request
+	lang := requestLanguage(c)
 	if err := api.cache.Get(ctx, cacheKey, &show); err == nil {
-		c.JSON(http.StatusOK, dto.Success(show))
+		c.JSON(http.StatusOK, dto.Success(show.Localized(lang)))
 		return
 	}
 
@@ -139,13 +165,17 @@ func (api *ShowAPI) GetShow(c *gin.Context) {
 	// Cache the result
 	api.cache.Set(ctx, cacheKey, show, services.CacheTTLLong)
 
-	c.JSON(http.StatusOK, dto.Success(show))
+	c.JSON(http.StatusOK, dto.Success(show.Localized(lang)))
 }
 
 // CreateShow handles POST /api/v1/shows
 func (api *ShowAPI) CreateShow(c *gin.Context) {
+	// The show is identified by tmdb_id, or by imdb_id/tvdb_id resolved through TMDB's /find
 	var req struct {
-		TmdbID int `json:"tmdb_id" binding:"required"`
+		TmdbID          int    `json:"tmdb_id"`
+		ImdbID          string `json:"imdb_id"`
+		TvdbID          int    `json:"tvdb_id"`
+		IncludeSpecials bool   `json:"include_specials"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -153,14 +183,32 @@ func (api *ShowAPI) CreateShow(c *gin.Context) {
 		return
 	}
 
+	if req.TmdbID == 0 {
+		source, externalID, err := parseExternalID(req.ImdbID, req.TvdbID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+			return
+		}
+		result, err := api.crawler.GetTMDBService().FindShowByExternalID(c.Request.Context(), source, externalID)
+		if errors.Is(err, services.ErrExternalIDNotFound) {
+			c.JSON(http.StatusNotFound, dto.NotFound(err.Error()))
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+			return
+		}
+		req.TmdbID = result.ID
+	}
+
 	// Check if show already exists
 	if _, err := api.showRepo.GetByTmdbID(req.TmdbID); err == nil {
 		c.JSON(http.StatusConflict, dto.Error(409, "Show already exists"))
 		return
 	}
 
-	// Crawl the show
-	if err := api.crawler.CrawlShow(req.TmdbID); err != nil {
+	// Crawl the show, including specials on the first pass when requested
+	if err := api.crawler.CrawlNewShow(c.Request.Context(), req.TmdbID, req.IncludeSpecials); err != nil {
 		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
 		return
 	}
@@ -178,6 +226,29 @@ func (api *ShowAPI) CreateShow(c *gin.Context) {
 	c.JSON(http.StatusCreated, dto.SuccessWithMessage("Show created successfully", show))
 }
 
+// LookupShow handles GET /api/v1/shows/lookup?imdb_id=tt0903747 or ?tvdb_id=81189
+func (api *ShowAPI) LookupShow(c *gin.Context) {
+	tvdbID, _ := strconv.Atoi(c.Query("tvdb_id"))
+	source, externalID, err := parseExternalID(c.Query("imdb_id"), tvdbID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		return
+	}
+
+	var show *models.Show
+	if source == services.ExternalSourceIMDb {
+		show, err = api.showRepo.GetByImdbID(externalID)
+	} else {
+		show, err = api.showRepo.GetByTvdbID(tvdbID)
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.NotFound("Show not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(show.Localized(requestLanguage(c))))
+}
+
 // UpdateShow handles PUT /api/v1/shows/:id
 func (api *ShowAPI) UpdateShow(c *gin.Context) {
 	idStr := c.Param("id")
@@ -205,14 +276,32 @@ func (api *ShowAPI) UpdateShow(c *gin.Context) {
 	req.TmdbID = show.TmdbID // Keep original TMDB ID
 	req.CreatedAt = show.CreatedAt
 
+	// Changing the specials setting requires a full crawl to add or drop season 0
+	if req.IncludeSpecials != show.IncludeSpecials {
+		req.ChangeCursor = nil
+	}
+
 	if err := api.showRepo.Update(&req); err != nil {
 		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
 		return
 	}
 
+	// A changed air schedule moves the air instants of all episodes
+	airScheduleChanged := req.AirTimezone != show.AirTimezone || req.AirTime != show.AirTime
+	if airScheduleChanged {
+		if err := api.episodeRepo.WithContext(c.Request.Context()).UpdateAirInstants(&req); err != nil {
+			c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+			return
+		}
+	}
+
 	// Invalidate caches
 	api.cache.Delete(context.Background(), services.ShowCacheKeyBuilder.Build("detail", idStr))
 	api.cache.InvalidatePattern(context.Background(), "show:list*")
+	if airScheduleChanged {
+		api.cache.InvalidatePattern(context.Background(), "episode*")
+		api.cache.InvalidatePattern(context.Background(), "today*")
+	}
 
 	c.JSON(http.StatusOK, dto.SuccessWithMessage("Show updated successfully", &req))
 }
@@ -255,7 +344,7 @@ func (api *ShowAPI) RefreshShow(c *gin.Context) {
 	}
 
 	// Refresh show
-	if err := api.crawler.CrawlShow(show.TmdbID); err != nil {
+	if err := api.crawler.CrawlShow(c.Request.Context(), show.TmdbID); err != nil {
 		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
 		return
 	}
@@ -288,7 +377,7 @@ func (api *ShowAPI) BatchCreateShows(c *gin.Context) {
 	}
 
 	// Batch crawl
-	results := api.crawler.BatchCrawl(req.TmdbIDs)
+	results := api.crawler.BatchCrawl(c.Request.Context(), req.TmdbIDs)
 
 	// Count successes
 	successCount := 0
@@ -314,7 +403,11 @@ func (api *ShowAPI) GetShowEpisodes(c *gin.Context) {
 	}
 
 	// Build cache key
+	lang := requestLanguage(c)
 	cacheKey := services.EpisodeCacheKeyBuilder.Build("show", idStr)
+	if lang != "" {
+		cacheKey = services.EpisodeCacheKeyBuilder.Build("show", idStr, lang)
+	}
 	ctx := context.Background()
 
 	var response map[string]interface{}
@@ -338,6 +431,10 @@ func (api *ShowAPI) GetShowEpisodes(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
 		return
 	}
+	if lang != "" {
+		show = show.Localized(lang)
+		episodes = services.LocalizeEpisodes(episodes, lang)
+	}
 
 	// Group episodes by season
 	type SeasonEpisodes struct {
@@ -380,3 +477,48 @@ func (api *ShowAPI) GetShowEpisodes(c *gin.Context) {
 
 	c.JSON(http.StatusOK, dto.Success(response))
 }
+
+// requestLanguage returns the metadata language requested with ?lang= (e.g. en-US).
+// An empty result means the stored primary-language text.
+func requestLanguage(c *gin.Context) string {
+	return strings.TrimSpace(c.Query("lang"))
+}
+
+// timezoneHeader is the request header carrying the viewer timezone when ?tz= is not set
+const timezoneHeader = "X-Timezone"
+
+// requestTimezone returns the viewer timezone requested with ?tz= or the X-Timezone
+// header, as an IANA name such as America/New_York. Without either, fallback
+// (normally the DEFAULT_TIMEZONE helper) is returned.
+func requestTimezone(c *gin.Context, fallback *utils.TimezoneHelper) (*utils.TimezoneHelper, error) {
+	name := strings.TrimSpace(c.Query("tz"))
+	if name == "" {
+		name = strings.TrimSpace(c.GetHeader(timezoneHeader))
+	}
+	if name == "" {
+		return fallback, nil
+	}
+	// "Local" would be the server's timezone, which is not a viewer timezone
+	location, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		return nil, fmt.Errorf("invalid timezone %q, expected an IANA name such as Asia/Shanghai", name)
+	}
+	return utils.NewTimezoneHelper(location), nil
+}
+
+var imdbIDPattern = regexp.MustCompile(`^tt\d+$`)
+
+// parseExternalID returns the TMDB /find source and ID for an IMDb or TVDB ID.
+// IMDb IDs take precedence when both are given.
+func parseExternalID(imdbID string, tvdbID int) (source, externalID string, err error) {
+	if imdbID = strings.ToLower(strings.TrimSpace(imdbID)); imdbID != "" {
+		if !imdbIDPattern.MatchString(imdbID) {
+			return "", "", fmt.Errorf("invalid imdb_id %q, expected e.g. tt0903747", imdbID)
+		}
+		return services.ExternalSourceIMDb, imdbID, nil
+	}
+	if tvdbID > 0 {
+		return services.ExternalSourceTVDB, strconv.Itoa(tvdbID), nil
+	}
+	return "", "", fmt.Errorf("one of tmdb_id, imdb_id or tvdb_id is required")
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xc9973/go-tmdb-crawler/dto"
+	"github.com/xc9973/go-tmdb-crawler/services"
+	"gorm.io/gorm"
+)
+
+// TemplateAPI handles the Markdown and Telegraph template endpoints
+type TemplateAPI struct {
+	templates *services.TemplateService
+	markdown  *services.MarkdownService
+}
+
+// NewTemplateAPI creates a new template API instance
+func NewTemplateAPI(templates *services.TemplateService, markdown *services.MarkdownService) *TemplateAPI {
+	return &TemplateAPI{
+		templates: templates,
+		markdown:  markdown,
+	}
+}
+
+// ListTemplates handles GET /api/v1/publish/templates
+func (api *TemplateAPI) ListTemplates(c *gin.Context) {
+	templates, err := api.templates.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(templates))
+}
+
+// GetTemplate handles GET /api/v1/publish/templates/:name
+// Returns the stored template, or the default when it has not been edited.
+func (api *TemplateAPI) GetTemplate(c *gin.Context) {
+	template, err := api.templates.Get(c.Param("name"))
+	if err != nil {
+		api.templateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(template))
+}
+
+// SaveTemplate handles PUT /api/v1/publish/templates/:name
+// The template is only saved when it parses.
+func (api *TemplateAPI) SaveTemplate(c *gin.Context) {
+	var req struct {
+		Source string `json:"source" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		return
+	}
+
+	name := c.Param("name")
+	if err := api.templates.Save(name, req.Source); err != nil {
+		api.templateError(c, err)
+		return
+	}
+
+	template, err := api.templates.Get(name)
+	if err != nil {
+		api.templateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessWithMessage("Template saved successfully", template))
+}
+
+// ResetTemplate handles DELETE /api/v1/publish/templates/:name
+// Deletes the stored template, restoring the default.
+func (api *TemplateAPI) ResetTemplate(c *gin.Context) {
+	if err := api.templates.Reset(c.Param("name")); err != nil {
+		api.templateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessWithMessage("Template reset to the default", nil))
+}
+
+// PreviewTemplate handles POST /api/v1/publish/templates/:name/preview?lang=&tz=
+// Renders the template against live data and returns the Markdown together with
+// the HTML and Telegraph nodes it converts to. A source in the body is previewed
+// instead of the stored template, without saving it. The range template needs
+// start_date and end_date, the show template show_id.
+func (api *TemplateAPI) PreviewTemplate(c *gin.Context) {
+	var req struct {
+		Source    string `json:"source"`
+		ShowID    uint   `json:"show_id"`
+		StartDate string `json:"start_date"`
+		EndDate   string `json:"end_date"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		return
+	}
+
+	name := c.Param("name")
+	var startDate, endDate time.Time
+	switch name {
+	case services.RangeTemplate:
+		if req.StartDate == "" || req.EndDate == "" {
+			c.JSON(http.StatusBadRequest, dto.BadRequest("start_date and end_date are required"))
+			return
+		}
+		var err error
+		if startDate, err = time.Parse("2006-01-02", req.StartDate); err != nil {
+			c.JSON(http.StatusBadRequest, dto.BadRequest("Invalid start_date format. Use YYYY-MM-DD"))
+			return
+		}
+		if endDate, err = time.Parse("2006-01-02", req.EndDate); err != nil {
+			c.JSON(http.StatusBadRequest, dto.BadRequest("Invalid end_date format. Use YYYY-MM-DD"))
+			return
+		}
+	case services.ShowTemplate:
+		if req.ShowID == 0 {
+			c.JSON(http.StatusBadRequest, dto.BadRequest("show_id is required"))
+			return
+		}
+	}
+
+	tzHelper, err := requestTimezone(c, nil)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		return
+	}
+
+	markdown, err := api.markdown.WithLanguage(requestLanguage(c)).WithTimezone(tzHelper).
+		PreviewTemplate(name, req.Source, req.ShowID, startDate, endDate)
+	if err != nil {
+		api.templateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(gin.H{
+		"name":     name,
+		"markdown": markdown,
+		"html":     services.MarkdownToHTML(markdown),
+		"nodes":    services.MarkdownToNodes(markdown),
+	}))
+}
+
+// templateError writes the response of a template error
+func (api *TemplateAPI) templateError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrUnknownTemplate):
+		c.JSON(http.StatusNotFound, dto.NotFound(err.Error()))
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, dto.NotFound("Show not found"))
+	case errors.Is(err, services.ErrInvalidTemplate):
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+	}
+}
@@ -0,0 +1,224 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xc9973/go-tmdb-crawler/dto"
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/repositories"
+	"github.com/xc9973/go-tmdb-crawler/services"
+	"gorm.io/gorm"
+)
+
+// TMDBListAPI handles the followed TMDB list endpoints
+type TMDBListAPI struct {
+	listSync *services.ListSyncService
+	listRepo repositories.TMDBListRepository
+	logRepo  repositories.CrawlLogRepository
+	cache    services.CacheService
+}
+
+// NewTMDBListAPI creates a new TMDB list API instance
+func NewTMDBListAPI(
+	listSync *services.ListSyncService,
+	listRepo repositories.TMDBListRepository,
+	logRepo repositories.CrawlLogRepository,
+	cache services.CacheService,
+) *TMDBListAPI {
+	return &TMDBListAPI{
+		listSync: listSync,
+		listRepo: listRepo,
+		logRepo:  logRepo,
+		cache:    cache,
+	}
+}
+
+// ListLists handles GET /api/v1/lists
+func (api *TMDBListAPI) ListLists(c *gin.Context) {
+	lists, err := api.listRepo.WithContext(c.Request.Context()).ListAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(lists))
+}
+
+// CreateList handles POST /api/v1/lists
+// The list is registered only; its shows are crawled by the next sync.
+func (api *TMDBListAPI) CreateList(c *gin.Context) {
+	var req struct {
+		ListID         int   `json:"list_id" binding:"required"`
+		Enabled        *bool `json:"enabled"`
+		ArchiveRemoved bool  `json:"archive_removed"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		return
+	}
+
+	listRepo := api.listRepo.WithContext(c.Request.Context())
+	if _, err := listRepo.GetByListID(req.ListID); err == nil {
+		c.JSON(http.StatusConflict, dto.Error(409, "List already exists"))
+		return
+	}
+
+	list := &models.TMDBList{
+		ListID:         req.ListID,
+		Enabled:        req.Enabled == nil || *req.Enabled,
+		ArchiveRemoved: req.ArchiveRemoved,
+	}
+	if err := list.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		return
+	}
+	if err := listRepo.Create(list); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+	// GORM skips zero values that have a default, so a disabled list is stored explicitly
+	if !list.Enabled {
+		if err := listRepo.Update(list); err != nil {
+			c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessWithMessage("List created successfully", list))
+}
+
+// UpdateList handles PUT /api/v1/lists/:id
+func (api *TMDBListAPI) UpdateList(c *gin.Context) {
+	list, ok := api.getList(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Enabled        *bool `json:"enabled"`
+		ArchiveRemoved *bool `json:"archive_removed"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		return
+	}
+
+	if req.Enabled != nil {
+		list.Enabled = *req.Enabled
+	}
+	if req.ArchiveRemoved != nil {
+		list.ArchiveRemoved = *req.ArchiveRemoved
+	}
+	if err := api.listRepo.WithContext(c.Request.Context()).Update(list); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessWithMessage("List updated successfully", list))
+}
+
+// DeleteList handles DELETE /api/v1/lists/:id
+// The shows of the list stay followed.
+func (api *TMDBListAPI) DeleteList(c *gin.Context) {
+	list, ok := api.getList(c)
+	if !ok {
+		return
+	}
+
+	if err := api.listRepo.WithContext(c.Request.Context()).Delete(list.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessWithMessage("List deleted successfully", nil))
+}
+
+// GetHistory handles GET /api/v1/lists/:id/history
+func (api *TMDBListAPI) GetHistory(c *gin.Context) {
+	list, ok := api.getList(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	logs, err := api.logRepo.WithContext(c.Request.Context()).GetByListID(list.ListID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(logs))
+}
+
+// SyncList handles POST /api/v1/lists/:id/sync
+func (api *TMDBListAPI) SyncList(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest("Invalid list ID"))
+		return
+	}
+
+	result, err := api.listSync.Sync(c.Request.Context(), uint(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, dto.NotFound("List not found"))
+		case errors.Is(err, services.ErrListSyncRunning):
+			c.JSON(http.StatusConflict, dto.Error(409, err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		}
+		return
+	}
+
+	api.cache.InvalidatePattern(context.Background(), "show:list*")
+	c.JSON(http.StatusOK, dto.SuccessWithMessage(
+		fmt.Sprintf("List sync %s: %d crawled, %d failed, %d archived", result.Status, result.Crawled, result.Failed, result.Archived),
+		result,
+	))
+}
+
+// SyncAll handles POST /api/v1/lists/sync
+func (api *TMDBListAPI) SyncAll(c *gin.Context) {
+	results, err := api.listSync.SyncAll(c.Request.Context())
+	if err != nil {
+		if errors.Is(err, services.ErrListSyncRunning) {
+			c.JSON(http.StatusConflict, dto.Error(409, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	api.cache.InvalidatePattern(context.Background(), "show:list*")
+	c.JSON(http.StatusOK, dto.SuccessWithMessage(fmt.Sprintf("%d lists synced", len(results)), results))
+}
+
+// getList loads the list in the path, writing the error response when it cannot
+func (api *TMDBListAPI) getList(c *gin.Context) (*models.TMDBList, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest("Invalid list ID"))
+		return nil, false
+	}
+
+	list, err := api.listRepo.WithContext(c.Request.Context()).GetByID(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dto.NotFound("List not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		}
+		return nil, false
+	}
+	return list, true
+}
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xc9973/go-tmdb-crawler/dto"
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/repositories"
+	"github.com/xc9973/go-tmdb-crawler/services"
+	"gorm.io/gorm"
+)
+
+// WebhookAPI handles the event webhook endpoints
+type WebhookAPI struct {
+	webhooks    *services.WebhookService
+	webhookRepo repositories.WebhookRepository
+}
+
+// NewWebhookAPI creates a new webhook API instance
+func NewWebhookAPI(webhooks *services.WebhookService, webhookRepo repositories.WebhookRepository) *WebhookAPI {
+	return &WebhookAPI{
+		webhooks:    webhooks,
+		webhookRepo: webhookRepo,
+	}
+}
+
+// ListWebhooks handles GET /api/v1/webhooks
+func (api *WebhookAPI) ListWebhooks(c *gin.Context) {
+	endpoints, err := api.webhookRepo.WithContext(c.Request.Context()).ListEndpoints()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(gin.H{
+		"endpoints": endpoints,
+		"events":    models.WebhookEvents,
+	}))
+}
+
+// CreateWebhook handles POST /api/v1/webhooks
+// The signing secret is only returned here; it cannot be shown again.
+func (api *WebhookAPI) CreateWebhook(c *gin.Context) {
+	var req struct {
+		Name   string   `json:"name" binding:"required"`
+		URL    string   `json:"url" binding:"required"`
+		Events []string `json:"events"`
+		Secret string   `json:"secret"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		return
+	}
+
+	endpoint, err := api.webhooks.CreateEndpoint(c.Request.Context(), req.Name, req.URL, req.Events, req.Secret)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidWebhook) {
+			c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessWithMessage("Webhook created, store the secret now: it is not shown again", gin.H{
+		"endpoint": endpoint,
+		"secret":   endpoint.Secret,
+	}))
+}
+
+// UpdateWebhook handles PUT /api/v1/webhooks/:id
+// Omitted fields are left unchanged; rotate_secret returns a new signing secret.
+func (api *WebhookAPI) UpdateWebhook(c *gin.Context) {
+	endpoint, ok := api.getEndpoint(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Name         *string   `json:"name"`
+		URL          *string   `json:"url"`
+		Events       *[]string `json:"events"`
+		Enabled      *bool     `json:"enabled"`
+		RotateSecret bool      `json:"rotate_secret"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		return
+	}
+
+	if req.Name != nil {
+		endpoint.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.URL != nil {
+		endpoint.URL = strings.TrimSpace(*req.URL)
+	}
+	if req.Events != nil {
+		endpoint.SetEventList(*req.Events)
+	}
+	if req.Enabled != nil {
+		endpoint.Enabled = *req.Enabled
+	}
+	if err := endpoint.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest(err.Error()))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if req.RotateSecret {
+		if err := api.webhooks.RotateSecret(ctx, endpoint); err != nil {
+			c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+			return
+		}
+		c.JSON(http.StatusOK, dto.SuccessWithMessage("Webhook updated, store the new secret now: it is not shown again", gin.H{
+			"endpoint": endpoint,
+			"secret":   endpoint.Secret,
+		}))
+		return
+	}
+
+	if err := api.webhookRepo.WithContext(ctx).UpdateEndpoint(endpoint); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessWithMessage("Webhook updated successfully", gin.H{
+		"endpoint": endpoint,
+	}))
+}
+
+// DeleteWebhook handles DELETE /api/v1/webhooks/:id
+// The delivery log of the endpoint is deleted with it.
+func (api *WebhookAPI) DeleteWebhook(c *gin.Context) {
+	endpoint, ok := api.getEndpoint(c)
+	if !ok {
+		return
+	}
+
+	if err := api.webhookRepo.WithContext(c.Request.Context()).DeleteEndpoint(endpoint.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessWithMessage("Webhook deleted successfully", nil))
+}
+
+// TestWebhook handles POST /api/v1/webhooks/:id/test
+// Sends a ping event once, without retries, and returns the delivery.
+func (api *WebhookAPI) TestWebhook(c *gin.Context) {
+	endpoint, ok := api.getEndpoint(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := api.webhooks.Ping(c.Request.Context(), endpoint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(delivery))
+}
+
+// ListDeliveries handles GET /api/v1/webhooks/:id/deliveries?limit=
+// The deliveries to the endpoint, newest first.
+func (api *WebhookAPI) ListDeliveries(c *gin.Context) {
+	endpoint, ok := api.getEndpoint(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	deliveries, err := api.webhookRepo.WithContext(c.Request.Context()).ListDeliveries(endpoint.ID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(deliveries))
+}
+
+// getEndpoint loads the endpoint of the :id parameter, writing the error response when it fails
+func (api *WebhookAPI) getEndpoint(c *gin.Context) (*models.WebhookEndpoint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.BadRequest("Invalid webhook ID"))
+		return nil, false
+	}
+
+	endpoint, err := api.webhookRepo.WithContext(c.Request.Context()).GetEndpoint(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dto.NotFound("Webhook not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.InternalError(err.Error()))
+		}
+		return nil, false
+	}
+	return endpoint, true
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/xc9973/go-tmdb-crawler/config"
+	"github.com/xc9973/go-tmdb-crawler/models"
 	"github.com/xc9973/go-tmdb-crawler/repositories"
 	"github.com/xc9973/go-tmdb-crawler/services"
 	"github.com/xc9973/go-tmdb-crawler/services/correction"
@@ -47,6 +49,7 @@ var schedulerCmd = &cobra.Command{
 		episodeRepo := repositories.NewEpisodeRepository(db)
 		crawlLogRepo := repositories.NewCrawlLogRepository(db)
 		crawlTaskRepo := repositories.NewCrawlTaskRepository(db)
+		episodeChangeRepo := repositories.NewEpisodeChangeRepository(db)
 		telegraphPostRepo := repositories.NewTelegraphPostRepository(db)
 
 		// Load timezone
@@ -55,16 +58,45 @@ var schedulerCmd = &cobra.Command{
 			log.Fatalf("Failed to load timezone '%s': %v", cfg.Timezone.Default, err)
 		}
 
+		timezoneHelper := utils.NewTimezoneHelper(location)
+		episodeRepo.SetTimezoneHelper(timezoneHelper)
+
 		// Initialize services
 		logger := utils.NewLogger(cfg.App.LogLevel, cfg.Paths.Log)
-		tmdb := services.MustTMDBService(cfg.TMDB.APIKey, cfg.TMDB.BaseURL, cfg.TMDB.Language)
-		crawler := services.NewCrawlerService(tmdb, showRepo, episodeRepo, crawlLogRepo, crawlTaskRepo)
+		tmdb := newTMDBService(cfg, db)
+		crawler := services.NewCrawlerService(tmdb, showRepo, episodeRepo, crawlLogRepo, crawlTaskRepo, episodeChangeRepo)
+		crawler.SetConcurrency(cfg.Crawler.Concurrency)
+		crawler.SetFallbackLanguages(cfg.TMDB.FallbackLanguages)
+		crawler.SetEpisodeExternalIDs(cfg.TMDB.EpisodeExternalIDs)
 		telegraph := services.NewTelegraphService(cfg.Telegraph.Token, cfg.Telegraph.ShortName, cfg.Telegraph.AuthorName, cfg.Telegraph.AuthorURL)
-		publisher := services.NewPublisherService(telegraph, showRepo, episodeRepo, telegraphPostRepo, nil)
+		publisher := services.NewPublisherService(telegraph, showRepo, episodeRepo, telegraphPostRepo, timezoneHelper)
+		movieRepo := setMovieReleases(cfg, db, publisher)
+		setPublishTargets(cfg, db, publisher, telegraph, telegraphPostRepo)
 		correctionService := correction.NewService(showRepo, episodeRepo, crawlTaskRepo, crawler, location)
+		webhooks := newWebhookService(cfg, db, logger)
+		crawler.SetEventEmitter(webhooks)
+		publisher.SetEventEmitter(webhooks)
+		correctionService.SetEventEmitter(webhooks)
 
 		// Initialize scheduler
 		scheduler := services.NewScheduler(crawler, publisher, correctionService, logger)
+		if cfg.Discovery.Enabled {
+			scheduler.SetDiscovery(newDiscoveryService(cfg, db, crawler, showRepo), cfg.Discovery.Cron)
+		}
+		if cfg.ListSync.Enabled {
+			scheduler.SetListSync(newListSyncService(db, crawler, showRepo), cfg.ListSync.Cron)
+		}
+		if cfg.Movies.RefreshCron != "" {
+			scheduler.SetMovieRefresh(services.NewMovieService(tmdb, movieRepo), cfg.Movies.RefreshCron)
+		}
+		botCtx, stopBot := context.WithCancel(context.Background())
+		defer stopBot()
+		if telegramBot := newTelegramBot(cfg, showRepo, episodeRepo, crawler, timezoneHelper, logger); telegramBot != nil {
+			scheduler.SetTelegramBot(telegramBot)
+			if cfg.Telegram.Commands && cfg.Telegram.CommandsProcess == "scheduler" {
+				go telegramBot.Run(botCtx)
+			}
+		}
 
 		// Start scheduler
 		log.Println("Starting scheduler service...")
@@ -89,7 +121,9 @@ var schedulerCmd = &cobra.Command{
 
 		// Stop scheduler
 		log.Println("Stopping scheduler...")
+		stopBot()
 		scheduler.Stop()
+		webhooks.Close()
 		log.Println("Scheduler stopped")
 	},
 }
@@ -116,32 +150,76 @@ var schedulerRunOnceCmd = &cobra.Command{
 		episodeRepo := repositories.NewEpisodeRepository(db)
 		crawlLogRepo := repositories.NewCrawlLogRepository(db)
 		crawlTaskRepo := repositories.NewCrawlTaskRepository(db)
+		episodeChangeRepo := repositories.NewEpisodeChangeRepository(db)
 		telegraphPostRepo := repositories.NewTelegraphPostRepository(db)
 
+		location, err := time.LoadLocation(cfg.Timezone.Default)
+		if err != nil {
+			log.Fatalf("Failed to load timezone '%s': %v", cfg.Timezone.Default, err)
+		}
+		timezoneHelper := utils.NewTimezoneHelper(location)
+		episodeRepo.SetTimezoneHelper(timezoneHelper)
+
 		// Initialize services
-		tmdb := services.MustTMDBService(cfg.TMDB.APIKey, cfg.TMDB.BaseURL, cfg.TMDB.Language)
-		crawler := services.NewCrawlerService(tmdb, showRepo, episodeRepo, crawlLogRepo, crawlTaskRepo)
+		tmdb := newTMDBService(cfg, db)
+		crawler := services.NewCrawlerService(tmdb, showRepo, episodeRepo, crawlLogRepo, crawlTaskRepo, episodeChangeRepo)
+		crawler.SetConcurrency(cfg.Crawler.Concurrency)
+		crawler.SetFallbackLanguages(cfg.TMDB.FallbackLanguages)
+		crawler.SetEpisodeExternalIDs(cfg.TMDB.EpisodeExternalIDs)
 		telegraph := services.NewTelegraphService(cfg.Telegraph.Token, cfg.Telegraph.ShortName, cfg.Telegraph.AuthorName, cfg.Telegraph.AuthorURL)
-		publisher := services.NewPublisherService(telegraph, showRepo, episodeRepo, telegraphPostRepo, nil)
+		publisher := services.NewPublisherService(telegraph, showRepo, episodeRepo, telegraphPostRepo, timezoneHelper)
+		setMovieReleases(cfg, db, publisher)
+		setPublishTargets(cfg, db, publisher, telegraph, telegraphPostRepo)
+		webhooks := newWebhookService(cfg, db, utils.NewLogger(cfg.App.LogLevel, cfg.Paths.Log))
+		crawler.SetEventEmitter(webhooks)
+		publisher.SetEventEmitter(webhooks)
 
-		// Run crawl job
+		// Run crawl job, stopping early on interrupt
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
 		log.Println("Running crawl job...")
-		if err := crawler.RefreshAll(); err != nil {
+		summary, err := crawler.RefreshAll(ctx)
+		if err != nil {
 			log.Printf("Crawl job failed: %v", err)
+		} else if summary.Failed > 0 {
+			log.Printf("Crawl job completed with failures: %d succeeded, %d failed, %d skipped",
+				summary.Succeeded, summary.Failed, summary.Skipped)
 		} else {
-			log.Println("Crawl job completed successfully")
+			log.Printf("Crawl job completed successfully: %d shows, %d episodes added, %d updated",
+				summary.Succeeded, summary.EpisodesAdded, summary.EpisodesUpdated)
 		}
 
 		// Run publish job
 		log.Println("Running publish job...")
 		result, err := publisher.PublishTodayUpdates()
+		if result != nil {
+			for _, target := range result.Targets {
+				if target.Success {
+					log.Printf("Published to %s: %s", target.Target, target.URL)
+				} else {
+					log.Printf("Publish to %s failed: %s", target.Target, target.Error)
+				}
+			}
+		}
 		if err != nil {
 			log.Printf("Publish job failed: %v", err)
 		} else if result.Success {
 			log.Printf("Publish job completed successfully: %s", result.URL)
+			if telegramBot := newTelegramBot(cfg, showRepo, episodeRepo, crawler, timezoneHelper, utils.NewLogger(cfg.App.LogLevel, cfg.Paths.Log)); telegramBot != nil {
+				if err := telegramBot.NotifyPublish(ctx, result); err != nil {
+					log.Printf("Telegram notification failed: %v", err)
+				}
+			}
 		} else {
 			log.Printf("Publish job skipped: %v", result.Error)
 		}
+
+		// Deliver the webhook events before exiting; an interrupt drops the pending retries
+		go func() {
+			<-ctx.Done()
+			webhooks.Close()
+		}()
+		webhooks.Wait()
 	},
 }
 
@@ -180,3 +258,114 @@ func init() {
 	schedulerCmd.AddCommand(schedulerRunOnceCmd)
 	schedulerCmd.AddCommand(schedulerStatusCmd)
 }
+
+// newDiscoveryService creates the discovery service with the configured rules
+func newDiscoveryService(cfg *config.Config, db *gorm.DB, crawler *services.CrawlerService, showRepo repositories.ShowRepository) *services.DiscoveryService {
+	if err := db.AutoMigrate(&models.DiscoveryCandidate{}); err != nil {
+		log.Fatalf("Failed to migrate discovery candidates table: %v", err)
+	}
+	discovery := services.NewDiscoveryService(crawler, showRepo, repositories.NewDiscoveryCandidateRepository(db))
+	discovery.SetRules(services.DiscoveryRules{
+		Sources:       cfg.Discovery.Sources,
+		Pages:         cfg.Discovery.Pages,
+		Languages:     cfg.Discovery.Languages,
+		Countries:     cfg.Discovery.Countries,
+		GenreIDs:      cfg.Discovery.GenreIDs,
+		MinPopularity: cfg.Discovery.MinPopularity,
+		MinVoteCount:  cfg.Discovery.MinVoteCount,
+		AutoFollow:    cfg.Discovery.AutoFollow,
+	})
+	return discovery
+}
+
+// newListSyncService creates the TMDB list sync service
+func newListSyncService(db *gorm.DB, crawler *services.CrawlerService, showRepo repositories.ShowRepository) *services.ListSyncService {
+	if err := db.AutoMigrate(&models.TMDBList{}); err != nil {
+		log.Fatalf("Failed to migrate TMDB lists table: %v", err)
+	}
+	return services.NewListSyncService(crawler, showRepo, repositories.NewTMDBListRepository(db))
+}
+
+// setMovieReleases adds the configured movie releases to the publisher's update lists
+// and returns the movie repository
+func setMovieReleases(cfg *config.Config, db *gorm.DB, publisher *services.PublisherService) repositories.MovieRepository {
+	if err := db.AutoMigrate(&models.Movie{}, &models.MovieReleaseDate{}); err != nil {
+		log.Fatalf("Failed to migrate movie tables: %v", err)
+	}
+	movieRepo := repositories.NewMovieRepository(db)
+	publisher.SetMovieReleases(movieRepo, repositories.MovieReleaseFilter{
+		Regions: cfg.Movies.ReleaseRegions,
+		Types:   cfg.Movies.ReleaseTypes,
+	})
+	return movieRepo
+}
+
+// setPublishTargets sets the configured publish targets and templates and records their results
+func setPublishTargets(cfg *config.Config, db *gorm.DB, publisher *services.PublisherService, telegraph *services.TelegraphService, telegraphPostRepo repositories.TelegraphPostRepository) {
+	if err := db.AutoMigrate(&models.PublishRecord{}, &models.TelegraphPost{}, &models.TelegraphPostRevision{}); err != nil {
+		log.Fatalf("Failed to migrate publish tables: %v", err)
+	}
+	targets, err := services.NewPublishTargets(cfg.Publish.Targets, services.NewPublishTargetOptions(cfg, telegraph, telegraphPostRepo))
+	if err != nil {
+		log.Fatalf("Failed to initialize publish targets: %v", err)
+	}
+	publisher.SetTargets(targets)
+	publisher.SetPublishRecords(repositories.NewPublishRecordRepository(db))
+	publisher.SetTemplates(services.NewTemplateService(cfg.Publish.TemplatesDir))
+}
+
+// newWebhookService creates the event webhook service with the configured retries
+func newWebhookService(cfg *config.Config, db *gorm.DB, logger *utils.Logger) *services.WebhookService {
+	if err := db.AutoMigrate(&models.WebhookEndpoint{}, &models.WebhookDelivery{}); err != nil {
+		log.Fatalf("Failed to migrate webhook tables: %v", err)
+	}
+	webhooks := services.NewWebhookService(repositories.NewWebhookRepository(db), logger)
+	webhooks.SetRetry(cfg.Webhooks.MaxAttempts, cfg.Webhooks.RetryBackoff)
+	return webhooks
+}
+
+// newTelegramBot creates the Telegram bot, or returns nil when no bot token is configured
+func newTelegramBot(cfg *config.Config, showRepo repositories.ShowRepository, episodeRepo repositories.EpisodeRepository, crawler *services.CrawlerService, timezoneHelper *utils.TimezoneHelper, logger *utils.Logger) *services.TelegramBot {
+	if cfg.Telegram.BotToken == "" {
+		return nil
+	}
+	bot := services.NewTelegramBot(services.NewTelegramClient(cfg.Telegram.BotToken, cfg.Telegram.APIURL),
+		showRepo, episodeRepo, crawler, timezoneHelper, logger)
+	bot.SetNotifyChats(cfg.Telegram.NotifyChatIDs)
+	bot.SetAdminUsers(cfg.Telegram.AdminUserIDs)
+	return bot
+}
+
+// newTMDBService creates the TMDB client with the configured rate limit, mode and response cache
+func newTMDBService(cfg *config.Config, db *gorm.DB) *services.TMDBService {
+	var tmdb *services.TMDBService
+	if cfg.TMDB.Mode == services.TMDBModeReplay {
+		// Replay mode serves fixtures and needs no API key
+		tmdb = services.NewTMDBService(cfg.TMDB.APIKey, cfg.TMDB.BaseURL, cfg.TMDB.Language)
+	} else {
+		tmdb = services.MustTMDBService(cfg.TMDB.APIKey, cfg.TMDB.BaseURL, cfg.TMDB.Language)
+	}
+	tmdb.SetRateLimit(cfg.TMDB.RateLimit)
+	tmdb.SetAccessToken(cfg.TMDB.ReadAccessToken)
+	if err := tmdb.SetMode(cfg.TMDB.Mode, cfg.TMDB.FixturesDir); err != nil {
+		log.Fatalf("Failed to set TMDB mode: %v", err)
+	}
+
+	if cfg.TMDB.CacheBackend == services.TMDBCacheBackendSQL {
+		if err := db.AutoMigrate(&models.TMDBCacheEntry{}); err != nil {
+			log.Fatalf("Failed to migrate TMDB cache table: %v", err)
+		}
+	}
+	cache, err := services.NewTMDBCacheStore(cfg.TMDB.CacheBackend, cfg.Paths.Data, repositories.NewTMDBCacheRepository(db))
+	if err != nil {
+		log.Fatalf("Failed to initialize TMDB cache: %v", err)
+	}
+	tmdb.SetCacheStore(cache)
+	tmdb.SetCacheTTLs(services.TMDBCacheTTLs{
+		Show:    cfg.TMDB.CacheTTLShow,
+		Season:  cfg.TMDB.CacheTTLSeason,
+		Search:  cfg.TMDB.CacheTTLSearch,
+		Changes: cfg.TMDB.CacheTTLChanges,
+	})
+	return tmdb
+}
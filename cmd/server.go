@@ -1,8 +1,15 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/xc9973/go-tmdb-crawler/api"
@@ -20,16 +27,33 @@ var serverCmd = &cobra.Command{
 			log.Fatalf("Failed to load config: %v", err)
 		}
 
+		// Stop the server and its background work on interrupt
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
 		// Initialize router (SetupRouter handles all database and service initialization)
-		router := api.SetupRouter(cfg)
+		router := api.SetupRouter(ctx, cfg)
 
 		// Start server
 		addr := fmt.Sprintf(":%d", cfg.App.Port)
 		fmt.Printf("Server starting on http://localhost%s\n", addr)
 
-		if err := router.Run(addr); err != nil {
+		srv := &http.Server{Addr: addr, Handler: router}
+		stopped := make(chan struct{})
+		go func() {
+			defer close(stopped)
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := srv.Shutdown(shutdownCtx); err != nil {
+				log.Printf("Failed to shut down server: %v", err)
+			}
+		}()
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Fatalf("Failed to start server: %v", err)
 		}
+		<-stopped
+		log.Println("Server stopped")
 	},
 }
 
@@ -3,7 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -14,8 +16,15 @@ type Config struct {
 	App       AppConfig
 	Database  DatabaseConfig
 	TMDB      TMDBConfig
+	Crawler   CrawlerConfig
 	Telegraph TelegraphConfig
+	Publish   PublishConfig
+	Telegram  TelegramConfig
+	Webhooks  WebhooksConfig
 	Scheduler SchedulerConfig
+	Discovery DiscoveryConfig
+	ListSync  ListSyncConfig
+	Movies    MoviesConfig
 	Paths     PathsConfig
 	CORS      CORSConfig
 	Timezone  TimezoneConfig
@@ -46,6 +55,33 @@ type TMDBConfig struct {
 	APIKey   string
 	BaseURL  string
 	Language string
+	// ReadAccessToken is the v4 bearer token, needed to read private TMDB lists
+	ReadAccessToken string
+	// FallbackLanguages are used, in order, for names and overviews missing in Language
+	FallbackLanguages []string
+	// EpisodeExternalIDs fetches IMDb/TVDB IDs for every episode (one request per episode)
+	EpisodeExternalIDs bool
+	// RateLimit is the maximum number of TMDB requests per second
+	RateLimit int
+	// CacheBackend is where TMDB responses are cached: memory, disk or sql
+	CacheBackend string
+	// Cache lifetimes per endpoint type
+	CacheTTLShow    time.Duration
+	CacheTTLSeason  time.Duration
+	CacheTTLSearch  time.Duration
+	CacheTTLChanges time.Duration
+	// Mode is live, record (save responses as fixtures) or replay (serve fixtures offline)
+	Mode string
+	// FixturesDir holds the recorded responses for record and replay mode
+	FixturesDir string
+}
+
+// CrawlerConfig holds crawler configuration
+type CrawlerConfig struct {
+	// Concurrency is the number of shows crawled in parallel
+	Concurrency int
+	// ResumeInterrupted resumes tasks interrupted by a restart instead of marking them interrupted
+	ResumeInterrupted bool
 }
 
 // TelegraphConfig holds Telegraph configuration
@@ -56,6 +92,47 @@ type TelegraphConfig struct {
 	AuthorURL  string
 }
 
+// PublishConfig selects where the update lists are published
+type PublishConfig struct {
+	// Targets receive every update list, in order: telegraph, telegram, webhook, html, feed
+	Targets []string
+	// Dir receives the html pages and the Atom feed
+	Dir string
+	// BaseURL is the public URL of Dir, used for links in the pages and the feed
+	BaseURL string
+	// TelegramChatID is the chat or @channel of the telegram target, which posts as the Telegram bot
+	TelegramChatID string
+	// WebhookURL receives the update lists as JSON
+	WebhookURL string
+	// TemplatesDir holds the user-edited templates of the Markdown and the published pages
+	TemplatesDir string
+}
+
+// TelegramConfig holds the Telegram bot settings
+type TelegramConfig struct {
+	BotToken string
+	// APIURL is the Bot API server; point it at a local fake server for testing
+	APIURL string
+	// NotifyChatIDs receive the link and a summary after each scheduled publish
+	NotifyChatIDs []string
+	// Commands answers /today, /week, /show and /follow by polling for updates
+	Commands bool
+	// CommandsProcess is the process that polls for commands, server or scheduler.
+	// Telegram allows one poller per bot token.
+	CommandsProcess string
+	// AdminUserIDs are the Telegram users allowed to /follow shows
+	AdminUserIDs []int
+}
+
+// WebhooksConfig holds the delivery settings of the event webhooks.
+// The endpoints themselves are managed through the admin API.
+type WebhooksConfig struct {
+	// MaxAttempts is how often a delivery is tried before it is given up on
+	MaxAttempts int
+	// RetryBackoff is the wait before the first retry; it doubles with every further retry
+	RetryBackoff time.Duration
+}
+
 // SchedulerConfig holds scheduler configuration
 type SchedulerConfig struct {
 	Enabled bool
@@ -63,6 +140,43 @@ type SchedulerConfig struct {
 	TZ      string
 }
 
+// DiscoveryConfig holds the rules for discovering new shows in TMDB's TV lists
+type DiscoveryConfig struct {
+	Enabled bool
+	// Cron is the 6-field spec of the discovery job (seconds first)
+	Cron string
+	// Sources are the TMDB lists to pull: on_the_air, airing_today, trending
+	Sources []string
+	// Pages is the number of pages pulled per list
+	Pages int
+	// Languages, Countries and GenreIDs restrict candidates; empty accepts all
+	Languages []string
+	Countries []string
+	GenreIDs  []int
+	// MinPopularity and MinVoteCount are the lowest accepted TMDB values
+	MinPopularity float64
+	MinVoteCount  int
+	// AutoFollow follows matching shows right away instead of queueing them for review
+	AutoFollow bool
+}
+
+// ListSyncConfig holds the schedule of the TMDB list sync job
+type ListSyncConfig struct {
+	Enabled bool
+	// Cron is the 6-field spec of the list sync job (seconds first)
+	Cron string
+}
+
+// MoviesConfig selects the movie releases shown in the update lists
+type MoviesConfig struct {
+	// ReleaseRegions are ISO 3166-1 country codes; empty includes every region
+	ReleaseRegions []string
+	// ReleaseTypes are TMDB release types (1 premiere ... 6 TV); empty includes every type
+	ReleaseTypes []int
+	// RefreshCron is the 6-field spec of the movie refresh job; empty disables it
+	RefreshCron string
+}
+
 // PathsConfig holds paths configuration
 type PathsConfig struct {
 	Web  string
@@ -119,9 +233,27 @@ func Load() (*Config, error) {
 			SSLMode:  getEnv("DB_SSL_MODE", "disable"),
 		},
 		TMDB: TMDBConfig{
-			APIKey:   getEnv("TMDB_API_KEY", ""),
-			BaseURL:  getEnv("TMDB_BASE_URL", "https://api.themoviedb.org/3"),
-			Language: getEnv("TMDB_LANGUAGE", "zh-CN"),
+			APIKey:          getEnv("TMDB_API_KEY", ""),
+			BaseURL:         getEnv("TMDB_BASE_URL", "https://api.themoviedb.org/3"),
+			Language:        getEnv("TMDB_LANGUAGE", "zh-CN"),
+			ReadAccessToken: getEnv("TMDB_READ_ACCESS_TOKEN", ""),
+			RateLimit:       getEnvAsInt("TMDB_RATE_LIMIT", 40),
+
+			FallbackLanguages:  getEnvAsList("TMDB_FALLBACK_LANGUAGES", []string{"en-US"}),
+			EpisodeExternalIDs: getEnvAsBool("TMDB_EPISODE_EXTERNAL_IDS", false),
+
+			CacheBackend:    getEnv("TMDB_CACHE_BACKEND", "memory"),
+			CacheTTLShow:    getEnvAsDuration("TMDB_CACHE_TTL_SHOW", 5*time.Minute),
+			CacheTTLSeason:  getEnvAsDuration("TMDB_CACHE_TTL_SEASON", 5*time.Minute),
+			CacheTTLSearch:  getEnvAsDuration("TMDB_CACHE_TTL_SEARCH", 5*time.Minute),
+			CacheTTLChanges: getEnvAsDuration("TMDB_CACHE_TTL_CHANGES", 5*time.Minute),
+
+			Mode:        getEnv("TMDB_MODE", "live"),
+			FixturesDir: getEnv("TMDB_FIXTURES_DIR", ""),
+		},
+		Crawler: CrawlerConfig{
+			Concurrency:       getEnvAsInt("CRAWLER_CONCURRENCY", 4),
+			ResumeInterrupted: getEnvAsBool("CRAWLER_RESUME_INTERRUPTED", false),
 		},
 		Telegraph: TelegraphConfig{
 			Token:      getEnv("TELEGRAPH_TOKEN", ""),
@@ -129,11 +261,52 @@ func Load() (*Config, error) {
 			AuthorName: getEnv("TELEGRAPH_AUTHOR_NAME", "剧集更新助手"),
 			AuthorURL:  getEnv("TELEGRAPH_AUTHOR_URL", ""),
 		},
+		Publish: PublishConfig{
+			Targets:        getEnvAsList("PUBLISH_TARGETS", []string{"telegraph"}),
+			Dir:            getEnv("PUBLISH_DIR", ""),
+			BaseURL:        getEnv("PUBLISH_BASE_URL", ""),
+			TelegramChatID: getEnv("TELEGRAM_CHAT_ID", ""),
+			WebhookURL:     getEnv("PUBLISH_WEBHOOK_URL", ""),
+			TemplatesDir:   getEnv("PUBLISH_TEMPLATES_DIR", ""),
+		},
+		Telegram: TelegramConfig{
+			BotToken:        getEnv("TELEGRAM_BOT_TOKEN", ""),
+			APIURL:          getEnv("TELEGRAM_API_URL", "https://api.telegram.org"),
+			NotifyChatIDs:   getEnvAsList("TELEGRAM_NOTIFY_CHAT_IDS", nil),
+			Commands:        getEnvAsBool("TELEGRAM_BOT_COMMANDS", false),
+			CommandsProcess: getEnv("TELEGRAM_BOT_COMMANDS_PROCESS", "server"),
+			AdminUserIDs:    getEnvAsIntList("TELEGRAM_ADMIN_USER_IDS", nil),
+		},
+		Webhooks: WebhooksConfig{
+			MaxAttempts:  getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 5),
+			RetryBackoff: getEnvAsDuration("WEBHOOK_RETRY_BACKOFF", 30*time.Second),
+		},
 		Scheduler: SchedulerConfig{
 			Enabled: getEnvAsBool("ENABLE_SCHEDULER", true),
 			Cron:    getEnv("DAILY_CRON", "0 8 * * *"),
 			TZ:      getEnv("SCHEDULER_TZ", "Asia/Shanghai"),
 		},
+		Discovery: DiscoveryConfig{
+			Enabled:       getEnvAsBool("DISCOVERY_ENABLED", false),
+			Cron:          getEnv("DISCOVERY_CRON", "0 0 6 * * *"),
+			Sources:       getEnvAsList("DISCOVERY_SOURCES", []string{"on_the_air", "airing_today", "trending"}),
+			Pages:         getEnvAsInt("DISCOVERY_PAGES", 1),
+			Languages:     getEnvAsList("DISCOVERY_LANGUAGES", nil),
+			Countries:     getEnvAsList("DISCOVERY_COUNTRIES", nil),
+			GenreIDs:      getEnvAsIntList("DISCOVERY_GENRES", nil),
+			MinPopularity: getEnvAsFloat("DISCOVERY_MIN_POPULARITY", 0),
+			MinVoteCount:  getEnvAsInt("DISCOVERY_MIN_VOTE_COUNT", 0),
+			AutoFollow:    getEnvAsBool("DISCOVERY_AUTO_FOLLOW", false),
+		},
+		ListSync: ListSyncConfig{
+			Enabled: getEnvAsBool("LIST_SYNC_ENABLED", false),
+			Cron:    getEnv("LIST_SYNC_CRON", "0 0 5 * * *"),
+		},
+		Movies: MoviesConfig{
+			ReleaseRegions: getEnvAsList("MOVIE_RELEASE_REGIONS", nil),
+			ReleaseTypes:   getEnvAsIntList("MOVIE_RELEASE_TYPES", []int{3, 4}),
+			RefreshCron:    getEnv("MOVIE_REFRESH_CRON", "0 0 4 * * *"),
+		},
 		Paths: PathsConfig{
 			Web:  getEnv("WEB_DIR", "./web"),
 			Log:  getEnv("LOG_DIR", "./logs"),
@@ -142,7 +315,7 @@ func Load() (*Config, error) {
 		CORS: CORSConfig{
 			AllowedOrigins: getEnv("CORS_ALLOWED_ORIGINS", ""),
 			AllowedMethods: getEnv("CORS_ALLOWED_METHODS", "GET,POST,PUT,DELETE,OPTIONS"),
-			AllowedHeaders: getEnv("CORS_ALLOWED_HEADERS", "Content-Type,Authorization,X-Admin-API-Key"),
+			AllowedHeaders: getEnv("CORS_ALLOWED_HEADERS", "Content-Type,Authorization,X-Admin-API-Key,X-Timezone"),
 		},
 		Timezone: TimezoneConfig{
 			Default: getEnv("DEFAULT_TIMEZONE", "Asia/Shanghai"),
@@ -157,15 +330,80 @@ func Load() (*Config, error) {
 	if cfg.Database.Type == "postgres" && cfg.Database.Password == "" {
 		return nil, fmt.Errorf("DB_PASSWORD is required for PostgreSQL")
 	}
-	if cfg.TMDB.APIKey == "" {
+	switch cfg.TMDB.Mode {
+	case "live", "record", "replay":
+	default:
+		return nil, fmt.Errorf("TMDB_MODE must be live, record or replay")
+	}
+	// Replay mode never talks to TMDB, so it runs without an API key
+	if cfg.TMDB.APIKey == "" && cfg.TMDB.Mode != "replay" {
 		return nil, fmt.Errorf("TMDB_API_KEY is required")
 	}
+	if cfg.TMDB.FixturesDir == "" {
+		cfg.TMDB.FixturesDir = filepath.Join(cfg.Paths.Data, "tmdb_fixtures")
+	}
+	if cfg.Publish.Dir == "" {
+		cfg.Publish.Dir = filepath.Join(cfg.Paths.Data, "publish")
+	}
+	if cfg.Publish.TemplatesDir == "" {
+		cfg.Publish.TemplatesDir = filepath.Join(cfg.Paths.Data, "templates")
+	}
+	for _, target := range cfg.Publish.Targets {
+		switch target {
+		case "telegraph", "html", "feed":
+		case "telegram":
+			if cfg.Telegram.BotToken == "" || cfg.Publish.TelegramChatID == "" {
+				return nil, fmt.Errorf("TELEGRAM_BOT_TOKEN and TELEGRAM_CHAT_ID are required for the telegram publish target")
+			}
+		case "webhook":
+			if cfg.Publish.WebhookURL == "" {
+				return nil, fmt.Errorf("PUBLISH_WEBHOOK_URL is required for the webhook publish target")
+			}
+		default:
+			return nil, fmt.Errorf("PUBLISH_TARGETS must only contain telegraph, telegram, webhook, html or feed")
+		}
+	}
+	if (len(cfg.Telegram.NotifyChatIDs) > 0 || cfg.Telegram.Commands) && cfg.Telegram.BotToken == "" {
+		return nil, fmt.Errorf("TELEGRAM_BOT_TOKEN is required for Telegram notifications and commands")
+	}
+	if cfg.Telegram.CommandsProcess != "server" && cfg.Telegram.CommandsProcess != "scheduler" {
+		return nil, fmt.Errorf("TELEGRAM_BOT_COMMANDS_PROCESS must be server or scheduler")
+	}
+	if cfg.Webhooks.MaxAttempts < 1 || cfg.Webhooks.MaxAttempts > 20 {
+		return nil, fmt.Errorf("WEBHOOK_MAX_ATTEMPTS must be between 1 and 20")
+	}
+	if cfg.Webhooks.RetryBackoff < 0 {
+		return nil, fmt.Errorf("WEBHOOK_RETRY_BACKOFF cannot be negative")
+	}
 	if cfg.App.Port < 1 || cfg.App.Port > 65535 {
 		return nil, fmt.Errorf("APP_PORT must be between 1 and 65535")
 	}
 	if cfg.Database.Port < 1 || cfg.Database.Port > 65535 {
 		return nil, fmt.Errorf("DB_PORT must be between 1 and 65535")
 	}
+	if cfg.Crawler.Concurrency < 1 {
+		return nil, fmt.Errorf("CRAWLER_CONCURRENCY must be at least 1")
+	}
+	switch cfg.TMDB.CacheBackend {
+	case "memory", "disk", "sql":
+	default:
+		return nil, fmt.Errorf("TMDB_CACHE_BACKEND must be memory, disk or sql")
+	}
+	for _, source := range cfg.Discovery.Sources {
+		switch source {
+		case "on_the_air", "airing_today", "trending":
+		default:
+			return nil, fmt.Errorf("DISCOVERY_SOURCES must only contain on_the_air, airing_today or trending")
+		}
+	}
+	if cfg.Discovery.Pages < 1 {
+		return nil, fmt.Errorf("DISCOVERY_PAGES must be at least 1")
+	}
+	for _, releaseType := range cfg.Movies.ReleaseTypes {
+		if releaseType < 1 || releaseType > 6 {
+			return nil, fmt.Errorf("MOVIE_RELEASE_TYPES must only contain release types 1 to 6")
+		}
+	}
 	if cfg.Database.Type != "sqlite" && cfg.Database.Type != "postgres" {
 		return nil, fmt.Errorf("DB_TYPE must be sqlite or postgres")
 	}
@@ -212,6 +450,57 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvAsFloat gets an environment variable as float
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsIntList gets a comma-separated environment variable as a list of integers.
+// Items that are not integers are skipped.
+func getEnvAsIntList(key string, defaultValue []int) []int {
+	items := getEnvAsList(key, nil)
+	if items == nil {
+		return defaultValue
+	}
+	var list []int
+	for _, item := range items {
+		if i, err := strconv.Atoi(item); err == nil {
+			list = append(list, i)
+		}
+	}
+	return list
+}
+
+// getEnvAsList gets a comma-separated environment variable as a list
+func getEnvAsList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// getEnvAsDuration gets an environment variable as duration (e.g. "10m", "6h")
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
 // GetDSN returns the database connection string for PostgreSQL
 func (c *Config) GetDSN() string {
 	return fmt.Sprintf(
@@ -14,6 +14,8 @@ type ListResponse struct {
 	Page       int         `json:"page"`
 	PageSize   int         `json:"page_size"`
 	TotalPages int         `json:"total_pages"`
+	// Facets holds per-value counts for list filters, e.g. {"genres": [...]}
+	Facets map[string]interface{} `json:"facets,omitempty"`
 }
 
 // Success creates a success response
@@ -1,5 +1,7 @@
 package dto
 
+import "encoding/json"
+
 // TMDBShowResponse represents the response from TMDB show details API
 type TMDBShowResponse struct {
 	ID           int              `json:"id"`
@@ -15,6 +17,25 @@ type TMDBShowResponse struct {
 	VoteAverage  float32          `json:"vote_average"`
 	VoteCount    int              `json:"vote_count"`
 	Seasons      []TMDBSeasonInfo `json:"seasons"`
+
+	// Production and run schedule
+	Networks            []TMDBCompany `json:"networks"`
+	ProductionCompanies []TMDBCompany `json:"production_companies"`
+	OriginCountry       []string      `json:"origin_country"`
+	EpisodeRunTime      []int         `json:"episode_run_time"`
+	InProduction        bool          `json:"in_production"`
+	LastAirDate         string        `json:"last_air_date"`
+	LastEpisodeToAir    *TMDBEpisode  `json:"last_episode_to_air"`
+	NextEpisodeToAir    *TMDBEpisode  `json:"next_episode_to_air"`
+
+	// ExternalIDs is set when requested with append_to_response=external_ids
+	ExternalIDs *TMDBExternalIDs `json:"external_ids,omitempty"`
+}
+
+// TMDBExternalIDs represents the IDs of a show or episode in other databases
+type TMDBExternalIDs struct {
+	IMDbID string `json:"imdb_id"`
+	TVDBID int    `json:"tvdb_id"`
 }
 
 // TMDBGenre represents a genre
@@ -23,6 +44,14 @@ type TMDBGenre struct {
 	Name string `json:"name"`
 }
 
+// TMDBCompany represents a network or production company
+type TMDBCompany struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	LogoPath      string `json:"logo_path,omitempty"`
+	OriginCountry string `json:"origin_country,omitempty"`
+}
+
 // TMDBSeasonInfo represents season information
 type TMDBSeasonInfo struct {
 	SeasonNumber int    `json:"season_number"`
@@ -64,15 +93,109 @@ type TMDBSearchResponse struct {
 	TotalResults int              `json:"total_results"`
 }
 
-// TMDBShowResult represents a show in search results
+// TMDBShowResult represents a show in search results and TV lists
 type TMDBShowResult struct {
-	ID           int     `json:"id"`
-	Name         string  `json:"name"`
-	OriginalName string  `json:"original_name"`
-	PosterPath   string  `json:"poster_path"`
-	FirstAirDate string  `json:"first_air_date"`
-	Overview     string  `json:"overview"`
-	VoteAverage  float32 `json:"vote_average"`
+	ID               int      `json:"id"`
+	Name             string   `json:"name"`
+	OriginalName     string   `json:"original_name"`
+	OriginalLanguage string   `json:"original_language"`
+	OriginCountry    []string `json:"origin_country"`
+	GenreIDs         []int    `json:"genre_ids"`
+	PosterPath       string   `json:"poster_path"`
+	FirstAirDate     string   `json:"first_air_date"`
+	Overview         string   `json:"overview"`
+	Popularity       float64  `json:"popularity"`
+	VoteAverage      float32  `json:"vote_average"`
+	VoteCount        int      `json:"vote_count"`
+}
+
+// TMDBMovieResponse represents the response from TMDB movie details API
+type TMDBMovieResponse struct {
+	ID               int         `json:"id"`
+	ImdbID           string      `json:"imdb_id"`
+	Title            string      `json:"title"`
+	OriginalTitle    string      `json:"original_title"`
+	OriginalLanguage string      `json:"original_language"`
+	Status           string      `json:"status"`
+	ReleaseDate      string      `json:"release_date"`
+	Runtime          int         `json:"runtime"`
+	Overview         string      `json:"overview"`
+	PosterPath       string      `json:"poster_path"`
+	BackdropPath     string      `json:"backdrop_path"`
+	Genres           []TMDBGenre `json:"genres"`
+	Popularity       float64     `json:"popularity"`
+	VoteAverage      float32     `json:"vote_average"`
+	VoteCount        int         `json:"vote_count"`
+
+	// ReleaseDates is set when requested with append_to_response=release_dates
+	ReleaseDates *TMDBReleaseDatesResponse `json:"release_dates,omitempty"`
+}
+
+// TMDBReleaseDatesResponse represents the release dates of a movie per country
+type TMDBReleaseDatesResponse struct {
+	Results []TMDBReleaseDateCountry `json:"results"`
+}
+
+// TMDBReleaseDateCountry represents the release dates of a movie in one country
+type TMDBReleaseDateCountry struct {
+	ISO31661     string            `json:"iso_3166_1"`
+	ReleaseDates []TMDBReleaseDate `json:"release_dates"`
+}
+
+// TMDBReleaseDate represents a single release of a movie.
+// Type: 1 premiere, 2 limited theatrical, 3 theatrical, 4 digital, 5 physical, 6 TV
+type TMDBReleaseDate struct {
+	Certification string `json:"certification"`
+	Note          string `json:"note"`
+	ReleaseDate   string `json:"release_date"` // RFC 3339, e.g. 2024-03-01T00:00:00.000Z
+	Type          int    `json:"type"`
+}
+
+// TMDBMovieResult represents a movie in search results
+type TMDBMovieResult struct {
+	ID               int     `json:"id"`
+	Title            string  `json:"title"`
+	OriginalTitle    string  `json:"original_title"`
+	OriginalLanguage string  `json:"original_language"`
+	GenreIDs         []int   `json:"genre_ids"`
+	PosterPath       string  `json:"poster_path"`
+	ReleaseDate      string  `json:"release_date"`
+	Overview         string  `json:"overview"`
+	Popularity       float64 `json:"popularity"`
+	VoteAverage      float32 `json:"vote_average"`
+	VoteCount        int     `json:"vote_count"`
+}
+
+// TMDBMovieSearchResponse represents the response from TMDB movie search API
+type TMDBMovieSearchResponse struct {
+	Page         int               `json:"page"`
+	Results      []TMDBMovieResult `json:"results"`
+	TotalPages   int               `json:"total_pages"`
+	TotalResults int               `json:"total_results"`
+}
+
+// TMDBFindResponse represents the response from TMDB /find/{external_id} API
+type TMDBFindResponse struct {
+	TVResults []TMDBShowResult `json:"tv_results"`
+}
+
+// TMDBListResponse represents a page of a TMDB v4 list (/4/list/{list_id})
+type TMDBListResponse struct {
+	ID           int            `json:"id"`
+	Name         string         `json:"name"`
+	Description  string         `json:"description"`
+	Page         int            `json:"page"`
+	Results      []TMDBListItem `json:"results"`
+	TotalPages   int            `json:"total_pages"`
+	TotalResults int            `json:"total_results"`
+}
+
+// TMDBListItem represents an entry of a TMDB list; lists mix movies and TV shows
+type TMDBListItem struct {
+	ID        int    `json:"id"`
+	MediaType string `json:"media_type"` // "tv" or "movie"
+	Name      string `json:"name"`
+	Title     string `json:"title"`
 }
 
 // TMDBErrorResponse represents an error response from TMDB
@@ -81,3 +204,45 @@ type TMDBErrorResponse struct {
 	StatusMessage string `json:"status_message"`
 	Success       bool   `json:"success"`
 }
+
+// TMDBChangesResponse represents the response from TMDB /tv/changes API
+type TMDBChangesResponse struct {
+	Results      []TMDBChangedItem `json:"results"`
+	Page         int               `json:"page"`
+	TotalPages   int               `json:"total_pages"`
+	TotalResults int               `json:"total_results"`
+}
+
+// TMDBChangedItem represents a changed TV show in the changes list
+type TMDBChangedItem struct {
+	ID    int  `json:"id"`
+	Adult bool `json:"adult"`
+}
+
+// TMDBShowChangesResponse represents the response from TMDB /tv/{id}/changes API
+type TMDBShowChangesResponse struct {
+	Changes []TMDBChange `json:"changes"`
+}
+
+// TMDBChange represents changes to a single field of a show
+type TMDBChange struct {
+	Key   string           `json:"key"`
+	Items []TMDBChangeItem `json:"items"`
+}
+
+// TMDBChangeItem represents a single change entry
+// Value and OriginalValue vary by key, e.g. {"season_id": 1, "season_number": 2} for seasons
+type TMDBChangeItem struct {
+	ID            string          `json:"id"`
+	Action        string          `json:"action"`
+	Time          string          `json:"time"`
+	ISO6391       string          `json:"iso_639_1,omitempty"`
+	Value         json.RawMessage `json:"value,omitempty"`
+	OriginalValue json.RawMessage `json:"original_value,omitempty"`
+}
+
+// TMDBSeasonChangeValue represents the value of a "season" change item
+type TMDBSeasonChangeValue struct {
+	SeasonID     int `json:"season_id"`
+	SeasonNumber int `json:"season_number"`
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// redactedQueryParams are query parameters whose values are secrets, such as calendar feed tokens
+var redactedQueryParams = []string{"token"}
+
+// RequestLogger returns gin's request logger with secret query parameters redacted
+func RequestLogger() gin.HandlerFunc {
+	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
+		param.Path = redactPath(param.Path)
+		return formatRequestLog(param)
+	})
+}
+
+// redactPath replaces the values of secret query parameters in a logged path
+func redactPath(path string) string {
+	index := strings.Index(path, "?")
+	if index == -1 {
+		return path
+	}
+	query, err := url.ParseQuery(path[index+1:])
+	if err != nil {
+		// Keep the path only, as the query cannot be checked
+		return path[:index] + "?REDACTED"
+	}
+	redacted := false
+	for _, name := range redactedQueryParams {
+		if _, ok := query[name]; ok {
+			query.Set(name, "REDACTED")
+			redacted = true
+		}
+	}
+	if !redacted {
+		return path
+	}
+	return path[:index] + "?" + query.Encode()
+}
+
+// formatRequestLog formats a request like gin's default logger
+func formatRequestLog(param gin.LogFormatterParams) string {
+	var statusColor, methodColor, resetColor string
+	if param.IsOutputColor() {
+		statusColor = param.StatusCodeColor()
+		methodColor = param.MethodColor()
+		resetColor = param.ResetColor()
+	}
+	if param.Latency > time.Minute {
+		param.Latency = param.Latency.Truncate(time.Second)
+	}
+	return fmt.Sprintf("[GIN] %v |%s %3d %s| %13v | %15s |%s %-7s %s %#v\n%s",
+		param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		statusColor, param.StatusCode, resetColor,
+		param.Latency,
+		param.ClientIP,
+		methodColor, param.Method, resetColor,
+		param.Path,
+		param.ErrorMessage,
+	)
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRedactPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/api/v1/calendar.ics", "/api/v1/calendar.ics"},
+		{"/api/v1/calendar.ics?token=secret", "/api/v1/calendar.ics?token=REDACTED"},
+		{"/api/v1/shows/1/calendar.ics?days=30&token=secret", "/api/v1/shows/1/calendar.ics?days=30&token=REDACTED"},
+		{"/api/v1/shows?page=2", "/api/v1/shows?page=2"},
+		{"/api/v1/calendar.ics?token=%zz", "/api/v1/calendar.ics?REDACTED"},
+	}
+	for _, tt := range tests {
+		if got := redactPath(tt.path); got != tt.want {
+			t.Errorf("redactPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRequestLogger_RedactsFeedTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var output bytes.Buffer
+	defaultWriter := gin.DefaultWriter
+	gin.DefaultWriter = &output
+	defer func() { gin.DefaultWriter = defaultWriter }()
+
+	router := gin.New()
+	router.Use(RequestLogger())
+	var token string
+	router.GET("/calendar.ics", func(c *gin.Context) {
+		token = c.Query("token")
+		c.Status(http.StatusOK)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/calendar.ics?token=secret", nil))
+
+	if token != "secret" {
+		t.Errorf("Expected the handler to get the token, got %q", token)
+	}
+	if strings.Contains(output.String(), "secret") || !strings.Contains(output.String(), "token=REDACTED") {
+		t.Errorf("Expected the token to be redacted from the log, got %q", output.String())
+	}
+}
@@ -0,0 +1,9 @@
+-- TMDB Crawler Incremental Crawl Migration
+-- Version: 007
+-- Created: 2026-10-16
+-- Description: Track the TMDB change cursor per show for incremental crawls
+-- Note: SQLite picks this column up through GORM AutoMigrate of the shows table
+
+ALTER TABLE shows ADD COLUMN IF NOT EXISTS change_cursor TIMESTAMP DEFAULT NULL;
+
+COMMENT ON COLUMN shows.change_cursor IS 'Time up to which TMDB changes have been applied, NULL means a full crawl is required';
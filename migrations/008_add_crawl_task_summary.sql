@@ -0,0 +1,9 @@
+-- TMDB Crawler Crawl Task Summary Migration
+-- Version: 008
+-- Created: 2026-10-16
+-- Description: Persist the per-show result summary of batch crawl tasks
+-- Note: SQLite picks this column up through GORM AutoMigrate of the crawl_tasks table
+
+ALTER TABLE crawl_tasks ADD COLUMN IF NOT EXISTS summary TEXT;
+
+COMMENT ON COLUMN crawl_tasks.summary IS 'JSON summary of per-show results (succeeded, failed with reasons, skipped, episodes added/updated)';
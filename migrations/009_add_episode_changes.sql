@@ -0,0 +1,25 @@
+-- TMDB Crawler Episode Change History Migration
+-- Version: 009
+-- Created: 2026-10-16
+-- Description: Record episode changes (added/removed/air date moved/title changed) detected by crawls
+-- Note: SQLite picks this table up through GORM AutoMigrate
+
+CREATE TABLE IF NOT EXISTS episode_changes (
+    id SERIAL PRIMARY KEY,
+    crawl_log_id INTEGER REFERENCES crawl_logs(id) ON DELETE SET NULL,
+    show_id INTEGER NOT NULL REFERENCES shows(id) ON DELETE CASCADE,
+    season_number INTEGER NOT NULL,
+    episode_number INTEGER NOT NULL,
+    change_type VARCHAR(30) NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_change_crawl_log_id ON episode_changes(crawl_log_id);
+CREATE INDEX IF NOT EXISTS idx_change_show_id ON episode_changes(show_id);
+CREATE INDEX IF NOT EXISTS idx_change_type ON episode_changes(change_type);
+CREATE INDEX IF NOT EXISTS idx_change_created_at ON episode_changes(created_at);
+
+COMMENT ON TABLE episode_changes IS 'Episode change history detected during crawls';
+COMMENT ON COLUMN episode_changes.change_type IS 'added/removed/air_date_changed/title_changed';
@@ -0,0 +1,9 @@
+-- TMDB Crawler Specials Migration
+-- Version: 010
+-- Created: 2026-10-16
+-- Description: Per-show opt-in for crawling season 0 specials
+-- Note: SQLite picks this column up through GORM AutoMigrate of the shows table
+
+ALTER TABLE shows ADD COLUMN IF NOT EXISTS include_specials BOOLEAN DEFAULT FALSE;
+
+COMMENT ON COLUMN shows.include_specials IS 'Whether season 0 specials are crawled for this show';
@@ -0,0 +1,15 @@
+-- TMDB Crawler Crawl Task Progress Migration
+-- Version: 011
+-- Created: 2026-10-16
+-- Description: Track live progress of batch crawl tasks
+-- Note: SQLite picks these columns up through GORM AutoMigrate of the crawl_tasks table
+
+ALTER TABLE crawl_tasks ADD COLUMN IF NOT EXISTS total_shows INTEGER DEFAULT 0;
+ALTER TABLE crawl_tasks ADD COLUMN IF NOT EXISTS processed_shows INTEGER DEFAULT 0;
+ALTER TABLE crawl_tasks ADD COLUMN IF NOT EXISTS current_show VARCHAR(255);
+ALTER TABLE crawl_tasks ADD COLUMN IF NOT EXISTS estimated_finish_at TIMESTAMP;
+
+COMMENT ON COLUMN crawl_tasks.total_shows IS 'Number of shows the task will crawl';
+COMMENT ON COLUMN crawl_tasks.processed_shows IS 'Number of shows crawled so far (succeeded, failed or cancelled)';
+COMMENT ON COLUMN crawl_tasks.current_show IS 'Show most recently started by a crawl worker';
+COMMENT ON COLUMN crawl_tasks.estimated_finish_at IS 'Estimated completion time based on the average time per show';
@@ -0,0 +1,20 @@
+-- TMDB Crawler TMDB Cache Migration
+-- Version: 012
+-- Created: 2026-10-16
+-- Description: Shared TMDB response cache for the sql cache backend (TMDB_CACHE_BACKEND=sql)
+-- Note: SQLite picks this table up through GORM AutoMigrate
+
+CREATE TABLE IF NOT EXISTS tmdb_cache (
+    key VARCHAR(512) PRIMARY KEY,
+    body BYTEA NOT NULL,
+    etag VARCHAR(255),
+    last_modified VARCHAR(64),
+    expires_at TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_tmdb_cache_expires_at ON tmdb_cache(expires_at);
+
+COMMENT ON TABLE tmdb_cache IS 'Cached TMDB API responses shared by the server and scheduler processes';
+COMMENT ON COLUMN tmdb_cache.etag IS 'ETag returned by TMDB, sent as If-None-Match when revalidating';
+COMMENT ON COLUMN tmdb_cache.last_modified IS 'Last-Modified returned by TMDB, sent as If-Modified-Since when revalidating';
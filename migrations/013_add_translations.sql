@@ -0,0 +1,12 @@
+-- TMDB Crawler Translations Migration
+-- Version: 013
+-- Created: 2026-10-16
+-- Description: Per-language names and overviews for shows and episodes
+-- Note: SQLite picks the shows column up through GORM AutoMigrate; the episodes
+--       column is added at startup because episodes are not auto-migrated
+
+ALTER TABLE shows ADD COLUMN IF NOT EXISTS translations TEXT;
+ALTER TABLE episodes ADD COLUMN IF NOT EXISTS translations TEXT;
+
+COMMENT ON COLUMN shows.translations IS 'JSON object of language code to {name, overview}, e.g. {"en-US": {"name": "..."}}';
+COMMENT ON COLUMN episodes.translations IS 'JSON object of language code to {name, overview}, e.g. {"en-US": {"name": "..."}}';
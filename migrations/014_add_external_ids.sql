@@ -0,0 +1,19 @@
+-- TMDB Crawler External IDs Migration
+-- Version: 014
+-- Created: 2026-10-16
+-- Description: IMDb and TVDB IDs of shows and episodes, for matching against other tools
+-- Note: SQLite picks the shows columns up through GORM AutoMigrate; the episodes
+--       columns are added at startup because episodes are not auto-migrated
+
+ALTER TABLE shows ADD COLUMN IF NOT EXISTS imdb_id VARCHAR(20);
+ALTER TABLE shows ADD COLUMN IF NOT EXISTS tvdb_id INTEGER;
+ALTER TABLE episodes ADD COLUMN IF NOT EXISTS imdb_id VARCHAR(20);
+ALTER TABLE episodes ADD COLUMN IF NOT EXISTS tvdb_id INTEGER DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_imdb_id ON shows(imdb_id);
+CREATE INDEX IF NOT EXISTS idx_tvdb_id ON shows(tvdb_id);
+
+COMMENT ON COLUMN shows.imdb_id IS 'IMDb ID from TMDB external_ids, e.g. tt0903747';
+COMMENT ON COLUMN shows.tvdb_id IS 'TVDB ID from TMDB external_ids';
+COMMENT ON COLUMN episodes.imdb_id IS 'IMDb ID of the episode (only with TMDB_EPISODE_EXTERNAL_IDS)';
+COMMENT ON COLUMN episodes.tvdb_id IS 'TVDB ID of the episode (only with TMDB_EPISODE_EXTERNAL_IDS)';
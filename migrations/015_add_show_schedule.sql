@@ -0,0 +1,24 @@
+-- TMDB Crawler Show Schedule Migration
+-- Version: 015
+-- Created: 2026-10-16
+-- Description: Networks, production companies, origin country and run schedule of shows
+-- Note: SQLite picks these columns up through GORM AutoMigrate
+
+ALTER TABLE shows ADD COLUMN IF NOT EXISTS networks TEXT;
+ALTER TABLE shows ADD COLUMN IF NOT EXISTS production_companies TEXT;
+ALTER TABLE shows ADD COLUMN IF NOT EXISTS origin_country VARCHAR(50);
+ALTER TABLE shows ADD COLUMN IF NOT EXISTS episode_run_time INTEGER DEFAULT 0;
+ALTER TABLE shows ADD COLUMN IF NOT EXISTS in_production BOOLEAN DEFAULT FALSE;
+ALTER TABLE shows ADD COLUMN IF NOT EXISTS last_air_date DATE;
+ALTER TABLE shows ADD COLUMN IF NOT EXISTS last_episode_season INTEGER DEFAULT 0;
+ALTER TABLE shows ADD COLUMN IF NOT EXISTS last_episode_number INTEGER DEFAULT 0;
+ALTER TABLE shows ADD COLUMN IF NOT EXISTS next_episode_season INTEGER DEFAULT 0;
+ALTER TABLE shows ADD COLUMN IF NOT EXISTS next_episode_number INTEGER DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_origin_country ON shows(origin_country);
+
+COMMENT ON COLUMN shows.networks IS 'JSON array of TMDB networks ({"id","name","logo_path","origin_country"})';
+COMMENT ON COLUMN shows.production_companies IS 'JSON array of TMDB production companies';
+COMMENT ON COLUMN shows.origin_country IS 'Comma-separated ISO 3166-1 origin countries, e.g. US,GB';
+COMMENT ON COLUMN shows.episode_run_time IS 'Typical episode run time in minutes';
+COMMENT ON COLUMN shows.next_air_date IS 'Air date of next_episode_to_air from TMDB';
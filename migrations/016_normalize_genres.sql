@@ -0,0 +1,37 @@
+-- TMDB Crawler Genres Migration
+-- Version: 016
+-- Created: 2026-10-16
+-- Description: Move show genres from a JSON string column into genres and show_genres tables
+-- Note: SQLite creates the tables through GORM AutoMigrate and converts the JSON
+--       at startup (ShowRepository.ConvertLegacyGenres)
+
+CREATE TABLE IF NOT EXISTS genres (
+    id INTEGER PRIMARY KEY,             -- TMDB genre ID
+    name VARCHAR(100) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS show_genres (
+    show_id INTEGER NOT NULL REFERENCES shows(id) ON DELETE CASCADE,
+    genre_id INTEGER NOT NULL REFERENCES genres(id) ON DELETE CASCADE,
+    PRIMARY KEY (show_id, genre_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_show_genres_genre_id ON show_genres(genre_id);
+
+-- Convert the existing JSON ([{"id":18,"name":"剧情"}, ...])
+INSERT INTO genres (id, name)
+SELECT DISTINCT ON ((g->>'id')::INTEGER) (g->>'id')::INTEGER, g->>'name'
+FROM shows, jsonb_array_elements(shows.genres::jsonb) AS g
+WHERE shows.genres IS NOT NULL AND shows.genres LIKE '[%'
+ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name;
+
+INSERT INTO show_genres (show_id, genre_id)
+SELECT shows.id, (g->>'id')::INTEGER
+FROM shows, jsonb_array_elements(shows.genres::jsonb) AS g
+WHERE shows.genres IS NOT NULL AND shows.genres LIKE '[%'
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE shows DROP COLUMN IF EXISTS genres;
+
+COMMENT ON TABLE genres IS 'TMDB genres';
+COMMENT ON TABLE show_genres IS 'Genres of each show';
@@ -0,0 +1,37 @@
+-- TMDB Crawler Discovery Migration
+-- Version: 017
+-- Created: 2026-10-16
+-- Description: Review queue of shows discovered in TMDB's on_the_air, airing_today and trending lists
+-- Note: SQLite picks this table up through GORM AutoMigrate
+
+CREATE TABLE IF NOT EXISTS discovery_candidates (
+    id SERIAL PRIMARY KEY,
+    tmdb_id INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    original_name VARCHAR(255),
+    original_language VARCHAR(10),
+    origin_country VARCHAR(50),
+    genre_ids VARCHAR(255),
+    overview TEXT,
+    poster_path VARCHAR(512),
+    first_air_date DATE,
+    popularity DECIMAL(10,3) DEFAULT 0,
+    vote_average DECIMAL(3,1) DEFAULT 0,
+    vote_count INTEGER DEFAULT 0,
+    sources VARCHAR(100),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    auto_followed BOOLEAN DEFAULT FALSE,
+    show_id INTEGER REFERENCES shows(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP,
+    last_seen_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_discovery_tmdb_id ON discovery_candidates(tmdb_id);
+CREATE INDEX IF NOT EXISTS idx_discovery_status ON discovery_candidates(status);
+CREATE INDEX IF NOT EXISTS idx_discovery_created_at ON discovery_candidates(created_at);
+
+COMMENT ON TABLE discovery_candidates IS 'Shows found by discovery, waiting for review or already reviewed';
+COMMENT ON COLUMN discovery_candidates.status IS 'pending/approved/rejected';
+COMMENT ON COLUMN discovery_candidates.sources IS 'Comma-separated TMDB lists the show was seen in';
@@ -0,0 +1,30 @@
+-- TMDB Crawler List Sync Migration
+-- Version: 018
+-- Created: 2026-10-16
+-- Description: Followed TMDB v4 lists and archiving of shows dropped from them
+-- Note: SQLite picks these changes up through GORM AutoMigrate
+
+CREATE TABLE IF NOT EXISTS tmdb_lists (
+    id SERIAL PRIMARY KEY,
+    list_id INTEGER NOT NULL,
+    name VARCHAR(255),
+    description TEXT,
+    enabled BOOLEAN DEFAULT TRUE,
+    archive_removed BOOLEAN DEFAULT FALSE,
+    show_tmdb_ids TEXT,
+    item_count INTEGER DEFAULT 0,
+    last_synced_at TIMESTAMP,
+    last_sync_status VARCHAR(20),
+    last_sync_error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tmdb_list_id ON tmdb_lists(list_id);
+
+ALTER TABLE shows ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_archived_at ON shows(archived_at);
+
+COMMENT ON TABLE tmdb_lists IS 'TMDB v4 lists whose shows are crawled by the list sync job';
+COMMENT ON COLUMN tmdb_lists.show_tmdb_ids IS 'Comma-separated TMDB IDs of the shows on the list at the last sync';
+COMMENT ON COLUMN shows.archived_at IS 'Set when the show was removed from a synced list; archived shows are not refreshed';
@@ -0,0 +1,53 @@
+-- TMDB Crawler Movies Migration
+-- Version: 019
+-- Created: 2026-10-16
+-- Description: Movies and their release dates per region and release type
+-- Note: SQLite picks these changes up through GORM AutoMigrate
+
+CREATE TABLE IF NOT EXISTS movies (
+    id SERIAL PRIMARY KEY,
+    tmdb_id INTEGER NOT NULL,
+    imdb_id VARCHAR(20),
+    title VARCHAR(255) NOT NULL,
+    original_title VARCHAR(255),
+    status VARCHAR(50),
+    language VARCHAR(10),
+    release_date DATE,
+    runtime INTEGER DEFAULT 0,
+    overview TEXT,
+    poster_path VARCHAR(512),
+    backdrop_path VARCHAR(512),
+    popularity DECIMAL(5,2) DEFAULT 0.0,
+    vote_average DECIMAL(3,1) DEFAULT 0.0,
+    vote_count INTEGER DEFAULT 0,
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_crawled_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_movies_tmdb_id ON movies(tmdb_id);
+CREATE INDEX IF NOT EXISTS idx_movies_imdb_id ON movies(imdb_id);
+CREATE INDEX IF NOT EXISTS idx_movies_title ON movies(title);
+CREATE INDEX IF NOT EXISTS idx_movies_release_date ON movies(release_date);
+CREATE INDEX IF NOT EXISTS idx_movies_last_crawled ON movies(last_crawled_at);
+
+CREATE TABLE IF NOT EXISTS movie_release_dates (
+    id SERIAL PRIMARY KEY,
+    movie_id INTEGER NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    region VARCHAR(2) NOT NULL,
+    type INTEGER NOT NULL,
+    release_date TIMESTAMP NOT NULL,
+    certification VARCHAR(20),
+    note VARCHAR(255)
+);
+
+CREATE INDEX IF NOT EXISTS idx_movie_release_dates_movie_id ON movie_release_dates(movie_id);
+CREATE INDEX IF NOT EXISTS idx_movie_release_dates_region ON movie_release_dates(region);
+CREATE INDEX IF NOT EXISTS idx_movie_release_dates_release_date ON movie_release_dates(release_date);
+
+ALTER TABLE telegraph_posts ADD COLUMN IF NOT EXISTS movies_count INTEGER DEFAULT 0;
+
+COMMENT ON TABLE movies IS 'Movies followed from TMDB';
+COMMENT ON TABLE movie_release_dates IS 'Release dates of movies from TMDB, one row per region and release';
+COMMENT ON COLUMN movie_release_dates.type IS '1 premiere, 2 limited theatrical, 3 theatrical, 4 digital, 5 physical, 6 TV';
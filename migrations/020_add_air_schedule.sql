@@ -0,0 +1,16 @@
+-- TMDB Crawler Air Schedule Migration
+-- Version: 020
+-- Created: 2026-10-16
+-- Description: Broadcast timezone and air time of shows, broadcast instant of episodes
+-- Note: SQLite picks the shows columns up through GORM AutoMigrate; the episodes
+--       column is added at startup because episodes are not auto-migrated
+
+ALTER TABLE shows ADD COLUMN IF NOT EXISTS air_timezone VARCHAR(64);
+ALTER TABLE shows ADD COLUMN IF NOT EXISTS air_time VARCHAR(5);
+ALTER TABLE episodes ADD COLUMN IF NOT EXISTS air_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_air_at ON episodes(air_at);
+
+COMMENT ON COLUMN shows.air_timezone IS 'IANA timezone the show is broadcast in, derived from the network unless set by hand';
+COMMENT ON COLUMN shows.air_time IS 'Local air time HH:MM in air_timezone';
+COMMENT ON COLUMN episodes.air_at IS 'Broadcast instant in UTC; NULL when only the air date is known';
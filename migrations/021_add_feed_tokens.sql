@@ -0,0 +1,21 @@
+-- TMDB Crawler Calendar Feed Tokens Migration
+-- Version: 021
+-- Created: 2026-10-16
+-- Description: Revocable tokens for subscribing to the iCalendar feeds
+-- Note: SQLite picks these changes up through GORM AutoMigrate
+
+CREATE TABLE IF NOT EXISTS feed_tokens (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    token_prefix VARCHAR(8),
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_feed_tokens_hash ON feed_tokens(token_hash);
+CREATE INDEX IF NOT EXISTS idx_feed_tokens_revoked_at ON feed_tokens(revoked_at);
+
+COMMENT ON TABLE feed_tokens IS 'Tokens calendar clients pass as ?token= to read the ICS feeds';
+COMMENT ON COLUMN feed_tokens.token_hash IS 'Hex SHA-256 of the token; the token itself is only shown when created';
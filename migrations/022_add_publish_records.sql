@@ -0,0 +1,28 @@
+-- TMDB Crawler Publish Records Migration
+-- Version: 022
+-- Created: 2026-10-16
+-- Description: One record per publish target for every published update list
+-- Note: SQLite picks these changes up through GORM AutoMigrate
+
+CREATE TABLE IF NOT EXISTS publish_records (
+    id SERIAL PRIMARY KEY,
+    target VARCHAR(32) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    slug VARCHAR(100),
+    content_hash VARCHAR(64),
+    success BOOLEAN DEFAULT FALSE,
+    url VARCHAR(512),
+    path VARCHAR(512),
+    error TEXT,
+    shows_count INTEGER DEFAULT 0,
+    episodes_count INTEGER DEFAULT 0,
+    movies_count INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_publish_records_target ON publish_records(target);
+CREATE INDEX IF NOT EXISTS idx_publish_records_hash ON publish_records(content_hash);
+CREATE INDEX IF NOT EXISTS idx_publish_records_created_at ON publish_records(created_at);
+
+COMMENT ON TABLE publish_records IS 'Result of each publish target (telegraph, telegram, webhook, html, feed)';
+COMMENT ON COLUMN publish_records.content_hash IS 'SHA-256 of the rendered content; a target is not sent the same content twice';
@@ -0,0 +1,27 @@
+-- TMDB Crawler Telegraph Slots Migration
+-- Version: 023
+-- Created: 2026-10-16
+-- Description: Key Telegraph pages by publish slot and keep the history of their edits
+-- Note: SQLite picks these changes up through GORM AutoMigrate
+
+ALTER TABLE telegraph_posts ADD COLUMN IF NOT EXISTS slot VARCHAR(100);
+ALTER TABLE telegraph_posts ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_telegraph_slot ON telegraph_posts(slot);
+CREATE INDEX IF NOT EXISTS idx_publish_records_slug ON publish_records(slug);
+
+CREATE TABLE IF NOT EXISTS telegraph_post_revisions (
+    id SERIAL PRIMARY KEY,
+    post_id INTEGER NOT NULL REFERENCES telegraph_posts(id) ON DELETE CASCADE,
+    title VARCHAR(255),
+    content_hash VARCHAR(64) NOT NULL,
+    shows_count INTEGER DEFAULT 0,
+    episodes_count INTEGER DEFAULT 0,
+    movies_count INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_telegraph_revisions_post_id ON telegraph_post_revisions(post_id);
+
+COMMENT ON COLUMN telegraph_posts.slot IS 'Publish slot of the page (today-2026-01-02, week-2026-W01, month-2026-01, show-12); a changed slot edits its page in place';
+COMMENT ON TABLE telegraph_post_revisions IS 'Every version of a Telegraph page: the page as created and each edit';
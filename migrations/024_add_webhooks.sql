@@ -0,0 +1,40 @@
+-- TMDB Crawler Event Webhooks Migration
+-- Version: 024
+-- Created: 2026-10-16
+-- Description: Webhook endpoints that receive crawl, publish and correction events, and the log of their deliveries
+-- Note: SQLite picks these changes up through GORM AutoMigrate
+
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    url VARCHAR(512) NOT NULL,
+    secret VARCHAR(128) NOT NULL,
+    events VARCHAR(512),
+    enabled BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_enabled ON webhook_endpoints(enabled);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id SERIAL PRIMARY KEY,
+    endpoint_id INTEGER NOT NULL REFERENCES webhook_endpoints(id) ON DELETE CASCADE,
+    event VARCHAR(50) NOT NULL,
+    payload TEXT,
+    attempts INTEGER DEFAULT 0,
+    status_code INTEGER,
+    success BOOLEAN DEFAULT FALSE,
+    error TEXT,
+    duration_ms INTEGER,
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_endpoint_id ON webhook_deliveries(endpoint_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_event ON webhook_deliveries(event);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_created_at ON webhook_deliveries(created_at);
+
+COMMENT ON COLUMN webhook_endpoints.secret IS 'HMAC-SHA256 key of the X-Webhook-Signature header';
+COMMENT ON COLUMN webhook_endpoints.events IS 'Comma-separated subscribed events; empty subscribes to all';
+COMMENT ON TABLE webhook_deliveries IS 'One row per event delivered to an endpoint; attempts, status_code and error are those of the last try';
@@ -0,0 +1,14 @@
+-- TMDB Crawler List Sync Log Migration
+-- Version: 025
+-- Created: 2026-10-16
+-- Description: Store the TMDB list of list_sync logs in its own column instead of tmdb_id
+-- Note: SQLite picks these changes up through GORM AutoMigrate
+
+ALTER TABLE crawl_logs ADD COLUMN IF NOT EXISTS list_id INTEGER;
+CREATE INDEX IF NOT EXISTS idx_log_list_id ON crawl_logs(list_id);
+
+-- Move the list IDs that earlier list syncs stored as the TMDB show ID
+UPDATE crawl_logs SET list_id = tmdb_id, tmdb_id = 0
+WHERE action = 'list_sync' AND list_id IS NULL;
+
+COMMENT ON COLUMN crawl_logs.list_id IS 'TMDB list ID of list_sync logs; tmdb_id is 0 for these logs';
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // BackupVersion is the current backup format version
 const BackupVersion = "1.0"
@@ -24,12 +27,39 @@ type BackupStats struct {
 
 // BackupData holds all the data tables
 type BackupData struct {
-	Shows          []Show          `json:"shows"`
+	Shows          []BackupShow    `json:"shows"`
 	Episodes       []Episode       `json:"episodes"`
 	CrawlLogs      []CrawlLog      `json:"crawl_logs"`
 	TelegraphPosts []TelegraphPost `json:"telegraph_posts"`
 }
 
+// BackupShow is a show with its genres in a backup
+type BackupShow struct {
+	Show
+	Genres BackupGenres `json:"genres"`
+}
+
+// BackupGenres are the genres of a backed up show. Backups made before genres
+// had their own table store them as a JSON string, which is parsed with ParseLegacyGenres.
+type BackupGenres []Genre
+
+// UnmarshalJSON accepts both an array of genres and the legacy JSON string
+func (g *BackupGenres) UnmarshalJSON(data []byte) error {
+	var legacy string
+	if err := json.Unmarshal(data, &legacy); err == nil {
+		genres, _ := ParseLegacyGenres(legacy)
+		*g = genres
+		return nil
+	}
+
+	var genres []Genre
+	if err := json.Unmarshal(data, &genres); err != nil {
+		return err
+	}
+	*g = genres
+	return nil
+}
+
 // BackupStatus represents the current backup status
 type BackupStatus struct {
 	LastBackup *time.Time  `json:"last_backup,omitempty"`
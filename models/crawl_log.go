@@ -9,8 +9,9 @@ import (
 type CrawlLog struct {
 	ID            uint      `gorm:"primaryKey" json:"id"`
 	ShowID        *uint     `gorm:"index:idx_log_show_id" json:"show_id,omitempty"`
-	TmdbID        int       `gorm:"not null;index:idx_log_tmdb_id" json:"tmdb_id"`
-	Action        string    `gorm:"size:50;not null;index:idx_log_action" json:"action"`                 // 'fetch'/'refresh'/'batch'
+	TmdbID        int       `gorm:"not null;index:idx_log_tmdb_id" json:"tmdb_id"`                       // 0 for list_sync
+	ListID        *int      `gorm:"index:idx_log_list_id" json:"list_id,omitempty"`                      // TMDB list ID, only for list_sync
+	Action        string    `gorm:"size:50;not null;index:idx_log_action" json:"action"`                 // 'fetch'/'refresh'/'batch'/'list_sync'
 	Status        string    `gorm:"size:20;not null;index:idx_log_status;default:success" json:"status"` // 'success'/'failed'/'partial'
 	EpisodesCount int       `gorm:"default:0" json:"episodes_count"`
 	ErrorMessage  string    `gorm:"type:text" json:"error_message,omitempty"`
@@ -52,14 +53,19 @@ func (c *CrawlLog) GetDuration() string {
 
 // Validate validates the crawl log data
 func (c *CrawlLog) Validate() error {
-	if c.TmdbID <= 0 {
+	if c.Action == "list_sync" {
+		if c.ListID == nil || *c.ListID <= 0 {
+			return fmt.Errorf("invalid TMDB list ID")
+		}
+	} else if c.TmdbID <= 0 {
 		return fmt.Errorf("invalid TMDB ID")
 	}
 
 	validActions := map[string]bool{
-		"fetch":   true,
-		"refresh": true,
-		"batch":   true,
+		"fetch":     true,
+		"refresh":   true,
+		"batch":     true,
+		"list_sync": true,
 	}
 	if !validActions[c.Action] {
 		return fmt.Errorf("invalid action: %s", c.Action)
@@ -179,6 +179,24 @@ func TestCrawlLog_Validate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Valid list sync log",
+			log: &CrawlLog{
+				ListID: &[]int{8200000}[0],
+				Action: "list_sync",
+				Status: "success",
+			},
+			wantErr: false,
+		},
+		{
+			name: "List sync log without list ID",
+			log: &CrawlLog{
+				TmdbID: 8200000,
+				Action: "list_sync",
+				Status: "success",
+			},
+			wantErr: true,
+		},
 		{
 			name: "Invalid TMDB ID",
 			log: &CrawlLog{
@@ -6,11 +6,13 @@ import (
 )
 
 // CrawlTask represents an async crawl task
-// Status: queued/running/success/failed
+// Status: queued/running/success/failed/cancelled/interrupted
 // Type: refresh_all/crawl_by_status
-// Params: JSON string for task inputs
+// Params: JSON string for task inputs (batch tasks also checkpoint their remaining TMDB IDs here)
 // ErrorMessage: failure reason, if any
+// Summary: JSON string with per-show results of batch tasks
 // StartedAt/FinishedAt: timestamps for execution window
+// TotalShows/ProcessedShows/CurrentShow/EstimatedFinishAt: live progress of batch tasks
 //
 // Note: keep fields minimal to avoid schema churn.
 type CrawlTask struct {
@@ -19,9 +21,16 @@ type CrawlTask struct {
 	Status       string     `gorm:"size:20;not null;index:idx_task_status;default:queued" json:"status"`
 	Params       string     `gorm:"type:text" json:"params,omitempty"`
 	ErrorMessage string     `gorm:"type:text" json:"error_message,omitempty"`
+	Summary      string     `gorm:"type:text" json:"-"`
 	StartedAt    *time.Time `json:"started_at,omitempty"`
 	FinishedAt   *time.Time `json:"finished_at,omitempty"`
 	CreatedAt    time.Time  `gorm:"index:idx_created_at;autoCreateTime" json:"created_at"`
+
+	// Progress
+	TotalShows        int        `gorm:"default:0" json:"total_shows"`
+	ProcessedShows    int        `gorm:"default:0" json:"processed_shows"`
+	CurrentShow       string     `gorm:"size:255" json:"current_show,omitempty"`
+	EstimatedFinishAt *time.Time `json:"estimated_finish_at,omitempty"`
 }
 
 // TableName specifies the table name for CrawlTask model
@@ -43,10 +52,12 @@ func (c *CrawlTask) Validate() error {
 	}
 
 	validStatuses := map[string]bool{
-		"queued":  true,
-		"running": true,
-		"success": true,
-		"failed":  true,
+		"queued":      true,
+		"running":     true,
+		"success":     true,
+		"failed":      true,
+		"cancelled":   true,
+		"interrupted": true,
 	}
 	if !validStatuses[c.Status] {
 		return fmt.Errorf("invalid task status: %s", c.Status)
@@ -60,9 +71,13 @@ func (c *CrawlTask) IsRunning() bool {
 	return c.Status == "running"
 }
 
-// IsCompleted checks if the task has completed (success or failed)
+// IsCompleted checks if the task has completed (success, failed, cancelled or interrupted)
 func (c *CrawlTask) IsCompleted() bool {
-	return c.Status == "success" || c.Status == "failed"
+	switch c.Status {
+	case "success", "failed", "cancelled", "interrupted":
+		return true
+	}
+	return false
 }
 
 // GetDuration returns the task execution duration
@@ -73,3 +88,18 @@ func (c *CrawlTask) GetDuration() *time.Duration {
 	duration := c.FinishedAt.Sub(*c.StartedAt)
 	return &duration
 }
+
+// GetProgressPercent returns the share of processed shows as a percentage (0-100)
+func (c *CrawlTask) GetProgressPercent() int {
+	if c.TotalShows <= 0 {
+		if c.IsCompleted() {
+			return 100
+		}
+		return 0
+	}
+	percent := c.ProcessedShows * 100 / c.TotalShows
+	if percent > 100 {
+		percent = 100
+	}
+	return percent
+}
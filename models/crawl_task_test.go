@@ -140,6 +140,16 @@ func TestCrawlTask_IsCompleted(t *testing.T) {
 			status:   "failed",
 			expected: true,
 		},
+		{
+			name:     "Cancelled status",
+			status:   "cancelled",
+			expected: true,
+		},
+		{
+			name:     "Interrupted status",
+			status:   "interrupted",
+			expected: true,
+		},
 		{
 			name:     "Running status",
 			status:   "running",
@@ -249,3 +259,40 @@ func TestCrawlTask_GetDuration_Accuracy(t *testing.T) {
 		t.Errorf("CrawlTask.GetDuration() = %v, want %v", *duration, expected)
 	}
 }
+
+func TestCrawlTask_GetProgressPercent(t *testing.T) {
+	tests := []struct {
+		name     string
+		task     CrawlTask
+		expected int
+	}{
+		{
+			name:     "Not started",
+			task:     CrawlTask{Status: "queued"},
+			expected: 0,
+		},
+		{
+			name:     "Half done",
+			task:     CrawlTask{Status: "running", TotalShows: 10, ProcessedShows: 5},
+			expected: 50,
+		},
+		{
+			name:     "Completed without shows",
+			task:     CrawlTask{Status: "success"},
+			expected: 100,
+		},
+		{
+			name:     "Clamped to 100",
+			task:     CrawlTask{Status: "running", TotalShows: 2, ProcessedShows: 3},
+			expected: 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.task.GetProgressPercent(); got != tt.expected {
+				t.Errorf("CrawlTask.GetProgressPercent() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
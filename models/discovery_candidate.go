@@ -0,0 +1,83 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Discovery candidate statuses
+const (
+	DiscoveryStatusPending  = "pending"
+	DiscoveryStatusApproved = "approved"
+	DiscoveryStatusRejected = "rejected"
+)
+
+// DiscoveryCandidate is a show found in a TMDB TV list that matched the discovery rules
+// Status: pending (in the review queue) / approved (followed) / rejected
+// Sources: comma-separated TMDB lists the show was seen in (on_the_air, airing_today, trending)
+// AutoFollowed: approved by the rules without review
+type DiscoveryCandidate struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	TmdbID           int        `gorm:"uniqueIndex:idx_discovery_tmdb_id;not null" json:"tmdb_id"`
+	Name             string     `gorm:"size:255;not null" json:"name"`
+	OriginalName     string     `gorm:"size:255" json:"original_name"`
+	OriginalLanguage string     `gorm:"size:10" json:"original_language"`
+	OriginCountry    string     `gorm:"size:50" json:"origin_country"` // Comma-separated ISO 3166-1 codes
+	GenreIDs         string     `gorm:"size:255" json:"genre_ids"`     // Comma-separated TMDB genre IDs
+	Overview         string     `gorm:"type:text" json:"overview"`
+	PosterPath       string     `gorm:"size:512" json:"poster_path"`
+	FirstAirDate     *time.Time `json:"first_air_date"`
+	Popularity       float64    `gorm:"default:0" json:"popularity"`
+	VoteAverage      float32    `gorm:"default:0" json:"vote_average"`
+	VoteCount        int        `gorm:"default:0" json:"vote_count"`
+	Sources          string     `gorm:"size:100" json:"sources"`
+
+	Status       string     `gorm:"size:20;not null;index:idx_discovery_status;default:pending" json:"status"`
+	AutoFollowed bool       `gorm:"default:false" json:"auto_followed"`
+	ShowID       *uint      `json:"show_id,omitempty"`
+	ReviewedAt   *time.Time `json:"reviewed_at,omitempty"`
+	LastSeenAt   time.Time  `json:"last_seen_at"`
+	CreatedAt    time.Time  `gorm:"index:idx_discovery_created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for DiscoveryCandidate model
+func (DiscoveryCandidate) TableName() string {
+	return "discovery_candidates"
+}
+
+// Validate validates the discovery candidate data
+func (d *DiscoveryCandidate) Validate() error {
+	if d.TmdbID <= 0 {
+		return fmt.Errorf("invalid TMDB ID")
+	}
+	if d.Name == "" {
+		return fmt.Errorf("candidate name cannot be empty")
+	}
+	switch d.Status {
+	case DiscoveryStatusPending, DiscoveryStatusApproved, DiscoveryStatusRejected:
+	default:
+		return fmt.Errorf("invalid discovery status: %s", d.Status)
+	}
+	return nil
+}
+
+// IsPending checks if the candidate is waiting for review
+func (d *DiscoveryCandidate) IsPending() bool {
+	return d.Status == DiscoveryStatusPending
+}
+
+// AddSource records that the candidate was seen in a TMDB list
+func (d *DiscoveryCandidate) AddSource(source string) {
+	for _, existing := range strings.Split(d.Sources, ",") {
+		if existing == source {
+			return
+		}
+	}
+	if d.Sources == "" {
+		d.Sources = source
+		return
+	}
+	d.Sources += "," + source
+}
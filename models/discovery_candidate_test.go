@@ -0,0 +1,53 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestDiscoveryCandidate_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		candidate *DiscoveryCandidate
+		wantErr   bool
+	}{
+		{
+			name:      "Valid pending candidate",
+			candidate: &DiscoveryCandidate{TmdbID: 1, Name: "Show", Status: DiscoveryStatusPending},
+			wantErr:   false,
+		},
+		{
+			name:      "Missing TMDB ID",
+			candidate: &DiscoveryCandidate{Name: "Show", Status: DiscoveryStatusPending},
+			wantErr:   true,
+		},
+		{
+			name:      "Missing name",
+			candidate: &DiscoveryCandidate{TmdbID: 1, Status: DiscoveryStatusPending},
+			wantErr:   true,
+		},
+		{
+			name:      "Invalid status",
+			candidate: &DiscoveryCandidate{TmdbID: 1, Name: "Show", Status: "followed"},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.candidate.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDiscoveryCandidate_AddSource(t *testing.T) {
+	candidate := &DiscoveryCandidate{}
+	candidate.AddSource("trending")
+	candidate.AddSource("on_the_air")
+	candidate.AddSource("trending")
+	if candidate.Sources != "trending,on_the_air" {
+		t.Errorf("Expected trending,on_the_air, got %s", candidate.Sources)
+	}
+}
@@ -9,17 +9,22 @@ import (
 
 // Episode represents a single episode of a TV show
 type Episode struct {
-	ID            uint       `gorm:"primaryKey" json:"id"`
-	ShowID        uint       `gorm:"not null;index:idx_show_id,priority:1;uniqueIndex:unique_episode,priority:1" json:"show_id"`
-	SeasonNumber  int        `gorm:"not null;index:idx_show_id,priority:2;index:idx_season_number;uniqueIndex:unique_episode,priority:2" json:"season_number"`
-	EpisodeNumber int        `gorm:"not null;index:idx_show_id,priority:3;uniqueIndex:unique_episode,priority:3" json:"episode_number"`
-	Name          string     `gorm:"size:255" json:"name"`
-	Overview      string     `gorm:"type:text" json:"overview"`
-	AirDate       *time.Time `gorm:"index:idx_air_date" json:"air_date"`
-	StillPath     string     `gorm:"size:512" json:"still_path"`
-	Runtime       int        `gorm:"default:0" json:"runtime"` // in minutes
-	VoteAverage   float32    `gorm:"type:decimal(3,1);default:0.0" json:"vote_average"`
-	VoteCount     int        `gorm:"default:0" json:"vote_count"`
+	ID            uint         `gorm:"primaryKey" json:"id"`
+	ShowID        uint         `gorm:"not null;index:idx_show_id,priority:1;uniqueIndex:unique_episode,priority:1" json:"show_id"`
+	SeasonNumber  int          `gorm:"not null;index:idx_show_id,priority:2;index:idx_season_number;uniqueIndex:unique_episode,priority:2" json:"season_number"`
+	EpisodeNumber int          `gorm:"not null;index:idx_show_id,priority:3;uniqueIndex:unique_episode,priority:3" json:"episode_number"`
+	Name          string       `gorm:"size:255" json:"name"`
+	Overview      string       `gorm:"type:text" json:"overview"`
+	Translations  Translations `gorm:"type:text" json:"translations,omitempty"` // Name/overview per language
+	AirDate       *time.Time   `gorm:"index:idx_air_date" json:"air_date"`
+	// AirAt is the broadcast instant (UTC) when the show's air time is known; nil means date-only
+	AirAt       *time.Time `gorm:"index:idx_air_at" json:"air_at,omitempty"`
+	StillPath   string     `gorm:"size:512" json:"still_path"`
+	Runtime     int        `gorm:"default:0" json:"runtime"` // in minutes
+	VoteAverage float32    `gorm:"type:decimal(3,1);default:0.0" json:"vote_average"`
+	VoteCount   int        `gorm:"default:0" json:"vote_count"`
+	ImdbID      string     `gorm:"size:20" json:"imdb_id,omitempty"`
+	TvdbID      int        `gorm:"default:0" json:"tvdb_id,omitempty"`
 
 	// Timestamps
 	CreatedAt time.Time `json:"created_at"`
@@ -39,11 +44,37 @@ func (Episode) TableName() string {
 	return "episodes"
 }
 
-// GetEpisodeCode returns the episode code in format S01E01
+// GetEpisodeCode returns the episode code in format S01E01 (SP01 for specials)
 func (e *Episode) GetEpisodeCode() string {
+	if e.IsSpecial() {
+		return fmt.Sprintf("SP%02d", e.EpisodeNumber)
+	}
 	return fmt.Sprintf("S%02dE%02d", e.SeasonNumber, e.EpisodeNumber)
 }
 
+// Localized returns a copy of the episode (and its show, if loaded) with the
+// name and overview in lang. Fields without a translation keep the stored text.
+func (e *Episode) Localized(lang string) *Episode {
+	localized := *e
+	if tr, ok := e.Translations.Get(lang); ok {
+		if tr.Name != "" {
+			localized.Name = tr.Name
+		}
+		if tr.Overview != "" {
+			localized.Overview = tr.Overview
+		}
+	}
+	if e.Show != nil {
+		localized.Show = e.Show.Localized(lang)
+	}
+	return &localized
+}
+
+// IsSpecial checks if the episode belongs to season 0 (specials)
+func (e *Episode) IsSpecial() bool {
+	return e.SeasonNumber == 0
+}
+
 // IsAired checks if the episode has already aired
 // Uses current time in the system's local timezone for comparison
 func (e *Episode) IsAired() bool {
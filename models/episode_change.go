@@ -0,0 +1,55 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// EpisodeChange records a change to an episode detected while crawling
+// ChangeType: added/removed/air_date_changed/title_changed
+// OldValue/NewValue: previous and new title or air date (YYYY-MM-DD)
+type EpisodeChange struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	CrawlLogID    *uint     `gorm:"index:idx_change_crawl_log_id" json:"crawl_log_id,omitempty"`
+	ShowID        uint      `gorm:"not null;index:idx_change_show_id" json:"show_id"`
+	SeasonNumber  int       `gorm:"not null" json:"season_number"`
+	EpisodeNumber int       `gorm:"not null" json:"episode_number"`
+	ChangeType    string    `gorm:"size:30;not null;index:idx_change_type" json:"change_type"`
+	OldValue      string    `gorm:"type:text" json:"old_value,omitempty"`
+	NewValue      string    `gorm:"type:text" json:"new_value,omitempty"`
+	CreatedAt     time.Time `gorm:"index:idx_change_created_at;autoCreateTime" json:"created_at"`
+
+	// Relationships
+	CrawlLog *CrawlLog `gorm:"foreignKey:CrawlLogID;constraint:OnDelete:SET NULL" json:"-"`
+	Show     *Show     `gorm:"foreignKey:ShowID;constraint:OnDelete:CASCADE" json:"show,omitempty"`
+}
+
+// TableName specifies the table name for EpisodeChange model
+func (EpisodeChange) TableName() string {
+	return "episode_changes"
+}
+
+// Validate validates the episode change data
+func (c *EpisodeChange) Validate() error {
+	if c.ShowID == 0 {
+		return fmt.Errorf("show ID is required")
+	}
+
+	validTypes := map[string]bool{
+		"added":            true,
+		"removed":          true,
+		"air_date_changed": true,
+		"title_changed":    true,
+	}
+	if !validTypes[c.ChangeType] {
+		return fmt.Errorf("invalid change type: %s", c.ChangeType)
+	}
+
+	return nil
+}
+
+// GetEpisodeCode returns the episode code in the same format as Episode.GetEpisodeCode
+func (c *EpisodeChange) GetEpisodeCode() string {
+	episode := Episode{SeasonNumber: c.SeasonNumber, EpisodeNumber: c.EpisodeNumber}
+	return episode.GetEpisodeCode()
+}
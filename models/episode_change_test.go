@@ -0,0 +1,55 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestEpisodeChange_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  *EpisodeChange
+		wantErr bool
+	}{
+		{
+			name:    "Valid added change",
+			change:  &EpisodeChange{ShowID: 1, ChangeType: "added"},
+			wantErr: false,
+		},
+		{
+			name:    "Valid air date change",
+			change:  &EpisodeChange{ShowID: 1, ChangeType: "air_date_changed", OldValue: "2026-01-01", NewValue: "2026-01-08"},
+			wantErr: false,
+		},
+		{
+			name:    "Missing show ID",
+			change:  &EpisodeChange{ChangeType: "removed"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid change type",
+			change:  &EpisodeChange{ShowID: 1, ChangeType: "renamed"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.change.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEpisodeChange_GetEpisodeCode(t *testing.T) {
+	change := &EpisodeChange{SeasonNumber: 2, EpisodeNumber: 5}
+	if code := change.GetEpisodeCode(); code != "S02E05" {
+		t.Errorf("Expected S02E05, got %s", code)
+	}
+
+	special := &EpisodeChange{SeasonNumber: 0, EpisodeNumber: 3}
+	if code := special.GetEpisodeCode(); code != "SP03" {
+		t.Errorf("Expected SP03, got %s", code)
+	}
+}
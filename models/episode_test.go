@@ -25,10 +25,10 @@ func TestEpisode_GetEpisodeCode(t *testing.T) {
 			expected:      "S10E15",
 		},
 		{
-			name:          "Season 0 Episode 1",
+			name:          "Special Episode 1",
 			seasonNumber:  0,
 			episodeNumber: 1,
-			expected:      "S00E01",
+			expected:      "SP01",
 		},
 		{
 			name:          "Season 1 Episode 0",
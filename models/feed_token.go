@@ -0,0 +1,41 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// FeedToken grants read access to the calendar feeds.
+// Calendar clients cannot send the admin API key, so they subscribe with a
+// long-lived token in the feed URL instead. Only a SHA-256 hash of the token
+// is stored; the token itself is shown once when it is created.
+type FeedToken struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	Name        string     `gorm:"size:100;not null" json:"name"`
+	TokenHash   string     `gorm:"size:64;uniqueIndex:idx_feed_tokens_hash;not null" json:"-"`
+	TokenPrefix string     `gorm:"size:8" json:"token_prefix"` // First characters of the token, to tell tokens apart
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	RevokedAt   *time.Time `gorm:"index:idx_feed_tokens_revoked_at" json:"revoked_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// TableName specifies the table name for FeedToken model
+func (FeedToken) TableName() string {
+	return "feed_tokens"
+}
+
+// IsRevoked checks if the token has been revoked
+func (t *FeedToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// Validate validates the feed token data
+func (t *FeedToken) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("feed token name cannot be empty")
+	}
+	if len(t.TokenHash) != 64 {
+		return fmt.Errorf("invalid feed token hash")
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFeedToken_Validate(t *testing.T) {
+	hash := strings.Repeat("a", 64)
+	tests := []struct {
+		name    string
+		token   *FeedToken
+		wantErr bool
+	}{
+		{"Valid token", &FeedToken{Name: "Google Calendar", TokenHash: hash}, false},
+		{"Empty name", &FeedToken{TokenHash: hash}, true},
+		{"Invalid hash", &FeedToken{Name: "Google Calendar", TokenHash: "abc"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.token.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("FeedToken.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFeedToken_IsRevoked(t *testing.T) {
+	token := &FeedToken{}
+	if token.IsRevoked() {
+		t.Error("Expected a new token not to be revoked")
+	}
+	now := time.Now()
+	token.RevokedAt = &now
+	if !token.IsRevoked() {
+		t.Error("Expected the token to be revoked")
+	}
+}
@@ -0,0 +1,43 @@
+package models
+
+import "encoding/json"
+
+// Genre represents a TMDB genre; the ID is TMDB's genre ID
+type Genre struct {
+	ID   int    `gorm:"primaryKey;autoIncrement:false" json:"id"`
+	Name string `gorm:"size:100;not null" json:"name"`
+}
+
+// TableName specifies the table name for Genre model
+func (Genre) TableName() string {
+	return "genres"
+}
+
+// ParseLegacyGenres parses genres stored as a TMDB JSON string, as the shows.genres
+// column and older backups hold them. It reports false for other text, such as a
+// hand-written value; the next crawl fills the genres of those shows.
+func ParseLegacyGenres(value string) ([]Genre, bool) {
+	var genres []Genre
+	if err := json.Unmarshal([]byte(value), &genres); err != nil {
+		return nil, false
+	}
+	return genres, true
+}
+
+// ShowGenre links a show to one of its genres
+type ShowGenre struct {
+	ShowID  uint `gorm:"primaryKey"`
+	GenreID int  `gorm:"primaryKey;index:idx_show_genres_genre_id"`
+}
+
+// TableName specifies the table name for ShowGenre model
+func (ShowGenre) TableName() string {
+	return "show_genres"
+}
+
+// GenreCount is the number of shows in a genre, used for list facets
+type GenreCount struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
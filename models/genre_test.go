@@ -0,0 +1,28 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLegacyGenres(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   []Genre
+		wantOK bool
+	}{
+		{"tmdb json", `[{"id":18,"name":"Drama"},{"id":80,"name":"Crime"}]`, []Genre{{ID: 18, Name: "Drama"}, {ID: 80, Name: "Crime"}}, true},
+		{"empty array", `[]`, []Genre{}, true},
+		{"hand-written", "剧情, 犯罪", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseLegacyGenres(tt.value)
+			if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLegacyGenres(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
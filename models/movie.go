@@ -0,0 +1,150 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Movie represents a movie from TMDB
+type Movie struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	TmdbID        int        `gorm:"uniqueIndex:idx_movies_tmdb_id;not null" json:"tmdb_id"`
+	ImdbID        string     `gorm:"size:20;index:idx_movies_imdb_id" json:"imdb_id"`
+	Title         string     `gorm:"size:255;not null;index:idx_movies_title" json:"title"`
+	OriginalTitle string     `gorm:"size:255" json:"original_title"`
+	Status        string     `gorm:"size:50" json:"status"` // Rumored, Planned, In Production, Post Production, Released, Canceled
+	Language      string     `gorm:"size:10" json:"language"`
+	ReleaseDate   *time.Time `gorm:"index:idx_movies_release_date" json:"release_date"` // TMDB primary release date
+	Runtime       int        `gorm:"default:0" json:"runtime"`                          // Minutes
+	Overview      string     `gorm:"type:text" json:"overview"`
+	PosterPath    string     `gorm:"size:512" json:"poster_path"`
+	BackdropPath  string     `gorm:"size:512" json:"backdrop_path"`
+	Popularity    float64    `gorm:"type:decimal(5,2);default:0.0" json:"popularity"`
+	VoteAverage   float32    `gorm:"type:decimal(3,1);default:0.0" json:"vote_average"`
+	VoteCount     int        `gorm:"default:0" json:"vote_count"`
+
+	// Local fields
+	Notes string `gorm:"type:text" json:"notes"`
+
+	// Timestamps
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	LastCrawledAt *time.Time `gorm:"index:idx_movies_last_crawled" json:"last_crawled_at"`
+
+	// Relationships
+	ReleaseDates []MovieReleaseDate `gorm:"foreignKey:MovieID;constraint:OnDelete:CASCADE" json:"release_dates,omitempty"`
+}
+
+// TableName specifies the table name for Movie model
+func (Movie) TableName() string {
+	return "movies"
+}
+
+// IsReleased checks if TMDB reports the movie as released
+func (m *Movie) IsReleased() bool {
+	return m.Status == "Released"
+}
+
+// BeforeCreate hook
+func (m *Movie) BeforeCreate(tx *gorm.DB) error {
+	now := time.Now()
+	m.CreatedAt = now
+	m.UpdatedAt = now
+	return m.Validate()
+}
+
+// BeforeUpdate hook
+func (m *Movie) BeforeUpdate(tx *gorm.DB) error {
+	m.UpdatedAt = time.Now()
+	return m.Validate()
+}
+
+// Validate validates the movie data
+func (m *Movie) Validate() error {
+	if m.Title == "" {
+		return fmt.Errorf("movie title cannot be empty")
+	}
+	if m.TmdbID <= 0 {
+		return fmt.Errorf("invalid TMDB ID")
+	}
+	return nil
+}
+
+// IsExpired checks if the movie data needs to be refreshed (older than 24 hours)
+func (m *Movie) IsExpired() bool {
+	if m.LastCrawledAt == nil {
+		return true
+	}
+	return time.Since(*m.LastCrawledAt) > 24*time.Hour
+}
+
+// Release types used by TMDB's release dates
+const (
+	ReleaseTypePremiere          = 1
+	ReleaseTypeTheatricalLimited = 2
+	ReleaseTypeTheatrical        = 3
+	ReleaseTypeDigital           = 4
+	ReleaseTypePhysical          = 5
+	ReleaseTypeTV                = 6
+)
+
+// releaseTypeNames holds the display name of each release type
+var releaseTypeNames = map[int]string{
+	ReleaseTypePremiere:          "首映",
+	ReleaseTypeTheatricalLimited: "限映",
+	ReleaseTypeTheatrical:        "院线",
+	ReleaseTypeDigital:           "数字",
+	ReleaseTypePhysical:          "实体",
+	ReleaseTypeTV:                "电视",
+}
+
+// IsValidReleaseType checks if t is one of TMDB's release types
+func IsValidReleaseType(t int) bool {
+	_, ok := releaseTypeNames[t]
+	return ok
+}
+
+// MovieReleaseDate is a release of a movie in one region
+// Region: ISO 3166-1 country code (e.g. US)
+// Type: one of the ReleaseType constants
+type MovieReleaseDate struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	MovieID       uint      `gorm:"not null;index:idx_movie_release_dates_movie_id" json:"movie_id"`
+	Region        string    `gorm:"size:2;not null;index:idx_movie_release_dates_region" json:"region"`
+	Type          int       `gorm:"not null" json:"type"`
+	ReleaseDate   time.Time `gorm:"not null;index:idx_movie_release_dates_release_date" json:"release_date"`
+	Certification string    `gorm:"size:20" json:"certification"`
+	Note          string    `gorm:"size:255" json:"note"`
+
+	// Relationships
+	Movie *Movie `gorm:"foreignKey:MovieID" json:"movie,omitempty"`
+}
+
+// TableName specifies the table name for MovieReleaseDate model
+func (MovieReleaseDate) TableName() string {
+	return "movie_release_dates"
+}
+
+// GetTypeName returns the display name of the release type
+func (r *MovieReleaseDate) GetTypeName() string {
+	if name, ok := releaseTypeNames[r.Type]; ok {
+		return name
+	}
+	return "未知"
+}
+
+// Validate validates the release date data
+func (r *MovieReleaseDate) Validate() error {
+	if len(r.Region) != 2 {
+		return fmt.Errorf("invalid region: %q", r.Region)
+	}
+	if !IsValidReleaseType(r.Type) {
+		return fmt.Errorf("invalid release type: %d", r.Type)
+	}
+	if r.ReleaseDate.IsZero() {
+		return fmt.Errorf("release date cannot be empty")
+	}
+	return nil
+}
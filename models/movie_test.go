@@ -0,0 +1,57 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMovie_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		movie   Movie
+		wantErr bool
+	}{
+		{"Valid movie", Movie{TmdbID: 603, Title: "The Matrix"}, false},
+		{"Empty title", Movie{TmdbID: 603}, true},
+		{"Invalid TMDB ID", Movie{Title: "The Matrix"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.movie.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Movie.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMovieReleaseDate_Validate(t *testing.T) {
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		release MovieReleaseDate
+		wantErr bool
+	}{
+		{"Valid release", MovieReleaseDate{Region: "US", Type: ReleaseTypeTheatrical, ReleaseDate: date}, false},
+		{"Invalid region", MovieReleaseDate{Region: "USA", Type: ReleaseTypeTheatrical, ReleaseDate: date}, true},
+		{"Invalid type", MovieReleaseDate{Region: "US", Type: 7, ReleaseDate: date}, true},
+		{"Missing date", MovieReleaseDate{Region: "US", Type: ReleaseTypeDigital}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.release.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("MovieReleaseDate.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMovieReleaseDate_GetTypeName(t *testing.T) {
+	if got := (&MovieReleaseDate{Type: ReleaseTypeDigital}).GetTypeName(); got != "数字" {
+		t.Errorf("GetTypeName() = %q, want 数字", got)
+	}
+	if got := (&MovieReleaseDate{Type: 0}).GetTypeName(); got != "未知" {
+		t.Errorf("GetTypeName() = %q, want 未知", got)
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// PublishRecord is the outcome of publishing one update list to one target.
+// A publish that fans out to several targets leaves one record per target.
+type PublishRecord struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	Target        string    `gorm:"size:32;not null;index:idx_publish_records_target" json:"target"` // telegraph, telegram, webhook, html, feed
+	Title         string    `gorm:"size:255;not null" json:"title"`
+	Slug          string    `gorm:"size:100;index:idx_publish_records_slug" json:"slug"`
+	ContentHash   string    `gorm:"size:64;index:idx_publish_records_hash" json:"content_hash"`
+	Success       bool      `gorm:"default:false" json:"success"`
+	URL           string    `gorm:"size:512" json:"url"`
+	Path          string    `gorm:"size:512" json:"path"` // Telegraph page path or file written
+	Error         string    `gorm:"type:text" json:"error,omitempty"`
+	ShowsCount    int       `gorm:"default:0" json:"shows_count"`
+	EpisodesCount int       `gorm:"default:0" json:"episodes_count"`
+	MoviesCount   int       `gorm:"default:0" json:"movies_count"`
+	CreatedAt     time.Time `gorm:"index:idx_publish_records_created_at" json:"created_at"`
+}
+
+// TableName specifies the table name for PublishRecord model
+func (PublishRecord) TableName() string {
+	return "publish_records"
+}
+
+// Validate validates the publish record data
+func (r *PublishRecord) Validate() error {
+	if r.Target == "" {
+		return fmt.Errorf("publish target cannot be empty")
+	}
+	if r.Title == "" {
+		return fmt.Errorf("title cannot be empty")
+	}
+	return nil
+}
@@ -11,6 +11,8 @@ import (
 type Show struct {
 	ID           uint       `gorm:"primaryKey" json:"id"`
 	TmdbID       int        `gorm:"uniqueIndex:idx_tmdb_id;not null" json:"tmdb_id"`
+	ImdbID       string     `gorm:"size:20;index:idx_imdb_id" json:"imdb_id"`
+	TvdbID       int        `gorm:"index:idx_tvdb_id" json:"tvdb_id"`
 	Name         string     `gorm:"size:255;not null;index:idx_name" json:"name"`
 	OriginalName string     `gorm:"size:255" json:"original_name"`
 	Status       string     `gorm:"size:50;index:idx_status" json:"status"`
@@ -18,19 +20,37 @@ type Show struct {
 	Language     string     `gorm:"size:10" json:"language"`
 	FirstAirDate *time.Time `gorm:"index:idx_first_air_date" json:"first_air_date"`
 	Overview     string     `gorm:"type:text" json:"overview"`
+	Translations Translations `gorm:"type:text" json:"translations,omitempty"` // Name/overview per language
 	PosterPath   string     `gorm:"size:512" json:"poster_path"`
 	BackdropPath string     `gorm:"size:512" json:"backdrop_path"`
-	Genres       string     `gorm:"size:255" json:"genres"`
 	Popularity   float64    `gorm:"type:decimal(5,2);default:0.0" json:"popularity"`
 	VoteAverage  float32    `gorm:"type:decimal(3,1);default:0.0" json:"vote_average"`
 	VoteCount    int        `gorm:"default:0" json:"vote_count"`
 
+	// Production and run schedule
+	Networks            string     `gorm:"type:text" json:"networks"`              // JSON array of TMDB networks
+	ProductionCompanies string     `gorm:"type:text" json:"production_companies"`  // JSON array of TMDB companies
+	OriginCountry       string     `gorm:"size:50;index:idx_origin_country" json:"origin_country"` // Comma-separated ISO 3166-1 codes
+	EpisodeRunTime      int        `gorm:"default:0" json:"episode_run_time"`      // Minutes
+	InProduction        bool       `gorm:"default:false" json:"in_production"`
+	LastAirDate         *time.Time `json:"last_air_date"`
+	LastEpisodeSeason   int        `gorm:"default:0" json:"last_episode_season"`
+	LastEpisodeNumber   int        `gorm:"default:0" json:"last_episode_number"`
+	NextEpisodeSeason   int        `gorm:"default:0" json:"next_episode_season"`
+	NextEpisodeNumber   int        `gorm:"default:0" json:"next_episode_number"`
+	// Broadcast schedule, derived from the network when empty; set by hand to override
+	AirTimezone         string     `gorm:"size:64" json:"air_timezone"` // IANA zone, e.g. "America/New_York"
+	AirTime             string     `gorm:"size:5" json:"air_time"`      // Local air time "HH:MM"
+
 	// Local fields
 	LastSeasonNumber int        `gorm:"default:0" json:"last_season_number"`
 	LastEpisodeCount int        `gorm:"default:0" json:"last_episode_count"`
 	NextAirDate      *time.Time `gorm:"index:idx_next_air_date" json:"next_air_date"`
 	CustomStatus     string     `gorm:"size:50" json:"custom_status"`
 	Notes            string     `gorm:"type:text" json:"notes"`
+	IncludeSpecials  bool       `gorm:"default:false" json:"include_specials"` // Crawl season 0 specials
+	// ArchivedAt is set when the show was removed from a synced TMDB list; archived shows are no longer refreshed
+	ArchivedAt       *time.Time `gorm:"index:idx_archived_at" json:"archived_at"`
 
 	// Correction fields
 	RefreshThreshold      int        `gorm:"default:0" json:"refresh_threshold"`
@@ -41,9 +61,12 @@ type Show struct {
 	CreatedAt     time.Time  `json:"created_at"`
 	UpdatedAt     time.Time  `json:"updated_at"`
 	LastCrawledAt *time.Time `gorm:"index:idx_last_crawled" json:"last_crawled_at"`
+	// ChangeCursor is the point up to which TMDB changes have been applied (nil = full crawl needed)
+	ChangeCursor *time.Time `json:"change_cursor"`
 
 	// Relationships
 	Episodes []Episode `gorm:"foreignKey:ShowID;constraint:OnDelete:CASCADE" json:"episodes,omitempty"`
+	Genres   []Genre   `gorm:"many2many:show_genres;constraint:OnDelete:CASCADE" json:"genres"`
 }
 
 // TableName specifies the table name for Show model
@@ -61,6 +84,11 @@ func (s *Show) IsEnded() bool {
 	return s.Status == "Ended"
 }
 
+// IsArchived checks if the show has been archived
+func (s *Show) IsArchived() bool {
+	return s.ArchivedAt != nil
+}
+
 // GetDisplayStatus returns the display status (custom or original)
 func (s *Show) GetDisplayStatus() string {
 	if s.CustomStatus != "" {
@@ -69,6 +97,21 @@ func (s *Show) GetDisplayStatus() string {
 	return s.Status
 }
 
+// Localized returns a copy of the show with the name and overview in lang.
+// Fields without a translation keep the stored (primary or fallback) text.
+func (s *Show) Localized(lang string) *Show {
+	localized := *s
+	if tr, ok := s.Translations.Get(lang); ok {
+		if tr.Name != "" {
+			localized.Name = tr.Name
+		}
+		if tr.Overview != "" {
+			localized.Overview = tr.Overview
+		}
+	}
+	return &localized
+}
+
 // GetDisplayType returns the display type
 func (s *Show) GetDisplayType() string {
 	if s.Type != "" {
@@ -99,9 +142,39 @@ func (s *Show) Validate() error {
 	if s.TmdbID <= 0 {
 		return fmt.Errorf("invalid TMDB ID")
 	}
+	if s.AirTimezone != "" {
+		if _, err := time.LoadLocation(s.AirTimezone); err != nil {
+			return fmt.Errorf("invalid air timezone: %s", s.AirTimezone)
+		}
+	}
+	if s.AirTime != "" {
+		if _, err := time.Parse("15:04", s.AirTime); err != nil {
+			return fmt.Errorf("air time must be HH:MM: %s", s.AirTime)
+		}
+	}
 	return nil
 }
 
+// AirInstant returns the moment an episode airing on airDate is broadcast:
+// the show's air time on that date in its broadcast timezone, in UTC.
+// Returns nil when the date, the air time or the timezone is unknown.
+func (s *Show) AirInstant(airDate *time.Time) *time.Time {
+	if airDate == nil || s.AirTime == "" || s.AirTimezone == "" {
+		return nil
+	}
+	location, err := time.LoadLocation(s.AirTimezone)
+	if err != nil {
+		return nil
+	}
+	clock, err := time.Parse("15:04", s.AirTime)
+	if err != nil {
+		return nil
+	}
+	instant := time.Date(airDate.Year(), airDate.Month(), airDate.Day(),
+		clock.Hour(), clock.Minute(), 0, 0, location).UTC()
+	return &instant
+}
+
 // IsExpired checks if the show data needs to be refreshed (older than 24 hours)
 func (s *Show) IsExpired() bool {
 	if s.LastCrawledAt == nil {
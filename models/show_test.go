@@ -187,6 +187,34 @@ func TestShow_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Air schedule",
+			show: &Show{
+				Name:        "Test Show",
+				TmdbID:      123,
+				AirTimezone: "America/New_York",
+				AirTime:     "21:00",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Unknown air timezone",
+			show: &Show{
+				Name:        "Test Show",
+				TmdbID:      123,
+				AirTimezone: "Mars/Olympus_Mons",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid air time",
+			show: &Show{
+				Name:    "Test Show",
+				TmdbID:  123,
+				AirTime: "9pm",
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -315,6 +343,31 @@ func TestShow_ShouldRefresh(t *testing.T) {
 	}
 }
 
+func TestShow_AirInstant(t *testing.T) {
+	airDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	show := &Show{AirTimezone: "America/New_York", AirTime: "21:00"}
+
+	// 21:00 EST is 02:00 UTC the next day
+	got := show.AirInstant(&airDate)
+	if got == nil || !got.Equal(time.Date(2024, 3, 2, 2, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected 2024-03-02 02:00 UTC, got %v", got)
+	}
+
+	// Daylight saving time moves the instant by an hour
+	summer := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	if got := show.AirInstant(&summer); got == nil || got.Hour() != 1 {
+		t.Errorf("Expected 01:00 UTC during DST, got %v", got)
+	}
+
+	if got := show.AirInstant(nil); got != nil {
+		t.Errorf("Expected nil without an air date, got %v", got)
+	}
+	show.AirTime = ""
+	if got := show.AirInstant(&airDate); got != nil {
+		t.Errorf("Expected nil without an air time, got %v", got)
+	}
+}
+
 func TestShow_TableName(t *testing.T) {
 	show := Show{}
 	if got := show.TableName(); got != "shows" {
@@ -16,8 +16,13 @@ type TelegraphPost struct {
 	ContentHash   string    `gorm:"size:64;index:idx_content_hash;not null" json:"content_hash"` // MD5 hash
 	ShowsCount    int       `gorm:"default:0" json:"shows_count"`
 	EpisodesCount int       `gorm:"default:0" json:"episodes_count"`
-	DateRange     string    `gorm:"size:50" json:"date_range"` // '2026-01-11 to 2026-02-10'
+	MoviesCount   int       `gorm:"default:0" json:"movies_count"`
+	DateRange     string    `gorm:"size:50" json:"date_range"`                     // '2026-01-11 to 2026-02-10'
+	Slot          string    `gorm:"size:100;index:idx_telegraph_slot" json:"slot"` // 'today-2026-01-11', 'week-2026-W02', 'show-12'
 	CreatedAt     time.Time `gorm:"index:idx_telegraph_created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	Revisions []TelegraphPostRevision `gorm:"foreignKey:PostID" json:"revisions,omitempty"`
 }
 
 // TableName specifies the table name for TelegraphPost model
@@ -25,6 +30,24 @@ func (TelegraphPost) TableName() string {
 	return "telegraph_posts"
 }
 
+// TelegraphPostRevision records one version of a Telegraph page: the page as
+// created and every edit made to it since
+type TelegraphPostRevision struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	PostID        uint      `gorm:"not null;index:idx_telegraph_revisions_post_id" json:"post_id"`
+	Title         string    `gorm:"size:255" json:"title"`
+	ContentHash   string    `gorm:"size:64;not null" json:"content_hash"`
+	ShowsCount    int       `gorm:"default:0" json:"shows_count"`
+	EpisodesCount int       `gorm:"default:0" json:"episodes_count"`
+	MoviesCount   int       `gorm:"default:0" json:"movies_count"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for TelegraphPostRevision model
+func (TelegraphPostRevision) TableName() string {
+	return "telegraph_post_revisions"
+}
+
 // GetFullURL returns the full Telegraph URL
 func (t *TelegraphPost) GetFullURL() string {
 	if t.TelegraphURL != "" {
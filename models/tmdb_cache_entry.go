@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// TMDBCacheEntry is a cached TMDB API response
+// Key: request URL plus query parameters (without the API key)
+// ETag/LastModified: validators used to revalidate the entry once it has expired
+type TMDBCacheEntry struct {
+	Key          string    `gorm:"primaryKey;size:512" json:"key"`
+	Body         []byte    `gorm:"not null" json:"body"`
+	ETag         string    `gorm:"column:etag;size:255" json:"etag,omitempty"`
+	LastModified string    `gorm:"size:64" json:"last_modified,omitempty"`
+	ExpiresAt    time.Time `gorm:"index:idx_tmdb_cache_expires_at" json:"expires_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for TMDBCacheEntry model
+func (TMDBCacheEntry) TableName() string {
+	return "tmdb_cache"
+}
+
+// IsFresh checks if the entry can be used without asking TMDB
+func (e *TMDBCacheEntry) IsFresh(now time.Time) bool {
+	return now.Before(e.ExpiresAt)
+}
+
+// CanRevalidate checks if the entry has a validator for a conditional request
+func (e *TMDBCacheEntry) CanRevalidate() bool {
+	return e.ETag != "" || e.LastModified != ""
+}
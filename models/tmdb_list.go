@@ -0,0 +1,62 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TMDBList is a TMDB v4 list whose shows are followed and kept in sync
+// ShowTmdbIDs: comma-separated TMDB IDs of the shows on the list at the last sync
+// ArchiveRemoved: archive shows that were dropped from the list
+// LastSyncStatus: 'success'/'failed'/'partial'
+type TMDBList struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	ListID         int        `gorm:"uniqueIndex:idx_tmdb_list_id;not null" json:"list_id"`
+	Name           string     `gorm:"size:255" json:"name"`
+	Description    string     `gorm:"type:text" json:"description"`
+	Enabled        bool       `gorm:"default:true" json:"enabled"`
+	ArchiveRemoved bool       `gorm:"default:false" json:"archive_removed"`
+	ShowTmdbIDs    string     `gorm:"type:text" json:"-"`
+	ItemCount      int        `gorm:"default:0" json:"item_count"`
+	LastSyncedAt   *time.Time `json:"last_synced_at"`
+	LastSyncStatus string     `gorm:"size:20" json:"last_sync_status"`
+	LastSyncError  string     `gorm:"type:text" json:"last_sync_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for TMDBList model
+func (TMDBList) TableName() string {
+	return "tmdb_lists"
+}
+
+// Validate validates the TMDB list data
+func (l *TMDBList) Validate() error {
+	if l.ListID <= 0 {
+		return fmt.Errorf("invalid TMDB list ID")
+	}
+	return nil
+}
+
+// GetShowTmdbIDs returns the TMDB IDs of the shows on the list at the last sync
+func (l *TMDBList) GetShowTmdbIDs() []int {
+	var ids []int
+	for _, part := range strings.Split(l.ShowTmdbIDs, ",") {
+		if id, err := strconv.Atoi(strings.TrimSpace(part)); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// SetShowTmdbIDs stores the TMDB IDs of the shows on the list
+func (l *TMDBList) SetShowTmdbIDs(ids []int) {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	l.ShowTmdbIDs = strings.Join(parts, ",")
+	l.ItemCount = len(ids)
+}
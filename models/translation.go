@@ -0,0 +1,100 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Translation is the name and overview of a show or episode in one language
+type Translation struct {
+	Name     string `json:"name,omitempty"`
+	Overview string `json:"overview,omitempty"`
+}
+
+// Translations maps a TMDB language code (e.g. zh-CN, en-US) to its translation.
+// Stored as a JSON object in a text column.
+type Translations map[string]Translation
+
+// Value implements driver.Valuer
+func (t Translations) Value() (driver.Value, error) {
+	if len(t) == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner
+func (t *Translations) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*t = nil
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("unsupported translations type: %T", value)
+	}
+
+	if len(data) == 0 {
+		*t = nil
+		return nil
+	}
+	return json.Unmarshal(data, t)
+}
+
+// Get returns the translation for lang.
+// An exact match wins; otherwise the first translation (by code) with the
+// same base language is used, so "en" finds "en-US".
+func (t Translations) Get(lang string) (Translation, bool) {
+	if lang == "" || len(t) == 0 {
+		return Translation{}, false
+	}
+	if tr, ok := t[lang]; ok {
+		return tr, true
+	}
+
+	codes := make([]string, 0, len(t))
+	for code := range t {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		if strings.EqualFold(code, lang) {
+			return t[code], true
+		}
+	}
+	base := baseLanguage(lang)
+	for _, code := range codes {
+		if baseLanguage(code) == base {
+			return t[code], true
+		}
+	}
+	return Translation{}, false
+}
+
+// Set stores the translation for lang
+func (t *Translations) Set(lang string, tr Translation) {
+	if *t == nil {
+		*t = make(Translations)
+	}
+	(*t)[lang] = tr
+}
+
+// baseLanguage returns the lowercase language subtag of a code ("en-US" -> "en")
+func baseLanguage(lang string) string {
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	return strings.ToLower(lang)
+}
@@ -0,0 +1,79 @@
+package models
+
+import "testing"
+
+func TestTranslations_Get(t *testing.T) {
+	translations := Translations{
+		"zh-CN": {Name: "简体"},
+		"zh-TW": {Name: "繁體"},
+		"en-US": {Name: "English"},
+	}
+
+	tests := []struct {
+		lang  string
+		want  string
+		found bool
+	}{
+		{lang: "zh-TW", want: "繁體", found: true},
+		{lang: "en-us", want: "English", found: true},
+		{lang: "en", want: "English", found: true},
+		{lang: "zh", want: "简体", found: true}, // first by code
+		{lang: "ja-JP", found: false},
+		{lang: "", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			got, found := translations.Get(tt.lang)
+			if found != tt.found || got.Name != tt.want {
+				t.Errorf("Get(%q) = %q, %v; want %q, %v", tt.lang, got.Name, found, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+func TestTranslations_ValueScan(t *testing.T) {
+	translations := Translations{"en-US": {Name: "Pilot", Overview: "The first episode"}}
+
+	value, err := translations.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	var scanned Translations
+	if err := scanned.Scan(value); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if scanned["en-US"] != translations["en-US"] {
+		t.Errorf("Round trip mismatch: %+v", scanned)
+	}
+
+	var empty Translations
+	if err := empty.Scan(nil); err != nil || empty != nil {
+		t.Errorf("Expected nil translations from NULL, got %+v (%v)", empty, err)
+	}
+}
+
+func TestEpisode_Localized(t *testing.T) {
+	show := &Show{Name: "测试剧集", Translations: Translations{"en-US": {Name: "Test Show"}}}
+	episode := &Episode{
+		Name:         "第1集",
+		Overview:     "中文简介",
+		Translations: Translations{"en-US": {Name: "Pilot"}},
+		Show:         show,
+	}
+
+	localized := episode.Localized("en-US")
+	if localized.Name != "Pilot" {
+		t.Errorf("Expected localized name Pilot, got %s", localized.Name)
+	}
+	if localized.Overview != "中文简介" {
+		t.Errorf("Expected stored overview without translation, got %s", localized.Overview)
+	}
+	if localized.Show.Name != "Test Show" {
+		t.Errorf("Expected localized show name, got %s", localized.Show.Name)
+	}
+	if episode.Name != "第1集" || show.Name != "测试剧集" {
+		t.Error("Localized should not modify the original episode or show")
+	}
+}
@@ -0,0 +1,130 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Webhook events
+const (
+	WebhookEventEpisodeAdded          = "episode.added"
+	WebhookEventEpisodeAirDateChanged = "episode.air_date_changed"
+	WebhookEventShowStatusChanged     = "show.status_changed"
+	WebhookEventCrawlFailed           = "crawl.failed"
+	WebhookEventPublishCompleted      = "publish.completed"
+	WebhookEventStaleDetected         = "correction.stale_detected"
+	// WebhookEventPing is only sent by the test delivery of an endpoint
+	WebhookEventPing = "ping"
+)
+
+// WebhookEvents lists the events an endpoint can subscribe to
+var WebhookEvents = []string{
+	WebhookEventEpisodeAdded,
+	WebhookEventEpisodeAirDateChanged,
+	WebhookEventShowStatusChanged,
+	WebhookEventCrawlFailed,
+	WebhookEventPublishCompleted,
+	WebhookEventStaleDetected,
+}
+
+// IsWebhookEvent reports whether event is one of WebhookEvents
+func IsWebhookEvent(event string) bool {
+	for _, e := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEndpoint is a URL that receives webhook events.
+// Events is a comma-separated list of subscribed events; empty subscribes to all.
+// Every delivery is signed with Secret (HMAC-SHA256).
+type WebhookEndpoint struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"size:100;not null" json:"name"`
+	URL       string    `gorm:"size:512;not null" json:"url"`
+	Secret    string    `gorm:"size:128;not null" json:"-"`
+	Events    string    `gorm:"size:512" json:"events"` // 'episode.added,crawl.failed'
+	Enabled   bool      `gorm:"default:true;index:idx_webhook_endpoints_enabled" json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for WebhookEndpoint model
+func (WebhookEndpoint) TableName() string {
+	return "webhook_endpoints"
+}
+
+// EventList returns the subscribed events; an empty list means all events
+func (e *WebhookEndpoint) EventList() []string {
+	var events []string
+	for _, event := range strings.Split(e.Events, ",") {
+		if event = strings.TrimSpace(event); event != "" {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// SetEventList sets the subscribed events
+func (e *WebhookEndpoint) SetEventList(events []string) {
+	e.Events = strings.Join(events, ",")
+}
+
+// Subscribes reports whether the endpoint receives event
+func (e *WebhookEndpoint) Subscribes(event string) bool {
+	events := e.EventList()
+	if len(events) == 0 {
+		return true
+	}
+	for _, subscribed := range events {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate validates the webhook endpoint data
+func (e *WebhookEndpoint) Validate() error {
+	if strings.TrimSpace(e.Name) == "" {
+		return fmt.Errorf("webhook name cannot be empty")
+	}
+	u, err := url.Parse(e.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook URL must be an http or https URL")
+	}
+	if e.Secret == "" {
+		return fmt.Errorf("webhook secret cannot be empty")
+	}
+	for _, event := range e.EventList() {
+		if !IsWebhookEvent(event) {
+			return fmt.Errorf("unknown webhook event: %s", event)
+		}
+	}
+	return nil
+}
+
+// WebhookDelivery records the delivery of one event to one endpoint.
+// Attempts counts the requests made; StatusCode and Error are those of the last one.
+type WebhookDelivery struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	EndpointID  uint       `gorm:"not null;index:idx_webhook_deliveries_endpoint_id" json:"endpoint_id"`
+	Event       string     `gorm:"size:50;not null;index:idx_webhook_deliveries_event" json:"event"`
+	Payload     string     `gorm:"type:text" json:"payload"`
+	Attempts    int        `gorm:"default:0" json:"attempts"`
+	StatusCode  int        `json:"status_code,omitempty"`
+	Success     bool       `gorm:"default:false" json:"success"`
+	Error       string     `gorm:"type:text" json:"error,omitempty"`
+	DurationMs  int        `json:"duration_ms"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt   time.Time  `gorm:"index:idx_webhook_deliveries_created_at" json:"created_at"`
+}
+
+// TableName specifies the table name for WebhookDelivery model
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
@@ -0,0 +1,38 @@
+package models
+
+import "testing"
+
+func TestWebhookEndpoint_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint *WebhookEndpoint
+		wantErr  bool
+	}{
+		{"Valid endpoint", &WebhookEndpoint{Name: "Bot", URL: "https://example.com/hook", Secret: "s", Events: "episode.added, crawl.failed"}, false},
+		{"All events", &WebhookEndpoint{Name: "Bot", URL: "http://localhost:8080/hook", Secret: "s"}, false},
+		{"Empty name", &WebhookEndpoint{URL: "https://example.com/hook", Secret: "s"}, true},
+		{"Relative URL", &WebhookEndpoint{Name: "Bot", URL: "/hook", Secret: "s"}, true},
+		{"No secret", &WebhookEndpoint{Name: "Bot", URL: "https://example.com/hook"}, true},
+		{"Unknown event", &WebhookEndpoint{Name: "Bot", URL: "https://example.com/hook", Secret: "s", Events: "ping"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.endpoint.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("WebhookEndpoint.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWebhookEndpoint_Subscribes(t *testing.T) {
+	endpoint := &WebhookEndpoint{}
+	if !endpoint.Subscribes(WebhookEventCrawlFailed) {
+		t.Error("Expected an endpoint without events to receive every event")
+	}
+
+	endpoint.SetEventList([]string{WebhookEventEpisodeAdded, WebhookEventPublishCompleted})
+	if !endpoint.Subscribes(WebhookEventPublishCompleted) || endpoint.Subscribes(WebhookEventCrawlFailed) {
+		t.Errorf("Unexpected subscriptions for %q", endpoint.Events)
+	}
+}
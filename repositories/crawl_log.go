@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"time"
 
 	"github.com/xc9973/go-tmdb-crawler/models"
@@ -9,9 +10,11 @@ import (
 
 // CrawlLogRepository defines the interface for crawl log data operations
 type CrawlLogRepository interface {
+	WithContext(ctx context.Context) CrawlLogRepository
 	Create(log *models.CrawlLog) error
 	GetByID(id uint) (*models.CrawlLog, error)
 	GetByShowID(showID uint, limit int) ([]*models.CrawlLog, error)
+	GetByListID(listID int, limit int) ([]*models.CrawlLog, error)
 	GetRecent(limit int) ([]*models.CrawlLog, error)
 	ListAll() ([]*models.CrawlLog, error)
 	GetByStatus(status string, page, pageSize int) ([]*models.CrawlLog, int64, error)
@@ -31,6 +34,11 @@ func NewCrawlLogRepository(db *gorm.DB) CrawlLogRepository {
 	return &crawlLogRepository{db: db}
 }
 
+// WithContext returns a repository whose queries are bound to ctx
+func (r *crawlLogRepository) WithContext(ctx context.Context) CrawlLogRepository {
+	return &crawlLogRepository{db: r.db.WithContext(ctx)}
+}
+
 // Create creates a new crawl log
 func (r *crawlLogRepository) Create(log *models.CrawlLog) error {
 	return r.db.Create(log).Error
@@ -60,6 +68,20 @@ func (r *crawlLogRepository) GetByShowID(showID uint, limit int) ([]*models.Craw
 	return logs, err
 }
 
+// GetByListID retrieves the sync logs of a TMDB list, newest first
+func (r *crawlLogRepository) GetByListID(listID int, limit int) ([]*models.CrawlLog, error) {
+	var logs []*models.CrawlLog
+	query := r.db.Where("action = ? AND list_id = ?", "list_sync", listID).
+		Order("created_at DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	err := query.Find(&logs).Error
+	return logs, err
+}
+
 // GetRecent retrieves recent crawl logs
 func (r *crawlLogRepository) GetRecent(limit int) ([]*models.CrawlLog, error) {
 	var logs []*models.CrawlLog
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"time"
 
 	"github.com/xc9973/go-tmdb-crawler/models"
@@ -9,12 +10,14 @@ import (
 
 // CrawlTaskRepository defines data operations for crawl tasks
 type CrawlTaskRepository interface {
+	WithContext(ctx context.Context) CrawlTaskRepository
 	Create(task *models.CrawlTask) error
 	Update(task *models.CrawlTask) error
 	GetByID(id uint) (*models.CrawlTask, error)
 	GetByStatus(status string, page, pageSize int) ([]*models.CrawlTask, int64, error)
 	GetRecent(limit int) ([]*models.CrawlTask, error)
 	GetRunning() ([]*models.CrawlTask, error)
+	GetUnfinished(taskTypes []string) ([]*models.CrawlTask, error)
 	Delete(id uint) error
 	DeleteOld(days int) error
 	Count() (int64, error)
@@ -30,6 +33,11 @@ func NewCrawlTaskRepository(db *gorm.DB) CrawlTaskRepository {
 	return &crawlTaskRepository{db: db}
 }
 
+// WithContext returns a repository whose queries are bound to ctx
+func (r *crawlTaskRepository) WithContext(ctx context.Context) CrawlTaskRepository {
+	return &crawlTaskRepository{db: r.db.WithContext(ctx)}
+}
+
 // Create creates a new crawl task
 func (r *crawlTaskRepository) Create(task *models.CrawlTask) error {
 	return r.db.Create(task).Error
@@ -89,6 +97,15 @@ func (r *crawlTaskRepository) GetRunning() ([]*models.CrawlTask, error) {
 	return tasks, err
 }
 
+// GetUnfinished retrieves the queued or running tasks of the given types, oldest first
+func (r *crawlTaskRepository) GetUnfinished(taskTypes []string) ([]*models.CrawlTask, error) {
+	var tasks []*models.CrawlTask
+	err := r.db.Where("status IN ? AND type IN ?", []string{"queued", "running"}, taskTypes).
+		Order("created_at ASC, id ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
 // Delete deletes a crawl task by ID
 func (r *crawlTaskRepository) Delete(id uint) error {
 	return r.db.Delete(&models.CrawlTask{}, id).Error
@@ -218,6 +218,25 @@ func TestCrawlTaskRepository_GetRunning(t *testing.T) {
 	}
 }
 
+func TestCrawlTaskRepository_GetUnfinished(t *testing.T) {
+	db := setupCrawlTaskDB(t)
+	repo := NewCrawlTaskRepository(db)
+
+	now := time.Now()
+	for _, status := range []string{"queued", "running", "success", "failed", "interrupted"} {
+		db.Create(&models.CrawlTask{Type: "refresh_all", Status: status, StartedAt: &now})
+	}
+	db.Create(&models.CrawlTask{Type: "correction", Status: "queued"})
+
+	found, err := repo.GetUnfinished([]string{"refresh_all", "crawl_by_status"})
+	if err != nil {
+		t.Fatalf("Failed to get unfinished tasks: %v", err)
+	}
+	if len(found) != 2 || found[0].Status != "queued" || found[1].Status != "running" {
+		t.Errorf("Expected the queued and running tasks, got %+v", found)
+	}
+}
+
 func TestCrawlTaskRepository_Delete(t *testing.T) {
 	db := setupCrawlTaskDB(t)
 	repo := NewCrawlTaskRepository(db)
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"gorm.io/gorm"
+)
+
+// DiscoveryCandidateRepository defines data operations for the discovery review queue
+type DiscoveryCandidateRepository interface {
+	WithContext(ctx context.Context) DiscoveryCandidateRepository
+	Create(candidate *models.DiscoveryCandidate) error
+	Update(candidate *models.DiscoveryCandidate) error
+	GetByID(id uint) (*models.DiscoveryCandidate, error)
+	GetByTmdbIDs(tmdbIDs []int) ([]*models.DiscoveryCandidate, error)
+	List(status string, page, pageSize int) ([]*models.DiscoveryCandidate, int64, error)
+	CountByStatus() (map[string]int64, error)
+}
+
+type discoveryCandidateRepository struct {
+	db *gorm.DB
+}
+
+// NewDiscoveryCandidateRepository creates a new discovery candidate repository instance
+func NewDiscoveryCandidateRepository(db *gorm.DB) DiscoveryCandidateRepository {
+	return &discoveryCandidateRepository{db: db}
+}
+
+// WithContext returns a repository whose queries are bound to ctx
+func (r *discoveryCandidateRepository) WithContext(ctx context.Context) DiscoveryCandidateRepository {
+	return &discoveryCandidateRepository{db: r.db.WithContext(ctx)}
+}
+
+// Create creates a new candidate
+func (r *discoveryCandidateRepository) Create(candidate *models.DiscoveryCandidate) error {
+	return r.db.Create(candidate).Error
+}
+
+// Update updates a candidate
+func (r *discoveryCandidateRepository) Update(candidate *models.DiscoveryCandidate) error {
+	return r.db.Save(candidate).Error
+}
+
+// GetByID retrieves a candidate by ID
+func (r *discoveryCandidateRepository) GetByID(id uint) (*models.DiscoveryCandidate, error) {
+	var candidate models.DiscoveryCandidate
+	if err := r.db.First(&candidate, id).Error; err != nil {
+		return nil, err
+	}
+	return &candidate, nil
+}
+
+// GetByTmdbIDs retrieves the candidates of the given TMDB IDs
+func (r *discoveryCandidateRepository) GetByTmdbIDs(tmdbIDs []int) ([]*models.DiscoveryCandidate, error) {
+	var candidates []*models.DiscoveryCandidate
+	if len(tmdbIDs) == 0 {
+		return candidates, nil
+	}
+	err := r.db.Where("tmdb_id IN ?", tmdbIDs).Find(&candidates).Error
+	return candidates, err
+}
+
+// List retrieves candidates with pagination, most popular first.
+// An empty status lists every candidate.
+func (r *discoveryCandidateRepository) List(status string, page, pageSize int) ([]*models.DiscoveryCandidate, int64, error) {
+	var candidates []*models.DiscoveryCandidate
+	var total int64
+
+	query := r.db.Model(&models.DiscoveryCandidate{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Order("popularity DESC, id ASC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&candidates).Error
+	return candidates, total, err
+}
+
+// CountByStatus returns the number of candidates per status
+func (r *discoveryCandidateRepository) CountByStatus() (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	if err := r.db.Model(&models.DiscoveryCandidate{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
@@ -1,7 +1,9 @@
 package repositories
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/xc9973/go-tmdb-crawler/models"
@@ -11,15 +13,20 @@ import (
 
 // EpisodeRepository defines the interface for episode data operations
 type EpisodeRepository interface {
+	WithContext(ctx context.Context) EpisodeRepository
 	Create(episode *models.Episode) error
 	CreateBatch(episodes []*models.Episode) error
+	ReplaceSeasons(showID uint, seasonNumbers []int, episodes []*models.Episode) error
 	GetByID(id uint) (*models.Episode, error)
 	GetByShowID(showID uint) ([]*models.Episode, error)
 	GetBySeason(showID uint, seasonNumber int) ([]*models.Episode, error)
 	GetByDateRange(startDate, endDate time.Time) ([]*models.Episode, error)
+	GetByDateRangeFiltered(startDate, endDate time.Time, filter EpisodeFilter) ([]*models.Episode, error)
 	GetTodayUpdates() ([]*models.Episode, error)
 	GetTodayUpdatesWithUploadStatus() ([]map[string]interface{}, error)
+	WithTimezone(tzHelper *utils.TimezoneHelper) EpisodeRepository
 	Update(episode *models.Episode) error
+	UpdateAirInstants(show *models.Show) error
 	Delete(id uint) error
 	DeleteByShowID(showID uint) error
 	CountByShowID(showID uint) (int64, error)
@@ -27,6 +34,18 @@ type EpisodeRepository interface {
 	SetTimezoneHelper(tzHelper *utils.TimezoneHelper)
 }
 
+// EpisodeFilter narrows episode queries; empty fields are ignored
+type EpisodeFilter struct {
+	// ShowID limits the episodes to one show; without it archived shows are left out
+	ShowID uint
+	// Status matches the show status ("Returning Series")
+	Status string
+	// Genre matches a show genre by TMDB ID ("18") or case-insensitive name ("Drama")
+	Genre string
+	// Uploaded matches the uploaded state of the episodes when set
+	Uploaded *bool
+}
+
 type episodeRepository struct {
 	db             *gorm.DB
 	timezoneHelper *utils.TimezoneHelper
@@ -42,6 +61,20 @@ func NewEpisodeRepository(db *gorm.DB) EpisodeRepository {
 	}
 }
 
+// WithContext returns a repository whose queries are bound to ctx
+func (r *episodeRepository) WithContext(ctx context.Context) EpisodeRepository {
+	return &episodeRepository{db: r.db.WithContext(ctx), timezoneHelper: r.timezoneHelper}
+}
+
+// WithTimezone returns a repository whose dates are interpreted in the timezone of tzHelper,
+// e.g. a viewer's timezone for one request. A nil tzHelper keeps the configured timezone.
+func (r *episodeRepository) WithTimezone(tzHelper *utils.TimezoneHelper) EpisodeRepository {
+	if tzHelper == nil {
+		return r
+	}
+	return &episodeRepository{db: r.db, timezoneHelper: tzHelper}
+}
+
 // SetTimezoneHelper sets the timezone helper for date operations
 // This should be called during application initialization
 func (r *episodeRepository) SetTimezoneHelper(tzHelper *utils.TimezoneHelper) {
@@ -81,6 +114,30 @@ func (r *episodeRepository) CreateBatch(episodes []*models.Episode) error {
 	})
 }
 
+// ReplaceSeasons replaces the episodes of the given seasons of a show
+// Episodes of other seasons are left untouched
+func (r *episodeRepository) ReplaceSeasons(showID uint, seasonNumbers []int, episodes []*models.Episode) error {
+	if len(seasonNumbers) == 0 {
+		return nil
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("show_id = ? AND season_number IN ?", showID, seasonNumbers).
+			Delete(&models.Episode{}).Error; err != nil {
+			return fmt.Errorf("failed to delete old episodes: %w", err)
+		}
+
+		if len(episodes) == 0 {
+			return nil
+		}
+		if err := tx.CreateInBatches(episodes, 100).Error; err != nil {
+			return fmt.Errorf("failed to insert episodes: %w", err)
+		}
+
+		return nil
+	})
+}
+
 // GetByID retrieves an episode by ID
 func (r *episodeRepository) GetByID(id uint) (*models.Episode, error) {
 	var episode models.Episode
@@ -116,11 +173,48 @@ func (r *episodeRepository) GetByDateRange(startDate, endDate time.Time) ([]*mod
 	var episodes []*models.Episode
 
 	// Use timezone-aware date boundaries
-	start, end := r.timezoneHelper.DateRange(startDate, endDate)
+	start, end := r.timezoneHelper.DayRange(startDate, endDate)
+	condition, args := r.airWindow("", start, end)
 
-	err := r.db.Where("air_date >= ? AND air_date <= ?", start, end).
+	err := r.db.Where(condition, args...).
 		Preload("Show").
-		Order("air_date ASC").
+		Order("COALESCE(air_at, air_date) ASC").
+		Find(&episodes).Error
+	return episodes, err
+}
+
+// GetByDateRangeFiltered retrieves the episodes matching filter within a date range
+// The range is inclusive: [startDate, endDate]
+// Dates are interpreted in the configured timezone
+func (r *episodeRepository) GetByDateRangeFiltered(startDate, endDate time.Time, filter EpisodeFilter) ([]*models.Episode, error) {
+	var episodes []*models.Episode
+
+	start, end := r.timezoneHelper.DayRange(startDate, endDate)
+	condition, args := r.airWindow("", start, end)
+
+	query := r.db.Where(condition, args...)
+	if filter.ShowID != 0 {
+		query = query.Where("show_id = ?", filter.ShowID)
+	} else {
+		query = query.Where("show_id IN (?)", r.db.Model(&models.Show{}).Select("id").Where("archived_at IS NULL"))
+	}
+	if status := strings.TrimSpace(filter.Status); status != "" {
+		query = query.Where("show_id IN (?)", r.db.Model(&models.Show{}).Select("id").Where("status = ?", status))
+	}
+	if genre := strings.TrimSpace(filter.Genre); genre != "" {
+		query = query.Where("show_id IN (?)", genreShowIDs(r.db, genre))
+	}
+	if filter.Uploaded != nil {
+		uploaded := r.db.Model(&models.UploadedEpisode{}).Select("episode_id").Where("uploaded = ?", true)
+		if *filter.Uploaded {
+			query = query.Where("id IN (?)", uploaded)
+		} else {
+			query = query.Where("id NOT IN (?)", uploaded)
+		}
+	}
+
+	err := query.Preload("Show").
+		Order("COALESCE(air_at, air_date) ASC").
 		Find(&episodes).Error
 	return episodes, err
 }
@@ -133,18 +227,33 @@ func (r *episodeRepository) GetTodayUpdates() ([]*models.Episode, error) {
 
 	// Use timezone-aware today boundaries
 	start, end := r.timezoneHelper.TodayRange()
+	condition, args := r.airWindow("", start, end)
 
-	err := r.db.Where("air_date >= ? AND air_date < ?", start, end).
+	err := r.db.Where(condition, args...).
 		Preload("Show").
-		Order("air_date ASC").
+		Order("COALESCE(air_at, air_date) ASC").
 		Find(&episodes).Error
 	return episodes, err
 }
 
+// airWindow builds the condition for episodes airing in [start, end).
+// Episodes with a known air instant are matched by that instant; date-only
+// episodes by their air date, taken as a day in the configured timezone.
+// The bounds are passed in UTC, the timezone the air dates and instants are stored in.
+func (r *episodeRepository) airWindow(prefix string, start, end time.Time) (string, []interface{}) {
+	condition := fmt.Sprintf("((%[1]sair_at IS NOT NULL AND %[1]sair_at >= ? AND %[1]sair_at < ?) OR "+
+		"(%[1]sair_at IS NULL AND %[1]sair_date >= ? AND %[1]sair_date < ?))", prefix)
+	return condition, []interface{}{
+		start.UTC(), end.UTC(),
+		r.timezoneHelper.DateOf(start), r.timezoneHelper.DateOf(end),
+	}
+}
+
 // GetTodayUpdatesWithUploadStatus retrieves episodes airing today with upload status
 // 返回结构与前端 today.js 期望的格式匹配
 func (r *episodeRepository) GetTodayUpdatesWithUploadStatus() ([]map[string]interface{}, error) {
 	start, end := r.timezoneHelper.TodayRange()
+	condition, args := r.airWindow("e.", start, end)
 
 	type Result struct {
 		ID            uint
@@ -152,6 +261,7 @@ func (r *episodeRepository) GetTodayUpdatesWithUploadStatus() ([]map[string]inte
 		EpisodeNumber int
 		Name          string
 		AirDate       *time.Time
+		AirAt         *time.Time
 		StillPath     string
 		VoteAverage   float32
 		ShowID        uint
@@ -169,6 +279,7 @@ func (r *episodeRepository) GetTodayUpdatesWithUploadStatus() ([]map[string]inte
             e.episode_number,
             e.name,
             e.air_date,
+            e.air_at,
             e.still_path,
             e.vote_average,
             e.show_id,
@@ -179,9 +290,9 @@ func (r *episodeRepository) GetTodayUpdatesWithUploadStatus() ([]map[string]inte
         FROM episodes e
         INNER JOIN shows s ON e.show_id = s.id
         LEFT JOIN uploaded_episodes ue ON e.id = ue.episode_id
-        WHERE e.air_date >= ? AND e.air_date < ?
-        ORDER BY e.air_date ASC
-    `, start, end).Scan(&results).Error
+        WHERE `+condition+`
+        ORDER BY COALESCE(e.air_at, e.air_date) ASC
+    `, args...).Scan(&results).Error
 
 	if err != nil {
 		return nil, err
@@ -189,13 +300,19 @@ func (r *episodeRepository) GetTodayUpdatesWithUploadStatus() ([]map[string]inte
 
 	// 转换为 map 格式以匹配前端期望
 	episodes := make([]map[string]interface{}, len(results))
+	tzHelper := r.timezoneHelper
 	for i, r := range results {
+		ep := models.Episode{SeasonNumber: r.SeasonNumber, EpisodeNumber: r.EpisodeNumber}
 		episodes[i] = map[string]interface{}{
 			"id":             r.ID,
 			"season_number":  r.SeasonNumber,
 			"episode_number": r.EpisodeNumber,
+			"episode_code":   ep.GetEpisodeCode(),
+			"is_special":     ep.IsSpecial(),
 			"name":           r.Name,
 			"air_date":       r.AirDate,
+			"air_at":         r.AirAt,
+			"local_air_date": tzHelper.FormatAirDate(r.AirAt, r.AirDate),
 			"still_path":     r.StillPath,
 			"vote_average":   r.VoteAverage,
 			"show_id":        r.ShowID,
@@ -214,6 +331,24 @@ func (r *episodeRepository) Update(episode *models.Episode) error {
 	return r.db.Save(episode).Error
 }
 
+// UpdateAirInstants recomputes the air instants of all episodes of a show
+// from its air time and broadcast timezone
+func (r *episodeRepository) UpdateAirInstants(show *models.Show) error {
+	var episodes []*models.Episode
+	if err := r.db.Where("show_id = ?", show.ID).Find(&episodes).Error; err != nil {
+		return err
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, episode := range episodes {
+			if err := tx.Model(episode).UpdateColumn("air_at", show.AirInstant(episode.AirDate)).Error; err != nil {
+				return fmt.Errorf("failed to update air instant of episode %d: %w", episode.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
 // Delete deletes an episode by ID
 func (r *episodeRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Episode{}, id).Error
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"gorm.io/gorm"
+)
+
+// EpisodeChangeRepository defines data operations for episode change history
+type EpisodeChangeRepository interface {
+	WithContext(ctx context.Context) EpisodeChangeRepository
+	CreateBatch(changes []*models.EpisodeChange) error
+	GetByShowID(showID uint, page, pageSize int) ([]*models.EpisodeChange, int64, error)
+	GetByDateRange(startDate, endDate time.Time, page, pageSize int) ([]*models.EpisodeChange, int64, error)
+	GetByCrawlLogID(crawlLogID uint) ([]*models.EpisodeChange, error)
+	DeleteOld(days int) error
+}
+
+type episodeChangeRepository struct {
+	db *gorm.DB
+}
+
+// NewEpisodeChangeRepository creates a new episode change repository instance
+func NewEpisodeChangeRepository(db *gorm.DB) EpisodeChangeRepository {
+	return &episodeChangeRepository{db: db}
+}
+
+// WithContext returns a repository whose queries are bound to ctx
+func (r *episodeChangeRepository) WithContext(ctx context.Context) EpisodeChangeRepository {
+	return &episodeChangeRepository{db: r.db.WithContext(ctx)}
+}
+
+// CreateBatch creates multiple episode changes
+func (r *episodeChangeRepository) CreateBatch(changes []*models.EpisodeChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(changes, 100).Error
+}
+
+// GetByShowID retrieves the change history of a show with pagination
+func (r *episodeChangeRepository) GetByShowID(showID uint, page, pageSize int) ([]*models.EpisodeChange, int64, error) {
+	return r.paginate(r.db.Model(&models.EpisodeChange{}).Where("show_id = ?", showID), page, pageSize)
+}
+
+// GetByDateRange retrieves changes detected within a date range with pagination
+func (r *episodeChangeRepository) GetByDateRange(startDate, endDate time.Time, page, pageSize int) ([]*models.EpisodeChange, int64, error) {
+	query := r.db.Model(&models.EpisodeChange{}).
+		Where("created_at >= ? AND created_at <= ?", startDate, endDate)
+	return r.paginate(query, page, pageSize)
+}
+
+// GetByCrawlLogID retrieves the changes recorded by a crawl
+func (r *episodeChangeRepository) GetByCrawlLogID(crawlLogID uint) ([]*models.EpisodeChange, error) {
+	var changes []*models.EpisodeChange
+	err := r.db.Where("crawl_log_id = ?", crawlLogID).
+		Order("season_number ASC, episode_number ASC").
+		Find(&changes).Error
+	return changes, err
+}
+
+// DeleteOld deletes episode changes older than specified days
+func (r *episodeChangeRepository) DeleteOld(days int) error {
+	cutoffDate := time.Now().AddDate(0, 0, -days)
+	return r.db.Where("created_at < ?", cutoffDate).Delete(&models.EpisodeChange{}).Error
+}
+
+// paginate counts and loads one page of changes, newest first
+func (r *episodeChangeRepository) paginate(query *gorm.DB, page, pageSize int) ([]*models.EpisodeChange, int64, error) {
+	var changes []*models.EpisodeChange
+	var total int64
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get paginated data
+	offset := (page - 1) * pageSize
+	err := query.Preload("Show").
+		Order("created_at DESC, id DESC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&changes).Error
+
+	return changes, total, err
+}
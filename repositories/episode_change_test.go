@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupEpisodeChangeDB(t *testing.T) *gorm.DB {
+	// Use unique database name for each test to avoid conflicts
+	dbName := fmt.Sprintf("file:EpisodeChangeTest_%d?mode=memory&cache=shared", time.Now().UnixNano())
+	db, err := gorm.Open(sqlite.Open(dbName), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+
+	err = db.AutoMigrate(&models.Show{}, &models.CrawlLog{}, &models.EpisodeChange{})
+	if err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	return db
+}
+
+func TestEpisodeChangeRepository_GetByShowID(t *testing.T) {
+	db := setupEpisodeChangeDB(t)
+	repo := NewEpisodeChangeRepository(db)
+
+	show := &models.Show{TmdbID: 500, Name: "Changed Show"}
+	other := &models.Show{TmdbID: 501, Name: "Other Show"}
+	db.Create(show)
+	db.Create(other)
+
+	log := &models.CrawlLog{ShowID: &show.ID, TmdbID: 500, Action: "fetch", Status: "success"}
+	db.Create(log)
+
+	changes := []*models.EpisodeChange{
+		{CrawlLogID: &log.ID, ShowID: show.ID, SeasonNumber: 1, EpisodeNumber: 1, ChangeType: "title_changed", OldValue: "Old", NewValue: "New"},
+		{CrawlLogID: &log.ID, ShowID: show.ID, SeasonNumber: 1, EpisodeNumber: 2, ChangeType: "removed", OldValue: "Gone"},
+		{ShowID: other.ID, SeasonNumber: 1, EpisodeNumber: 1, ChangeType: "added", NewValue: "Pilot"},
+	}
+	if err := repo.CreateBatch(changes); err != nil {
+		t.Fatalf("Failed to create changes: %v", err)
+	}
+
+	results, total, err := repo.GetByShowID(show.ID, 1, 1)
+	if err != nil {
+		t.Fatalf("GetByShowID failed: %v", err)
+	}
+	if total != 2 || len(results) != 1 {
+		t.Errorf("Expected 1 of 2 changes, got %d of %d", len(results), total)
+	}
+	if results[0].Show == nil || results[0].Show.Name != "Changed Show" {
+		t.Error("Show should be preloaded")
+	}
+
+	byLog, err := repo.GetByCrawlLogID(log.ID)
+	if err != nil {
+		t.Fatalf("GetByCrawlLogID failed: %v", err)
+	}
+	if len(byLog) != 2 {
+		t.Errorf("Expected 2 changes for crawl log, got %d", len(byLog))
+	}
+}
+
+func TestEpisodeChangeRepository_GetByDateRange(t *testing.T) {
+	db := setupEpisodeChangeDB(t)
+	repo := NewEpisodeChangeRepository(db)
+
+	show := &models.Show{TmdbID: 502, Name: "Range Show"}
+	db.Create(show)
+
+	now := time.Now()
+	recent := &models.EpisodeChange{ShowID: show.ID, SeasonNumber: 1, EpisodeNumber: 1, ChangeType: "air_date_changed"}
+	old := &models.EpisodeChange{ShowID: show.ID, SeasonNumber: 1, EpisodeNumber: 2, ChangeType: "added", CreatedAt: now.AddDate(0, 0, -10)}
+	repo.CreateBatch([]*models.EpisodeChange{recent, old})
+
+	results, total, err := repo.GetByDateRange(now.AddDate(0, 0, -1), now.Add(time.Minute), 1, 20)
+	if err != nil {
+		t.Fatalf("GetByDateRange failed: %v", err)
+	}
+	if total != 1 || len(results) != 1 || results[0].ID != recent.ID {
+		t.Errorf("Expected only the recent change, got %d results", total)
+	}
+
+	if err := repo.DeleteOld(5); err != nil {
+		t.Fatalf("DeleteOld failed: %v", err)
+	}
+	_, total, _ = repo.GetByShowID(show.ID, 1, 20)
+	if total != 1 {
+		t.Errorf("Expected 1 change after DeleteOld, got %d", total)
+	}
+}
@@ -228,6 +228,124 @@ func TestEpisodeRepository_GetTodayUpdates(t *testing.T) {
 	}
 }
 
+func TestEpisodeRepository_GetTodayUpdates_AirInstant(t *testing.T) {
+	db := setupEpisodeDB(t)
+	repo := NewEpisodeRepository(db)
+	show := createTestShow(db, 1, "Test Show")
+
+	shanghai, _ := time.LoadLocation("Asia/Shanghai")
+	tzHelper := utils.NewTimezoneHelper(shanghai)
+	repo.SetTimezoneHelper(tzHelper)
+
+	todayStart, _ := tzHelper.TodayRange()
+	today := tzHelper.DateOf(todayStart)
+	yesterday := today.AddDate(0, 0, -1)
+
+	episodes := []*models.Episode{
+		// Date-only: today's date in the configured timezone
+		{ShowID: show.ID, SeasonNumber: 1, EpisodeNumber: 1, Name: "E01", AirDate: timePtr(today)},
+		// Aired yesterday evening in New York, which is this morning in Shanghai
+		{ShowID: show.ID, SeasonNumber: 1, EpisodeNumber: 2, Name: "E02", AirDate: timePtr(yesterday),
+			AirAt: timePtr(todayStart.Add(10 * time.Hour).UTC())},
+		// Dated today but airing after midnight in Shanghai
+		{ShowID: show.ID, SeasonNumber: 1, EpisodeNumber: 3, Name: "E03", AirDate: timePtr(today),
+			AirAt: timePtr(todayStart.Add(25 * time.Hour).UTC())},
+	}
+	for _, ep := range episodes {
+		db.Create(ep)
+	}
+
+	found, err := repo.GetTodayUpdates()
+	if err != nil {
+		t.Fatalf("Failed to get today's updates: %v", err)
+	}
+	if len(found) != 2 || found[0].Name != "E01" || found[1].Name != "E02" {
+		t.Errorf("Expected E01 and E02 for today, got %d episodes", len(found))
+	}
+
+	found, err = repo.GetByDateRange(today, today.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Failed to get episodes by date range: %v", err)
+	}
+	if len(found) != 3 {
+		t.Errorf("Expected 3 episodes in the date range, got %d", len(found))
+	}
+}
+
+func TestEpisodeRepository_WithTimezone(t *testing.T) {
+	db := setupEpisodeDB(t)
+	repo := NewEpisodeRepository(db)
+	show := createTestShow(db, 1, "Test Show")
+
+	// 21:00 on March 1st in New York is March 2nd in Shanghai
+	airDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	airAt := time.Date(2024, 3, 2, 2, 0, 0, 0, time.UTC)
+	db.Create(&models.Episode{ShowID: show.ID, SeasonNumber: 1, EpisodeNumber: 1, Name: "E01", AirDate: &airDate, AirAt: &airAt})
+
+	newYork, _ := time.LoadLocation("America/New_York")
+	shanghai, _ := time.LoadLocation("Asia/Shanghai")
+	tests := []struct {
+		name     string
+		location *time.Location
+		day      time.Time
+		expected int
+	}{
+		{"New York on March 1st", newYork, airDate, 1},
+		{"Shanghai on March 1st", shanghai, airDate, 0},
+		{"Shanghai on March 2nd", shanghai, airDate.AddDate(0, 0, 1), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := repo.WithTimezone(utils.NewTimezoneHelper(tt.location)).GetByDateRange(tt.day, tt.day)
+			if err != nil {
+				t.Fatalf("GetByDateRange failed: %v", err)
+			}
+			if len(found) != tt.expected {
+				t.Errorf("Expected %d episodes, got %d", tt.expected, len(found))
+			}
+		})
+	}
+
+	// The repository itself keeps its timezone (UTC: March 2nd)
+	if found, _ := repo.GetByDateRange(airDate, airDate); len(found) != 0 {
+		t.Errorf("Expected no episodes on March 1st in UTC, got %d", len(found))
+	}
+}
+
+func TestEpisodeRepository_UpdateAirInstants(t *testing.T) {
+	db := setupEpisodeDB(t)
+	repo := NewEpisodeRepository(db)
+	show := createTestShow(db, 1, "Test Show")
+
+	airDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	db.Create(&models.Episode{ShowID: show.ID, SeasonNumber: 1, EpisodeNumber: 1, Name: "E01", AirDate: &airDate})
+	db.Create(&models.Episode{ShowID: show.ID, SeasonNumber: 1, EpisodeNumber: 2, Name: "E02"})
+
+	show.AirTimezone = "America/New_York"
+	show.AirTime = "21:00"
+	if err := repo.UpdateAirInstants(show); err != nil {
+		t.Fatalf("UpdateAirInstants failed: %v", err)
+	}
+
+	episodes, _ := repo.GetByShowID(show.ID)
+	expected := time.Date(2024, 3, 2, 2, 0, 0, 0, time.UTC)
+	if episodes[0].AirAt == nil || !episodes[0].AirAt.Equal(expected) {
+		t.Errorf("Expected air instant %v, got %v", expected, episodes[0].AirAt)
+	}
+	if episodes[1].AirAt != nil {
+		t.Errorf("Expected no air instant without an air date, got %v", episodes[1].AirAt)
+	}
+
+	// Clearing the air time falls back to the air date
+	show.AirTime = ""
+	if err := repo.UpdateAirInstants(show); err != nil {
+		t.Fatalf("UpdateAirInstants failed: %v", err)
+	}
+	if episodes, _ = repo.GetByShowID(show.ID); episodes[0].AirAt != nil {
+		t.Errorf("Expected the air instant to be cleared, got %v", episodes[0].AirAt)
+	}
+}
+
 func TestEpisodeRepository_Update(t *testing.T) {
 	db := setupEpisodeDB(t)
 	repo := NewEpisodeRepository(db)
@@ -370,3 +488,93 @@ func TestEpisodeRepository_SetTimezoneHelper(t *testing.T) {
 func timePtr(t time.Time) *time.Time {
 	return &t
 }
+
+func TestEpisodeRepository_ReplaceSeasons(t *testing.T) {
+	db := setupEpisodeDB(t)
+	repo := NewEpisodeRepository(db)
+	show := createTestShow(db, 300, "Replace Seasons Show")
+
+	initial := []*models.Episode{
+		{ShowID: show.ID, SeasonNumber: 1, EpisodeNumber: 1, Name: "S1E1"},
+		{ShowID: show.ID, SeasonNumber: 2, EpisodeNumber: 1, Name: "S2E1"},
+		{ShowID: show.ID, SeasonNumber: 2, EpisodeNumber: 2, Name: "S2E2"},
+		{ShowID: show.ID, SeasonNumber: 3, EpisodeNumber: 1, Name: "S3E1"},
+	}
+	if err := repo.CreateBatch(initial); err != nil {
+		t.Fatalf("Failed to create episodes: %v", err)
+	}
+
+	// Season 2 is replaced, season 3 was removed upstream
+	replacement := []*models.Episode{
+		{ShowID: show.ID, SeasonNumber: 2, EpisodeNumber: 1, Name: "S2E1 renamed"},
+	}
+	if err := repo.ReplaceSeasons(show.ID, []int{2, 3}, replacement); err != nil {
+		t.Fatalf("ReplaceSeasons failed: %v", err)
+	}
+
+	episodes, _ := repo.GetByShowID(show.ID)
+	if len(episodes) != 2 {
+		t.Fatalf("Expected 2 episodes, got %d", len(episodes))
+	}
+	if episodes[0].Name != "S1E1" {
+		t.Errorf("Season 1 should be untouched, got %q", episodes[0].Name)
+	}
+	if episodes[1].Name != "S2E1 renamed" {
+		t.Errorf("Season 2 should be replaced, got %q", episodes[1].Name)
+	}
+}
+
+func TestEpisodeRepository_GetByDateRangeFiltered(t *testing.T) {
+	db := setupEpisodeDB(t)
+	if err := db.AutoMigrate(&models.Genre{}, &models.ShowGenre{}, &models.UploadedEpisode{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	repo := NewEpisodeRepository(db)
+
+	drama := createTestShow(db, 1, "Drama Show")
+	ended := createTestShow(db, 2, "Ended Show")
+	db.Model(ended).Update("status", "Ended")
+	archived := createTestShow(db, 3, "Archived Show")
+	db.Model(archived).Update("archived_at", time.Now())
+	db.Create(&models.Genre{ID: 18, Name: "Drama"})
+	db.Create(&models.ShowGenre{ShowID: drama.ID, GenreID: 18})
+
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	episodes := []*models.Episode{
+		{ShowID: drama.ID, SeasonNumber: 1, EpisodeNumber: 1, AirDate: timePtr(day)},
+		{ShowID: drama.ID, SeasonNumber: 1, EpisodeNumber: 2, AirDate: timePtr(day.AddDate(0, 0, 1))},
+		{ShowID: ended.ID, SeasonNumber: 1, EpisodeNumber: 1, AirDate: timePtr(day)},
+		{ShowID: archived.ID, SeasonNumber: 1, EpisodeNumber: 1, AirDate: timePtr(day)},
+		{ShowID: drama.ID, SeasonNumber: 1, EpisodeNumber: 3, AirDate: timePtr(day.AddDate(0, 0, 10))},
+	}
+	for _, ep := range episodes {
+		db.Create(ep)
+	}
+	db.Create(&models.UploadedEpisode{EpisodeID: episodes[0].ID, Uploaded: true})
+
+	uploaded, notUploaded := true, false
+	tests := []struct {
+		name     string
+		filter   EpisodeFilter
+		expected int
+	}{
+		{"No filter skips archived shows", EpisodeFilter{}, 3},
+		{"Show", EpisodeFilter{ShowID: archived.ID}, 1},
+		{"Status", EpisodeFilter{Status: "Ended"}, 1},
+		{"Genre by name", EpisodeFilter{Genre: "drama"}, 2},
+		{"Genre by ID", EpisodeFilter{Genre: "18"}, 2},
+		{"Uploaded", EpisodeFilter{Uploaded: &uploaded}, 1},
+		{"Not uploaded", EpisodeFilter{Uploaded: &notUploaded}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := repo.GetByDateRangeFiltered(day, day.AddDate(0, 0, 1), tt.filter)
+			if err != nil {
+				t.Fatalf("GetByDateRangeFiltered failed: %v", err)
+			}
+			if len(found) != tt.expected {
+				t.Errorf("Expected %d episodes, got %d", tt.expected, len(found))
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"gorm.io/gorm"
+)
+
+// FeedTokenRepository defines data operations for calendar feed tokens
+type FeedTokenRepository interface {
+	WithContext(ctx context.Context) FeedTokenRepository
+	Create(token *models.FeedToken) error
+	GetByID(id uint) (*models.FeedToken, error)
+	GetByHash(tokenHash string) (*models.FeedToken, error)
+	ListAll() ([]*models.FeedToken, error)
+	Revoke(id uint) error
+	TouchLastUsed(id uint, usedAt time.Time) error
+}
+
+type feedTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewFeedTokenRepository creates a new feed token repository instance
+func NewFeedTokenRepository(db *gorm.DB) FeedTokenRepository {
+	return &feedTokenRepository{db: db}
+}
+
+// WithContext returns a repository whose queries are bound to ctx
+func (r *feedTokenRepository) WithContext(ctx context.Context) FeedTokenRepository {
+	return &feedTokenRepository{db: r.db.WithContext(ctx)}
+}
+
+// Create creates a new feed token
+func (r *feedTokenRepository) Create(token *models.FeedToken) error {
+	return r.db.Create(token).Error
+}
+
+// GetByID retrieves a feed token by ID
+func (r *feedTokenRepository) GetByID(id uint) (*models.FeedToken, error) {
+	var token models.FeedToken
+	if err := r.db.First(&token, id).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// GetByHash retrieves a feed token by the SHA-256 hash of the token
+func (r *feedTokenRepository) GetByHash(tokenHash string) (*models.FeedToken, error) {
+	var token models.FeedToken
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// ListAll retrieves all feed tokens, including revoked ones
+func (r *feedTokenRepository) ListAll() ([]*models.FeedToken, error) {
+	var tokens []*models.FeedToken
+	err := r.db.Order("id ASC").Find(&tokens).Error
+	return tokens, err
+}
+
+// Revoke marks a feed token as revoked; revoking twice keeps the first time
+func (r *feedTokenRepository) Revoke(id uint) error {
+	result := r.db.Model(&models.FeedToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		// Distinguish an unknown token from one that is already revoked
+		if _, err := r.GetByID(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TouchLastUsed records when a feed token was last used
+func (r *feedTokenRepository) TouchLastUsed(id uint, usedAt time.Time) error {
+	return r.db.Model(&models.FeedToken{}).Where("id = ?", id).Update("last_used_at", usedAt).Error
+}
@@ -0,0 +1,226 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/utils"
+	"gorm.io/gorm"
+)
+
+// MovieRepository defines the interface for movie data operations
+type MovieRepository interface {
+	WithContext(ctx context.Context) MovieRepository
+	Create(movie *models.Movie) error
+	GetByID(id uint) (*models.Movie, error)
+	GetByTmdbID(tmdbID int) (*models.Movie, error)
+	List(search string, page, pageSize int) ([]*models.Movie, int64, error)
+	ListAll() ([]*models.Movie, error)
+	Update(movie *models.Movie) error
+	ReplaceReleaseDates(movieID uint, releases []*models.MovieReleaseDate) error
+	GetReleasesByDateRange(startDate, endDate time.Time, filter MovieReleaseFilter) ([]*models.MovieReleaseDate, error)
+	GetTodayReleases(filter MovieReleaseFilter) ([]*models.MovieReleaseDate, error)
+	Delete(id uint) error
+	Count() (int64, error)
+	SetTimezoneHelper(tzHelper *utils.TimezoneHelper)
+	WithTimezone(tzHelper *utils.TimezoneHelper) MovieRepository
+}
+
+// MovieReleaseFilter narrows the release dates of movies; empty fields are ignored
+type MovieReleaseFilter struct {
+	// Regions are ISO 3166-1 country codes ("US")
+	Regions []string
+	// Types are release types (see models.ReleaseTypeTheatrical etc.)
+	Types []int
+}
+
+type movieRepository struct {
+	db             *gorm.DB
+	timezoneHelper *utils.TimezoneHelper
+}
+
+// NewMovieRepository creates a new movie repository instance
+func NewMovieRepository(db *gorm.DB) MovieRepository {
+	// Default to UTC if no timezone specified
+	location, _ := time.LoadLocation("UTC")
+	return &movieRepository{
+		db:             db,
+		timezoneHelper: utils.NewTimezoneHelper(location),
+	}
+}
+
+// WithContext returns a repository whose queries are bound to ctx
+func (r *movieRepository) WithContext(ctx context.Context) MovieRepository {
+	return &movieRepository{db: r.db.WithContext(ctx), timezoneHelper: r.timezoneHelper}
+}
+
+// WithTimezone returns a repository whose dates are interpreted in the timezone of tzHelper.
+// A nil tzHelper keeps the configured timezone.
+func (r *movieRepository) WithTimezone(tzHelper *utils.TimezoneHelper) MovieRepository {
+	if tzHelper == nil {
+		return r
+	}
+	return &movieRepository{db: r.db, timezoneHelper: tzHelper}
+}
+
+// SetTimezoneHelper sets the timezone helper for date operations
+// This should be called during application initialization
+func (r *movieRepository) SetTimezoneHelper(tzHelper *utils.TimezoneHelper) {
+	r.timezoneHelper = tzHelper
+}
+
+// Create creates a new movie
+func (r *movieRepository) Create(movie *models.Movie) error {
+	return r.db.Omit("ReleaseDates").Create(movie).Error
+}
+
+// GetByID retrieves a movie by ID with its release dates
+func (r *movieRepository) GetByID(id uint) (*models.Movie, error) {
+	var movie models.Movie
+	err := r.db.Preload("ReleaseDates", func(db *gorm.DB) *gorm.DB {
+		return db.Order("release_date ASC, region ASC, type ASC")
+	}).First(&movie, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &movie, nil
+}
+
+// GetByTmdbID retrieves a movie by TMDB ID
+func (r *movieRepository) GetByTmdbID(tmdbID int) (*models.Movie, error) {
+	var movie models.Movie
+	err := r.db.Where("tmdb_id = ?", tmdbID).First(&movie).Error
+	if err != nil {
+		return nil, err
+	}
+	return &movie, nil
+}
+
+// List retrieves movies with pagination, optionally filtered by a title search
+func (r *movieRepository) List(search string, page, pageSize int) ([]*models.Movie, int64, error) {
+	var movies []*models.Movie
+	var total int64
+
+	query := r.db.Model(&models.Movie{})
+	if search != "" {
+		if r.db.Dialector.Name() == "sqlite" {
+			q := strings.ToLower(search)
+			query = query.Where("LOWER(title) LIKE ? OR LOWER(original_title) LIKE ?", "%"+q+"%", "%"+q+"%")
+		} else {
+			query = query.Where("title ILIKE ? OR original_title ILIKE ?", "%"+search+"%", "%"+search+"%")
+		}
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Order("created_at DESC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&movies).Error
+
+	return movies, total, err
+}
+
+// ListAll retrieves all movies
+func (r *movieRepository) ListAll() ([]*models.Movie, error) {
+	var movies []*models.Movie
+	err := r.db.Find(&movies).Error
+	return movies, err
+}
+
+// Update updates a movie; its release dates are left untouched
+func (r *movieRepository) Update(movie *models.Movie) error {
+	return r.db.Omit("ReleaseDates").Save(movie).Error
+}
+
+// ReplaceReleaseDates replaces all release dates of a movie
+func (r *movieRepository) ReplaceReleaseDates(movieID uint, releases []*models.MovieReleaseDate) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("movie_id = ?", movieID).Delete(&models.MovieReleaseDate{}).Error; err != nil {
+			return fmt.Errorf("failed to delete old release dates: %w", err)
+		}
+
+		if len(releases) == 0 {
+			return nil
+		}
+		for _, release := range releases {
+			release.MovieID = movieID
+		}
+		if err := tx.CreateInBatches(releases, 100).Error; err != nil {
+			return fmt.Errorf("failed to insert release dates: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetReleasesByDateRange retrieves the releases matching filter within a date range
+// The range is inclusive: [startDate, endDate]
+func (r *movieRepository) GetReleasesByDateRange(startDate, endDate time.Time, filter MovieReleaseFilter) ([]*models.MovieReleaseDate, error) {
+	var releases []*models.MovieReleaseDate
+
+	// Use timezone-aware date boundaries; release dates are date-only values
+	start, end := r.timezoneHelper.DayRange(startDate, endDate)
+
+	err := r.filteredReleases(filter).
+		Where("release_date >= ? AND release_date < ?", r.timezoneHelper.DateOf(start), r.timezoneHelper.DateOf(end)).
+		Preload("Movie").
+		Order("release_date ASC, movie_id ASC").
+		Find(&releases).Error
+	return releases, err
+}
+
+// GetTodayReleases retrieves the releases matching filter that are today
+// Today is determined based on the configured timezone
+// The range is [startOfDay, endOfDay) - start inclusive, end exclusive
+func (r *movieRepository) GetTodayReleases(filter MovieReleaseFilter) ([]*models.MovieReleaseDate, error) {
+	var releases []*models.MovieReleaseDate
+
+	// Use timezone-aware today boundaries
+	start, end := r.timezoneHelper.TodayRange()
+
+	err := r.filteredReleases(filter).
+		Where("release_date >= ? AND release_date < ?", r.timezoneHelper.DateOf(start), r.timezoneHelper.DateOf(end)).
+		Preload("Movie").
+		Order("release_date ASC, movie_id ASC").
+		Find(&releases).Error
+	return releases, err
+}
+
+// filteredReleases builds the release dates query for filter
+func (r *movieRepository) filteredReleases(filter MovieReleaseFilter) *gorm.DB {
+	query := r.db.Model(&models.MovieReleaseDate{})
+	if len(filter.Regions) > 0 {
+		regions := make([]string, len(filter.Regions))
+		for i, region := range filter.Regions {
+			regions[i] = strings.ToUpper(strings.TrimSpace(region))
+		}
+		query = query.Where("region IN ?", regions)
+	}
+	if len(filter.Types) > 0 {
+		query = query.Where("type IN ?", filter.Types)
+	}
+	return query
+}
+
+// Delete deletes a movie and its release dates
+func (r *movieRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("movie_id = ?", id).Delete(&models.MovieReleaseDate{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Movie{}, id).Error
+	})
+}
+
+// Count returns the total number of movies
+func (r *movieRepository) Count() (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Movie{}).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/utils"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupMovieDB(t *testing.T) *gorm.DB {
+	dbName := fmt.Sprintf("file:MovieTest_%d?mode=memory&cache=shared", time.Now().UnixNano())
+	db, err := gorm.Open(sqlite.Open(dbName), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+
+	if err := db.AutoMigrate(&models.Movie{}, &models.MovieReleaseDate{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	return db
+}
+
+func TestMovieRepository_ReplaceReleaseDates(t *testing.T) {
+	db := setupMovieDB(t)
+	repo := NewMovieRepository(db)
+
+	movie := &models.Movie{TmdbID: 603, Title: "The Matrix"}
+	if err := repo.Create(movie); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	first := []*models.MovieReleaseDate{
+		{Region: "US", Type: models.ReleaseTypeTheatrical, ReleaseDate: date},
+		{Region: "GB", Type: models.ReleaseTypeTheatrical, ReleaseDate: date},
+	}
+	if err := repo.ReplaceReleaseDates(movie.ID, first); err != nil {
+		t.Fatalf("ReplaceReleaseDates failed: %v", err)
+	}
+
+	second := []*models.MovieReleaseDate{
+		{Region: "US", Type: models.ReleaseTypeDigital, ReleaseDate: date.AddDate(0, 1, 0)},
+	}
+	if err := repo.ReplaceReleaseDates(movie.ID, second); err != nil {
+		t.Fatalf("ReplaceReleaseDates failed: %v", err)
+	}
+
+	stored, err := repo.GetByID(movie.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if len(stored.ReleaseDates) != 1 || stored.ReleaseDates[0].Type != models.ReleaseTypeDigital {
+		t.Errorf("Expected only the digital release, got %+v", stored.ReleaseDates)
+	}
+
+	// Updating the movie leaves its release dates alone
+	stored.Notes = "watch"
+	stored.ReleaseDates = nil
+	if err := repo.Update(stored); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if stored, _ = repo.GetByID(movie.ID); len(stored.ReleaseDates) != 1 {
+		t.Errorf("Expected the release dates to be kept, got %d", len(stored.ReleaseDates))
+	}
+
+	if err := repo.Delete(movie.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	var count int64
+	db.Model(&models.MovieReleaseDate{}).Count(&count)
+	if count != 0 {
+		t.Errorf("Expected release dates to be deleted with the movie, got %d", count)
+	}
+}
+
+func TestMovieRepository_GetReleasesByDateRange(t *testing.T) {
+	db := setupMovieDB(t)
+	repo := NewMovieRepository(db)
+
+	movie := &models.Movie{TmdbID: 603, Title: "The Matrix"}
+	if err := repo.Create(movie); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	releases := []*models.MovieReleaseDate{
+		{Region: "US", Type: models.ReleaseTypePremiere, ReleaseDate: day.AddDate(0, 0, -10)},
+		{Region: "US", Type: models.ReleaseTypeTheatrical, ReleaseDate: day},
+		{Region: "GB", Type: models.ReleaseTypeTheatrical, ReleaseDate: day.AddDate(0, 0, 1)},
+		{Region: "US", Type: models.ReleaseTypeDigital, ReleaseDate: day.AddDate(0, 0, 2)},
+	}
+	if err := repo.ReplaceReleaseDates(movie.ID, releases); err != nil {
+		t.Fatalf("ReplaceReleaseDates failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		filter   MovieReleaseFilter
+		expected int
+	}{
+		{"No filter", MovieReleaseFilter{}, 3},
+		{"Region", MovieReleaseFilter{Regions: []string{"us"}}, 2},
+		{"Type", MovieReleaseFilter{Types: []int{models.ReleaseTypeTheatrical}}, 2},
+		{"Region and type", MovieReleaseFilter{Regions: []string{"US"}, Types: []int{models.ReleaseTypeDigital}}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := repo.GetReleasesByDateRange(day, day.AddDate(0, 0, 2), tt.filter)
+			if err != nil {
+				t.Fatalf("GetReleasesByDateRange failed: %v", err)
+			}
+			if len(found) != tt.expected {
+				t.Fatalf("Expected %d releases, got %d", tt.expected, len(found))
+			}
+			if found[0].Movie == nil || found[0].Movie.Title != "The Matrix" {
+				t.Errorf("Expected the movie to be loaded, got %+v", found[0].Movie)
+			}
+		})
+	}
+}
+
+func TestMovieRepository_GetTodayReleases_Timezone(t *testing.T) {
+	db := setupMovieDB(t)
+	repo := NewMovieRepository(db)
+
+	movie := &models.Movie{TmdbID: 603, Title: "The Matrix"}
+	if err := repo.Create(movie); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	shanghai, _ := time.LoadLocation("Asia/Shanghai")
+	tzHelper := utils.NewTimezoneHelper(shanghai)
+	today := tzHelper.DateOf(time.Now())
+	releases := []*models.MovieReleaseDate{
+		{Region: "US", Type: models.ReleaseTypeTheatrical, ReleaseDate: today},
+		{Region: "US", Type: models.ReleaseTypeDigital, ReleaseDate: today.AddDate(0, 0, 1)},
+	}
+	if err := repo.ReplaceReleaseDates(movie.ID, releases); err != nil {
+		t.Fatalf("ReplaceReleaseDates failed: %v", err)
+	}
+
+	// Release dates are calendar dates, compared with today's date in the timezone
+	found, err := repo.WithTimezone(tzHelper).GetTodayReleases(MovieReleaseFilter{})
+	if err != nil {
+		t.Fatalf("GetTodayReleases failed: %v", err)
+	}
+	if len(found) != 1 || found[0].Type != models.ReleaseTypeTheatrical {
+		t.Errorf("Expected today's theatrical release, got %+v", found)
+	}
+}
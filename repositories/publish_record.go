@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"gorm.io/gorm"
+)
+
+// PublishRecordRepository defines data operations for per-target publish results
+type PublishRecordRepository interface {
+	WithContext(ctx context.Context) PublishRecordRepository
+	Create(record *models.PublishRecord) error
+	GetLatestSuccessful(target, slug string) (*models.PublishRecord, error)
+	ListRecent(target string, limit int) ([]*models.PublishRecord, error)
+}
+
+type publishRecordRepository struct {
+	db *gorm.DB
+}
+
+// NewPublishRecordRepository creates a new publish record repository instance
+func NewPublishRecordRepository(db *gorm.DB) PublishRecordRepository {
+	return &publishRecordRepository{db: db}
+}
+
+// WithContext returns a repository whose queries are bound to ctx
+func (r *publishRecordRepository) WithContext(ctx context.Context) PublishRecordRepository {
+	return &publishRecordRepository{db: r.db.WithContext(ctx)}
+}
+
+// Create creates a new publish record
+func (r *publishRecordRepository) Create(record *models.PublishRecord) error {
+	return r.db.Create(record).Error
+}
+
+// GetLatestSuccessful retrieves the latest successful publish of a slug to target
+func (r *publishRecordRepository) GetLatestSuccessful(target, slug string) (*models.PublishRecord, error) {
+	var record models.PublishRecord
+	err := r.db.Where("target = ? AND slug = ? AND success = ?", target, slug, true).
+		Order("id DESC").
+		First(&record).Error
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// ListRecent retrieves the latest publish records, newest first.
+// An empty target lists the records of all targets.
+func (r *publishRecordRepository) ListRecent(target string, limit int) ([]*models.PublishRecord, error) {
+	var records []*models.PublishRecord
+	query := r.db.Order("id DESC")
+	if target != "" {
+		query = query.Where("target = ?", target)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&records).Error
+	return records, err
+}
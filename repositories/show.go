@@ -1,35 +1,63 @@
 package repositories
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/xc9973/go-tmdb-crawler/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ShowRepository defines the interface for show data operations
 type ShowRepository interface {
+	WithContext(ctx context.Context) ShowRepository
 	Create(show *models.Show) error
 	CreateBatch(shows []*models.Show) error
 	GetByID(id uint) (*models.Show, error)
 	GetByTmdbID(tmdbID int) (*models.Show, error)
 	GetByTmdbIDs(tmdbIDs []int) ([]*models.Show, error)
+	GetByImdbID(imdbID string) (*models.Show, error)
+	GetByTvdbID(tvdbID int) (*models.Show, error)
 	List(page, pageSize int) ([]*models.Show, int64, error)
 	ListByStatus(status string, page, pageSize int) ([]*models.Show, int64, error)
 	ListFiltered(status, search string, page, pageSize int) ([]*models.Show, int64, error)
+	ListByFilter(filter ShowFilter, page, pageSize int) ([]*models.Show, int64, error)
+	CountGenres(filter ShowFilter) ([]models.GenreCount, error)
 	ListAll() ([]*models.Show, error)
+	ListActive() ([]*models.Show, error)
 	ListReturning() ([]*models.Show, error)
 	ListExpired() ([]*models.Show, error)
 	ListNeedRefresh() ([]*models.Show, error)
 	Update(show *models.Show) error
 	UpdateBatch(shows []*models.Show) error
+	UpdateChangeCursor(ids []uint, cursor time.Time) error
+	SetArchived(ids []uint, archivedAt *time.Time) error
+	ReplaceGenres(showID uint, genres []models.Genre) error
+	ConvertLegacyGenres() (int, error)
 	Delete(id uint) error
 	Count() (int64, error)
 	CountByStatus(status string) (int64, error)
 	Search(query string, page, pageSize int) ([]*models.Show, int64, error)
 }
 
+// ShowFilter narrows a show list; empty fields are ignored
+type ShowFilter struct {
+	Status string
+	Search string
+	// Network matches a network by TMDB ID ("49") or case-insensitive name ("HBO")
+	Network string
+	// Country matches one of the ISO 3166-1 origin countries ("US")
+	Country string
+	// Genre matches a genre by TMDB ID ("18") or case-insensitive name ("Drama")
+	Genre string
+}
+
 type showRepository struct {
 	db *gorm.DB
 }
@@ -39,6 +67,11 @@ func NewShowRepository(db *gorm.DB) ShowRepository {
 	return &showRepository{db: db}
 }
 
+// WithContext returns a repository whose queries are bound to ctx
+func (r *showRepository) WithContext(ctx context.Context) ShowRepository {
+	return &showRepository{db: r.db.WithContext(ctx)}
+}
+
 // Create creates a new show
 func (r *showRepository) Create(show *models.Show) error {
 	return r.db.Create(show).Error
@@ -55,7 +88,7 @@ func (r *showRepository) CreateBatch(shows []*models.Show) error {
 // GetByID retrieves a show by ID
 func (r *showRepository) GetByID(id uint) (*models.Show, error) {
 	var show models.Show
-	err := r.db.First(&show, id).Error
+	err := r.db.Preload("Genres").First(&show, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -79,32 +112,64 @@ func (r *showRepository) GetByTmdbIDs(tmdbIDs []int) ([]*models.Show, error) {
 	return shows, err
 }
 
+// GetByImdbID retrieves a show by IMDb ID (e.g. tt0903747)
+func (r *showRepository) GetByImdbID(imdbID string) (*models.Show, error) {
+	var show models.Show
+	err := r.db.Where("imdb_id = ?", imdbID).First(&show).Error
+	if err != nil {
+		return nil, err
+	}
+	return &show, nil
+}
+
+// GetByTvdbID retrieves a show by TVDB ID
+func (r *showRepository) GetByTvdbID(tvdbID int) (*models.Show, error) {
+	var show models.Show
+	err := r.db.Where("tvdb_id = ?", tvdbID).First(&show).Error
+	if err != nil {
+		return nil, err
+	}
+	return &show, nil
+}
+
 // List retrieves shows with pagination
 func (r *showRepository) List(page, pageSize int) ([]*models.Show, int64, error) {
-	return r.listWithFilters("", "", page, pageSize)
+	return r.listWithFilters(ShowFilter{}, page, pageSize)
 }
 
 // ListByStatus retrieves shows with pagination filtered by status
 func (r *showRepository) ListByStatus(status string, page, pageSize int) ([]*models.Show, int64, error) {
-	return r.listWithFilters(status, "", page, pageSize)
+	return r.listWithFilters(ShowFilter{Status: status}, page, pageSize)
 }
 
 // ListFiltered retrieves shows with pagination filtered by status and search keyword
 func (r *showRepository) ListFiltered(status, search string, page, pageSize int) ([]*models.Show, int64, error) {
-	return r.listWithFilters(status, search, page, pageSize)
+	return r.listWithFilters(ShowFilter{Status: status, Search: search}, page, pageSize)
 }
 
-// ListAll retrieves all shows
+// ListByFilter retrieves shows with pagination matching all fields of filter
+func (r *showRepository) ListByFilter(filter ShowFilter, page, pageSize int) ([]*models.Show, int64, error) {
+	return r.listWithFilters(filter, page, pageSize)
+}
+
+// ListAll retrieves all shows with their genres
 func (r *showRepository) ListAll() ([]*models.Show, error) {
 	var shows []*models.Show
-	err := r.db.Find(&shows).Error
+	err := r.db.Preload("Genres").Find(&shows).Error
+	return shows, err
+}
+
+// ListActive retrieves all shows that are not archived
+func (r *showRepository) ListActive() ([]*models.Show, error) {
+	var shows []*models.Show
+	err := r.db.Where("archived_at IS NULL").Find(&shows).Error
 	return shows, err
 }
 
-// ListReturning retrieves all returning/airing shows
+// ListReturning retrieves all returning/airing shows that are not archived
 func (r *showRepository) ListReturning() ([]*models.Show, error) {
 	var shows []*models.Show
-	err := r.db.Where("status = ?", "Returning Series").
+	err := r.db.Where("status = ? AND archived_at IS NULL", "Returning Series").
 		Order("next_air_date ASC").
 		Find(&shows).Error
 	return shows, err
@@ -119,7 +184,8 @@ func (r *showRepository) ListExpired() ([]*models.Show, error) {
 	return shows, err
 }
 
-// ListNeedRefresh retrieves shows that should be refreshed based on status and last crawl time
+// ListNeedRefresh retrieves shows that should be refreshed based on status and last crawl time.
+// Archived shows are never refreshed.
 func (r *showRepository) ListNeedRefresh() ([]*models.Show, error) {
 	var shows []*models.Show
 	twentyFourHoursAgo := time.Now().Add(-24 * time.Hour)
@@ -127,8 +193,9 @@ func (r *showRepository) ListNeedRefresh() ([]*models.Show, error) {
 
 	// Returning series: refresh if older than 24 hours
 	// Ended series: refresh if older than 7 days
-	err := r.db.Where("(status = ? AND (last_crawled_at IS NULL OR last_crawled_at < ?)) OR "+
-		"(status = ? AND (last_crawled_at IS NULL OR last_crawled_at < ?))",
+	err := r.db.Where("archived_at IS NULL AND ("+
+		"(status = ? AND (last_crawled_at IS NULL OR last_crawled_at < ?)) OR "+
+		"(status = ? AND (last_crawled_at IS NULL OR last_crawled_at < ?)))",
 		"Returning Series", twentyFourHoursAgo,
 		"Ended", sevenDaysAgo).
 		Find(&shows).Error
@@ -148,9 +215,98 @@ func (r *showRepository) UpdateBatch(shows []*models.Show) error {
 	return r.db.Save(shows).Error
 }
 
+// UpdateChangeCursor advances the change cursor of the given shows
+func (r *showRepository) UpdateChangeCursor(ids []uint, cursor time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&models.Show{}).
+		Where("id IN ?", ids).
+		UpdateColumn("change_cursor", cursor).Error
+}
+
+// SetArchived archives the given shows, or restores them when archivedAt is nil
+func (r *showRepository) SetArchived(ids []uint, archivedAt *time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&models.Show{}).
+		Where("id IN ?", ids).
+		UpdateColumn("archived_at", archivedAt).Error
+}
+
+// ReplaceGenres sets the genres of a show.
+// Genres are upserted so renamed TMDB genres pick up the new name.
+func (r *showRepository) ReplaceGenres(showID uint, genres []models.Genre) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("show_id = ?", showID).Delete(&models.ShowGenre{}).Error; err != nil {
+			return err
+		}
+		if len(genres) == 0 {
+			return nil
+		}
+
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name"}),
+		}).Create(&genres).Error; err != nil {
+			return err
+		}
+
+		links := make([]models.ShowGenre, 0, len(genres))
+		for _, genre := range genres {
+			links = append(links, models.ShowGenre{ShowID: showID, GenreID: genre.ID})
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error
+	})
+}
+
+// ConvertLegacyGenres moves genres stored as a JSON string in shows.genres
+// into the genres tables and drops the legacy column.
+// It returns the number of converted shows; without the column it does nothing.
+func (r *showRepository) ConvertLegacyGenres() (int, error) {
+	migrator := r.db.Migrator()
+	if !migrator.HasColumn(&models.Show{}, "genres") {
+		return 0, nil
+	}
+
+	var rows []struct {
+		ID     uint
+		Genres string
+	}
+	if err := r.db.Table("shows").
+		Select("id, genres").
+		Where("genres IS NOT NULL AND genres <> ''").
+		Scan(&rows).Error; err != nil {
+		return 0, err
+	}
+
+	converted := 0
+	for _, row := range rows {
+		genres, ok := models.ParseLegacyGenres(row.Genres)
+		if !ok {
+			continue
+		}
+		if err := r.ReplaceGenres(row.ID, genres); err != nil {
+			return converted, fmt.Errorf("failed to convert genres of show %d: %w", row.ID, err)
+		}
+		converted++
+	}
+
+	if err := r.db.Exec("ALTER TABLE shows DROP COLUMN genres").Error; err != nil {
+		return converted, fmt.Errorf("failed to drop legacy genres column: %w", err)
+	}
+	return converted, nil
+}
+
 // Delete deletes a show by ID
 func (r *showRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Show{}, id).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("show_id = ?", id).Delete(&models.ShowGenre{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Show{}, id).Error
+	})
 }
 
 // Count returns the total number of shows
@@ -171,35 +327,104 @@ func (r *showRepository) CountByStatus(status string) (int64, error) {
 
 // Search searches shows by name or original name
 func (r *showRepository) Search(query string, page, pageSize int) ([]*models.Show, int64, error) {
-	return r.listWithFilters("", query, page, pageSize)
+	return r.listWithFilters(ShowFilter{Search: query}, page, pageSize)
 }
 
-func (r *showRepository) listWithFilters(status, search string, page, pageSize int) ([]*models.Show, int64, error) {
+// CountGenres returns the number of shows per genre among the shows matching filter.
+// The genre filter itself is ignored so the counts show every available choice.
+func (r *showRepository) CountGenres(filter ShowFilter) ([]models.GenreCount, error) {
+	filter.Genre = ""
+	matching := r.filteredQuery(filter).Select("id")
+
+	var counts []models.GenreCount
+	err := r.db.Table("genres").
+		Select("genres.id, genres.name, COUNT(show_genres.show_id) AS count").
+		Joins("JOIN show_genres ON show_genres.genre_id = genres.id").
+		Where("show_genres.show_id IN (?)", matching).
+		Group("genres.id, genres.name").
+		Order("count DESC, genres.name ASC").
+		Scan(&counts).Error
+	return counts, err
+}
+
+func (r *showRepository) listWithFilters(filter ShowFilter, page, pageSize int) ([]*models.Show, int64, error) {
 	var shows []*models.Show
 	var total int64
 
+	query := r.filteredQuery(filter)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Preload("Genres").
+		Order("created_at DESC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&shows).Error
+
+	return shows, total, err
+}
+
+// filteredQuery builds the shows query for filter
+func (r *showRepository) filteredQuery(filter ShowFilter) *gorm.DB {
 	query := r.db.Model(&models.Show{})
-	if status != "" {
+	if status := filter.Status; status != "" {
 		query = query.Where("status = ?", status)
 	}
-	if search != "" {
+	if search := filter.Search; search != "" {
 		if r.db.Dialector.Name() == "sqlite" {
-			q := strings.ToLower(search)
-			query = query.Where("LOWER(name) LIKE ? OR LOWER(original_name) LIKE ?", "%"+q+"%", "%"+q+"%")
+			q := "%" + escapeLike(strings.ToLower(search)) + "%"
+			query = query.Where(`LOWER(name) LIKE ? ESCAPE '\' OR LOWER(original_name) LIKE ? ESCAPE '\'`, q, q)
 		} else {
-			query = query.Where("name ILIKE ? OR original_name ILIKE ?", "%"+search+"%", "%"+search+"%")
+			q := "%" + escapeLike(search) + "%"
+			query = query.Where(`name ILIKE ? ESCAPE '\' OR original_name ILIKE ? ESCAPE '\'`, q, q)
 		}
 	}
 
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
+	if network := strings.TrimSpace(filter.Network); network != "" {
+		// Networks are stored as a JSON array of {"id":..,"name":..} objects;
+		// a field is followed by another one or ends its object
+		field := fmt.Sprintf(`"name":%s`, jsonString(network))
+		if id, err := strconv.Atoi(network); err == nil {
+			field = fmt.Sprintf(`"id":%d`, id)
+		}
+		pattern := "%" + escapeLike(strings.ToLower(field))
+		query = query.Where(`(LOWER(networks) LIKE ? ESCAPE '\' OR LOWER(networks) LIKE ? ESCAPE '\')`, pattern+",%", pattern+"}%")
+	}
+	if country := strings.TrimSpace(filter.Country); country != "" {
+		query = query.Where("(',' || origin_country || ',') LIKE ?", "%,"+strings.ToUpper(country)+",%")
+	}
+	if genre := strings.TrimSpace(filter.Genre); genre != "" {
+		query = query.Where("id IN (?)", genreShowIDs(r.db, genre))
 	}
 
-	offset := (page - 1) * pageSize
-	err := query.Order("created_at DESC").
-		Limit(pageSize).
-		Offset(offset).
-		Find(&shows).Error
+	return query
+}
 
-	return shows, total, err
+// escapeLike escapes the LIKE wildcards in s, for patterns used with ESCAPE '\'
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// jsonString returns s encoded as a JSON string as the crawler stores it,
+// without HTML escaping so names like "A&E" match
+func jsonString(s string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	return strings.TrimSpace(buf.String())
+}
+
+// genreShowIDs builds a subquery of the IDs of shows with a genre,
+// given by TMDB ID ("18") or case-insensitive name ("Drama")
+func genreShowIDs(db *gorm.DB, genre string) *gorm.DB {
+	genreShows := db.Table("show_genres").
+		Select("show_genres.show_id").
+		Joins("JOIN genres ON genres.id = show_genres.genre_id")
+	if id, err := strconv.Atoi(genre); err == nil {
+		return genreShows.Where("genres.id = ?", id)
+	}
+	return genreShows.Where("LOWER(genres.name) = ?", strings.ToLower(genre))
 }
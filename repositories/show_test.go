@@ -3,6 +3,7 @@ package repositories
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/xc9973/go-tmdb-crawler/models"
 	"gorm.io/driver/sqlite"
@@ -235,3 +236,226 @@ func TestShowRepository_SearchCaseInsensitivity(t *testing.T) {
 		})
 	}
 }
+
+func TestShowRepository_UpdateChangeCursor(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:TestShowRepository_UpdateChangeCursor?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Show{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	repo := NewShowRepository(db)
+
+	first := &models.Show{TmdbID: 401, Name: "First"}
+	second := &models.Show{TmdbID: 402, Name: "Second"}
+	repo.Create(first)
+	repo.Create(second)
+
+	cursor := time.Date(2026, 1, 15, 8, 0, 0, 0, time.UTC)
+	if err := repo.UpdateChangeCursor([]uint{first.ID}, cursor); err != nil {
+		t.Fatalf("UpdateChangeCursor failed: %v", err)
+	}
+
+	updated, _ := repo.GetByID(first.ID)
+	if updated.ChangeCursor == nil || !updated.ChangeCursor.Equal(cursor) {
+		t.Errorf("Expected cursor %v, got %v", cursor, updated.ChangeCursor)
+	}
+	untouched, _ := repo.GetByID(second.ID)
+	if untouched.ChangeCursor != nil {
+		t.Error("Cursor of other show should not change")
+	}
+
+	if err := repo.UpdateChangeCursor(nil, cursor); err != nil {
+		t.Errorf("Empty update should be a no-op, got %v", err)
+	}
+}
+
+func TestShowRepository_GetByExternalID(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:TestShowRepository_GetByExternalID?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Show{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	repo := NewShowRepository(db)
+
+	repo.Create(&models.Show{TmdbID: 1396, Name: "Breaking Bad", ImdbID: "tt0903747", TvdbID: 81189})
+	repo.Create(&models.Show{TmdbID: 1399, Name: "No IDs"})
+
+	show, err := repo.GetByImdbID("tt0903747")
+	if err != nil || show.TmdbID != 1396 {
+		t.Errorf("Expected show 1396 by IMDb ID, got %v (%v)", show, err)
+	}
+	show, err = repo.GetByTvdbID(81189)
+	if err != nil || show.TmdbID != 1396 {
+		t.Errorf("Expected show 1396 by TVDB ID, got %v (%v)", show, err)
+	}
+	if _, err := repo.GetByImdbID("tt0000000"); err == nil {
+		t.Error("Expected error for unknown IMDb ID")
+	}
+}
+
+func TestShowRepository_Genres(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:TestShowRepository_Genres?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Show{}, &models.Genre{}, &models.ShowGenre{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	repo := NewShowRepository(db)
+
+	drama := models.Genre{ID: 18, Name: "Drama"}
+	crime := models.Genre{ID: 80, Name: "Crime"}
+	comedy := models.Genre{ID: 35, Name: "Comedy"}
+	shows := []*models.Show{
+		{TmdbID: 1, Name: "Breaking Bad", Status: "Ended"},
+		{TmdbID: 2, Name: "The Wire", Status: "Ended"},
+		{TmdbID: 3, Name: "The Office", Status: "Returning Series"},
+	}
+	for _, show := range shows {
+		if err := repo.Create(show); err != nil {
+			t.Fatalf("Failed to create show: %v", err)
+		}
+	}
+	repo.ReplaceGenres(shows[0].ID, []models.Genre{drama, comedy})
+	repo.ReplaceGenres(shows[1].ID, []models.Genre{drama, crime})
+	repo.ReplaceGenres(shows[2].ID, []models.Genre{comedy})
+
+	// Replacing drops genres the show no longer has
+	if err := repo.ReplaceGenres(shows[0].ID, []models.Genre{drama, crime}); err != nil {
+		t.Fatalf("ReplaceGenres failed: %v", err)
+	}
+	show, _ := repo.GetByID(shows[0].ID)
+	if len(show.Genres) != 2 {
+		t.Errorf("Expected 2 genres after replace, got %v", show.Genres)
+	}
+
+	for _, tt := range []struct {
+		filter ShowFilter
+		want   int64
+	}{
+		{ShowFilter{Genre: "drama"}, 2},
+		{ShowFilter{Genre: "35"}, 1},
+		{ShowFilter{Genre: "Crime", Search: "wire"}, 1},
+		{ShowFilter{Genre: "Comedy", Status: "Ended"}, 0},
+	} {
+		if _, total, err := repo.ListByFilter(tt.filter, 1, 10); err != nil || total != tt.want {
+			t.Errorf("ListByFilter(%+v) = %d (%v), want %d", tt.filter, total, err, tt.want)
+		}
+	}
+
+	// Facets count the other filters but ignore the genre filter
+	counts, err := repo.CountGenres(ShowFilter{Status: "Ended", Genre: "Comedy"})
+	if err != nil {
+		t.Fatalf("CountGenres failed: %v", err)
+	}
+	got := make(map[string]int64)
+	for _, count := range counts {
+		got[count.Name] = count.Count
+	}
+	if got["Drama"] != 2 || got["Crime"] != 2 || got["Comedy"] != 0 {
+		t.Errorf("Unexpected genre counts: %v", counts)
+	}
+
+	// Deleting a show removes its genre links
+	if err := repo.Delete(shows[2].ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	var links int64
+	db.Model(&models.ShowGenre{}).Where("show_id = ?", shows[2].ID).Count(&links)
+	if links != 0 {
+		t.Errorf("Expected genre links to be deleted, got %d", links)
+	}
+}
+
+func TestShowRepository_NetworkAndSearchFilters(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:TestShowRepository_NetworkFilters?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Show{}, &models.Genre{}, &models.ShowGenre{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	repo := NewShowRepository(db)
+
+	shows := []*models.Show{
+		// A network stored without its optional fields ends its object after the name
+		{TmdbID: 1, Name: "The Wire", Networks: `[{"id":49,"name":"HBO"}]`},
+		{TmdbID: 2, Name: "Succession", Networks: `[{"id":49,"name":"HBO","logo_path":"/hbo.png","origin_country":"US"}]`},
+		{TmdbID: 3, Name: "Longmire", Networks: `[{"id":129,"name":"A&E","origin_country":"US"}]`},
+		{TmdbID: 4, Name: "100% Human", Networks: `[{"id":490,"name":"HBO Max"}]`},
+		{TmdbID: 5, Name: "Show_One"},
+	}
+	for _, show := range shows {
+		if err := repo.Create(show); err != nil {
+			t.Fatalf("Failed to create show: %v", err)
+		}
+	}
+
+	for _, tt := range []struct {
+		filter ShowFilter
+		want   int64
+	}{
+		{ShowFilter{Network: "hbo"}, 2},
+		{ShowFilter{Network: "49"}, 2},
+		{ShowFilter{Network: "HBO Max"}, 1},
+		{ShowFilter{Network: "a&e"}, 1},
+		{ShowFilter{Network: "H_O"}, 0},
+		{ShowFilter{Network: "%"}, 0},
+		{ShowFilter{Search: "100%"}, 1},
+		{ShowFilter{Search: "%"}, 1},
+		{ShowFilter{Search: "w__e"}, 0},
+		{ShowFilter{Search: "_one"}, 1},
+	} {
+		if _, total, err := repo.ListByFilter(tt.filter, 1, 10); err != nil || total != tt.want {
+			t.Errorf("ListByFilter(%+v) = %d (%v), want %d", tt.filter, total, err, tt.want)
+		}
+	}
+}
+
+func TestShowRepository_ConvertLegacyGenres(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:TestShowRepository_ConvertLegacyGenres?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Show{}, &models.Genre{}, &models.ShowGenre{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	repo := NewShowRepository(db)
+
+	// Nothing to do without the legacy column
+	if converted, err := repo.ConvertLegacyGenres(); err != nil || converted != 0 {
+		t.Fatalf("Expected no conversion, got %d (%v)", converted, err)
+	}
+
+	if err := db.Exec("ALTER TABLE shows ADD COLUMN genres VARCHAR(255)").Error; err != nil {
+		t.Fatalf("Failed to add legacy column: %v", err)
+	}
+	show := &models.Show{TmdbID: 1396, Name: "Breaking Bad"}
+	if err := repo.Create(show); err != nil {
+		t.Fatalf("Failed to create show: %v", err)
+	}
+	db.Exec("UPDATE shows SET genres = ? WHERE id = ?", `[{"id":18,"name":"剧情"},{"id":80,"name":"犯罪"}]`, show.ID)
+
+	converted, err := repo.ConvertLegacyGenres()
+	if err != nil {
+		t.Fatalf("ConvertLegacyGenres failed: %v", err)
+	}
+	if converted != 1 {
+		t.Errorf("Expected 1 converted show, got %d", converted)
+	}
+	if db.Migrator().HasColumn(&models.Show{}, "genres") {
+		t.Error("Expected legacy genres column to be dropped")
+	}
+
+	stored, err := repo.GetByID(show.ID)
+	if err != nil {
+		t.Fatalf("Show lost after conversion: %v", err)
+	}
+	if len(stored.Genres) != 2 || stored.Genres[0].Name == "" {
+		t.Errorf("Expected 2 converted genres, got %v", stored.Genres)
+	}
+}
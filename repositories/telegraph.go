@@ -13,6 +13,7 @@ type TelegraphPostRepository interface {
 	GetByID(id uint) (*models.TelegraphPost, error)
 	GetByPath(path string) (*models.TelegraphPost, error)
 	GetByContentHash(hash string) (*models.TelegraphPost, error)
+	GetBySlot(slot string) (*models.TelegraphPost, error)
 	GetRecent(limit int) ([]*models.TelegraphPost, error)
 	ListAll() ([]*models.TelegraphPost, error)
 	GetToday() (*models.TelegraphPost, error)
@@ -22,6 +23,8 @@ type TelegraphPostRepository interface {
 	DeleteOld(days int) error
 	Count() (int64, error)
 	CountToday() (int64, error)
+	CreateRevision(revision *models.TelegraphPostRevision) error
+	ListRevisions(postID uint) ([]*models.TelegraphPostRevision, error)
 }
 
 type telegraphPostRepository struct {
@@ -70,6 +73,18 @@ func (r *telegraphPostRepository) GetByContentHash(hash string) (*models.Telegra
 	return &post, nil
 }
 
+// GetBySlot retrieves the latest telegraph post of a publish slot
+func (r *telegraphPostRepository) GetBySlot(slot string) (*models.TelegraphPost, error) {
+	var post models.TelegraphPost
+	err := r.db.Where("slot = ?", slot).
+		Order("id DESC").
+		First(&post).Error
+	if err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
 // GetRecent retrieves recent telegraph posts
 func (r *telegraphPostRepository) GetRecent(limit int) ([]*models.TelegraphPost, error) {
 	var posts []*models.TelegraphPost
@@ -146,3 +161,17 @@ func (r *telegraphPostRepository) CountToday() (int64, error) {
 		Count(&count).Error
 	return count, err
 }
+
+// CreateRevision records a version of a telegraph post
+func (r *telegraphPostRepository) CreateRevision(revision *models.TelegraphPostRevision) error {
+	return r.db.Create(revision).Error
+}
+
+// ListRevisions retrieves the versions of a telegraph post, oldest first
+func (r *telegraphPostRepository) ListRevisions(postID uint) ([]*models.TelegraphPostRevision, error) {
+	var revisions []*models.TelegraphPostRevision
+	err := r.db.Where("post_id = ?", postID).
+		Order("id ASC").
+		Find(&revisions).Error
+	return revisions, err
+}
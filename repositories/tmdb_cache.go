@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TMDBCacheRepository defines data operations for cached TMDB responses
+type TMDBCacheRepository interface {
+	WithContext(ctx context.Context) TMDBCacheRepository
+	Get(key string) (*models.TMDBCacheEntry, error)
+	Upsert(entry *models.TMDBCacheEntry) error
+	DeleteAll() error
+	DeleteExpiredBefore(t time.Time) error
+	Count() (int64, error)
+}
+
+type tmdbCacheRepository struct {
+	db *gorm.DB
+}
+
+// NewTMDBCacheRepository creates a new TMDB cache repository instance
+func NewTMDBCacheRepository(db *gorm.DB) TMDBCacheRepository {
+	return &tmdbCacheRepository{db: db}
+}
+
+// WithContext returns a repository whose queries are bound to ctx
+func (r *tmdbCacheRepository) WithContext(ctx context.Context) TMDBCacheRepository {
+	return &tmdbCacheRepository{db: r.db.WithContext(ctx)}
+}
+
+// Get retrieves a cache entry by key
+func (r *tmdbCacheRepository) Get(key string) (*models.TMDBCacheEntry, error) {
+	var entry models.TMDBCacheEntry
+	if err := r.db.Where("key = ?", key).First(&entry).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Upsert creates or replaces a cache entry
+func (r *tmdbCacheRepository) Upsert(entry *models.TMDBCacheEntry) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"body", "etag", "last_modified", "expires_at", "updated_at"}),
+	}).Create(entry).Error
+}
+
+// DeleteAll removes all cache entries
+func (r *tmdbCacheRepository) DeleteAll() error {
+	return r.db.Where("1 = 1").Delete(&models.TMDBCacheEntry{}).Error
+}
+
+// DeleteExpiredBefore removes entries that expired before t
+func (r *tmdbCacheRepository) DeleteExpiredBefore(t time.Time) error {
+	return r.db.Where("expires_at < ?", t).Delete(&models.TMDBCacheEntry{}).Error
+}
+
+// Count returns the number of cache entries
+func (r *tmdbCacheRepository) Count() (int64, error) {
+	var count int64
+	err := r.db.Model(&models.TMDBCacheEntry{}).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTMDBCacheDB(t *testing.T) *gorm.DB {
+	dbName := fmt.Sprintf("file:TMDBCacheTest_%d?mode=memory&cache=shared", time.Now().UnixNano())
+	db, err := gorm.Open(sqlite.Open(dbName), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+
+	if err := db.AutoMigrate(&models.TMDBCacheEntry{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	return db
+}
+
+func TestTMDBCacheRepository_UpsertAndGet(t *testing.T) {
+	db := setupTMDBCacheDB(t)
+	repo := NewTMDBCacheRepository(db)
+
+	entry := &models.TMDBCacheEntry{
+		Key:       "/tv/1?language=zh-CN",
+		Body:      []byte(`{"id":1}`),
+		ETag:      `"v1"`,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	if err := repo.Upsert(entry); err != nil {
+		t.Fatalf("Failed to insert entry: %v", err)
+	}
+
+	// Upserting the same key replaces the entry
+	entry = &models.TMDBCacheEntry{
+		Key:       "/tv/1?language=zh-CN",
+		Body:      []byte(`{"id":1,"name":"updated"}`),
+		ETag:      `"v2"`,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	if err := repo.Upsert(entry); err != nil {
+		t.Fatalf("Failed to replace entry: %v", err)
+	}
+
+	got, err := repo.Get("/tv/1?language=zh-CN")
+	if err != nil {
+		t.Fatalf("Failed to get entry: %v", err)
+	}
+	if string(got.Body) != `{"id":1,"name":"updated"}` {
+		t.Errorf("Expected updated body, got %s", got.Body)
+	}
+	if got.ETag != `"v2"` {
+		t.Errorf("Expected ETag \"v2\", got %s", got.ETag)
+	}
+
+	count, err := repo.Count()
+	if err != nil {
+		t.Fatalf("Failed to count entries: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 entry, got %d", count)
+	}
+
+	if _, err := repo.Get("/tv/2?language=zh-CN"); err == nil {
+		t.Error("Expected error for missing key")
+	}
+}
+
+func TestTMDBCacheRepository_Delete(t *testing.T) {
+	db := setupTMDBCacheDB(t)
+	repo := NewTMDBCacheRepository(db)
+
+	now := time.Now()
+	for i, expiresAt := range []time.Time{now.Add(-2 * time.Hour), now.Add(time.Hour)} {
+		entry := &models.TMDBCacheEntry{
+			Key:       fmt.Sprintf("/tv/%d", i),
+			Body:      []byte(`{}`),
+			ExpiresAt: expiresAt,
+		}
+		if err := repo.Upsert(entry); err != nil {
+			t.Fatalf("Failed to insert entry: %v", err)
+		}
+	}
+
+	if err := repo.DeleteExpiredBefore(now.Add(-time.Hour)); err != nil {
+		t.Fatalf("Failed to delete expired entries: %v", err)
+	}
+	if count, _ := repo.Count(); count != 1 {
+		t.Errorf("Expected 1 entry after deleting expired, got %d", count)
+	}
+
+	if err := repo.DeleteAll(); err != nil {
+		t.Fatalf("Failed to delete all entries: %v", err)
+	}
+	if count, _ := repo.Count(); count != 0 {
+		t.Errorf("Expected 0 entries, got %d", count)
+	}
+}
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"gorm.io/gorm"
+)
+
+// TMDBListRepository defines data operations for followed TMDB lists
+type TMDBListRepository interface {
+	WithContext(ctx context.Context) TMDBListRepository
+	Create(list *models.TMDBList) error
+	Update(list *models.TMDBList) error
+	GetByID(id uint) (*models.TMDBList, error)
+	GetByListID(listID int) (*models.TMDBList, error)
+	ListAll() ([]*models.TMDBList, error)
+	ListEnabled() ([]*models.TMDBList, error)
+	Delete(id uint) error
+}
+
+type tmdbListRepository struct {
+	db *gorm.DB
+}
+
+// NewTMDBListRepository creates a new TMDB list repository instance
+func NewTMDBListRepository(db *gorm.DB) TMDBListRepository {
+	return &tmdbListRepository{db: db}
+}
+
+// WithContext returns a repository whose queries are bound to ctx
+func (r *tmdbListRepository) WithContext(ctx context.Context) TMDBListRepository {
+	return &tmdbListRepository{db: r.db.WithContext(ctx)}
+}
+
+// Create creates a new TMDB list
+func (r *tmdbListRepository) Create(list *models.TMDBList) error {
+	return r.db.Create(list).Error
+}
+
+// Update updates a TMDB list
+func (r *tmdbListRepository) Update(list *models.TMDBList) error {
+	return r.db.Save(list).Error
+}
+
+// GetByID retrieves a TMDB list by ID
+func (r *tmdbListRepository) GetByID(id uint) (*models.TMDBList, error) {
+	var list models.TMDBList
+	if err := r.db.First(&list, id).Error; err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// GetByListID retrieves a TMDB list by its TMDB list ID
+func (r *tmdbListRepository) GetByListID(listID int) (*models.TMDBList, error) {
+	var list models.TMDBList
+	if err := r.db.Where("list_id = ?", listID).First(&list).Error; err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// ListAll retrieves all TMDB lists
+func (r *tmdbListRepository) ListAll() ([]*models.TMDBList, error) {
+	var lists []*models.TMDBList
+	err := r.db.Order("id ASC").Find(&lists).Error
+	return lists, err
+}
+
+// ListEnabled retrieves the TMDB lists that are synced
+func (r *tmdbListRepository) ListEnabled() ([]*models.TMDBList, error) {
+	var lists []*models.TMDBList
+	err := r.db.Where("enabled = ?", true).Order("id ASC").Find(&lists).Error
+	return lists, err
+}
+
+// Delete deletes a TMDB list; its shows stay followed
+func (r *tmdbListRepository) Delete(id uint) error {
+	return r.db.Delete(&models.TMDBList{}, id).Error
+}
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"gorm.io/gorm"
+)
+
+// WebhookRepository defines data operations for webhook endpoints and their deliveries
+type WebhookRepository interface {
+	WithContext(ctx context.Context) WebhookRepository
+	CreateEndpoint(endpoint *models.WebhookEndpoint) error
+	GetEndpoint(id uint) (*models.WebhookEndpoint, error)
+	ListEndpoints() ([]*models.WebhookEndpoint, error)
+	ListEnabledEndpoints() ([]*models.WebhookEndpoint, error)
+	UpdateEndpoint(endpoint *models.WebhookEndpoint) error
+	DeleteEndpoint(id uint) error
+	CreateDelivery(delivery *models.WebhookDelivery) error
+	UpdateDelivery(delivery *models.WebhookDelivery) error
+	ListDeliveries(endpointID uint, limit int) ([]*models.WebhookDelivery, error)
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository creates a new webhook repository instance
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+// WithContext returns a repository whose queries are bound to ctx
+func (r *webhookRepository) WithContext(ctx context.Context) WebhookRepository {
+	return &webhookRepository{db: r.db.WithContext(ctx)}
+}
+
+// CreateEndpoint creates a new webhook endpoint
+func (r *webhookRepository) CreateEndpoint(endpoint *models.WebhookEndpoint) error {
+	return r.db.Create(endpoint).Error
+}
+
+// GetEndpoint retrieves a webhook endpoint by ID
+func (r *webhookRepository) GetEndpoint(id uint) (*models.WebhookEndpoint, error) {
+	var endpoint models.WebhookEndpoint
+	if err := r.db.First(&endpoint, id).Error; err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+// ListEndpoints retrieves all webhook endpoints
+func (r *webhookRepository) ListEndpoints() ([]*models.WebhookEndpoint, error) {
+	var endpoints []*models.WebhookEndpoint
+	err := r.db.Order("id ASC").Find(&endpoints).Error
+	return endpoints, err
+}
+
+// ListEnabledEndpoints retrieves the webhook endpoints that receive events
+func (r *webhookRepository) ListEnabledEndpoints() ([]*models.WebhookEndpoint, error) {
+	var endpoints []*models.WebhookEndpoint
+	err := r.db.Where("enabled = ?", true).Order("id ASC").Find(&endpoints).Error
+	return endpoints, err
+}
+
+// UpdateEndpoint updates a webhook endpoint
+func (r *webhookRepository) UpdateEndpoint(endpoint *models.WebhookEndpoint) error {
+	return r.db.Save(endpoint).Error
+}
+
+// DeleteEndpoint deletes a webhook endpoint and its delivery log
+func (r *webhookRepository) DeleteEndpoint(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.WebhookEndpoint{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("endpoint_id = ?", id).Delete(&models.WebhookDelivery{}).Error
+	})
+}
+
+// CreateDelivery creates a new webhook delivery record
+func (r *webhookRepository) CreateDelivery(delivery *models.WebhookDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+// UpdateDelivery updates a webhook delivery record
+func (r *webhookRepository) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	return r.db.Save(delivery).Error
+}
+
+// ListDeliveries retrieves the latest deliveries to an endpoint, newest first
+func (r *webhookRepository) ListDeliveries(endpointID uint, limit int) ([]*models.WebhookDelivery, error) {
+	var deliveries []*models.WebhookDelivery
+	query := r.db.Where("endpoint_id = ?", endpointID).Order("id DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&deliveries).Error
+	return deliveries, err
+}
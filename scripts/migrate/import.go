@@ -55,7 +55,7 @@ func main() {
 	}
 
 	// 自动迁移表结构
-	if err := db.AutoMigrate(&models.Show{}, &models.Episode{}); err != nil {
+	if err := db.AutoMigrate(&models.Show{}, &models.Genre{}, &models.ShowGenre{}, &models.Episode{}); err != nil {
 		fmt.Printf("错误: 数据库迁移失败: %v\n", err)
 		os.Exit(1)
 	}
@@ -155,7 +155,6 @@ func importShows(showRepo repositories.ShowRepository, stats *MigrationStats) er
 			Overview:     getFieldValue(record, colIndex, "简介"),
 			PosterPath:   getFieldValue(record, colIndex, "海报路径"),
 			BackdropPath: getFieldValue(record, colIndex, "背景路径"),
-			Genres:       getFieldValue(record, colIndex, "类型"),
 		}
 
 		// 解析评分
@@ -172,6 +171,13 @@ func importShows(showRepo repositories.ShowRepository, stats *MigrationStats) er
 			continue
 		}
 
+		// 关联类型 (TMDB JSON 格式, 其他内容由下次爬取补全)
+		if genres, ok := models.ParseLegacyGenres(getFieldValue(record, colIndex, "类型")); ok {
+			if err := showRepo.ReplaceGenres(show.ID, genres); err != nil {
+				fmt.Printf("  警告: 关联类型失败: %v\n", err)
+			}
+		}
+
 		fmt.Printf("  ✅ 成功导入: %s (ID: %d)\n", show.Name, show.ID)
 		stats.ShowsSuccess++
 	}
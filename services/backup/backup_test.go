@@ -0,0 +1,106 @@
+package backup
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/repositories"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupBackupTest creates a backup service on an empty database
+func setupBackupTest(t *testing.T) (Service, repositories.ShowRepository) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "backup.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Show{}, &models.Genre{}, &models.ShowGenre{}, &models.Episode{},
+		&models.CrawlLog{}, &models.TelegraphPost{}, &models.TelegraphPostRevision{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	showRepo := repositories.NewShowRepository(db)
+	service := NewService(db, showRepo, repositories.NewEpisodeRepository(db),
+		repositories.NewCrawlLogRepository(db), repositories.NewTelegraphPostRepository(db))
+	return service, showRepo
+}
+
+func TestService_ExportImportGenres(t *testing.T) {
+	source, sourceShows := setupBackupTest(t)
+	show := &models.Show{TmdbID: 1396, Name: "Breaking Bad"}
+	if err := sourceShows.Create(show); err != nil {
+		t.Fatalf("Failed to create show: %v", err)
+	}
+	if err := sourceShows.ReplaceGenres(show.ID, []models.Genre{{ID: 18, Name: "Drama"}, {ID: 80, Name: "Crime"}}); err != nil {
+		t.Fatalf("Failed to link genres: %v", err)
+	}
+
+	exported, err := source.Export()
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	data, err := json.Marshal(exported)
+	if err != nil {
+		t.Fatalf("Failed to encode backup: %v", err)
+	}
+
+	// A restore replaces the genre links of the target, as it does its shows
+	target, targetShows := setupBackupTest(t)
+	stale := &models.Show{TmdbID: 2, Name: "Stale"}
+	if err := targetShows.Create(stale); err != nil {
+		t.Fatalf("Failed to create show: %v", err)
+	}
+	if err := targetShows.ReplaceGenres(stale.ID, []models.Genre{{ID: 35, Name: "Comedy"}}); err != nil {
+		t.Fatalf("Failed to link genres: %v", err)
+	}
+
+	var backup models.BackupExport
+	if err := json.Unmarshal(data, &backup); err != nil {
+		t.Fatalf("Failed to decode backup: %v", err)
+	}
+	if _, err := target.Import(&backup, ImportModeReplace); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	restored, err := targetShows.GetByID(show.ID)
+	if err != nil {
+		t.Fatalf("Failed to get restored show: %v", err)
+	}
+	if len(restored.Genres) != 2 {
+		t.Errorf("Expected the two exported genres to be restored, got %+v", restored.Genres)
+	}
+	if counts, _ := targetShows.CountGenres(repositories.ShowFilter{}); len(counts) != 2 {
+		t.Errorf("Expected only the restored genre links, got %+v", counts)
+	}
+}
+
+func TestService_ImportLegacyGenres(t *testing.T) {
+	service, showRepo := setupBackupTest(t)
+
+	// Backups made before the genres table store the genres as a JSON string
+	data := `{"version":"1.0","data":{"shows":[
+		{"id":7,"tmdb_id":1399,"name":"Game of Thrones","genres":"[{\"id\":10765,\"name\":\"Sci-Fi & Fantasy\"}]"},
+		{"id":8,"tmdb_id":1400,"name":"Seinfeld","genres":""}
+	]}}`
+	var backup models.BackupExport
+	if err := json.Unmarshal([]byte(data), &backup); err != nil {
+		t.Fatalf("Failed to decode legacy backup: %v", err)
+	}
+	if _, err := service.Import(&backup, ImportModeMerge); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	show, err := showRepo.GetByID(7)
+	if err != nil {
+		t.Fatalf("Failed to get imported show: %v", err)
+	}
+	if len(show.Genres) != 1 || show.Genres[0].ID != 10765 || show.Genres[0].Name != "Sci-Fi & Fantasy" {
+		t.Errorf("Expected the legacy genres to be linked, got %+v", show.Genres)
+	}
+	if show, err := showRepo.GetByID(8); err != nil || len(show.Genres) != 0 {
+		t.Errorf("Expected show 8 without genres, got %+v, %v", show, err)
+	}
+}
@@ -66,10 +66,10 @@ func (s *service) Export() (*models.BackupExport, error) {
 }
 
 // Helper functions to convert pointer slices to value slices
-func convertShowsToSlice(shows []*models.Show) []models.Show {
-	result := make([]models.Show, len(shows))
+func convertShowsToSlice(shows []*models.Show) []models.BackupShow {
+	result := make([]models.BackupShow, len(shows))
 	for i, s := range shows {
-		result[i] = *s
+		result[i] = models.BackupShow{Show: *s, Genres: s.Genres}
 	}
 	return result
 }
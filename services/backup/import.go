@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/repositories"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -92,27 +93,39 @@ func (s *service) clearAllTables(tx *gorm.DB) error {
 	if err := tx.Where("1 = 1").Delete(&models.Episode{}).Error; err != nil {
 		return fmt.Errorf("failed to clear episodes: %w", err)
 	}
+	if err := tx.Where("1 = 1").Delete(&models.TelegraphPostRevision{}).Error; err != nil {
+		return fmt.Errorf("failed to clear telegraph_post_revisions: %w", err)
+	}
 	if err := tx.Where("1 = 1").Delete(&models.TelegraphPost{}).Error; err != nil {
 		return fmt.Errorf("failed to clear telegraph_posts: %w", err)
 	}
+	if err := tx.Where("1 = 1").Delete(&models.ShowGenre{}).Error; err != nil {
+		return fmt.Errorf("failed to clear show_genres: %w", err)
+	}
 	if err := tx.Where("1 = 1").Delete(&models.Show{}).Error; err != nil {
 		return fmt.Errorf("failed to clear shows: %w", err)
 	}
 	return nil
 }
 
-// importShows imports shows with explicit ID handling
-func (s *service) importShows(tx *gorm.DB, shows []models.Show, mode ImportMode) (int, int, error) {
+// importShows imports shows with explicit ID handling, linking their genres
+func (s *service) importShows(tx *gorm.DB, shows []models.BackupShow, mode ImportMode) (int, int, error) {
 	if len(shows) == 0 {
 		return 0, 0, nil
 	}
 
+	showRepo := repositories.NewShowRepository(tx)
+
 	if mode == ImportModeReplace {
 		// Direct import with IDs (table is empty)
-		for _, show := range shows {
-			if err := tx.Create(&show).Error; err != nil {
+		for _, backupShow := range shows {
+			show := backupShow.Show
+			if err := tx.Omit("Genres").Create(&show).Error; err != nil {
 				return 0, 0, err
 			}
+			if err := showRepo.ReplaceGenres(show.ID, backupShow.Genres); err != nil {
+				return 0, 0, fmt.Errorf("failed to link genres of show %d: %w", show.ID, err)
+			}
 		}
 		return len(shows), 0, nil
 	}
@@ -121,20 +134,24 @@ func (s *service) importShows(tx *gorm.DB, shows []models.Show, mode ImportMode)
 	imported := 0
 	conflicts := 0
 
-	for _, show := range shows {
+	for _, backupShow := range shows {
+		show := backupShow.Show
 		result := tx.Clauses(clause.OnConflict{
 			Columns:   []clause.Column{{Name: "id"}},
 			DoNothing: true,
-		}).Create(&show)
+		}).Omit("Genres").Create(&show)
 
 		if result.Error != nil {
 			return 0, 0, result.Error
 		}
 		if result.RowsAffected == 0 {
 			conflicts++
-		} else {
-			imported++
+			continue
+		}
+		if err := showRepo.ReplaceGenres(show.ID, backupShow.Genres); err != nil {
+			return 0, 0, fmt.Errorf("failed to link genres of show %d: %w", show.ID, err)
 		}
+		imported++
 	}
 
 	return imported, conflicts, nil
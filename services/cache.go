@@ -237,6 +237,9 @@ var (
 
 	// SearchCacheKeyBuilder builds cache keys for search results
 	SearchCacheKeyBuilder = NewCacheKeyBuilder("search")
+
+	// MovieCacheKeyBuilder builds cache keys for movies
+	MovieCacheKeyBuilder = NewCacheKeyBuilder("movie")
 )
 
 // Common TTL values
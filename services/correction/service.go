@@ -1,6 +1,7 @@
 package correction
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"sync"
@@ -12,7 +13,12 @@ import (
 
 // Crawler defines the interface for crawler operations
 type Crawler interface {
-	CrawlShow(tmdbID int) error
+	CrawlShow(ctx context.Context, tmdbID int) error
+}
+
+// EventEmitter receives the stale shows found by a detection run
+type EventEmitter interface {
+	Emit(event string, data interface{})
 }
 
 // Service orchestrates the correction detection and refresh process
@@ -22,6 +28,7 @@ type Service struct {
 	taskRepo    repositories.CrawlTaskRepository
 	crawler     Crawler
 	detector    *Detector
+	events      EventEmitter
 	lastResult  *DetectionResult
 	resultMutex sync.RWMutex
 }
@@ -43,6 +50,11 @@ func NewService(
 	}
 }
 
+// SetEventEmitter reports every stale show a correction task is created for to events
+func (s *Service) SetEventEmitter(events EventEmitter) {
+	s.events = events
+}
+
 // GetLastDetectionResult returns the cached detection result
 func (s *Service) GetLastDetectionResult() *DetectionResult {
 	s.resultMutex.RLock()
@@ -95,6 +107,9 @@ func (s *Service) RunDetection() (*DetectionResult, error) {
 			continue
 		}
 		result.TasksCreated++
+		if s.events != nil {
+			s.events.Emit(models.WebhookEventStaleDetected, stale)
+		}
 	}
 
 	result.Duration = time.Since(startTime)
@@ -121,9 +136,10 @@ func (s *Service) analyzeShow(show *models.Show) (*StaleShowInfo, error) {
 	}
 
 	// Extract air dates
+	// Specials air irregularly and would distort the interval pattern
 	dates := make([]time.Time, 0, len(episodes))
 	for _, ep := range episodes {
-		if ep.AirDate != nil {
+		if ep.AirDate != nil && !ep.IsSpecial() {
 			dates = append(dates, *ep.AirDate)
 		}
 	}
@@ -184,8 +200,8 @@ func (s *Service) createCorrectionTask(stale *StaleShowInfo) error {
 }
 
 // RefreshShow manually refreshes a specific show (for immediate correction)
-func (s *Service) RefreshShow(showID uint, tmdbID int) error {
-	return s.crawler.CrawlShow(tmdbID)
+func (s *Service) RefreshShow(ctx context.Context, showID uint, tmdbID int) error {
+	return s.crawler.CrawlShow(ctx, tmdbID)
 }
 
 // ClearStaleFlag removes the stale_detected_at flag from a show
@@ -0,0 +1,27 @@
+package services
+
+import "context"
+
+// CrawlProgress receives progress updates from batch crawls.
+// Methods may be called concurrently from the crawl workers.
+type CrawlProgress interface {
+	// BatchStarted is called before crawling with the TMDB IDs of the batch
+	BatchStarted(tmdbIDs []int)
+	// ShowStarted is called when a worker starts crawling a show
+	ShowStarted(tmdbID int, name string)
+	// ShowFinished is called after a show was crawled, failed or cancelled
+	ShowFinished(result *CrawlResult)
+}
+
+type crawlProgressKey struct{}
+
+// WithCrawlProgress returns a context that reports batch crawl progress to progress
+func WithCrawlProgress(ctx context.Context, progress CrawlProgress) context.Context {
+	return context.WithValue(ctx, crawlProgressKey{}, progress)
+}
+
+// crawlProgressFrom returns the progress reporter carried by ctx, or nil
+func crawlProgressFrom(ctx context.Context) CrawlProgress {
+	progress, _ := ctx.Value(crawlProgressKey{}).(CrawlProgress)
+	return progress
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/xc9973/go-tmdb-crawler/models"
+)
+
+const (
+	showCrawlSuccess = "success"
+	showCrawlFailed  = "failed"
+	showCrawlSkipped = "skipped"
+)
+
+// CrawlSummary aggregates the per-show results of a batch crawl
+type CrawlSummary struct {
+	Total           int                 `json:"total"`
+	Succeeded       int                 `json:"succeeded"`
+	Failed          int                 `json:"failed"`
+	Skipped         int                 `json:"skipped"`
+	EpisodesAdded   int                 `json:"episodes_added"`
+	EpisodesUpdated int                 `json:"episodes_updated"`
+	Shows           []*ShowCrawlSummary `json:"shows"`
+}
+
+// ShowCrawlSummary is the outcome of crawling a single show
+// Status: success/failed/skipped
+type ShowCrawlSummary struct {
+	TmdbID          int    `json:"tmdb_id"`
+	Name            string `json:"name,omitempty"`
+	Status          string `json:"status"`
+	EpisodesAdded   int    `json:"episodes_added"`
+	EpisodesUpdated int    `json:"episodes_updated"`
+	Error           string `json:"error,omitempty"`
+	DurationMs      int64  `json:"duration_ms"`
+}
+
+// NewCrawlSummary creates an empty crawl summary
+func NewCrawlSummary() *CrawlSummary {
+	return &CrawlSummary{Shows: make([]*ShowCrawlSummary, 0)}
+}
+
+// AddResult records the result of crawling a show
+func (s *CrawlSummary) AddResult(show *models.Show, result *CrawlResult) {
+	entry := &ShowCrawlSummary{
+		TmdbID:          result.TmdbID,
+		EpisodesAdded:   result.EpisodesAdded,
+		EpisodesUpdated: result.EpisodesUpdated,
+		DurationMs:      result.Duration.Milliseconds(),
+	}
+	if show != nil {
+		entry.Name = show.Name
+	}
+
+	if result.Cancelled {
+		entry.Status = showCrawlSkipped
+		entry.Error = "cancelled"
+		s.Skipped++
+	} else if result.Success {
+		entry.Status = showCrawlSuccess
+		s.Succeeded++
+		s.EpisodesAdded += result.EpisodesAdded
+		s.EpisodesUpdated += result.EpisodesUpdated
+	} else {
+		entry.Status = showCrawlFailed
+		if result.Error != nil {
+			entry.Error = result.Error.Error()
+		}
+		s.Failed++
+	}
+
+	s.Total++
+	s.Shows = append(s.Shows, entry)
+}
+
+// AddSkipped records a show that was not crawled
+func (s *CrawlSummary) AddSkipped(show *models.Show, reason string) {
+	s.Total++
+	s.Skipped++
+	s.Shows = append(s.Shows, &ShowCrawlSummary{
+		TmdbID: show.TmdbID,
+		Name:   show.Name,
+		Status: showCrawlSkipped,
+		Error:  reason,
+	})
+}
+
+// Merge appends the results of other, e.g. those of the run that resumed a task
+func (s *CrawlSummary) Merge(other *CrawlSummary) {
+	if other == nil {
+		return
+	}
+	s.Total += other.Total
+	s.Succeeded += other.Succeeded
+	s.Failed += other.Failed
+	s.Skipped += other.Skipped
+	s.EpisodesAdded += other.EpisodesAdded
+	s.EpisodesUpdated += other.EpisodesUpdated
+	s.Shows = append(s.Shows, other.Shows...)
+}
+
+// Err returns an error describing the failed shows, or nil if none failed
+func (s *CrawlSummary) Err() error {
+	if s == nil || s.Failed == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d shows failed to crawl", s.Failed, s.Total)
+}
@@ -1,10 +1,15 @@
 package services
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/xc9973/go-tmdb-crawler/dto"
 	"github.com/xc9973/go-tmdb-crawler/models"
 	"github.com/xc9973/go-tmdb-crawler/repositories"
 )
@@ -16,8 +21,25 @@ type CrawlerService struct {
 	episodeRepo repositories.EpisodeRepository
 	logRepo     repositories.CrawlLogRepository
 	taskRepo    repositories.CrawlTaskRepository
+	changeRepo  repositories.EpisodeChangeRepository
+
+	// concurrency limits how many shows (and seasons per show) are fetched at once
+	concurrency int
+	// fallbackLanguages are tried in order when TMDB has no text in the primary language
+	fallbackLanguages []string
+	// episodeExternalIDs fetches IMDb/TVDB IDs per episode (one extra request per episode)
+	episodeExternalIDs bool
+	// events receives episode changes, show status changes and failed crawls
+	events EventEmitter
+	mu     sync.RWMutex
+
+	// writeMu serializes database writes from concurrent crawls (SQLite allows a single writer)
+	writeMu sync.Mutex
 }
 
+// DefaultCrawlConcurrency is the default number of concurrent crawl workers
+const DefaultCrawlConcurrency = 4
+
 // NewCrawlerService creates a new crawler service instance
 func NewCrawlerService(
 	tmdb *TMDBService,
@@ -25,6 +47,7 @@ func NewCrawlerService(
 	episodeRepo repositories.EpisodeRepository,
 	logRepo repositories.CrawlLogRepository,
 	taskRepo repositories.CrawlTaskRepository,
+	changeRepo repositories.EpisodeChangeRepository,
 ) *CrawlerService {
 	return &CrawlerService{
 		tmdb:        tmdb,
@@ -32,6 +55,72 @@ func NewCrawlerService(
 		episodeRepo: episodeRepo,
 		logRepo:     logRepo,
 		taskRepo:    taskRepo,
+		changeRepo:  changeRepo,
+		concurrency: DefaultCrawlConcurrency,
+	}
+}
+
+// SetConcurrency sets the maximum number of concurrent crawl workers
+func (s *CrawlerService) SetConcurrency(concurrency int) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.concurrency = concurrency
+}
+
+// GetConcurrency returns the maximum number of concurrent crawl workers
+func (s *CrawlerService) GetConcurrency() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.concurrency
+}
+
+// SetFallbackLanguages sets the languages used, in order, for names and
+// overviews that TMDB does not have in the primary language
+func (s *CrawlerService) SetFallbackLanguages(languages []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fallbackLanguages = append([]string(nil), languages...)
+}
+
+// GetFallbackLanguages returns the fallback languages
+func (s *CrawlerService) GetFallbackLanguages() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]string(nil), s.fallbackLanguages...)
+}
+
+// SetEpisodeExternalIDs enables fetching the IMDb and TVDB IDs of every episode.
+// Show external IDs are always fetched with the show details.
+func (s *CrawlerService) SetEpisodeExternalIDs(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.episodeExternalIDs = enabled
+}
+
+// fetchesEpisodeExternalIDs reports whether episode external IDs are fetched
+func (s *CrawlerService) fetchesEpisodeExternalIDs() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.episodeExternalIDs
+}
+
+// SetEventEmitter sets where episode changes, show status changes and failed crawls are reported
+func (s *CrawlerService) SetEventEmitter(events EventEmitter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = events
+}
+
+// emit reports an event, if an event emitter is set
+func (s *CrawlerService) emit(event string, data interface{}) {
+	s.mu.RLock()
+	events := s.events
+	s.mu.RUnlock()
+	if events != nil {
+		events.Emit(event, data)
 	}
 }
 
@@ -42,178 +131,384 @@ func (s *CrawlerService) GetTMDBService() *TMDBService {
 
 // CrawlResult represents the result of a crawl operation
 type CrawlResult struct {
-	TmdbID        int
-	Success       bool
-	EpisodesCount int
-	Error         error
-	Duration      time.Duration
+	TmdbID          int
+	Success         bool
+	EpisodesCount   int
+	EpisodesAdded   int
+	EpisodesUpdated int
+	Cancelled       bool // The crawl was interrupted or not started because the context was done
+	Error           error
+	Duration        time.Duration
 }
 
 // CrawlShow crawls a single show from TMDB
-func (s *CrawlerService) CrawlShow(tmdbID int) error {
+func (s *CrawlerService) CrawlShow(ctx context.Context, tmdbID int) error {
+	_, err := s.crawlShow(ctx, tmdbID)
+	return err
+}
+
+// CrawlNewShow crawls a show that is being added. includeSpecials is set on the new
+// show record before its seasons are fetched, so specials are part of the first crawl.
+// It has no effect on a show that already exists.
+func (s *CrawlerService) CrawlNewShow(ctx context.Context, tmdbID int, includeSpecials bool) error {
+	_, err := s.crawlShowWithOptions(ctx, tmdbID, includeSpecials)
+	return err
+}
+
+// crawlShow crawls a single show from TMDB and reports the episode changes
+func (s *CrawlerService) crawlShow(ctx context.Context, tmdbID int) (episodeStats, error) {
+	return s.crawlShowWithOptions(ctx, tmdbID, false)
+}
+
+// crawlShowWithOptions crawls a single show from TMDB and reports the episode changes.
+// includeSpecials only applies when the show is created by this crawl.
+// TMDB requests and data writes are bound to ctx; crawl logs are always written.
+func (s *CrawlerService) crawlShowWithOptions(ctx context.Context, tmdbID int, includeSpecials bool) (episodeStats, error) {
 	startTime := time.Now()
+	showRepo := s.showRepo.WithContext(ctx)
+	episodeRepo := s.episodeRepo.WithContext(ctx)
 
 	// Step 1: Fetch show details from TMDB first (before any DB writes)
-	tmdbShow, err := s.tmdb.GetShowDetails(tmdbID)
+	tmdbShow, err := s.tmdb.GetShowDetails(ctx, tmdbID)
 	if err != nil {
 		s.createCrawlLog(nil, tmdbID, "fetch", "failed", 0, err.Error(), startTime)
-		return fmt.Errorf("failed to fetch show details: %w", err)
+		return episodeStats{}, fmt.Errorf("failed to fetch show details: %w", err)
 	}
 
 	// Parse dates
 	firstAirDate, _ := ParseDate(tmdbShow.FirstAirDate)
 
 	// Step 2: Check if show already exists (no write yet)
-	show, err := s.showRepo.GetByTmdbID(tmdbID)
-	var isNewShow bool
-	if err != nil {
+	show, err := showRepo.GetByTmdbID(tmdbID)
+	isNewShow := err != nil
+	if isNewShow {
 		// Prepare new show object (don't create yet)
 		show = &models.Show{
-			TmdbID:       tmdbShow.ID,
-			Name:         tmdbShow.Name,
-			OriginalName: tmdbShow.OriginalName,
-			Status:       tmdbShow.Status,
-			FirstAirDate: firstAirDate,
-			Overview:     tmdbShow.Overview,
-			PosterPath:   tmdbShow.PosterPath,
-			BackdropPath: tmdbShow.BackdropPath,
-			Popularity:   tmdbShow.Popularity,
-			VoteAverage:  tmdbShow.VoteAverage,
-			VoteCount:    tmdbShow.VoteCount,
-		}
-
-		// Parse genres
-		if len(tmdbShow.Genres) > 0 {
-			genresJSON, _ := json.Marshal(tmdbShow.Genres)
-			show.Genres = string(genresJSON)
+			TmdbID:          tmdbShow.ID,
+			FirstAirDate:    firstAirDate,
+			IncludeSpecials: includeSpecials,
 		}
-
-		isNewShow = true
-	} else {
-		// Prepare updated show data (don't update yet)
-		show.Name = tmdbShow.Name
-		show.OriginalName = tmdbShow.OriginalName
-		show.Status = tmdbShow.Status
-		show.Overview = tmdbShow.Overview
-		show.PosterPath = tmdbShow.PosterPath
-		show.BackdropPath = tmdbShow.BackdropPath
-		show.Popularity = tmdbShow.Popularity
-		show.VoteAverage = tmdbShow.VoteAverage
-		show.VoteCount = tmdbShow.VoteCount
-
-		if len(tmdbShow.Genres) > 0 {
-			genresJSON, _ := json.Marshal(tmdbShow.Genres)
-			show.Genres = string(genresJSON)
-		}
-
-		isNewShow = false
 	}
 
-	// Step 3: Fetch all season/episode data first (before any DB writes)
-	type SeasonData struct {
-		SeasonNumber int
-		Episodes    []*models.Episode
-	}
-
-	allSeasonsData := make([]SeasonData, 0, len(tmdbShow.Seasons))
+	// Prepare show data (don't write yet)
+	oldStatus := show.Status
+	s.applyShowDetails(show, tmdbShow)
+	s.applyShowTranslations(ctx, show, tmdbShow)
 
-	for _, season := range tmdbShow.Seasons {
-		if season.SeasonNumber == 0 {
-			continue // Skip specials
-		}
-
-		// Fetch season details from TMDB
-		tmdbSeason, err := s.tmdb.GetSeasonEpisodes(tmdbID, season.SeasonNumber)
-		if err != nil {
-			s.createCrawlLog(nil, tmdbID, "fetch", "failed", 0,
-				fmt.Sprintf("failed to fetch season %d: %s", season.SeasonNumber, err.Error()), startTime)
-			return fmt.Errorf("failed to fetch season %d: %w", season.SeasonNumber, err)
-		}
-
-		var episodes []*models.Episode
-		for _, tmdbEpisode := range tmdbSeason.Episodes {
-			airDate, _ := ParseDate(tmdbEpisode.AirDate)
-
-			episode := &models.Episode{
-				SeasonNumber:  tmdbEpisode.SeasonNumber,
-				EpisodeNumber: tmdbEpisode.EpisodeNumber,
-				Name:          tmdbEpisode.Name,
-				Overview:      tmdbEpisode.Overview,
-				AirDate:       airDate,
-				StillPath:     tmdbEpisode.StillPath,
-				Runtime:       tmdbEpisode.Runtime,
-				VoteAverage:   tmdbEpisode.VoteAverage,
-				VoteCount:     tmdbEpisode.VoteCount,
-			}
-
-			episodes = append(episodes, episode)
-		}
-
-		allSeasonsData = append(allSeasonsData, SeasonData{
-			SeasonNumber: season.SeasonNumber,
-			Episodes:    episodes,
-		})
+	// Step 3: Fetch all season/episode data first (before any DB writes)
+	allEpisodes, err := s.fetchSeasonEpisodes(ctx, tmdbID, crawlSeasonNumbers(show, tmdbShow))
+	if err != nil {
+		s.createCrawlLog(nil, tmdbID, "fetch", "failed", 0, err.Error(), startTime)
+		return episodeStats{}, err
 	}
 
 	// Step 4: All data fetched successfully, now write to database
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
 
 	// Create or update the show record
 	if isNewShow {
-		if err := s.showRepo.Create(show); err != nil {
+		if err := showRepo.Create(show); err != nil {
 			s.createCrawlLog(nil, tmdbID, "fetch", "failed", 0, err.Error(), startTime)
-			return fmt.Errorf("failed to create show: %w", err)
+			return episodeStats{}, fmt.Errorf("failed to create show: %w", err)
 		}
 	} else {
-		if err := s.showRepo.Update(show); err != nil {
+		if err := showRepo.Update(show); err != nil {
 			s.createCrawlLog(&show.ID, tmdbID, "fetch", "failed", 0, err.Error(), startTime)
-			return fmt.Errorf("failed to update show: %w", err)
+			return episodeStats{}, fmt.Errorf("failed to update show: %w", err)
 		}
 	}
+	if err := showRepo.ReplaceGenres(show.ID, showGenres(tmdbShow)); err != nil {
+		s.createCrawlLog(&show.ID, tmdbID, "fetch", "failed", 0, err.Error(), startTime)
+		return episodeStats{}, fmt.Errorf("failed to save genres: %w", err)
+	}
 
 	// Step 5: Write all episodes to database in one batch
-	// Collect all episodes from all seasons
-	allEpisodes := make([]*models.Episode, 0)
-	for _, seasonData := range allSeasonsData {
-		// Set ShowID for all episodes
-		for _, ep := range seasonData.Episodes {
-			ep.ShowID = uint(show.ID)
+	for _, ep := range allEpisodes {
+		ep.ShowID = uint(show.ID)
+	}
+	applyAirInstants(show, allEpisodes)
+
+	// Compare against the stored episodes before they are replaced
+	// A new show has no history, so its episodes are counted but not recorded as changes
+	stats, changes := diffEpisodes(nil, allEpisodes)
+	if isNewShow {
+		changes = nil
+	} else {
+		existing, err := episodeRepo.GetByShowID(show.ID)
+		if err != nil {
+			s.createCrawlLog(&show.ID, tmdbID, "fetch", "failed", 0, err.Error(), startTime)
+			return episodeStats{}, fmt.Errorf("failed to load existing episodes: %w", err)
 		}
-		allEpisodes = append(allEpisodes, seasonData.Episodes...)
+		stats, changes = diffEpisodes(existing, allEpisodes)
 	}
 
 	// Batch create/update all episodes at once
-	if err := s.episodeRepo.CreateBatch(allEpisodes); err != nil {
+	if err := episodeRepo.CreateBatch(allEpisodes); err != nil {
 		s.createCrawlLog(&show.ID, tmdbID, "fetch", "partial", 0, err.Error(), startTime)
-		return fmt.Errorf("failed to save episodes: %w", err)
+		return episodeStats{}, fmt.Errorf("failed to save episodes: %w", err)
 	}
 
 	totalEpisodes := len(allEpisodes)
 
 	// Step 6: Update show metadata
+	// The change cursor is the crawl start, so changes made during the crawl are picked up next time
+	s.applySeasonMetadata(show, tmdbShow)
+	show.LastCrawledAt = &[]time.Time{time.Now()}[0]
+	show.ChangeCursor = &startTime
+	if err := showRepo.Update(show); err != nil {
+		// Log warning but don't fail - the main data is already saved
+		log := s.createCrawlLog(&show.ID, tmdbID, "fetch", "partial", totalEpisodes,
+			fmt.Sprintf("saved but failed to update metadata: %s", err.Error()), startTime)
+		s.recordEpisodeChanges(log, show, changes)
+		s.emitStatusChange(show, oldStatus)
+		return stats, fmt.Errorf("data saved but failed to update metadata: %w", err)
+	}
+
+	// Create success log
+	log := s.createCrawlLog(&show.ID, tmdbID, "fetch", "success", totalEpisodes, "", startTime)
+	s.recordEpisodeChanges(log, show, changes)
+	s.emitStatusChange(show, oldStatus)
+
+	return stats, nil
+}
+
+// applyShowDetails copies the TMDB show details onto the show record
+func (s *CrawlerService) applyShowDetails(show *models.Show, tmdbShow *dto.TMDBShowResponse) {
+	show.Name = tmdbShow.Name
+	show.OriginalName = tmdbShow.OriginalName
+	show.Status = tmdbShow.Status
+	show.Overview = tmdbShow.Overview
+	show.PosterPath = tmdbShow.PosterPath
+	show.BackdropPath = tmdbShow.BackdropPath
+	show.Popularity = tmdbShow.Popularity
+	show.VoteAverage = tmdbShow.VoteAverage
+	show.VoteCount = tmdbShow.VoteCount
+	if tmdbShow.ExternalIDs != nil {
+		show.ImdbID = tmdbShow.ExternalIDs.IMDbID
+		show.TvdbID = tmdbShow.ExternalIDs.TVDBID
+	}
+
+	s.applyShowSchedule(show, tmdbShow)
+}
+
+// showGenres converts the TMDB genres of a show
+func showGenres(tmdbShow *dto.TMDBShowResponse) []models.Genre {
+	genres := make([]models.Genre, 0, len(tmdbShow.Genres))
+	for _, genre := range tmdbShow.Genres {
+		genres = append(genres, models.Genre{ID: genre.ID, Name: genre.Name})
+	}
+	return genres
+}
+
+// applyShowSchedule copies networks, production companies and the run schedule.
+// NextAirDate follows next_episode_to_air and is cleared when TMDB has none.
+func (s *CrawlerService) applyShowSchedule(show *models.Show, tmdbShow *dto.TMDBShowResponse) {
+	show.Networks = marshalCompanies(tmdbShow.Networks)
+	show.ProductionCompanies = marshalCompanies(tmdbShow.ProductionCompanies)
+	show.OriginCountry = strings.Join(tmdbShow.OriginCountry, ",")
+	show.InProduction = tmdbShow.InProduction
+	show.EpisodeRunTime = 0
+	if len(tmdbShow.EpisodeRunTime) > 0 {
+		show.EpisodeRunTime = tmdbShow.EpisodeRunTime[0]
+	}
+	show.LastAirDate, _ = ParseDate(tmdbShow.LastAirDate)
+
+	show.LastEpisodeSeason, show.LastEpisodeNumber = 0, 0
+	if last := tmdbShow.LastEpisodeToAir; last != nil {
+		show.LastEpisodeSeason = last.SeasonNumber
+		show.LastEpisodeNumber = last.EpisodeNumber
+	}
+
+	show.NextAirDate = nil
+	show.NextEpisodeSeason, show.NextEpisodeNumber = 0, 0
+	if next := tmdbShow.NextEpisodeToAir; next != nil {
+		show.NextAirDate, _ = ParseDate(next.AirDate)
+		show.NextEpisodeSeason = next.SeasonNumber
+		show.NextEpisodeNumber = next.EpisodeNumber
+	}
+
+	applyAirSchedule(show, tmdbShow)
+}
+
+// networkAirSchedule is the broadcast timezone and local air time of a network
+type networkAirSchedule struct {
+	Timezone string
+	AirTime  string
+}
+
+// networkAirSchedules are networks known to release at a fixed time, by TMDB network name
+var networkAirSchedules = map[string]networkAirSchedule{
+	"Netflix": {Timezone: "America/Los_Angeles", AirTime: "00:00"},
+	"Disney+": {Timezone: "America/Los_Angeles", AirTime: "00:00"},
+}
+
+// broadcastTimezones maps the origin country of a network to the timezone it broadcasts in.
+// Countries spanning several timezones use the zone of their main broadcast market.
+var broadcastTimezones = map[string]string{
+	"US": "America/New_York",
+	"CA": "America/Toronto",
+	"MX": "America/Mexico_City",
+	"BR": "America/Sao_Paulo",
+	"GB": "Europe/London",
+	"IE": "Europe/Dublin",
+	"FR": "Europe/Paris",
+	"DE": "Europe/Berlin",
+	"ES": "Europe/Madrid",
+	"IT": "Europe/Rome",
+	"NL": "Europe/Amsterdam",
+	"SE": "Europe/Stockholm",
+	"NO": "Europe/Oslo",
+	"DK": "Europe/Copenhagen",
+	"JP": "Asia/Tokyo",
+	"KR": "Asia/Seoul",
+	"CN": "Asia/Shanghai",
+	"TW": "Asia/Taipei",
+	"HK": "Asia/Hong_Kong",
+	"TH": "Asia/Bangkok",
+	"IN": "Asia/Kolkata",
+	"AU": "Australia/Sydney",
+	"NZ": "Pacific/Auckland",
+}
+
+// applyAirSchedule derives the broadcast timezone and air time of a show from its
+// first network, falling back to the show's origin country for the timezone.
+// TMDB has no air times, so outside networkAirSchedules the air time is set by hand.
+// Only empty fields are filled, so values set by hand are kept.
+func applyAirSchedule(show *models.Show, tmdbShow *dto.TMDBShowResponse) {
+	country := ""
+	if len(tmdbShow.OriginCountry) > 0 {
+		country = tmdbShow.OriginCountry[0]
+	}
+	if len(tmdbShow.Networks) > 0 {
+		network := tmdbShow.Networks[0]
+		if schedule, ok := networkAirSchedules[network.Name]; ok && show.AirTimezone == "" && show.AirTime == "" {
+			show.AirTimezone = schedule.Timezone
+			show.AirTime = schedule.AirTime
+		}
+		if network.OriginCountry != "" {
+			country = network.OriginCountry
+		}
+	}
+	if show.AirTimezone == "" {
+		show.AirTimezone = broadcastTimezones[country]
+	}
+}
+
+// applyAirInstants sets the air instant of episodes from the show's air schedule
+func applyAirInstants(show *models.Show, episodes []*models.Episode) {
+	for _, episode := range episodes {
+		episode.AirAt = show.AirInstant(episode.AirDate)
+	}
+}
+
+// marshalCompanies encodes networks or companies as JSON for storage.
+// HTML escaping is off so names like "A&E" stay searchable.
+func marshalCompanies(companies []dto.TMDBCompany) string {
+	if len(companies) == 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(companies); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// applySeasonMetadata records the latest season information on the show
+func (s *CrawlerService) applySeasonMetadata(show *models.Show, tmdbShow *dto.TMDBShowResponse) {
 	if len(tmdbShow.Seasons) > 0 {
 		lastSeason := tmdbShow.Seasons[len(tmdbShow.Seasons)-1]
 		show.LastSeasonNumber = lastSeason.SeasonNumber
 		show.LastEpisodeCount = lastSeason.EpisodeCount
 	}
-	show.LastCrawledAt = &[]time.Time{time.Now()}[0]
-	if err := s.showRepo.Update(show); err != nil {
-		// Log warning but don't fail - the main data is already saved
-		s.createCrawlLog(&show.ID, tmdbID, "fetch", "partial", totalEpisodes,
-			fmt.Sprintf("saved but failed to update metadata: %s", err.Error()), startTime)
-		return fmt.Errorf("data saved but failed to update metadata: %w", err)
+}
+
+// crawlSeasonNumbers returns the season numbers of a show to crawl.
+// Specials (season 0) are skipped unless the show opts in.
+func crawlSeasonNumbers(show *models.Show, tmdbShow *dto.TMDBShowResponse) []int {
+	seasonNumbers := make([]int, 0, len(tmdbShow.Seasons))
+	for _, season := range tmdbShow.Seasons {
+		if season.SeasonNumber == 0 && !show.IncludeSpecials {
+			continue // Skip specials
+		}
+		seasonNumbers = append(seasonNumbers, season.SeasonNumber)
 	}
+	return seasonNumbers
+}
 
-	// Create success log
-	s.createCrawlLog(&show.ID, tmdbID, "fetch", "success", totalEpisodes, "", startTime)
+// fetchSeasonEpisodes fetches the episodes of the given seasons from TMDB.
+// Seasons are fetched concurrently; TMDBService rate-limits the requests.
+// Episodes are returned in season order and have no ShowID set.
+func (s *CrawlerService) fetchSeasonEpisodes(ctx context.Context, tmdbID int, seasonNumbers []int) ([]*models.Episode, error) {
+	seasonEpisodes := make([][]*models.Episode, len(seasonNumbers))
+	seasonErrs := make([]error, len(seasonNumbers))
+	runWorkerPool(s.GetConcurrency(), len(seasonNumbers), func(i int) {
+		seasonEpisodes[i], seasonErrs[i] = s.fetchSeason(ctx, tmdbID, seasonNumbers[i])
+	})
+
+	episodes := make([]*models.Episode, 0)
+	for i := range seasonEpisodes {
+		if err := seasonErrs[i]; err != nil {
+			return nil, fmt.Errorf("failed to fetch season %d: %w", seasonNumbers[i], err)
+		}
+		episodes = append(episodes, seasonEpisodes[i]...)
+	}
 
-	return nil
+	return episodes, nil
+}
+
+// fetchSeason fetches the episodes of one season, with translations from the
+// fallback languages for episodes that have no name or overview
+func (s *CrawlerService) fetchSeason(ctx context.Context, tmdbID, seasonNumber int) ([]*models.Episode, error) {
+	tmdbSeason, err := s.tmdb.GetSeasonEpisodes(ctx, tmdbID, seasonNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	primary := s.tmdb.Language()
+	episodes := make([]*models.Episode, 0, len(tmdbSeason.Episodes))
+	for _, tmdbEpisode := range tmdbSeason.Episodes {
+		airDate, _ := ParseDate(tmdbEpisode.AirDate)
+
+		episode := &models.Episode{
+			SeasonNumber:  tmdbEpisode.SeasonNumber,
+			EpisodeNumber: tmdbEpisode.EpisodeNumber,
+			Name:          tmdbEpisode.Name,
+			Overview:      tmdbEpisode.Overview,
+			AirDate:       airDate,
+			StillPath:     tmdbEpisode.StillPath,
+			Runtime:       tmdbEpisode.Runtime,
+			VoteAverage:   tmdbEpisode.VoteAverage,
+			VoteCount:     tmdbEpisode.VoteCount,
+		}
+		setTranslation(&episode.Translations, primary, tmdbEpisode.Name, tmdbEpisode.Overview)
+
+		episodes = append(episodes, episode)
+	}
+
+	s.applyEpisodeTranslations(ctx, tmdbID, seasonNumber, episodes)
+
+	if s.fetchesEpisodeExternalIDs() {
+		for _, episode := range episodes {
+			ids, err := s.tmdb.GetEpisodeExternalIDs(ctx, tmdbID, seasonNumber, episode.EpisodeNumber)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch external IDs of episode %d: %w", episode.EpisodeNumber, err)
+			}
+			episode.ImdbID = ids.IMDbID
+			episode.TvdbID = ids.TVDBID
+		}
+	}
+	return episodes, nil
 }
 
 // crawlSeason crawls a specific season (legacy, kept for potential future use)
 // Note: This function writes to database immediately. Use with caution.
-func (s *CrawlerService) crawlSeason(showID, tmdbID, seasonNumber int) ([]*models.Episode, error) {
+func (s *CrawlerService) crawlSeason(ctx context.Context, showID, tmdbID, seasonNumber int) ([]*models.Episode, error) {
 	// Fetch season details from TMDB
-	tmdbSeason, err := s.tmdb.GetSeasonEpisodes(tmdbID, seasonNumber)
+	tmdbSeason, err := s.tmdb.GetSeasonEpisodes(ctx, tmdbID, seasonNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -240,99 +535,160 @@ func (s *CrawlerService) crawlSeason(showID, tmdbID, seasonNumber int) ([]*model
 	}
 
 	// Batch create/update episodes
-	if err := s.episodeRepo.CreateBatch(episodes); err != nil {
+	if err := s.episodeRepo.WithContext(ctx).CreateBatch(episodes); err != nil {
 		return nil, err
 	}
 
 	return episodes, nil
 }
 
-// BatchCrawl crawls multiple shows
-func (s *CrawlerService) BatchCrawl(tmdbIDs []int) []*CrawlResult {
-	results := make([]*CrawlResult, 0, len(tmdbIDs))
-
-	for _, tmdbID := range tmdbIDs {
-		startTime := time.Now()
-		err := s.CrawlShow(tmdbID)
-		duration := time.Since(startTime)
-
-		result := &CrawlResult{
-			TmdbID:   tmdbID,
-			Success:  err == nil,
-			Error:    err,
-			Duration: duration,
-		}
+// BatchCrawl crawls multiple shows using a bounded worker pool.
+// Results are returned in the same order as tmdbIDs. Once ctx is done the
+// remaining shows are not crawled and their results are marked cancelled.
+func (s *CrawlerService) BatchCrawl(ctx context.Context, tmdbIDs []int) []*CrawlResult {
+	return s.batchCrawl(ctx, tmdbIDs, s.crawlShow)
+}
 
-		if err == nil {
-			// Get episode count
-			if show, err := s.showRepo.GetByTmdbID(tmdbID); err == nil {
-				if count, err := s.episodeRepo.CountByShowID(show.ID); err == nil {
-					result.EpisodesCount = int(count)
-				}
-			}
-		}
+// batchCrawl runs crawl for every TMDB ID on the worker pool, keeping results in input order.
+// Progress is reported to the CrawlProgress carried by ctx, if any.
+func (s *CrawlerService) batchCrawl(ctx context.Context, tmdbIDs []int, crawl func(ctx context.Context, tmdbID int) (episodeStats, error)) []*CrawlResult {
+	results := make([]*CrawlResult, len(tmdbIDs))
 
-		results = append(results, result)
+	progress := crawlProgressFrom(ctx)
+	if progress != nil {
+		progress.BatchStarted(tmdbIDs)
 	}
 
+	runWorkerPool(s.GetConcurrency(), len(tmdbIDs), func(i int) {
+		if progress != nil && ctx.Err() == nil {
+			progress.ShowStarted(tmdbIDs[i], s.showName(tmdbIDs[i]))
+		}
+		results[i] = s.crawlOne(ctx, tmdbIDs[i], crawl)
+		if progress != nil {
+			progress.ShowFinished(results[i])
+		}
+	})
+
 	return results
 }
 
-// RefreshAll refreshes all shows in the database
-func (s *CrawlerService) RefreshAll() error {
-	shows, err := s.showRepo.ListAll()
-	if err != nil {
-		return fmt.Errorf("failed to list shows: %w", err)
+// showName returns the stored name of a show, or an empty string for shows not crawled yet
+func (s *CrawlerService) showName(tmdbID int) string {
+	if show, err := s.showRepo.GetByTmdbID(tmdbID); err == nil {
+		return show.Name
 	}
+	return ""
+}
 
-	tmdbIDs := make([]int, len(shows))
-	for i, show := range shows {
-		tmdbIDs[i] = show.TmdbID
+// crawlOne crawls a single show and wraps the outcome in a CrawlResult
+func (s *CrawlerService) crawlOne(ctx context.Context, tmdbID int, crawl func(ctx context.Context, tmdbID int) (episodeStats, error)) *CrawlResult {
+	if err := ctx.Err(); err != nil {
+		return &CrawlResult{TmdbID: tmdbID, Cancelled: true, Error: err}
 	}
 
-	results := s.BatchCrawl(tmdbIDs)
+	startTime := time.Now()
+	stats, err := crawl(ctx, tmdbID)
+	duration := time.Since(startTime)
+
+	// A crawl interrupted by cancellation is not a failure of the show
+	if err != nil && ctx.Err() != nil {
+		return &CrawlResult{TmdbID: tmdbID, Cancelled: true, Error: ctx.Err(), Duration: duration}
+	}
 
-	// Check if all succeeded
-	for _, result := range results {
-		if !result.Success {
-			return fmt.Errorf("failed to crawl show %d: %w", result.TmdbID, result.Error)
+	result := &CrawlResult{
+		TmdbID:          tmdbID,
+		Success:         err == nil,
+		EpisodesAdded:   stats.added,
+		EpisodesUpdated: stats.updated,
+		Error:           err,
+		Duration:        duration,
+	}
+
+	if err == nil {
+		// Get episode count
+		if show, err := s.showRepo.GetByTmdbID(tmdbID); err == nil {
+			if count, err := s.episodeRepo.CountByShowID(show.ID); err == nil {
+				result.EpisodesCount = int(count)
+			}
 		}
 	}
 
-	return nil
+	return result
 }
 
-// CrawlByStatus refreshes shows based on status filter
-func (s *CrawlerService) CrawlByStatus(status string) error {
+// RefreshAll refreshes all shows in the database that are not archived.
+// Failed shows do not stop the refresh; they are reported in the summary.
+func (s *CrawlerService) RefreshAll(ctx context.Context) (*CrawlSummary, error) {
+	shows, err := s.showRepo.WithContext(ctx).ListActive()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shows: %w", err)
+	}
+
+	return s.crawlShows(ctx, shows), nil
+}
+
+// CrawlByStatus refreshes shows based on status filter; archived shows are left out.
+// Failed shows do not stop the crawl; they are reported in the summary.
+func (s *CrawlerService) CrawlByStatus(ctx context.Context, status string) (*CrawlSummary, error) {
 	var shows []*models.Show
 	var err error
 
+	showRepo := s.showRepo.WithContext(ctx)
 	if status == "returning" || status == "Returning Series" {
-		shows, err = s.showRepo.ListReturning()
+		shows, err = showRepo.ListReturning()
 	} else {
-		shows, err = s.showRepo.ListAll()
+		shows, err = showRepo.ListActive()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shows: %w", err)
 	}
+
+	return s.crawlShows(ctx, shows), nil
+}
+
+// CrawlShowsByTmdbID fully crawls the stored shows with the given TMDB IDs.
+// IDs without a stored show (e.g. deleted since) are reported as skipped.
+func (s *CrawlerService) CrawlShowsByTmdbID(ctx context.Context, tmdbIDs []int) (*CrawlSummary, error) {
+	shows, err := s.showRepo.WithContext(ctx).GetByTmdbIDs(tmdbIDs)
 	if err != nil {
-		return fmt.Errorf("failed to list shows: %w", err)
+		return nil, fmt.Errorf("failed to load shows: %w", err)
+	}
+
+	summary := s.crawlShows(ctx, shows)
+
+	found := make(map[int]bool, len(shows))
+	for _, show := range shows {
+		found[show.TmdbID] = true
+	}
+	for _, tmdbID := range tmdbIDs {
+		if !found[tmdbID] {
+			summary.AddSkipped(&models.Show{TmdbID: tmdbID}, "show no longer exists")
+		}
 	}
 
+	return summary, nil
+}
+
+// crawlShows fully crawls the given shows and summarizes the results
+func (s *CrawlerService) crawlShows(ctx context.Context, shows []*models.Show) *CrawlSummary {
+	summary := NewCrawlSummary()
+
 	tmdbIDs := make([]int, len(shows))
 	for i, show := range shows {
 		tmdbIDs[i] = show.TmdbID
 	}
 
-	results := s.BatchCrawl(tmdbIDs)
-	for _, result := range results {
-		if !result.Success {
-			return fmt.Errorf("failed to crawl show %d: %w", result.TmdbID, result.Error)
-		}
+	results := s.BatchCrawl(ctx, tmdbIDs)
+	for i, result := range results {
+		summary.AddResult(shows[i], result)
 	}
 
-	return nil
+	return summary
 }
 
 // createCrawlLog creates a crawl log entry
-func (s *CrawlerService) createCrawlLog(showID *uint, tmdbID int, action, status string, episodesCount int, errorMsg string, startTime time.Time) {
+// The log is written without the crawl's context so failures caused by cancellation are still recorded
+func (s *CrawlerService) createCrawlLog(showID *uint, tmdbID int, action, status string, episodesCount int, errorMsg string, startTime time.Time) *models.CrawlLog {
 	duration := time.Since(startTime)
 
 	log := &models.CrawlLog{
@@ -346,4 +702,67 @@ func (s *CrawlerService) createCrawlLog(showID *uint, tmdbID int, action, status
 	}
 
 	_ = s.logRepo.Create(log)
+
+	if status == "failed" {
+		s.emit(models.WebhookEventCrawlFailed, CrawlFailedEvent{
+			CrawlLogID: log.ID,
+			ShowID:     showID,
+			TmdbID:     tmdbID,
+			Action:     action,
+			Error:      errorMsg,
+		})
+	}
+	return log
+}
+
+// recordEpisodeChanges stores the episode changes detected by a crawl, linked to
+// its log, and reports added episodes and moved air dates
+func (s *CrawlerService) recordEpisodeChanges(log *models.CrawlLog, show *models.Show, changes []*models.EpisodeChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	if s.changeRepo != nil {
+		for _, change := range changes {
+			if log.ID != 0 {
+				change.CrawlLogID = &log.ID
+			}
+		}
+		_ = s.changeRepo.CreateBatch(changes)
+	}
+
+	for _, change := range changes {
+		var event string
+		switch change.ChangeType {
+		case episodeChangeAdded:
+			event = models.WebhookEventEpisodeAdded
+		case episodeChangeAirDateMoved:
+			event = models.WebhookEventEpisodeAirDateChanged
+		default:
+			continue
+		}
+		s.emit(event, EpisodeEvent{
+			ShowID:        show.ID,
+			TmdbID:        show.TmdbID,
+			ShowName:      show.Name,
+			SeasonNumber:  change.SeasonNumber,
+			EpisodeNumber: change.EpisodeNumber,
+			OldValue:      change.OldValue,
+			NewValue:      change.NewValue,
+		})
+	}
+}
+
+// emitStatusChange reports a change of the TMDB status of a stored show
+func (s *CrawlerService) emitStatusChange(show *models.Show, oldStatus string) {
+	if oldStatus == "" || oldStatus == show.Status {
+		return
+	}
+	s.emit(models.WebhookEventShowStatusChanged, ShowStatusEvent{
+		ShowID:    show.ID,
+		TmdbID:    show.TmdbID,
+		ShowName:  show.Name,
+		OldStatus: oldStatus,
+		NewStatus: show.Status,
+	})
 }
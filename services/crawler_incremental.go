@@ -0,0 +1,262 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/xc9973/go-tmdb-crawler/dto"
+	"github.com/xc9973/go-tmdb-crawler/models"
+)
+
+// tmdbChangeTimeLayout is the time format used in TMDB change items
+const tmdbChangeTimeLayout = "2006-01-02 15:04:05 UTC"
+
+// hasUsableChangeCursor reports whether a show can be refreshed incrementally at now
+func hasUsableChangeCursor(show *models.Show, now time.Time) bool {
+	return show.ChangeCursor != nil && now.Sub(*show.ChangeCursor) <= TMDBChangesMaxRange
+}
+
+// CrawlShowIncremental refreshes a show from TMDB's change history.
+// Only seasons reported as changed since the show's change cursor are re-fetched.
+// Falls back to a full CrawlShow when the show is unknown or has no usable cursor.
+func (s *CrawlerService) CrawlShowIncremental(ctx context.Context, tmdbID int) error {
+	_, err := s.crawlShowIncremental(ctx, tmdbID)
+	return err
+}
+
+// crawlShowIncremental refreshes a show incrementally and reports the episode changes
+func (s *CrawlerService) crawlShowIncremental(ctx context.Context, tmdbID int) (episodeStats, error) {
+	startTime := time.Now()
+	showRepo := s.showRepo.WithContext(ctx)
+	episodeRepo := s.episodeRepo.WithContext(ctx)
+
+	show, err := showRepo.GetByTmdbID(tmdbID)
+	if err != nil || !hasUsableChangeCursor(show, startTime) {
+		return s.crawlShow(ctx, tmdbID)
+	}
+	since := *show.ChangeCursor
+
+	// Step 1: Ask TMDB what changed since the cursor
+	changes, err := s.tmdb.GetShowChanges(ctx, tmdbID, since, startTime)
+	if err != nil {
+		s.createCrawlLog(&show.ID, tmdbID, "refresh", "failed", 0, err.Error(), startTime)
+		return episodeStats{}, fmt.Errorf("failed to fetch show changes: %w", err)
+	}
+
+	changedSeasons, showChanged, ok := collectShowChanges(changes, since, show.IncludeSpecials)
+	if !ok {
+		// A season or episode changed but TMDB did not say which season
+		return s.crawlShow(ctx, tmdbID)
+	}
+	if len(changedSeasons) == 0 && !showChanged {
+		// Nothing changed, just advance the cursor
+		s.writeMu.Lock()
+		defer s.writeMu.Unlock()
+
+		if err := showRepo.UpdateChangeCursor([]uint{show.ID}, startTime); err != nil {
+			s.createCrawlLog(&show.ID, tmdbID, "refresh", "failed", 0, err.Error(), startTime)
+			return episodeStats{}, fmt.Errorf("failed to update change cursor: %w", err)
+		}
+		s.createCrawlLog(&show.ID, tmdbID, "refresh", "success", 0, "", startTime)
+		return episodeStats{}, nil
+	}
+
+	// Step 2: Fetch show details and the changed seasons (before any DB writes)
+	tmdbShow, err := s.tmdb.GetShowDetails(ctx, tmdbID)
+	if err != nil {
+		s.createCrawlLog(&show.ID, tmdbID, "refresh", "failed", 0, err.Error(), startTime)
+		return episodeStats{}, fmt.Errorf("failed to fetch show details: %w", err)
+	}
+	airSchedule := show.AirTimezone + " " + show.AirTime
+	oldStatus := show.Status
+	s.applyShowDetails(show, tmdbShow)
+	s.applyShowTranslations(ctx, show, tmdbShow)
+
+	// Seasons that no longer exist on TMDB are cleared instead of fetched
+	existing := make(map[int]bool)
+	for _, seasonNumber := range crawlSeasonNumbers(show, tmdbShow) {
+		existing[seasonNumber] = true
+	}
+	fetchSeasons := make([]int, 0, len(changedSeasons))
+	for _, seasonNumber := range changedSeasons {
+		if existing[seasonNumber] {
+			fetchSeasons = append(fetchSeasons, seasonNumber)
+		}
+	}
+
+	episodes, err := s.fetchSeasonEpisodes(ctx, tmdbID, fetchSeasons)
+	if err != nil {
+		s.createCrawlLog(&show.ID, tmdbID, "refresh", "failed", 0, err.Error(), startTime)
+		return episodeStats{}, err
+	}
+
+	// Step 3: Write the show and the changed seasons
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	for _, ep := range episodes {
+		ep.ShowID = show.ID
+	}
+	applyAirInstants(show, episodes)
+	stored, err := episodeRepo.GetByShowID(show.ID)
+	if err != nil {
+		s.createCrawlLog(&show.ID, tmdbID, "refresh", "failed", 0, err.Error(), startTime)
+		return episodeStats{}, fmt.Errorf("failed to load existing episodes: %w", err)
+	}
+	// Only the replaced seasons are compared, other stored episodes are left as they are
+	replaced := make(map[int]bool)
+	for _, seasonNumber := range changedSeasons {
+		replaced[seasonNumber] = true
+	}
+	storedInSeasons := make([]*models.Episode, 0, len(stored))
+	for _, ep := range stored {
+		if replaced[ep.SeasonNumber] {
+			storedInSeasons = append(storedInSeasons, ep)
+		}
+	}
+	stats, episodeChanges := diffEpisodes(storedInSeasons, episodes)
+
+	if err := episodeRepo.ReplaceSeasons(show.ID, changedSeasons, episodes); err != nil {
+		s.createCrawlLog(&show.ID, tmdbID, "refresh", "failed", 0, err.Error(), startTime)
+		return episodeStats{}, fmt.Errorf("failed to save episodes: %w", err)
+	}
+	// Episodes of unchanged seasons follow a newly derived air schedule too
+	if show.AirTimezone+" "+show.AirTime != airSchedule {
+		if err := episodeRepo.UpdateAirInstants(show); err != nil {
+			s.createCrawlLog(&show.ID, tmdbID, "refresh", "failed", 0, err.Error(), startTime)
+			return episodeStats{}, fmt.Errorf("failed to update air instants: %w", err)
+		}
+	}
+	if err := showRepo.ReplaceGenres(show.ID, showGenres(tmdbShow)); err != nil {
+		s.createCrawlLog(&show.ID, tmdbID, "refresh", "failed", 0, err.Error(), startTime)
+		return episodeStats{}, fmt.Errorf("failed to save genres: %w", err)
+	}
+
+	s.applySeasonMetadata(show, tmdbShow)
+	show.LastCrawledAt = &[]time.Time{time.Now()}[0]
+	show.ChangeCursor = &startTime
+	if err := showRepo.Update(show); err != nil {
+		log := s.createCrawlLog(&show.ID, tmdbID, "refresh", "partial", len(episodes),
+			fmt.Sprintf("saved but failed to update metadata: %s", err.Error()), startTime)
+		s.recordEpisodeChanges(log, show, episodeChanges)
+		s.emitStatusChange(show, oldStatus)
+		return stats, fmt.Errorf("data saved but failed to update metadata: %w", err)
+	}
+
+	log := s.createCrawlLog(&show.ID, tmdbID, "refresh", "success", len(episodes), "", startTime)
+	s.recordEpisodeChanges(log, show, episodeChanges)
+	s.emitStatusChange(show, oldStatus)
+	return stats, nil
+}
+
+// RefreshIncremental refreshes all shows that are not archived using TMDB's change lists.
+// Shows without a usable change cursor get a full crawl, shows TMDB lists as
+// changed get an incremental crawl, and the rest only have their cursor advanced
+// and are reported as skipped.
+func (s *CrawlerService) RefreshIncremental(ctx context.Context) (*CrawlSummary, error) {
+	startTime := time.Now()
+	showRepo := s.showRepo.WithContext(ctx)
+
+	shows, err := showRepo.ListActive()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shows: %w", err)
+	}
+
+	// The global change list only needs to reach back to the oldest usable cursor
+	var earliest *time.Time
+	for _, show := range shows {
+		if hasUsableChangeCursor(show, startTime) && (earliest == nil || show.ChangeCursor.Before(*earliest)) {
+			earliest = show.ChangeCursor
+		}
+	}
+
+	changed := make(map[int]bool)
+	if earliest != nil {
+		ids, err := s.tmdb.GetChangedShowIDs(ctx, *earliest, startTime)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch TMDB changes: %w", err)
+		}
+		for _, id := range ids {
+			changed[id] = true
+		}
+	}
+
+	summary := NewCrawlSummary()
+	var crawlable []*models.Show
+	var tmdbIDs []int
+	var unchanged []uint
+	for _, show := range shows {
+		if !hasUsableChangeCursor(show, startTime) || changed[show.TmdbID] {
+			crawlable = append(crawlable, show)
+			tmdbIDs = append(tmdbIDs, show.TmdbID)
+		} else {
+			unchanged = append(unchanged, show.ID)
+			summary.AddSkipped(show, "no changes on TMDB")
+		}
+	}
+
+	s.writeMu.Lock()
+	err = showRepo.UpdateChangeCursor(unchanged, startTime)
+	s.writeMu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to update change cursors: %w", err)
+	}
+
+	results := s.batchCrawl(ctx, tmdbIDs, s.crawlShowIncremental)
+	for i, result := range results {
+		summary.AddResult(crawlable[i], result)
+	}
+
+	return summary, nil
+}
+
+// collectShowChanges extracts the changed season numbers from a show's change
+// history, ignoring items older than since and, unless includeSpecials is set,
+// changes to season 0. showChanged reports whether any
+// show-level field changed; ok is false if a season change could not be
+// attributed to a season number. Episode changes never carry a season number
+// and episodes do not store their TMDB ID, so any episode change also sets ok to false.
+func collectShowChanges(changes *dto.TMDBShowChangesResponse, since time.Time, includeSpecials bool) (seasons []int, showChanged, ok bool) {
+	seasonSet := make(map[int]bool)
+
+	for _, change := range changes.Changes {
+		for _, item := range change.Items {
+			if t, err := time.Parse(tmdbChangeTimeLayout, item.Time); err == nil && t.Before(since) {
+				continue // Already applied
+			}
+
+			if change.Key == "episode" {
+				return nil, showChanged, false
+			}
+			if change.Key != "season" {
+				showChanged = true
+				continue
+			}
+
+			found := false
+			for _, raw := range []json.RawMessage{item.Value, item.OriginalValue} {
+				var value dto.TMDBSeasonChangeValue
+				if len(raw) == 0 || json.Unmarshal(raw, &value) != nil || value.SeasonID == 0 {
+					continue
+				}
+				found = true
+				if value.SeasonNumber > 0 || includeSpecials {
+					seasonSet[value.SeasonNumber] = true
+				}
+			}
+			if !found {
+				return nil, showChanged, false
+			}
+		}
+	}
+
+	for seasonNumber := range seasonSet {
+		seasons = append(seasons, seasonNumber)
+	}
+	sort.Ints(seasons)
+
+	return seasons, showChanged, true
+}
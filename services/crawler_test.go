@@ -0,0 +1,1063 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xc9973/go-tmdb-crawler/dto"
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/repositories"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// fakeTMDB serves canned show and season responses
+type fakeTMDB struct {
+	mu          sync.Mutex
+	shows       map[int]*dto.TMDBShowResponse
+	seasons     map[string]*dto.TMDBSeasonResponse
+	showChanges map[int]*dto.TMDBShowChangesResponse
+	changedIDs  []int
+	// lists holds the results of TV list endpoints by path, e.g. /tv/on_the_air
+	lists map[string][]dto.TMDBShowResult
+	// tmdbLists holds the TMDB v4 lists by list ID
+	tmdbLists map[int]*dto.TMDBListResponse
+	// movies holds the movie details by TMDB ID
+	movies map[int]*dto.TMDBMovieResponse
+	// translated lists the languages that have overviews; others return empty overviews
+	translated  map[string]bool
+	delay       time.Duration
+	inFlight    int
+	maxInFlight int
+	requests    int
+}
+
+func newFakeTMDB() *fakeTMDB {
+	return &fakeTMDB{
+		shows:       make(map[int]*dto.TMDBShowResponse),
+		seasons:     make(map[string]*dto.TMDBSeasonResponse),
+		showChanges: make(map[int]*dto.TMDBShowChangesResponse),
+		lists:       make(map[string][]dto.TMDBShowResult),
+		tmdbLists:   make(map[int]*dto.TMDBListResponse),
+		movies:      make(map[int]*dto.TMDBMovieResponse),
+	}
+}
+
+// addShow registers a show with the given number of seasons and episodes per season
+func (f *fakeTMDB) addShow(tmdbID, seasons, episodesPerSeason int) {
+	show := &dto.TMDBShowResponse{
+		ID:     tmdbID,
+		Name:   fmt.Sprintf("Show %d", tmdbID),
+		Status: "Returning Series",
+	}
+	for sn := 1; sn <= seasons; sn++ {
+		show.Seasons = append(show.Seasons, dto.TMDBSeasonInfo{SeasonNumber: sn, EpisodeCount: episodesPerSeason})
+		season := &dto.TMDBSeasonResponse{SeasonNumber: sn}
+		for en := 1; en <= episodesPerSeason; en++ {
+			season.Episodes = append(season.Episodes, dto.TMDBEpisode{
+				SeasonNumber:  sn,
+				EpisodeNumber: en,
+				Name:          fmt.Sprintf("Episode %d", en),
+				AirDate:       fmt.Sprintf("2024-%02d-%02d", sn, en),
+			})
+		}
+		f.seasons[fmt.Sprintf("%d/%d", tmdbID, sn)] = season
+	}
+	f.shows[tmdbID] = show
+}
+
+func (f *fakeTMDB) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests++
+	f.inFlight++
+	if f.inFlight > f.maxInFlight {
+		f.maxInFlight = f.inFlight
+	}
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.inFlight--
+		f.mu.Unlock()
+	}()
+
+	time.Sleep(f.delay)
+
+	var tmdbID, seasonNumber, episodeNumber, listID, movieID int
+	var body interface{}
+	if results, ok := f.lists[r.URL.Path]; ok {
+		body = &dto.TMDBSearchResponse{Page: 1, TotalPages: 1, TotalResults: len(results), Results: results}
+	} else if _, err := fmt.Sscanf(r.URL.Path, "/4/list/%d", &listID); err == nil {
+		if list, ok := f.tmdbLists[listID]; ok {
+			body = list
+		}
+	} else if _, err := fmt.Sscanf(r.URL.Path, "/movie/%d", &movieID); err == nil {
+		if movie, ok := f.movies[movieID]; ok {
+			body = movie
+		}
+	} else if strings.HasPrefix(r.URL.Path, "/find/") {
+		externalID := strings.TrimPrefix(r.URL.Path, "/find/")
+		response := &dto.TMDBFindResponse{TVResults: []dto.TMDBShowResult{}}
+		for id, show := range f.shows {
+			ids := show.ExternalIDs
+			if ids != nil && (ids.IMDbID == externalID || fmt.Sprint(ids.TVDBID) == externalID) {
+				response.TVResults = append(response.TVResults, dto.TMDBShowResult{ID: id, Name: show.Name})
+			}
+		}
+		body = response
+	} else if n, _ := fmt.Sscanf(r.URL.Path, "/tv/%d/season/%d/episode/%d/external_ids", &tmdbID, &seasonNumber, &episodeNumber); n == 3 {
+		body = &dto.TMDBExternalIDs{IMDbID: fmt.Sprintf("tt%d%02d%02d", tmdbID, seasonNumber, episodeNumber)}
+	} else if r.URL.Path == "/tv/changes" {
+		response := &dto.TMDBChangesResponse{Page: 1, TotalPages: 1}
+		for _, id := range f.changedIDs {
+			response.Results = append(response.Results, dto.TMDBChangedItem{ID: id})
+		}
+		body = response
+	} else if strings.HasSuffix(r.URL.Path, "/changes") {
+		fmt.Sscanf(r.URL.Path, "/tv/%d/changes", &tmdbID)
+		body = &dto.TMDBShowChangesResponse{}
+		if changes, ok := f.showChanges[tmdbID]; ok {
+			body = changes
+		}
+	} else if n, _ := fmt.Sscanf(r.URL.Path, "/tv/%d/season/%d", &tmdbID, &seasonNumber); n == 2 {
+		if season, ok := f.seasons[fmt.Sprintf("%d/%d", tmdbID, seasonNumber)]; ok {
+			body = season
+		}
+	} else if n == 1 {
+		if show, ok := f.shows[tmdbID]; ok {
+			body = show
+		}
+	}
+
+	if body == nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(dto.TMDBErrorResponse{StatusCode: 34, StatusMessage: "not found"})
+		return
+	}
+	if lang := r.URL.Query().Get("language"); f.translated[lang] {
+		body = translateBody(body, lang)
+	}
+	json.NewEncoder(w).Encode(body)
+}
+
+// translateBody returns a copy of a show or season response with overviews in lang
+func translateBody(body interface{}, lang string) interface{} {
+	switch v := body.(type) {
+	case *dto.TMDBShowResponse:
+		show := *v
+		show.Overview = lang + " overview"
+		return &show
+	case *dto.TMDBSeasonResponse:
+		season := *v
+		season.Episodes = make([]dto.TMDBEpisode, len(v.Episodes))
+		for i, ep := range v.Episodes {
+			ep.Overview = fmt.Sprintf("%s overview %d", lang, ep.EpisodeNumber)
+			season.Episodes[i] = ep
+		}
+		return &season
+	}
+	return body
+}
+
+func setupCrawlerTest(t *testing.T, fake *fakeTMDB) (*CrawlerService, *gorm.DB) {
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	// File-backed database so concurrent connections share one store
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "crawler.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Show{}, &models.Episode{}, &models.CrawlLog{}, &models.CrawlTask{}, &models.EpisodeChange{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	tmdb := NewTMDBService("test-key", server.URL, "zh-CN")
+	tmdb.SetRateLimit(0)
+	crawler := NewCrawlerService(
+		tmdb,
+		repositories.NewShowRepository(db),
+		repositories.NewEpisodeRepository(db),
+		repositories.NewCrawlLogRepository(db),
+		repositories.NewCrawlTaskRepository(db),
+		repositories.NewEpisodeChangeRepository(db),
+	)
+	return crawler, db
+}
+
+func TestCrawlerService_BatchCrawl_OrderedResults(t *testing.T) {
+	fake := newFakeTMDB()
+	fake.delay = 20 * time.Millisecond
+	tmdbIDs := []int{101, 102, 103, 104, 105, 106}
+	for _, id := range tmdbIDs {
+		if id == 104 {
+			continue // Missing on TMDB
+		}
+		fake.addShow(id, 2, 3)
+	}
+
+	crawler, db := setupCrawlerTest(t, fake)
+	crawler.SetConcurrency(3)
+
+	results := crawler.BatchCrawl(context.Background(), tmdbIDs)
+
+	if len(results) != len(tmdbIDs) {
+		t.Fatalf("Expected %d results, got %d", len(tmdbIDs), len(results))
+	}
+	for i, result := range results {
+		if result.TmdbID != tmdbIDs[i] {
+			t.Errorf("Result %d: expected TMDB ID %d, got %d", i, tmdbIDs[i], result.TmdbID)
+		}
+		if tmdbIDs[i] == 104 {
+			if result.Success {
+				t.Errorf("Expected crawl of missing show to fail")
+			}
+			continue
+		}
+		if !result.Success {
+			t.Errorf("Crawl of %d failed: %v", result.TmdbID, result.Error)
+		}
+		if result.EpisodesCount != 6 {
+			t.Errorf("Expected 6 episodes for %d, got %d", result.TmdbID, result.EpisodesCount)
+		}
+	}
+
+	if fake.maxInFlight < 2 {
+		t.Errorf("Expected concurrent TMDB requests, max in flight was %d", fake.maxInFlight)
+	}
+
+	var logCount int64
+	db.Model(&models.CrawlLog{}).Count(&logCount)
+	if logCount != int64(len(tmdbIDs)) {
+		t.Errorf("Expected one crawl log per show (%d), got %d", len(tmdbIDs), logCount)
+	}
+}
+
+func TestCrawlerService_SetConcurrency(t *testing.T) {
+	crawler := NewCrawlerService(nil, nil, nil, nil, nil, nil)
+	if crawler.GetConcurrency() != DefaultCrawlConcurrency {
+		t.Errorf("Expected default concurrency %d, got %d", DefaultCrawlConcurrency, crawler.GetConcurrency())
+	}
+
+	crawler.SetConcurrency(0)
+	if crawler.GetConcurrency() != 1 {
+		t.Errorf("Concurrency should be clamped to 1, got %d", crawler.GetConcurrency())
+	}
+}
+
+func TestCrawlerService_RefreshIncremental(t *testing.T) {
+	fake := newFakeTMDB()
+	fake.addShow(201, 2, 2)
+	fake.addShow(202, 1, 2)
+
+	crawler, db := setupCrawlerTest(t, fake)
+	crawler.SetConcurrency(1)
+
+	// Full crawls set the initial change cursors
+	for _, result := range crawler.BatchCrawl(context.Background(), []int{201, 202}) {
+		if !result.Success {
+			t.Fatalf("Initial crawl of %d failed: %v", result.TmdbID, result.Error)
+		}
+	}
+	var show models.Show
+	db.Where("tmdb_id = ?", 201).First(&show)
+	if show.ChangeCursor == nil {
+		t.Fatal("Full crawl should set the change cursor")
+	}
+	firstCursor := *show.ChangeCursor
+
+	// TMDB renames episodes in both seasons but only reports season 2 as changed
+	fake.mu.Lock()
+	fake.seasons["201/1"].Episodes[0].Name = "Unreported rename"
+	fake.seasons["201/2"].Episodes[0].Name = "Reported rename"
+	fake.changedIDs = []int{201}
+	fake.showChanges[201] = &dto.TMDBShowChangesResponse{Changes: []dto.TMDBChange{{
+		Key: "season",
+		Items: []dto.TMDBChangeItem{{
+			Action: "updated",
+			Time:   time.Now().UTC().Add(time.Minute).Format(tmdbChangeTimeLayout),
+			Value:  json.RawMessage(`{"season_id": 9002, "season_number": 2}`),
+		}},
+	}}}
+	fake.mu.Unlock()
+
+	crawler.GetTMDBService().ClearCache()
+	summary, err := crawler.RefreshIncremental(context.Background())
+	if err != nil {
+		t.Fatalf("Incremental refresh failed: %v", err)
+	}
+	if summary.Succeeded != 1 || summary.Skipped != 1 || summary.EpisodesUpdated != 1 {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+
+	var episodes []models.Episode
+	db.Where("show_id = ?", show.ID).Order("season_number, episode_number").Find(&episodes)
+	if len(episodes) != 4 {
+		t.Fatalf("Expected 4 episodes, got %d", len(episodes))
+	}
+	if episodes[0].Name == "Unreported rename" {
+		t.Error("Season 1 should not have been re-fetched")
+	}
+	if episodes[2].Name != "Reported rename" {
+		t.Errorf("Season 2 should have been re-fetched, got name %q", episodes[2].Name)
+	}
+
+	// The unchanged show only has its cursor advanced
+	var unchanged models.Show
+	db.Where("tmdb_id = ?", 202).First(&unchanged)
+	if unchanged.ChangeCursor == nil || !unchanged.ChangeCursor.After(firstCursor) {
+		t.Error("Cursor of unchanged show should be advanced")
+	}
+
+	var refreshLogs int64
+	db.Model(&models.CrawlLog{}).Where("action = ?", "refresh").Count(&refreshLogs)
+	if refreshLogs != 1 {
+		t.Errorf("Expected 1 incremental crawl log, got %d", refreshLogs)
+	}
+
+	// An episode change has no season number, so the show is crawled in full
+	fake.mu.Lock()
+	fake.seasons["201/1"].Episodes[1].AirDate = "2024-01-09"
+	fake.showChanges[201] = &dto.TMDBShowChangesResponse{Changes: []dto.TMDBChange{{
+		Key: "episode",
+		Items: []dto.TMDBChangeItem{{
+			Action: "updated",
+			Time:   time.Now().UTC().Add(time.Minute).Format(tmdbChangeTimeLayout),
+			Value:  json.RawMessage(`{"episode_id": 9101, "episode_number": 2}`),
+		}},
+	}}}
+	fake.mu.Unlock()
+
+	crawler.GetTMDBService().ClearCache()
+	if _, err := crawler.RefreshIncremental(context.Background()); err != nil {
+		t.Fatalf("Incremental refresh failed: %v", err)
+	}
+	episodes = nil
+	db.Where("show_id = ?", show.ID).Order("season_number, episode_number").Find(&episodes)
+	if episodes[0].Name != "Unreported rename" {
+		t.Errorf("Expected a full crawl to pick up season 1, got name %q", episodes[0].Name)
+	}
+	if episodes[1].AirDate == nil || episodes[1].AirDate.Format("2006-01-02") != "2024-01-09" {
+		t.Errorf("Expected the changed air date, got %v", episodes[1].AirDate)
+	}
+}
+
+func TestCollectShowChanges(t *testing.T) {
+	since := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	changes := &dto.TMDBShowChangesResponse{Changes: []dto.TMDBChange{
+		{Key: "season", Items: []dto.TMDBChangeItem{
+			{Time: "2026-01-11 08:00:00 UTC", Value: json.RawMessage(`{"season_id": 1, "season_number": 3}`)},
+			{Time: "2026-01-09 08:00:00 UTC", Value: json.RawMessage(`{"season_id": 2, "season_number": 1}`)},
+			{Time: "2026-01-12 08:00:00 UTC", Action: "deleted", OriginalValue: json.RawMessage(`{"season_id": 3, "season_number": 2}`)},
+		}},
+		{Key: "name", Items: []dto.TMDBChangeItem{
+			{Time: "2026-01-09 08:00:00 UTC"},
+		}},
+	}}
+
+	seasons, showChanged, ok := collectShowChanges(changes, since, false)
+	if !ok {
+		t.Fatal("Expected all season changes to be attributed")
+	}
+	if showChanged {
+		t.Error("Name change before cursor should be ignored")
+	}
+	if fmt.Sprint(seasons) != "[2 3]" {
+		t.Errorf("Expected seasons [2 3], got %v", seasons)
+	}
+
+	changes.Changes[1].Items[0].Time = "2026-01-11 08:00:00 UTC"
+	if _, showChanged, _ = collectShowChanges(changes, since, false); !showChanged {
+		t.Error("Name change after cursor should be reported")
+	}
+
+	changes.Changes[0].Items = append(changes.Changes[0].Items, dto.TMDBChangeItem{
+		Time:  "2026-01-11 08:00:00 UTC",
+		Value: json.RawMessage(`{"season_id": 4, "season_number": 0}`),
+	})
+	if seasons, _, _ = collectShowChanges(changes, since, false); fmt.Sprint(seasons) != "[2 3]" {
+		t.Errorf("Specials should be ignored unless included, got %v", seasons)
+	}
+	if seasons, _, _ = collectShowChanges(changes, since, true); fmt.Sprint(seasons) != "[0 2 3]" {
+		t.Errorf("Expected seasons [0 2 3] with specials, got %v", seasons)
+	}
+
+	changes.Changes[0].Items = append(changes.Changes[0].Items, dto.TMDBChangeItem{Time: "2026-01-11 08:00:00 UTC"})
+	if _, _, ok = collectShowChanges(changes, since, false); ok {
+		t.Error("Season change without a season should not be attributed")
+	}
+
+	episodeChanges := &dto.TMDBShowChangesResponse{Changes: []dto.TMDBChange{
+		{Key: "episode", Items: []dto.TMDBChangeItem{
+			{Time: "2026-01-11 08:00:00 UTC", Value: json.RawMessage(`{"episode_id": 62085, "episode_number": 2}`)},
+		}},
+	}}
+	if _, _, ok = collectShowChanges(episodeChanges, since, false); ok {
+		t.Error("Episode change has no season and should not be attributed")
+	}
+}
+
+func TestCrawlerService_RefreshAll_ContinuesPastFailures(t *testing.T) {
+	fake := newFakeTMDB()
+	for _, id := range []int{301, 302, 303} {
+		fake.addShow(id, 1, 2)
+	}
+
+	crawler, db := setupCrawlerTest(t, fake)
+	crawler.BatchCrawl(context.Background(), []int{301, 302, 303})
+
+	// 302 disappears from TMDB, 303 gains an episode and renames another
+	fake.mu.Lock()
+	delete(fake.shows, 302)
+	season := fake.seasons["303/1"]
+	season.Episodes[0].Name = "Renamed"
+	season.Episodes = append(season.Episodes, dto.TMDBEpisode{SeasonNumber: 1, EpisodeNumber: 3, Name: "Episode 3"})
+	fake.mu.Unlock()
+	crawler.GetTMDBService().ClearCache()
+
+	summary, err := crawler.RefreshAll(context.Background())
+	if err != nil {
+		t.Fatalf("RefreshAll failed: %v", err)
+	}
+
+	if summary.Total != 3 || summary.Succeeded != 2 || summary.Failed != 1 {
+		t.Errorf("Unexpected summary counts: %+v", summary)
+	}
+	if summary.EpisodesAdded != 1 || summary.EpisodesUpdated != 1 {
+		t.Errorf("Expected 1 episode added and 1 updated, got %d and %d", summary.EpisodesAdded, summary.EpisodesUpdated)
+	}
+	for _, show := range summary.Shows {
+		if show.TmdbID == 302 && (show.Status != showCrawlFailed || show.Error == "") {
+			t.Errorf("Expected failure with reason for 302, got %+v", show)
+		}
+	}
+	if summary.Err() == nil {
+		t.Error("Summary with failures should report an error")
+	}
+
+	var changes []models.EpisodeChange
+	db.Order("episode_number").Find(&changes)
+	if len(changes) != 2 {
+		t.Fatalf("Expected 2 recorded episode changes, got %d", len(changes))
+	}
+	if changes[0].ChangeType != "title_changed" || changes[0].OldValue != "Episode 1" || changes[0].NewValue != "Renamed" {
+		t.Errorf("Unexpected title change: %+v", changes[0])
+	}
+	if changes[1].ChangeType != "added" || changes[1].CrawlLogID == nil {
+		t.Errorf("Expected added change linked to a crawl log, got %+v", changes[1])
+	}
+}
+
+func TestTaskManager_PersistsSummary(t *testing.T) {
+	fake := newFakeTMDB()
+	fake.addShow(401, 1, 2)
+
+	crawler, db := setupCrawlerTest(t, fake)
+	crawler.BatchCrawl(context.Background(), []int{401, 402})
+
+	manager := NewTaskManager(repositories.NewCrawlTaskRepository(db), crawler)
+	task, err := manager.StartRefreshAll()
+	if err != nil {
+		t.Fatalf("Failed to start task: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		task, err = manager.GetTask(task.ID)
+		if err == nil && (task.Status == taskStatusSuccess || task.Status == taskStatusFailed) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Task did not finish in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if task.Status != taskStatusSuccess {
+		t.Errorf("Expected task success, got %s: %s", task.Status, task.ErrorMessage)
+	}
+
+	var summary CrawlSummary
+	if err := json.Unmarshal([]byte(task.Summary), &summary); err != nil {
+		t.Fatalf("Failed to decode task summary: %v", err)
+	}
+	if summary.Total != 1 || summary.Succeeded != 1 {
+		t.Errorf("Unexpected persisted summary: %+v", summary)
+	}
+
+	if task.TotalShows != 1 || task.ProcessedShows != 1 || task.GetProgressPercent() != 100 {
+		t.Errorf("Expected completed progress 1/1, got %d/%d", task.ProcessedShows, task.TotalShows)
+	}
+}
+
+// recordingProgress records the progress callbacks of a batch crawl
+type recordingProgress struct {
+	mu       sync.Mutex
+	total    int
+	started  []string
+	finished []int
+}
+
+func (p *recordingProgress) BatchStarted(tmdbIDs []int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.total = len(tmdbIDs)
+}
+
+func (p *recordingProgress) ShowStarted(tmdbID int, name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.started = append(p.started, name)
+}
+
+func (p *recordingProgress) ShowFinished(result *CrawlResult) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.finished = append(p.finished, result.TmdbID)
+}
+
+func TestCrawlerService_BatchCrawl_ReportsProgress(t *testing.T) {
+	fake := newFakeTMDB()
+	fake.addShow(701, 1, 2)
+	fake.addShow(702, 1, 2)
+
+	crawler, _ := setupCrawlerTest(t, fake)
+	crawler.BatchCrawl(context.Background(), []int{701})
+
+	progress := &recordingProgress{}
+	crawler.BatchCrawl(WithCrawlProgress(context.Background(), progress), []int{701, 702, 703})
+
+	if progress.total != 3 {
+		t.Errorf("Expected total 3, got %d", progress.total)
+	}
+	if len(progress.started) != 3 || len(progress.finished) != 3 {
+		t.Fatalf("Expected 3 started and finished shows, got %d and %d", len(progress.started), len(progress.finished))
+	}
+
+	names := strings.Join(progress.started, ",")
+	if !strings.Contains(names, "Show 701") {
+		t.Errorf("Expected stored show name to be reported, got %q", names)
+	}
+}
+
+func TestTaskManager_CancelTask(t *testing.T) {
+	fake := newFakeTMDB()
+	for id := 601; id <= 605; id++ {
+		fake.addShow(id, 1, 2)
+	}
+
+	crawler, db := setupCrawlerTest(t, fake)
+	crawler.BatchCrawl(context.Background(), []int{601, 602, 603, 604, 605})
+	crawler.SetConcurrency(1)
+	crawler.GetTMDBService().ClearCache()
+	fake.delay = 50 * time.Millisecond
+
+	manager := NewTaskManager(repositories.NewCrawlTaskRepository(db), crawler)
+	if err := manager.CancelTask(9999); err != ErrTaskNotRunning {
+		t.Errorf("Expected ErrTaskNotRunning for unknown task, got %v", err)
+	}
+
+	task, err := manager.StartRefreshAll()
+	if err != nil {
+		t.Fatalf("Failed to start task: %v", err)
+	}
+	time.Sleep(80 * time.Millisecond)
+	if err := manager.CancelTask(task.ID); err != nil {
+		t.Fatalf("Failed to cancel task: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		task, err = manager.GetTask(task.ID)
+		if err == nil && task.IsCompleted() {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Task did not finish in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if task.Status != taskStatusCancelled {
+		t.Fatalf("Expected task cancelled, got %s: %s", task.Status, task.ErrorMessage)
+	}
+
+	var summary CrawlSummary
+	if err := json.Unmarshal([]byte(task.Summary), &summary); err != nil {
+		t.Fatalf("Failed to decode task summary: %v", err)
+	}
+	if summary.Total != 5 || summary.Skipped == 0 || summary.Failed != 0 {
+		t.Errorf("Expected partial summary with cancelled shows, got %+v", summary)
+	}
+
+	params, err := parseTaskParams(task.Params)
+	if err != nil {
+		t.Fatalf("Failed to decode task params: %v", err)
+	}
+	if len(params.RemainingTmdbIDs) != summary.Skipped {
+		t.Errorf("Expected %d remaining TMDB IDs in checkpoint, got %v", summary.Skipped, params.RemainingTmdbIDs)
+	}
+
+	if err := manager.CancelTask(task.ID); err != ErrTaskNotRunning {
+		t.Errorf("Expected ErrTaskNotRunning for finished task, got %v", err)
+	}
+}
+
+// waitForTask polls the task until it has completed
+func waitForTask(t *testing.T, manager *TaskManager, id uint) *models.CrawlTask {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		task, err := manager.GetTask(id)
+		if err == nil && task.IsCompleted() {
+			return task
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Task did not finish in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTaskManager_RecoverInterrupted(t *testing.T) {
+	fake := newFakeTMDB()
+	fake.addShow(801, 1, 2)
+	fake.addShow(802, 1, 2)
+
+	crawler, db := setupCrawlerTest(t, fake)
+	crawler.BatchCrawl(context.Background(), []int{801, 802})
+
+	taskRepo := repositories.NewCrawlTaskRepository(db)
+	startedAt := time.Now().Add(-time.Hour)
+	orphan := func(params string) *models.CrawlTask {
+		task := &models.CrawlTask{Type: "refresh_all", Status: taskStatusRunning, Params: params, StartedAt: &startedAt,
+			TotalShows: 3, ProcessedShows: 1}
+		if err := taskRepo.Create(task); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		return task
+	}
+
+	t.Run("MarkInterrupted", func(t *testing.T) {
+		task := orphan(`{"remaining_tmdb_ids":[801,802]}`)
+		manager := NewTaskManager(taskRepo, crawler)
+
+		resumed, interrupted, err := manager.RecoverInterrupted(false)
+		if err != nil || resumed != 0 || interrupted != 1 {
+			t.Fatalf("Expected 1 interrupted task, got resumed=%d interrupted=%d err=%v", resumed, interrupted, err)
+		}
+
+		stored, _ := taskRepo.GetByID(task.ID)
+		if stored.Status != taskStatusInterrupted || stored.FinishedAt == nil {
+			t.Errorf("Expected task marked interrupted, got %s", stored.Status)
+		}
+	})
+
+	t.Run("QueuedTask", func(t *testing.T) {
+		// The process died before the task's goroutine marked it running
+		task := &models.CrawlTask{Type: "refresh_all", Status: taskStatusQueued}
+		if err := taskRepo.Create(task); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		manager := NewTaskManager(taskRepo, crawler)
+
+		resumed, interrupted, err := manager.RecoverInterrupted(true)
+		if err != nil || resumed != 0 || interrupted != 1 {
+			t.Fatalf("Expected 1 interrupted task, got resumed=%d interrupted=%d err=%v", resumed, interrupted, err)
+		}
+
+		stored, _ := taskRepo.GetByID(task.ID)
+		if stored.Status != taskStatusInterrupted || stored.FinishedAt == nil {
+			t.Errorf("Expected queued task marked interrupted, got %s", stored.Status)
+		}
+	})
+
+	t.Run("OtherTaskTypes", func(t *testing.T) {
+		// Correction tasks are created and run by the correction service
+		task := &models.CrawlTask{Type: "correction", Status: taskStatusQueued}
+		if err := taskRepo.Create(task); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		manager := NewTaskManager(taskRepo, crawler)
+
+		resumed, interrupted, err := manager.RecoverInterrupted(true)
+		if err != nil || resumed != 0 || interrupted != 0 {
+			t.Fatalf("Expected no recovered tasks, got resumed=%d interrupted=%d err=%v", resumed, interrupted, err)
+		}
+
+		stored, _ := taskRepo.GetByID(task.ID)
+		if stored.Status != taskStatusQueued || stored.FinishedAt != nil {
+			t.Errorf("Expected the correction task to be left queued, got %s", stored.Status)
+		}
+	})
+
+	t.Run("ResumeFromCheckpoint", func(t *testing.T) {
+		withCheckpoint := orphan(`{"remaining_tmdb_ids":[801,802]}`)
+		// The show finished before the interruption is in the checkpointed summary
+		withCheckpoint.Summary = `{"total":1,"succeeded":1,"episodes_added":3,"shows":[{"tmdb_id":800,"name":"Show 800","status":"success","episodes_added":3}]}`
+		if err := taskRepo.Update(withCheckpoint); err != nil {
+			t.Fatalf("Failed to checkpoint summary: %v", err)
+		}
+		withoutCheckpoint := orphan("")
+		manager := NewTaskManager(taskRepo, crawler)
+
+		resumed, interrupted, err := manager.RecoverInterrupted(true)
+		if err != nil || resumed != 1 || interrupted != 1 {
+			t.Fatalf("Expected 1 resumed and 1 interrupted task, got resumed=%d interrupted=%d err=%v", resumed, interrupted, err)
+		}
+
+		task := waitForTask(t, manager, withCheckpoint.ID)
+		if task.Status != taskStatusSuccess {
+			t.Fatalf("Expected resumed task success, got %s: %s", task.Status, task.ErrorMessage)
+		}
+		if task.TotalShows != 3 || task.ProcessedShows != 3 {
+			t.Errorf("Expected progress 3/3 after resume, got %d/%d", task.ProcessedShows, task.TotalShows)
+		}
+		if !task.StartedAt.Equal(startedAt) {
+			t.Errorf("Expected original start time to be kept, got %v", task.StartedAt)
+		}
+		if params, _ := parseTaskParams(task.Params); len(params.RemainingTmdbIDs) != 0 {
+			t.Errorf("Expected empty checkpoint, got %v", params.RemainingTmdbIDs)
+		}
+
+		var summary CrawlSummary
+		if err := json.Unmarshal([]byte(task.Summary), &summary); err != nil {
+			t.Fatalf("Failed to decode task summary: %v", err)
+		}
+		if summary.Total != 3 || summary.Succeeded != 3 || summary.EpisodesAdded != 3 {
+			t.Errorf("Expected the summary to keep the show finished before the interruption, got %+v", summary)
+		}
+		if len(summary.Shows) != 3 || summary.Shows[0].TmdbID != 800 {
+			t.Errorf("Expected the earlier show first, got %+v", summary.Shows)
+		}
+
+		stored, _ := taskRepo.GetByID(withoutCheckpoint.ID)
+		if stored.Status != taskStatusInterrupted {
+			t.Errorf("Expected task without checkpoint marked interrupted, got %s", stored.Status)
+		}
+	})
+}
+
+func TestDiffEpisodes(t *testing.T) {
+	day := func(d int) *time.Time {
+		date := time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+	existing := []*models.Episode{
+		{ShowID: 1, SeasonNumber: 1, EpisodeNumber: 1, Name: "Pilot", AirDate: day(1)},
+		{ShowID: 1, SeasonNumber: 1, EpisodeNumber: 2, Name: "Second", AirDate: day(8)},
+		{ShowID: 1, SeasonNumber: 1, EpisodeNumber: 3, Name: "Third", AirDate: day(15)},
+	}
+	fetched := []*models.Episode{
+		{ShowID: 1, SeasonNumber: 1, EpisodeNumber: 1, Name: "Pilot", AirDate: day(1), Overview: "New overview"},
+		{ShowID: 1, SeasonNumber: 1, EpisodeNumber: 2, Name: "Second Part", AirDate: day(9)},
+		{ShowID: 1, SeasonNumber: 1, EpisodeNumber: 4, Name: "Fourth"},
+	}
+
+	stats, changes := diffEpisodes(existing, fetched)
+	if stats.added != 1 || stats.updated != 2 {
+		t.Errorf("Expected 1 added and 2 updated, got %+v", stats)
+	}
+
+	got := make([]string, len(changes))
+	for i, change := range changes {
+		got[i] = fmt.Sprintf("E%d %s %s->%s", change.EpisodeNumber, change.ChangeType, change.OldValue, change.NewValue)
+	}
+	want := []string{
+		"E2 air_date_changed 2026-01-08->2026-01-09",
+		"E2 title_changed Second->Second Part",
+		"E4 added ->Fourth",
+		"E3 removed Third->",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Unexpected changes:\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestCrawlerService_CrawlShow_Specials(t *testing.T) {
+	fake := newFakeTMDB()
+	fake.addShow(501, 1, 2)
+	fake.shows[501].Seasons = append([]dto.TMDBSeasonInfo{{SeasonNumber: 0, EpisodeCount: 1}}, fake.shows[501].Seasons...)
+	fake.seasons["501/0"] = &dto.TMDBSeasonResponse{Episodes: []dto.TMDBEpisode{
+		{SeasonNumber: 0, EpisodeNumber: 1, Name: "Special"},
+	}}
+
+	crawler, db := setupCrawlerTest(t, fake)
+
+	if err := crawler.CrawlShow(context.Background(), 501); err != nil {
+		t.Fatalf("Crawl failed: %v", err)
+	}
+	var count int64
+	db.Model(&models.Episode{}).Where("season_number = 0").Count(&count)
+	if count != 0 {
+		t.Errorf("Specials should be skipped by default, got %d", count)
+	}
+
+	db.Model(&models.Show{}).Where("tmdb_id = ?", 501).UpdateColumn("include_specials", true)
+	if err := crawler.CrawlShow(context.Background(), 501); err != nil {
+		t.Fatalf("Crawl with specials failed: %v", err)
+	}
+	var special models.Episode
+	if err := db.Where("season_number = 0").First(&special).Error; err != nil {
+		t.Fatalf("Special was not stored: %v", err)
+	}
+	if special.GetEpisodeCode() != "SP01" {
+		t.Errorf("Expected SP01, got %s", special.GetEpisodeCode())
+	}
+}
+
+func TestCrawlerService_CrawlNewShow_Specials(t *testing.T) {
+	fake := newFakeTMDB()
+	fake.addShow(502, 1, 2)
+	fake.shows[502].Seasons = append([]dto.TMDBSeasonInfo{{SeasonNumber: 0, EpisodeCount: 1}}, fake.shows[502].Seasons...)
+	fake.seasons["502/0"] = &dto.TMDBSeasonResponse{Episodes: []dto.TMDBEpisode{
+		{SeasonNumber: 0, EpisodeNumber: 1, Name: "Special"},
+	}}
+
+	crawler, db := setupCrawlerTest(t, fake)
+
+	if err := crawler.CrawlNewShow(context.Background(), 502, true); err != nil {
+		t.Fatalf("Crawl failed: %v", err)
+	}
+	var show models.Show
+	if err := db.Where("tmdb_id = ?", 502).First(&show).Error; err != nil {
+		t.Fatalf("Show was not stored: %v", err)
+	}
+	if !show.IncludeSpecials {
+		t.Error("Expected include_specials to be stored on the new show")
+	}
+	var count int64
+	db.Model(&models.Episode{}).Where("season_number = 0").Count(&count)
+	if count != 1 {
+		t.Errorf("Expected the special from the first crawl, got %d", count)
+	}
+	db.Model(&models.EpisodeChange{}).Count(&count)
+	if count != 0 {
+		t.Errorf("A new show should have no change history, got %d changes", count)
+	}
+}
+
+func TestCrawlerService_CrawlShow_FallbackLanguages(t *testing.T) {
+	fake := newFakeTMDB()
+	fake.addShow(601, 1, 2)
+	fake.translated = map[string]bool{"en-US": true}
+	crawler, db := setupCrawlerTest(t, fake)
+	crawler.SetFallbackLanguages([]string{"ja-JP", "en-US"})
+
+	if err := crawler.CrawlShow(context.Background(), 601); err != nil {
+		t.Fatalf("Crawl failed: %v", err)
+	}
+
+	var show models.Show
+	if err := db.Where("tmdb_id = ?", 601).First(&show).Error; err != nil {
+		t.Fatalf("Show was not stored: %v", err)
+	}
+	if show.Overview != "en-US overview" {
+		t.Errorf("Expected overview from en-US fallback, got %q", show.Overview)
+	}
+	if tr, ok := show.Translations["zh-CN"]; !ok || tr.Name != "Show 601" {
+		t.Errorf("Expected primary translation to be stored, got %+v", show.Translations)
+	}
+	if tr := show.Translations["ja-JP"]; tr.Overview != "" {
+		t.Errorf("Expected ja-JP translation without overview, got %+v", tr)
+	}
+
+	var episodes []models.Episode
+	db.Order("episode_number").Find(&episodes)
+	if len(episodes) != 2 {
+		t.Fatalf("Expected 2 episodes, got %d", len(episodes))
+	}
+	for _, ep := range episodes {
+		want := fmt.Sprintf("en-US overview %d", ep.EpisodeNumber)
+		if ep.Overview != want {
+			t.Errorf("Expected episode overview %q, got %q", want, ep.Overview)
+		}
+		if ep.Translations["en-US"].Overview != want {
+			t.Errorf("Expected en-US translation %q, got %+v", want, ep.Translations)
+		}
+	}
+
+	// A show with text in the primary language needs no fallback requests
+	fake.mu.Lock()
+	fake.translated = map[string]bool{"zh-CN": true, "en-US": true}
+	requests := fake.requests
+	fake.mu.Unlock()
+	crawler.GetTMDBService().ClearCache()
+	if err := crawler.CrawlShow(context.Background(), 601); err != nil {
+		t.Fatalf("Second crawl failed: %v", err)
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if got := fake.requests - requests; got != 2 {
+		t.Errorf("Expected 2 requests (show and season) without fallbacks, got %d", got)
+	}
+}
+
+func TestCrawlerService_CrawlShow_ExternalIDs(t *testing.T) {
+	fake := newFakeTMDB()
+	fake.addShow(701, 1, 2)
+	fake.shows[701].ExternalIDs = &dto.TMDBExternalIDs{IMDbID: "tt0903747", TVDBID: 81189}
+	crawler, db := setupCrawlerTest(t, fake)
+	crawler.SetEpisodeExternalIDs(true)
+
+	if err := crawler.CrawlShow(context.Background(), 701); err != nil {
+		t.Fatalf("Crawl failed: %v", err)
+	}
+
+	show, err := repositories.NewShowRepository(db).GetByImdbID("tt0903747")
+	if err != nil {
+		t.Fatalf("Show not found by IMDb ID: %v", err)
+	}
+	if show.TmdbID != 701 || show.TvdbID != 81189 {
+		t.Errorf("Unexpected show: tmdb_id=%d tvdb_id=%d", show.TmdbID, show.TvdbID)
+	}
+
+	var episode models.Episode
+	db.Where("season_number = 1 AND episode_number = 2").First(&episode)
+	if episode.ImdbID != "tt7010102" {
+		t.Errorf("Expected episode IMDb ID tt7010102, got %q", episode.ImdbID)
+	}
+
+	// External IDs resolve back to the TMDB show
+	result, err := crawler.GetTMDBService().FindShowByExternalID(context.Background(), ExternalSourceTVDB, "81189")
+	if err != nil {
+		t.Fatalf("Find by TVDB ID failed: %v", err)
+	}
+	if result.ID != 701 {
+		t.Errorf("Expected TMDB ID 701, got %d", result.ID)
+	}
+	if _, err := crawler.GetTMDBService().FindShowByExternalID(context.Background(), ExternalSourceIMDb, "tt0000001"); !errors.Is(err, ErrExternalIDNotFound) {
+		t.Errorf("Expected ErrExternalIDNotFound, got %v", err)
+	}
+}
+
+func TestCrawlerService_CrawlShow_Schedule(t *testing.T) {
+	fake := newFakeTMDB()
+	fake.addShow(801, 1, 2)
+	tmdbShow := fake.shows[801]
+	tmdbShow.Networks = []dto.TMDBCompany{{ID: 129, Name: "A&E", OriginCountry: "US"}}
+	tmdbShow.OriginCountry = []string{"US", "GB"}
+	tmdbShow.EpisodeRunTime = []int{45, 50}
+	tmdbShow.InProduction = true
+	tmdbShow.Genres = []dto.TMDBGenre{{ID: 18, Name: "Drama"}}
+	tmdbShow.LastEpisodeToAir = &dto.TMDBEpisode{SeasonNumber: 1, EpisodeNumber: 1, AirDate: "2024-01-01"}
+	tmdbShow.NextEpisodeToAir = &dto.TMDBEpisode{SeasonNumber: 1, EpisodeNumber: 2, AirDate: "2024-01-08"}
+	crawler, db := setupCrawlerTest(t, fake)
+
+	if err := crawler.CrawlShow(context.Background(), 801); err != nil {
+		t.Fatalf("Crawl failed: %v", err)
+	}
+
+	showRepo := repositories.NewShowRepository(db)
+	show, _ := showRepo.GetByTmdbID(801)
+	if show.NextAirDate == nil || show.NextAirDate.Format("2006-01-02") != "2024-01-08" {
+		t.Errorf("Expected next air date 2024-01-08, got %v", show.NextAirDate)
+	}
+	if show.NextEpisodeNumber != 2 || show.LastEpisodeNumber != 1 {
+		t.Errorf("Unexpected next/last episode: %d/%d", show.NextEpisodeNumber, show.LastEpisodeNumber)
+	}
+	if show.EpisodeRunTime != 45 || !show.InProduction || show.OriginCountry != "US,GB" {
+		t.Errorf("Unexpected schedule: runtime=%d in_production=%v country=%q", show.EpisodeRunTime, show.InProduction, show.OriginCountry)
+	}
+
+	for _, filter := range []repositories.ShowFilter{{Network: "a&e"}, {Network: "129"}, {Country: "gb"}, {Genre: "drama"}} {
+		if _, total, _ := showRepo.ListByFilter(filter, 1, 10); total != 1 {
+			t.Errorf("Expected filter %+v to match the show, got %d", filter, total)
+		}
+	}
+
+	// Without a next episode the air date is cleared
+	tmdbShow.NextEpisodeToAir = nil
+	crawler.GetTMDBService().ClearCache()
+	if err := crawler.CrawlShow(context.Background(), 801); err != nil {
+		t.Fatalf("Recrawl failed: %v", err)
+	}
+	show, _ = showRepo.GetByTmdbID(801)
+	if show.NextAirDate != nil {
+		t.Errorf("Expected next air date to be cleared, got %v", show.NextAirDate)
+	}
+}
+
+func TestCrawlerService_CrawlShow_AirSchedule(t *testing.T) {
+	fake := newFakeTMDB()
+	fake.addShow(802, 1, 2)
+	fake.addShow(803, 1, 1)
+	fake.shows[802].Networks = []dto.TMDBCompany{{ID: 213, Name: "Netflix", OriginCountry: "US"}}
+	fake.shows[803].Networks = []dto.TMDBCompany{{ID: 4, Name: "BBC One", OriginCountry: "GB"}}
+	crawler, db := setupCrawlerTest(t, fake)
+	ctx := context.Background()
+
+	for _, tmdbID := range []int{802, 803} {
+		if err := crawler.CrawlShow(ctx, tmdbID); err != nil {
+			t.Fatalf("Crawl of %d failed: %v", tmdbID, err)
+		}
+	}
+
+	showRepo := repositories.NewShowRepository(db)
+	episodeRepo := repositories.NewEpisodeRepository(db)
+
+	// A network with a known release time sets both timezone and air time
+	show, _ := showRepo.GetByTmdbID(802)
+	if show.AirTimezone != "America/Los_Angeles" || show.AirTime != "00:00" {
+		t.Errorf("Unexpected air schedule: %q %q", show.AirTimezone, show.AirTime)
+	}
+	episodes, _ := episodeRepo.GetByShowID(show.ID)
+	expected := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	if episodes[0].AirAt == nil || !episodes[0].AirAt.Equal(expected) {
+		t.Errorf("Expected air instant %v, got %v", expected, episodes[0].AirAt)
+	}
+
+	// Other networks only get the timezone of their country
+	other, _ := showRepo.GetByTmdbID(803)
+	if other.AirTimezone != "Europe/London" || other.AirTime != "" {
+		t.Errorf("Unexpected air schedule: %q %q", other.AirTimezone, other.AirTime)
+	}
+	if episodes, _ := episodeRepo.GetByShowID(other.ID); episodes[0].AirAt != nil {
+		t.Errorf("Expected no air instant without an air time, got %v", episodes[0].AirAt)
+	}
+
+	// A schedule set by hand survives a recrawl
+	show.AirTimezone = "America/New_York"
+	show.AirTime = "21:00"
+	if err := showRepo.Update(show); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	crawler.GetTMDBService().ClearCache()
+	if err := crawler.CrawlShow(ctx, 802); err != nil {
+		t.Fatalf("Recrawl failed: %v", err)
+	}
+	show, _ = showRepo.GetByTmdbID(802)
+	if show.AirTimezone != "America/New_York" || show.AirTime != "21:00" {
+		t.Errorf("Expected the hand-set schedule to be kept, got %q %q", show.AirTimezone, show.AirTime)
+	}
+	episodes, _ = episodeRepo.GetByShowID(show.ID)
+	expected = time.Date(2024, 1, 2, 2, 0, 0, 0, time.UTC)
+	if episodes[0].AirAt == nil || !episodes[0].AirAt.Equal(expected) {
+		t.Errorf("Expected air instant %v, got %v", expected, episodes[0].AirAt)
+	}
+	// An air time set by hand is combined with the derived timezone
+	other.AirTime = "21:00"
+	if err := showRepo.Update(other); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err := crawler.CrawlShow(ctx, 803); err != nil {
+		t.Fatalf("Recrawl failed: %v", err)
+	}
+	other, _ = showRepo.GetByTmdbID(803)
+	if other.AirTimezone != "Europe/London" || other.AirTime != "21:00" {
+		t.Errorf("Unexpected air schedule: %q %q", other.AirTimezone, other.AirTime)
+	}
+	episodes, _ = episodeRepo.GetByShowID(other.ID)
+	expected = time.Date(2024, 1, 1, 21, 0, 0, 0, time.UTC)
+	if episodes[0].AirAt == nil || !episodes[0].AirAt.Equal(expected) {
+		t.Errorf("Expected air instant %v, got %v", expected, episodes[0].AirAt)
+	}
+}
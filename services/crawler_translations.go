@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+
+	"github.com/xc9973/go-tmdb-crawler/dto"
+	"github.com/xc9973/go-tmdb-crawler/models"
+)
+
+// applyShowTranslations stores the show's name and overview per language.
+// The primary language always comes from tmdbShow; the fallback languages are
+// fetched, in order, only while the name or overview is still empty, and
+// fill the empty fields. Failed fallback requests are skipped.
+func (s *CrawlerService) applyShowTranslations(ctx context.Context, show *models.Show, tmdbShow *dto.TMDBShowResponse) {
+	primary := s.tmdb.Language()
+	show.Translations = nil
+	setTranslation(&show.Translations, primary, tmdbShow.Name, tmdbShow.Overview)
+
+	for _, lang := range s.GetFallbackLanguages() {
+		if show.Name != "" && show.Overview != "" {
+			return
+		}
+		if lang == primary {
+			continue
+		}
+
+		translated, err := s.tmdb.GetShowDetailsInLanguage(ctx, tmdbShow.ID, lang)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+		setTranslation(&show.Translations, lang, translated.Name, translated.Overview)
+		if show.Name == "" {
+			show.Name = translated.Name
+		}
+		if show.Overview == "" {
+			show.Overview = translated.Overview
+		}
+	}
+}
+
+// applyEpisodeTranslations fills the empty names and overviews of a season's
+// episodes from the fallback languages. A fallback season is only fetched while
+// some episode still misses text; its translations are stored for every episode.
+func (s *CrawlerService) applyEpisodeTranslations(ctx context.Context, tmdbID, seasonNumber int, episodes []*models.Episode) {
+	primary := s.tmdb.Language()
+	for _, lang := range s.GetFallbackLanguages() {
+		if !hasMissingText(episodes) {
+			return
+		}
+		if lang == primary {
+			continue
+		}
+
+		translated, err := s.tmdb.GetSeasonEpisodesInLanguage(ctx, tmdbID, seasonNumber, lang)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		byNumber := make(map[int]dto.TMDBEpisode, len(translated.Episodes))
+		for _, tmdbEpisode := range translated.Episodes {
+			byNumber[tmdbEpisode.EpisodeNumber] = tmdbEpisode
+		}
+		for _, episode := range episodes {
+			tmdbEpisode, ok := byNumber[episode.EpisodeNumber]
+			if !ok {
+				continue
+			}
+			setTranslation(&episode.Translations, lang, tmdbEpisode.Name, tmdbEpisode.Overview)
+			if episode.Name == "" {
+				episode.Name = tmdbEpisode.Name
+			}
+			if episode.Overview == "" {
+				episode.Overview = tmdbEpisode.Overview
+			}
+		}
+	}
+}
+
+// hasMissingText checks if any episode has no name or overview
+func hasMissingText(episodes []*models.Episode) bool {
+	for _, episode := range episodes {
+		if episode.Name == "" || episode.Overview == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// setTranslation stores a translation unless TMDB returned no text for lang
+func setTranslation(translations *models.Translations, lang, name, overview string) {
+	if lang == "" || (name == "" && overview == "") {
+		return
+	}
+	translations.Set(lang, models.Translation{Name: name, Overview: overview})
+}
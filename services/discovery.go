@@ -0,0 +1,350 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xc9973/go-tmdb-crawler/dto"
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/repositories"
+)
+
+// ErrCandidateNotPending is returned when approving or rejecting a reviewed candidate
+var ErrCandidateNotPending = errors.New("candidate is not pending review")
+
+// ErrDiscoveryRunning is returned when a discovery run is already in progress
+var ErrDiscoveryRunning = errors.New("discovery is already running")
+
+// DiscoveryRules decide which shows of the TMDB lists become candidates.
+// Empty lists and zero minimums match every show.
+type DiscoveryRules struct {
+	// Sources are the TMDB lists to pull (TMDBListOnTheAir, TMDBListAiringToday, TMDBListTrending)
+	Sources []string `json:"sources"`
+	// Pages is the number of pages pulled per list
+	Pages int `json:"pages"`
+	// Languages are accepted original languages (ISO 639-1, e.g. "en")
+	Languages []string `json:"languages"`
+	// Countries are accepted origin countries; one match is enough
+	Countries []string `json:"countries"`
+	// GenreIDs are accepted TMDB genres; one match is enough
+	GenreIDs      []int   `json:"genre_ids"`
+	MinPopularity float64 `json:"min_popularity"`
+	MinVoteCount  int     `json:"min_vote_count"`
+	// AutoFollow follows matching shows right away instead of queueing them for review
+	AutoFollow bool `json:"auto_follow"`
+}
+
+// DefaultDiscoveryRules returns rules that queue every show of the first page of each list
+func DefaultDiscoveryRules() DiscoveryRules {
+	return DiscoveryRules{
+		Sources: []string{TMDBListOnTheAir, TMDBListAiringToday, TMDBListTrending},
+		Pages:   1,
+	}
+}
+
+// Match reports whether a show of a TMDB list passes the rules
+func (r DiscoveryRules) Match(show *dto.TMDBShowResult) bool {
+	if show.Popularity < r.MinPopularity || show.VoteCount < r.MinVoteCount {
+		return false
+	}
+	if len(r.Languages) > 0 && !containsFold(r.Languages, show.OriginalLanguage) {
+		return false
+	}
+	if len(r.Countries) > 0 {
+		matched := false
+		for _, country := range show.OriginCountry {
+			if containsFold(r.Countries, country) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(r.GenreIDs) > 0 {
+		matched := false
+		for _, id := range show.GenreIDs {
+			if containsInt(r.GenreIDs, id) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// containsFold reports whether list contains value, ignoring case
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsInt reports whether list contains value
+func containsInt(list []int, value int) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// DiscoveryResult summarizes a discovery run
+type DiscoveryResult struct {
+	Fetched  int      `json:"fetched"`  // distinct shows in the lists
+	Matched  int      `json:"matched"`  // shows passing the rules that are not followed yet
+	Queued   int      `json:"queued"`   // new candidates waiting for review
+	Followed int      `json:"followed"` // shows followed automatically
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// DiscoveryService finds new shows in TMDB's TV lists and queues or follows them
+type DiscoveryService struct {
+	crawler       *CrawlerService
+	showRepo      repositories.ShowRepository
+	candidateRepo repositories.DiscoveryCandidateRepository
+
+	rules   DiscoveryRules
+	mu      sync.RWMutex
+	running sync.Mutex
+}
+
+// NewDiscoveryService creates a discovery service with the default rules
+func NewDiscoveryService(
+	crawler *CrawlerService,
+	showRepo repositories.ShowRepository,
+	candidateRepo repositories.DiscoveryCandidateRepository,
+) *DiscoveryService {
+	return &DiscoveryService{
+		crawler:       crawler,
+		showRepo:      showRepo,
+		candidateRepo: candidateRepo,
+		rules:         DefaultDiscoveryRules(),
+	}
+}
+
+// SetRules replaces the discovery rules
+func (s *DiscoveryService) SetRules(rules DiscoveryRules) {
+	if rules.Pages < 1 {
+		rules.Pages = 1
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules = rules
+}
+
+// GetRules returns the discovery rules
+func (s *DiscoveryService) GetRules() DiscoveryRules {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.rules
+}
+
+// Run pulls the TMDB lists and applies the rules.
+// Followed shows are skipped, rejected candidates stay rejected and pending
+// candidates get their list statistics refreshed. A list that fails is
+// reported in the result; the run only fails when no list could be read.
+func (s *DiscoveryService) Run(ctx context.Context) (*DiscoveryResult, error) {
+	if !s.running.TryLock() {
+		return nil, ErrDiscoveryRunning
+	}
+	defer s.running.Unlock()
+
+	rules := s.GetRules()
+	result := &DiscoveryResult{}
+
+	found := make(map[int]*dto.TMDBShowResult)
+	sources := make(map[int][]string)
+	var order []int
+	listsRead := 0
+	for _, source := range rules.Sources {
+		for page := 1; page <= rules.Pages; page++ {
+			response, err := s.crawler.GetTMDBService().GetShowList(ctx, source, page)
+			if err != nil {
+				if ctx.Err() != nil {
+					return result, ctx.Err()
+				}
+				result.Errors = append(result.Errors, fmt.Sprintf("%s page %d: %v", source, page, err))
+				break
+			}
+			listsRead++
+			for i := range response.Results {
+				show := &response.Results[i]
+				if _, seen := found[show.ID]; !seen {
+					found[show.ID] = show
+					order = append(order, show.ID)
+				}
+				sources[show.ID] = append(sources[show.ID], source)
+			}
+			if page >= response.TotalPages {
+				break
+			}
+		}
+	}
+	if listsRead == 0 && len(result.Errors) > 0 {
+		return result, fmt.Errorf("failed to fetch TMDB lists: %s", strings.Join(result.Errors, "; "))
+	}
+	result.Fetched = len(order)
+
+	showRepo := s.showRepo.WithContext(ctx)
+	candidateRepo := s.candidateRepo.WithContext(ctx)
+
+	followed := make(map[int]bool)
+	if len(order) > 0 {
+		shows, err := showRepo.GetByTmdbIDs(order)
+		if err != nil {
+			return result, fmt.Errorf("failed to load followed shows: %w", err)
+		}
+		for _, show := range shows {
+			followed[show.TmdbID] = true
+		}
+	}
+	stored, err := candidateRepo.GetByTmdbIDs(order)
+	if err != nil {
+		return result, fmt.Errorf("failed to load candidates: %w", err)
+	}
+	candidates := make(map[int]*models.DiscoveryCandidate, len(stored))
+	for _, candidate := range stored {
+		candidates[candidate.TmdbID] = candidate
+	}
+
+	now := time.Now()
+	for _, tmdbID := range order {
+		show := found[tmdbID]
+		if followed[tmdbID] || !rules.Match(show) {
+			continue
+		}
+
+		candidate, exists := candidates[tmdbID]
+		if exists && !candidate.IsPending() {
+			continue
+		}
+		result.Matched++
+
+		if !exists {
+			candidate = &models.DiscoveryCandidate{Status: models.DiscoveryStatusPending}
+		}
+		applyListResult(candidate, show)
+		for _, source := range sources[tmdbID] {
+			candidate.AddSource(source)
+		}
+		candidate.LastSeenAt = now
+
+		if exists {
+			err = candidateRepo.Update(candidate)
+		} else {
+			err = candidateRepo.Create(candidate)
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("show %d: %v", tmdbID, err))
+			continue
+		}
+
+		if rules.AutoFollow {
+			if err := s.follow(ctx, candidate, true); err != nil {
+				// The candidate stays in the queue for review
+				result.Errors = append(result.Errors, fmt.Sprintf("follow show %d: %v", tmdbID, err))
+			} else {
+				result.Followed++
+				continue
+			}
+		}
+		if !exists {
+			result.Queued++
+		}
+	}
+
+	return result, nil
+}
+
+// Approve follows a pending candidate: the show is crawled and added to the followed shows
+func (s *DiscoveryService) Approve(ctx context.Context, id uint) (*models.DiscoveryCandidate, error) {
+	candidate, err := s.candidateRepo.WithContext(ctx).GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !candidate.IsPending() {
+		return candidate, ErrCandidateNotPending
+	}
+	if err := s.follow(ctx, candidate, false); err != nil {
+		return candidate, err
+	}
+	return candidate, nil
+}
+
+// Reject removes a pending candidate from the queue; later runs keep skipping it
+func (s *DiscoveryService) Reject(ctx context.Context, id uint) (*models.DiscoveryCandidate, error) {
+	candidateRepo := s.candidateRepo.WithContext(ctx)
+	candidate, err := candidateRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !candidate.IsPending() {
+		return candidate, ErrCandidateNotPending
+	}
+
+	now := time.Now()
+	candidate.Status = models.DiscoveryStatusRejected
+	candidate.ReviewedAt = &now
+	if err := candidateRepo.Update(candidate); err != nil {
+		return candidate, err
+	}
+	return candidate, nil
+}
+
+// follow crawls the candidate's show, unless it is followed already, and marks the candidate approved
+func (s *DiscoveryService) follow(ctx context.Context, candidate *models.DiscoveryCandidate, auto bool) error {
+	showRepo := s.showRepo.WithContext(ctx)
+	show, err := showRepo.GetByTmdbID(candidate.TmdbID)
+	if err != nil {
+		if err := s.crawler.CrawlShow(ctx, candidate.TmdbID); err != nil {
+			return err
+		}
+		if show, err = showRepo.GetByTmdbID(candidate.TmdbID); err != nil {
+			return fmt.Errorf("failed to load followed show: %w", err)
+		}
+	}
+
+	now := time.Now()
+	candidate.Status = models.DiscoveryStatusApproved
+	candidate.AutoFollowed = auto
+	candidate.ShowID = &show.ID
+	candidate.ReviewedAt = &now
+	return s.candidateRepo.WithContext(ctx).Update(candidate)
+}
+
+// applyListResult copies the TMDB list entry of a show onto its candidate
+func applyListResult(candidate *models.DiscoveryCandidate, show *dto.TMDBShowResult) {
+	candidate.TmdbID = show.ID
+	candidate.Name = show.Name
+	if candidate.Name == "" {
+		candidate.Name = show.OriginalName
+	}
+	candidate.OriginalName = show.OriginalName
+	candidate.OriginalLanguage = show.OriginalLanguage
+	candidate.OriginCountry = strings.Join(show.OriginCountry, ",")
+	genreIDs := make([]string, 0, len(show.GenreIDs))
+	for _, id := range show.GenreIDs {
+		genreIDs = append(genreIDs, strconv.Itoa(id))
+	}
+	candidate.GenreIDs = strings.Join(genreIDs, ",")
+	candidate.Overview = show.Overview
+	candidate.PosterPath = show.PosterPath
+	candidate.FirstAirDate, _ = ParseDate(show.FirstAirDate)
+	candidate.Popularity = show.Popularity
+	candidate.VoteAverage = show.VoteAverage
+	candidate.VoteCount = show.VoteCount
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/xc9973/go-tmdb-crawler/dto"
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/repositories"
+)
+
+func setupDiscoveryTest(t *testing.T, fake *fakeTMDB) (*DiscoveryService, *CrawlerService, repositories.ShowRepository, repositories.DiscoveryCandidateRepository) {
+	crawler, db := setupCrawlerTest(t, fake)
+	if err := db.AutoMigrate(&models.DiscoveryCandidate{}); err != nil {
+		t.Fatalf("Failed to migrate discovery candidates: %v", err)
+	}
+	showRepo := repositories.NewShowRepository(db)
+	candidateRepo := repositories.NewDiscoveryCandidateRepository(db)
+	return NewDiscoveryService(crawler, showRepo, candidateRepo), crawler, showRepo, candidateRepo
+}
+
+func TestDiscoveryRules_Match(t *testing.T) {
+	show := &dto.TMDBShowResult{
+		ID:               1,
+		OriginalLanguage: "en",
+		OriginCountry:    []string{"GB", "US"},
+		GenreIDs:         []int{18, 80},
+		Popularity:       50,
+		VoteCount:        120,
+	}
+
+	tests := []struct {
+		name  string
+		rules DiscoveryRules
+		want  bool
+	}{
+		{"Empty rules", DiscoveryRules{}, true},
+		{"Language matches", DiscoveryRules{Languages: []string{"EN"}}, true},
+		{"Language does not match", DiscoveryRules{Languages: []string{"ja", "ko"}}, false},
+		{"One country matches", DiscoveryRules{Countries: []string{"us"}}, true},
+		{"No country matches", DiscoveryRules{Countries: []string{"JP"}}, false},
+		{"One genre matches", DiscoveryRules{GenreIDs: []int{80, 10765}}, true},
+		{"No genre matches", DiscoveryRules{GenreIDs: []int{16}}, false},
+		{"Popularity below minimum", DiscoveryRules{MinPopularity: 60}, false},
+		{"Vote count below minimum", DiscoveryRules{MinVoteCount: 200}, false},
+		{"All minimums met", DiscoveryRules{MinPopularity: 50, MinVoteCount: 120}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rules.Match(show); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscoveryService_Run_QueuesAndReviews(t *testing.T) {
+	fake := newFakeTMDB()
+	fake.addShow(901, 1, 1)
+	fake.addShow(902, 1, 2)
+	fake.addShow(903, 1, 1)
+	fake.lists["/tv/on_the_air"] = []dto.TMDBShowResult{
+		{ID: 901, Name: "Show 901", OriginalLanguage: "en", Popularity: 80, VoteCount: 300},
+		{ID: 902, Name: "Show 902", OriginalLanguage: "en", Popularity: 40, VoteCount: 150},
+		{ID: 904, Name: "Show 904", OriginalLanguage: "ja", Popularity: 90, VoteCount: 500},
+	}
+	fake.lists["/trending/tv/day"] = []dto.TMDBShowResult{
+		{ID: 902, Name: "Show 902", OriginalLanguage: "en", Popularity: 40, VoteCount: 150},
+		{ID: 903, Name: "Show 903", OriginalLanguage: "en", Popularity: 30, VoteCount: 10},
+	}
+
+	discovery, crawler, showRepo, candidateRepo := setupDiscoveryTest(t, fake)
+	discovery.SetRules(DiscoveryRules{
+		Sources:      []string{TMDBListOnTheAir, TMDBListTrending},
+		Languages:    []string{"en"},
+		MinVoteCount: 100,
+	})
+	ctx := context.Background()
+
+	// 901 is followed already and must not be queued
+	if err := crawler.CrawlShow(ctx, 901); err != nil {
+		t.Fatalf("CrawlShow failed: %v", err)
+	}
+
+	result, err := discovery.Run(ctx)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Fetched != 4 || result.Matched != 1 || result.Queued != 1 || result.Followed != 0 {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+
+	candidates, err := candidateRepo.GetByTmdbIDs([]int{901, 902, 903, 904})
+	if err != nil {
+		t.Fatalf("GetByTmdbIDs failed: %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].TmdbID != 902 {
+		t.Fatalf("Expected only show 902 to be queued, got %+v", candidates)
+	}
+	candidate := candidates[0]
+	if candidate.Status != models.DiscoveryStatusPending || candidate.Sources != "on_the_air,trending" {
+		t.Errorf("Unexpected candidate: status=%s sources=%s", candidate.Status, candidate.Sources)
+	}
+
+	// A second run refreshes the pending candidate without queueing it again
+	result, err = discovery.Run(ctx)
+	if err != nil {
+		t.Fatalf("Second run failed: %v", err)
+	}
+	if result.Matched != 1 || result.Queued != 0 {
+		t.Errorf("Expected the candidate to be refreshed only, got %+v", result)
+	}
+
+	approved, err := discovery.Approve(ctx, candidate.ID)
+	if err != nil {
+		t.Fatalf("Approve failed: %v", err)
+	}
+	if approved.Status != models.DiscoveryStatusApproved || approved.ShowID == nil || approved.AutoFollowed {
+		t.Errorf("Unexpected approved candidate: %+v", approved)
+	}
+	show, err := showRepo.GetByTmdbID(902)
+	if err != nil {
+		t.Fatalf("Approved show was not crawled: %v", err)
+	}
+	if *approved.ShowID != show.ID {
+		t.Errorf("Expected show ID %d, got %d", show.ID, *approved.ShowID)
+	}
+
+	if _, err := discovery.Reject(ctx, candidate.ID); !errors.Is(err, ErrCandidateNotPending) {
+		t.Errorf("Expected ErrCandidateNotPending, got %v", err)
+	}
+}
+
+func TestDiscoveryService_Reject_StaysRejected(t *testing.T) {
+	fake := newFakeTMDB()
+	fake.lists["/tv/airing_today"] = []dto.TMDBShowResult{
+		{ID: 911, Name: "Show 911", Popularity: 10},
+	}
+
+	discovery, _, _, candidateRepo := setupDiscoveryTest(t, fake)
+	discovery.SetRules(DiscoveryRules{Sources: []string{TMDBListAiringToday}})
+	ctx := context.Background()
+
+	if _, err := discovery.Run(ctx); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	candidates, _ := candidateRepo.GetByTmdbIDs([]int{911})
+	if len(candidates) != 1 {
+		t.Fatalf("Expected one candidate, got %d", len(candidates))
+	}
+	if _, err := discovery.Reject(ctx, candidates[0].ID); err != nil {
+		t.Fatalf("Reject failed: %v", err)
+	}
+
+	result, err := discovery.Run(ctx)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Matched != 0 || result.Queued != 0 {
+		t.Errorf("Expected the rejected show to be skipped, got %+v", result)
+	}
+	rejected, _ := candidateRepo.GetByID(candidates[0].ID)
+	if rejected.Status != models.DiscoveryStatusRejected {
+		t.Errorf("Expected candidate to stay rejected, got %s", rejected.Status)
+	}
+}
+
+func TestDiscoveryService_Run_AutoFollow(t *testing.T) {
+	fake := newFakeTMDB()
+	fake.addShow(921, 1, 3)
+	fake.lists["/tv/on_the_air"] = []dto.TMDBShowResult{
+		{ID: 921, Name: "Show 921", OriginCountry: []string{"KR"}, GenreIDs: []int{18}},
+		{ID: 922, Name: "Show 922", OriginCountry: []string{"US"}, GenreIDs: []int{18}},
+	}
+
+	discovery, _, showRepo, candidateRepo := setupDiscoveryTest(t, fake)
+	discovery.SetRules(DiscoveryRules{
+		Sources:    []string{TMDBListOnTheAir},
+		Countries:  []string{"KR"},
+		AutoFollow: true,
+	})
+
+	result, err := discovery.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Followed != 1 || result.Queued != 0 {
+		t.Fatalf("Expected one followed show, got %+v", result)
+	}
+
+	show, err := showRepo.GetByTmdbID(921)
+	if err != nil {
+		t.Fatalf("Auto-followed show was not crawled: %v", err)
+	}
+	candidates, _ := candidateRepo.GetByTmdbIDs([]int{921})
+	if len(candidates) != 1 || !candidates[0].AutoFollowed || candidates[0].ShowID == nil || *candidates[0].ShowID != show.ID {
+		t.Errorf("Unexpected auto-followed candidate: %+v", candidates)
+	}
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"github.com/xc9973/go-tmdb-crawler/models"
+)
+
+const (
+	episodeChangeAdded         = "added"
+	episodeChangeRemoved       = "removed"
+	episodeChangeAirDateMoved  = "air_date_changed"
+	episodeChangeTitleChanged  = "title_changed"
+	episodeChangeAirDateLayout = "2006-01-02"
+)
+
+// episodeStats counts the episodes added and updated by a crawl
+type episodeStats struct {
+	added   int
+	updated int
+}
+
+// episodeKey identifies an episode within a show
+type episodeKey struct {
+	season  int
+	episode int
+}
+
+// diffEpisodes compares fetched episodes against the stored ones.
+// Stored episodes missing from fetched are reported as removed, so callers
+// must only pass the stored episodes of the seasons that were fetched.
+func diffEpisodes(existing, fetched []*models.Episode) (episodeStats, []*models.EpisodeChange) {
+	byKey := make(map[episodeKey]*models.Episode, len(existing))
+	for _, ep := range existing {
+		byKey[episodeKey{ep.SeasonNumber, ep.EpisodeNumber}] = ep
+	}
+
+	var stats episodeStats
+	var changes []*models.EpisodeChange
+	for _, ep := range fetched {
+		key := episodeKey{ep.SeasonNumber, ep.EpisodeNumber}
+		old, ok := byKey[key]
+		if !ok {
+			stats.added++
+			changes = append(changes, newEpisodeChange(ep, episodeChangeAdded, "", ep.Name))
+			continue
+		}
+		delete(byKey, key)
+
+		if episodeContentChanged(old, ep) {
+			stats.updated++
+		}
+		if !sameAirDate(old, ep) {
+			changes = append(changes, newEpisodeChange(ep, episodeChangeAirDateMoved,
+				formatChangeAirDate(old), formatChangeAirDate(ep)))
+		}
+		if old.Name != ep.Name {
+			changes = append(changes, newEpisodeChange(ep, episodeChangeTitleChanged, old.Name, ep.Name))
+		}
+	}
+
+	for _, ep := range existing {
+		if _, ok := byKey[episodeKey{ep.SeasonNumber, ep.EpisodeNumber}]; ok {
+			changes = append(changes, newEpisodeChange(ep, episodeChangeRemoved, ep.Name, ""))
+		}
+	}
+
+	return stats, changes
+}
+
+// episodeContentChanged reports whether the TMDB-sourced fields of an episode differ
+func episodeContentChanged(old, new *models.Episode) bool {
+	return !sameAirDate(old, new) ||
+		old.Name != new.Name ||
+		old.Overview != new.Overview ||
+		old.StillPath != new.StillPath ||
+		old.Runtime != new.Runtime
+}
+
+// sameAirDate reports whether two episodes air on the same date
+func sameAirDate(old, new *models.Episode) bool {
+	if old.AirDate == nil || new.AirDate == nil {
+		return old.AirDate == nil && new.AirDate == nil
+	}
+	return old.AirDate.Equal(*new.AirDate)
+}
+
+func formatChangeAirDate(ep *models.Episode) string {
+	if ep.AirDate == nil {
+		return ""
+	}
+	return ep.AirDate.Format(episodeChangeAirDateLayout)
+}
+
+func newEpisodeChange(ep *models.Episode, changeType, oldValue, newValue string) *models.EpisodeChange {
+	return &models.EpisodeChange{
+		ShowID:        ep.ShowID,
+		SeasonNumber:  ep.SeasonNumber,
+		EpisodeNumber: ep.EpisodeNumber,
+		ChangeType:    changeType,
+		OldValue:      oldValue,
+		NewValue:      newValue,
+	}
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/repositories"
+)
+
+// ErrInvalidFeedToken is returned for unknown or revoked calendar feed tokens
+var ErrInvalidFeedToken = errors.New("invalid or revoked feed token")
+
+// defaultEventDuration is the length of an episode event when neither the
+// episode nor the show has a runtime
+const defaultEventDuration = 60 * time.Minute
+
+// CalendarFeedService builds iCalendar (RFC 5545) feeds of episodes and manages
+// the tokens calendar clients subscribe with
+type CalendarFeedService struct {
+	episodeRepo repositories.EpisodeRepository
+	tokenRepo   repositories.FeedTokenRepository
+}
+
+// NewCalendarFeedService creates a new calendar feed service instance
+func NewCalendarFeedService(episodeRepo repositories.EpisodeRepository, tokenRepo repositories.FeedTokenRepository) *CalendarFeedService {
+	return &CalendarFeedService{
+		episodeRepo: episodeRepo,
+		tokenRepo:   tokenRepo,
+	}
+}
+
+// CreateToken creates a feed token named name.
+// The returned string is the token itself; only its hash is stored.
+func (s *CalendarFeedService) CreateToken(ctx context.Context, name string) (*models.FeedToken, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", fmt.Errorf("failed to generate feed token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	feedToken := &models.FeedToken{
+		Name:        strings.TrimSpace(name),
+		TokenHash:   hashFeedToken(token),
+		TokenPrefix: token[:8],
+	}
+	if err := feedToken.Validate(); err != nil {
+		return nil, "", err
+	}
+	if err := s.tokenRepo.WithContext(ctx).Create(feedToken); err != nil {
+		return nil, "", fmt.Errorf("failed to create feed token: %w", err)
+	}
+	return feedToken, token, nil
+}
+
+// ValidateToken checks a token from a feed URL and records its use.
+// Returns ErrInvalidFeedToken for unknown and revoked tokens.
+func (s *CalendarFeedService) ValidateToken(ctx context.Context, token string) (*models.FeedToken, error) {
+	if token == "" {
+		return nil, ErrInvalidFeedToken
+	}
+	tokenRepo := s.tokenRepo.WithContext(ctx)
+	feedToken, err := tokenRepo.GetByHash(hashFeedToken(token))
+	if err != nil || feedToken.IsRevoked() {
+		return nil, ErrInvalidFeedToken
+	}
+
+	// Failing to record the use must not break the feed
+	now := time.Now()
+	if err := tokenRepo.TouchLastUsed(feedToken.ID, now); err == nil {
+		feedToken.LastUsedAt = &now
+	}
+	return feedToken, nil
+}
+
+// GenerateFeed builds the calendar of the episodes matching filter from startDate through endDate.
+// Names and overviews are rendered in lang when a translation exists.
+func (s *CalendarFeedService) GenerateFeed(ctx context.Context, name string, startDate, endDate time.Time, filter repositories.EpisodeFilter, lang string) (string, error) {
+	episodes, err := s.episodeRepo.WithContext(ctx).GetByDateRangeFiltered(startDate, endDate, filter)
+	if err != nil {
+		return "", fmt.Errorf("failed to get episodes: %w", err)
+	}
+	return RenderCalendar(name, LocalizeEpisodes(episodes, lang), time.Now()), nil
+}
+
+// RenderCalendar renders episodes as an iCalendar named name, with one VEVENT per episode.
+// Episodes with a known air instant become timed events lasting their runtime;
+// date-only episodes become all-day events. now is the DTSTAMP of the events.
+func RenderCalendar(name string, episodes []*models.Episode, now time.Time) string {
+	var builder strings.Builder
+	writeICalLine(&builder, "BEGIN:VCALENDAR")
+	writeICalLine(&builder, "VERSION:2.0")
+	writeICalLine(&builder, "PRODID:-//go-tmdb-crawler//Episode Calendar//EN")
+	writeICalLine(&builder, "CALSCALE:GREGORIAN")
+	writeICalLine(&builder, "METHOD:PUBLISH")
+	writeICalLine(&builder, "X-WR-CALNAME:"+escapeICalText(name))
+	// Ask clients to poll hourly; most use their own interval anyway
+	writeICalLine(&builder, "REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	writeICalLine(&builder, "X-PUBLISHED-TTL:PT1H")
+
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, episode := range episodes {
+		if episode == nil || episode.AirDate == nil {
+			continue
+		}
+		writeICalEvent(&builder, episode, stamp)
+	}
+
+	writeICalLine(&builder, "END:VCALENDAR")
+	return builder.String()
+}
+
+// writeICalEvent writes the VEVENT of an episode
+func writeICalEvent(builder *strings.Builder, episode *models.Episode, stamp string) {
+	showName := fmt.Sprintf("ShowID:%d", episode.ShowID)
+	tmdbID := 0
+	if episode.Show != nil {
+		showName = episode.Show.Name
+		tmdbID = episode.Show.TmdbID
+	}
+
+	summary := fmt.Sprintf("%s %s", showName, episode.GetEpisodeCode())
+	if episode.Name != "" {
+		summary += " - " + episode.Name
+	}
+
+	writeICalLine(builder, "BEGIN:VEVENT")
+	// Episodes are recreated on every crawl, so the UID is built from the show and
+	// episode numbers instead of the database ID to stay stable
+	writeICalLine(builder, fmt.Sprintf("UID:show-%d-s%d-e%d@go-tmdb-crawler",
+		episode.ShowID, episode.SeasonNumber, episode.EpisodeNumber))
+	writeICalLine(builder, "DTSTAMP:"+stamp)
+	if episode.AirAt != nil {
+		start := episode.AirAt.UTC()
+		writeICalLine(builder, "DTSTART:"+start.Format("20060102T150405Z"))
+		writeICalLine(builder, "DTEND:"+start.Add(episodeDuration(episode)).Format("20060102T150405Z"))
+	} else {
+		day := *episode.AirDate
+		writeICalLine(builder, "DTSTART;VALUE=DATE:"+day.Format("20060102"))
+		writeICalLine(builder, "DTEND;VALUE=DATE:"+day.AddDate(0, 0, 1).Format("20060102"))
+	}
+	writeICalLine(builder, "SUMMARY:"+escapeICalText(summary))
+
+	description := episode.Overview
+	if tmdbID > 0 {
+		url := fmt.Sprintf("https://www.themoviedb.org/tv/%d/season/%d/episode/%d",
+			tmdbID, episode.SeasonNumber, episode.EpisodeNumber)
+		writeICalLine(builder, "URL:"+url)
+		if description != "" {
+			description += "\n\n"
+		}
+		description += url
+	}
+	if description != "" {
+		writeICalLine(builder, "DESCRIPTION:"+escapeICalText(description))
+	}
+	writeICalLine(builder, "TRANSP:TRANSPARENT")
+	writeICalLine(builder, "END:VEVENT")
+}
+
+// episodeDuration returns the runtime of an episode, falling back to the show's
+// usual runtime and then to defaultEventDuration
+func episodeDuration(episode *models.Episode) time.Duration {
+	if episode.Runtime > 0 {
+		return time.Duration(episode.Runtime) * time.Minute
+	}
+	if episode.Show != nil && episode.Show.EpisodeRunTime > 0 {
+		return time.Duration(episode.Show.EpisodeRunTime) * time.Minute
+	}
+	return defaultEventDuration
+}
+
+// escapeICalText escapes a TEXT value (RFC 5545 section 3.3.11)
+func escapeICalText(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\n", `\n`,
+	).Replace(text)
+}
+
+// writeICalLine writes a content line ending in CRLF, folded after 75 octets
+// (RFC 5545 section 3.1) without splitting multi-byte characters
+func writeICalLine(builder *strings.Builder, line string) {
+	const maxOctets = 75
+	limit := maxOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		builder.WriteString(line[:cut])
+		builder.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts towards the limit
+		limit = maxOctets - 1
+	}
+	builder.WriteString(line)
+	builder.WriteString("\r\n")
+}
+
+// hashFeedToken returns the hex SHA-256 hash under which a feed token is stored
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
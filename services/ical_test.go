@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/repositories"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRenderCalendar(t *testing.T) {
+	show := &models.Show{ID: 1, TmdbID: 1396, Name: "Breaking Bad", EpisodeRunTime: 47}
+	airDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	airAt := time.Date(2024, 3, 2, 2, 0, 0, 0, time.UTC)
+	episodes := []*models.Episode{
+		{ShowID: 1, Show: show, SeasonNumber: 1, EpisodeNumber: 1, Name: "Pilot", AirDate: &airDate, AirAt: &airAt,
+			Overview: "Walter White, a chemistry teacher; diagnosed, with cancer"},
+		{ShowID: 1, Show: show, SeasonNumber: 1, EpisodeNumber: 2, AirDate: &airDate, Runtime: 50},
+		{ShowID: 1, Show: show, SeasonNumber: 1, EpisodeNumber: 3}, // No air date
+	}
+	now := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	calendar := RenderCalendar("TMDB 剧集更新", episodes, now)
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"X-WR-CALNAME:TMDB 剧集更新\r\n",
+		"UID:show-1-s1-e1@go-tmdb-crawler\r\n",
+		"DTSTAMP:20240201T120000Z\r\n",
+		// Timed event with the show's runtime
+		"DTSTART:20240302T020000Z\r\nDTEND:20240302T024700Z\r\n",
+		"SUMMARY:Breaking Bad S01E01 - Pilot\r\n",
+		// All-day event for a date-only episode
+		"DTSTART;VALUE=DATE:20240301\r\nDTEND;VALUE=DATE:20240302\r\n",
+		"SUMMARY:Breaking Bad S01E02\r\n",
+		"URL:https://www.themoviedb.org/tv/1396/season/1/episode/2\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(calendar, want) {
+			t.Errorf("Expected calendar to contain %q, got:\n%s", want, calendar)
+		}
+	}
+	if count := strings.Count(calendar, "BEGIN:VEVENT"); count != 2 {
+		t.Errorf("Expected 2 events, got %d", count)
+	}
+
+	// Text values are escaped, then folded at 75 octets
+	unfolded := strings.ReplaceAll(calendar, "\r\n ", "")
+	if !strings.Contains(unfolded, `teacher\; diagnosed\, with cancer\n\nhttps://`) {
+		t.Errorf("Expected an escaped description, got:\n%s", unfolded)
+	}
+	for _, line := range strings.Split(calendar, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Line longer than 75 octets: %q", line)
+		}
+	}
+}
+
+func TestWriteICalLine_FoldsMultiByteCharacters(t *testing.T) {
+	var builder strings.Builder
+	writeICalLine(&builder, "SUMMARY:"+strings.Repeat("绝命毒师", 20))
+
+	lines := strings.Split(strings.TrimSuffix(builder.String(), "\r\n"), "\r\n")
+	if len(lines) < 2 {
+		t.Fatalf("Expected the line to be folded, got %q", builder.String())
+	}
+	for i, line := range lines {
+		if len(line) > 75 {
+			t.Errorf("Line %d longer than 75 octets: %d", i, len(line))
+		}
+		if !strings.HasPrefix(line, "SUMMARY:") && !strings.HasPrefix(line, " ") {
+			t.Errorf("Continuation line %d does not start with a space: %q", i, line)
+		}
+		if strings.ContainsRune(line, '�') {
+			t.Errorf("Line %d splits a character: %q", i, line)
+		}
+	}
+}
+
+func TestCalendarFeedService_Tokens(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "feed.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.FeedToken{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	tokenRepo := repositories.NewFeedTokenRepository(db)
+	service := NewCalendarFeedService(nil, tokenRepo)
+	ctx := context.Background()
+
+	feedToken, token, err := service.CreateToken(ctx, "Google Calendar")
+	if err != nil {
+		t.Fatalf("CreateToken failed: %v", err)
+	}
+	if token == "" || feedToken.TokenHash == token || !strings.HasPrefix(token, feedToken.TokenPrefix) {
+		t.Errorf("Unexpected token %q for %+v", token, feedToken)
+	}
+
+	validated, err := service.ValidateToken(ctx, token)
+	if err != nil {
+		t.Fatalf("ValidateToken failed: %v", err)
+	}
+	if validated.ID != feedToken.ID || validated.LastUsedAt == nil {
+		t.Errorf("Expected the token to be recorded as used, got %+v", validated)
+	}
+	if _, err := service.ValidateToken(ctx, "wrong"); !errors.Is(err, ErrInvalidFeedToken) {
+		t.Errorf("Expected ErrInvalidFeedToken for an unknown token, got %v", err)
+	}
+
+	if err := tokenRepo.Revoke(feedToken.ID); err != nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+	if _, err := service.ValidateToken(ctx, token); !errors.Is(err, ErrInvalidFeedToken) {
+		t.Errorf("Expected ErrInvalidFeedToken for a revoked token, got %v", err)
+	}
+	if err := tokenRepo.Revoke(feedToken.ID); err != nil {
+		t.Errorf("Expected revoking twice to succeed, got %v", err)
+	}
+	if err := tokenRepo.Revoke(feedToken.ID + 1); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound for an unknown token, got %v", err)
+	}
+
+	if _, _, err := service.CreateToken(ctx, "  "); err == nil {
+		t.Error("Expected an error for a token without a name")
+	}
+}
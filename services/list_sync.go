@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/repositories"
+)
+
+// ErrListSyncRunning is returned when a list sync is already in progress
+var ErrListSyncRunning = errors.New("list sync is already running")
+
+// ListSyncResult summarizes the sync of one TMDB list
+type ListSyncResult struct {
+	ListID   int      `json:"list_id"`
+	Name     string   `json:"name"`
+	Shows    int      `json:"shows"`    // TV shows on the list (movies are ignored)
+	Crawled  int      `json:"crawled"`  // shows crawled successfully
+	Failed   int      `json:"failed"`   // shows whose crawl failed
+	Archived int      `json:"archived"` // shows archived because they left the list
+	Restored int      `json:"restored"` // archived shows that are back on the list
+	Status   string   `json:"status"`   // success/partial/failed
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// ListSyncService follows TMDB v4 lists: every show on a list is crawled and
+// shows dropped from a list can be archived
+type ListSyncService struct {
+	crawler  *CrawlerService
+	showRepo repositories.ShowRepository
+	listRepo repositories.TMDBListRepository
+
+	running sync.Mutex
+}
+
+// NewListSyncService creates a new list sync service
+func NewListSyncService(
+	crawler *CrawlerService,
+	showRepo repositories.ShowRepository,
+	listRepo repositories.TMDBListRepository,
+) *ListSyncService {
+	return &ListSyncService{
+		crawler:  crawler,
+		showRepo: showRepo,
+		listRepo: listRepo,
+	}
+}
+
+// SyncAll syncs every enabled list, one after another
+func (s *ListSyncService) SyncAll(ctx context.Context) ([]*ListSyncResult, error) {
+	if !s.running.TryLock() {
+		return nil, ErrListSyncRunning
+	}
+	defer s.running.Unlock()
+
+	lists, err := s.listRepo.WithContext(ctx).ListEnabled()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load lists: %w", err)
+	}
+
+	results := make([]*ListSyncResult, 0, len(lists))
+	for _, list := range lists {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		results = append(results, s.sync(ctx, list))
+	}
+	return results, nil
+}
+
+// Sync syncs a single list, whether it is enabled or not
+func (s *ListSyncService) Sync(ctx context.Context, id uint) (*ListSyncResult, error) {
+	if !s.running.TryLock() {
+		return nil, ErrListSyncRunning
+	}
+	defer s.running.Unlock()
+
+	list, err := s.listRepo.WithContext(ctx).GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.sync(ctx, list), nil
+}
+
+// sync crawls the shows of a list, archives or restores shows and records the
+// outcome on the list and in a list_sync crawl log
+func (s *ListSyncService) sync(ctx context.Context, list *models.TMDBList) *ListSyncResult {
+	startTime := time.Now()
+	result := &ListSyncResult{ListID: list.ListID, Name: list.Name}
+
+	tmdbIDs, err := s.fetchShowIDs(ctx, list)
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		s.finish(list, result, 0, startTime)
+		return result
+	}
+	result.Name = list.Name
+	result.Shows = len(tmdbIDs)
+
+	episodes := 0
+	for _, crawl := range s.crawler.BatchCrawl(ctx, tmdbIDs) {
+		switch {
+		case crawl.Success:
+			result.Crawled++
+			episodes += crawl.EpisodesCount
+		case crawl.Cancelled:
+		default:
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("show %d: %v", crawl.TmdbID, crawl.Error))
+		}
+	}
+
+	// A cancelled sync leaves the archive state and the stored list contents as they were
+	if err := ctx.Err(); err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("sync cancelled: %v", err))
+		s.finish(list, result, episodes, startTime)
+		return result
+	}
+
+	if err := s.updateArchive(ctx, list, tmdbIDs, result); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+	list.SetShowTmdbIDs(tmdbIDs)
+
+	s.finish(list, result, episodes, startTime)
+	return result
+}
+
+// fetchShowIDs reads every page of a list and returns the TMDB IDs of its TV shows.
+// The list's name and description are refreshed from TMDB.
+func (s *ListSyncService) fetchShowIDs(ctx context.Context, list *models.TMDBList) ([]int, error) {
+	var tmdbIDs []int
+	seen := make(map[int]bool)
+	for page := 1; ; page++ {
+		response, err := s.crawler.GetTMDBService().GetList(ctx, list.ListID, page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch TMDB list %d: %w", list.ListID, err)
+		}
+		if page == 1 {
+			list.Name = response.Name
+			list.Description = response.Description
+		}
+		for _, item := range response.Results {
+			if item.MediaType != "tv" || seen[item.ID] {
+				continue
+			}
+			seen[item.ID] = true
+			tmdbIDs = append(tmdbIDs, item.ID)
+		}
+		if page >= response.TotalPages {
+			break
+		}
+	}
+	return tmdbIDs, nil
+}
+
+// updateArchive restores archived shows that are on the list and, when the list
+// archives removed shows, archives the shows that left it. A show that is still
+// on another enabled list is not archived.
+func (s *ListSyncService) updateArchive(ctx context.Context, list *models.TMDBList, tmdbIDs []int, result *ListSyncResult) error {
+	showRepo := s.showRepo.WithContext(ctx)
+
+	shows, err := showRepo.GetByTmdbIDs(tmdbIDs)
+	if err != nil {
+		return fmt.Errorf("failed to load list shows: %w", err)
+	}
+	var restore []uint
+	for _, show := range shows {
+		if show.IsArchived() {
+			restore = append(restore, show.ID)
+		}
+	}
+	if err := showRepo.SetArchived(restore, nil); err != nil {
+		return fmt.Errorf("failed to restore shows: %w", err)
+	}
+	result.Restored = len(restore)
+
+	if !list.ArchiveRemoved {
+		return nil
+	}
+
+	keep := make(map[int]bool, len(tmdbIDs))
+	for _, id := range tmdbIDs {
+		keep[id] = true
+	}
+	others, err := s.listRepo.WithContext(ctx).ListEnabled()
+	if err != nil {
+		return fmt.Errorf("failed to load lists: %w", err)
+	}
+	for _, other := range others {
+		if other.ID == list.ID {
+			continue
+		}
+		for _, id := range other.GetShowTmdbIDs() {
+			keep[id] = true
+		}
+	}
+
+	var removed []int
+	for _, id := range list.GetShowTmdbIDs() {
+		if !keep[id] {
+			removed = append(removed, id)
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	shows, err = showRepo.GetByTmdbIDs(removed)
+	if err != nil {
+		return fmt.Errorf("failed to load removed shows: %w", err)
+	}
+	var archive []uint
+	for _, show := range shows {
+		if !show.IsArchived() {
+			archive = append(archive, show.ID)
+		}
+	}
+	now := time.Now()
+	if err := showRepo.SetArchived(archive, &now); err != nil {
+		return fmt.Errorf("failed to archive shows: %w", err)
+	}
+	result.Archived = len(archive)
+	return nil
+}
+
+// finish sets the sync status and stores it on the list and in the crawl logs.
+// Both are written without the sync's context so cancelled syncs are still recorded.
+func (s *ListSyncService) finish(list *models.TMDBList, result *ListSyncResult, episodes int, startTime time.Time) {
+	switch {
+	case len(result.Errors) == 0:
+		result.Status = "success"
+	case result.Crawled > 0:
+		result.Status = "partial"
+	default:
+		result.Status = "failed"
+	}
+
+	now := time.Now()
+	list.LastSyncedAt = &now
+	list.LastSyncStatus = result.Status
+	list.LastSyncError = strings.Join(result.Errors, "; ")
+	_ = s.listRepo.Update(list)
+
+	// Written directly rather than through createCrawlLog: a list sync is not a show
+	// crawl, so it has no TMDB show ID and does not emit crawl.failed
+	_ = s.crawler.logRepo.Create(&models.CrawlLog{
+		ListID:        &list.ListID,
+		Action:        "list_sync",
+		Status:        result.Status,
+		EpisodesCount: episodes,
+		ErrorMessage:  list.LastSyncError,
+		DurationMs:    int(time.Since(startTime).Milliseconds()),
+	})
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/xc9973/go-tmdb-crawler/dto"
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/repositories"
+)
+
+func tvListItems(ids ...int) []dto.TMDBListItem {
+	items := make([]dto.TMDBListItem, len(ids))
+	for i, id := range ids {
+		items[i] = dto.TMDBListItem{ID: id, MediaType: "tv"}
+	}
+	return items
+}
+
+func TestListSyncService_SyncAndArchive(t *testing.T) {
+	fake := newFakeTMDB()
+	for _, id := range []int{1001, 1002, 1003} {
+		fake.addShow(id, 1, 2)
+	}
+	fake.tmdbLists[100] = &dto.TMDBListResponse{
+		ID: 100, Name: "Team picks", Page: 1, TotalPages: 1,
+		Results: append(tvListItems(1001, 1002, 1003), dto.TMDBListItem{ID: 5000, MediaType: "movie"}),
+	}
+	fake.tmdbLists[200] = &dto.TMDBListResponse{ID: 200, Name: "Dramas", Page: 1, TotalPages: 1, Results: tvListItems(1003)}
+
+	crawler, db := setupCrawlerTest(t, fake)
+	if err := db.AutoMigrate(&models.TMDBList{}); err != nil {
+		t.Fatalf("Failed to migrate TMDB lists: %v", err)
+	}
+	showRepo := repositories.NewShowRepository(db)
+	listRepo := repositories.NewTMDBListRepository(db)
+	listSync := NewListSyncService(crawler, showRepo, listRepo)
+	ctx := context.Background()
+
+	picks := &models.TMDBList{ListID: 100, Enabled: true, ArchiveRemoved: true}
+	dramas := &models.TMDBList{ListID: 200, Enabled: true}
+	for _, list := range []*models.TMDBList{picks, dramas} {
+		if err := listRepo.Create(list); err != nil {
+			t.Fatalf("Failed to create list: %v", err)
+		}
+	}
+
+	results, err := listSync.SyncAll(ctx)
+	if err != nil {
+		t.Fatalf("SyncAll failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if r := results[0]; r.Status != "success" || r.Name != "Team picks" || r.Shows != 3 || r.Crawled != 3 {
+		t.Errorf("Unexpected result for list 100: %+v", r)
+	}
+	if count, _ := showRepo.Count(); count != 3 {
+		t.Errorf("Expected 3 followed shows, got %d", count)
+	}
+
+	logs, err := repositories.NewCrawlLogRepository(db).GetByListID(100, 0)
+	if err != nil {
+		t.Fatalf("GetByAction failed: %v", err)
+	}
+	if len(logs) != 1 || logs[0].Status != "success" {
+		t.Errorf("Expected one successful list_sync log, got %+v", logs)
+	}
+
+	// 1002 and 1003 leave the list; 1003 is still on the dramas list
+	fake.tmdbLists[100].Results = tvListItems(1001)
+	crawler.GetTMDBService().ClearCache()
+
+	result, err := listSync.Sync(ctx, picks.ID)
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if result.Archived != 1 {
+		t.Errorf("Expected 1 archived show, got %+v", result)
+	}
+	removed, _ := showRepo.GetByTmdbID(1002)
+	if !removed.IsArchived() {
+		t.Error("Expected show 1002 to be archived")
+	}
+	kept, _ := showRepo.GetByTmdbID(1003)
+	if kept.IsArchived() {
+		t.Error("Expected show 1003 to stay active")
+	}
+	returning, _ := showRepo.ListReturning()
+	if len(returning) != 2 {
+		t.Errorf("Expected archived show to be left out of refreshes, got %d returning shows", len(returning))
+	}
+
+	// A show that comes back is restored
+	fake.tmdbLists[100].Results = tvListItems(1001, 1002)
+	crawler.GetTMDBService().ClearCache()
+
+	result, err = listSync.Sync(ctx, picks.ID)
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if result.Restored != 1 || result.Archived != 0 {
+		t.Errorf("Expected 1 restored show, got %+v", result)
+	}
+	restored, _ := showRepo.GetByTmdbID(1002)
+	if restored.IsArchived() {
+		t.Error("Expected show 1002 to be restored")
+	}
+
+	stored, _ := listRepo.GetByID(picks.ID)
+	if stored.ItemCount != 2 || stored.LastSyncStatus != "success" || stored.LastSyncedAt == nil {
+		t.Errorf("Unexpected stored list: %+v", stored)
+	}
+}
+
+func TestListSyncService_Sync_ListNotFound(t *testing.T) {
+	fake := newFakeTMDB()
+	crawler, db := setupCrawlerTest(t, fake)
+	if err := db.AutoMigrate(&models.TMDBList{}); err != nil {
+		t.Fatalf("Failed to migrate TMDB lists: %v", err)
+	}
+	listRepo := repositories.NewTMDBListRepository(db)
+	listSync := NewListSyncService(crawler, repositories.NewShowRepository(db), listRepo)
+
+	list := &models.TMDBList{ListID: 300, Enabled: true}
+	if err := listRepo.Create(list); err != nil {
+		t.Fatalf("Failed to create list: %v", err)
+	}
+
+	result, err := listSync.Sync(context.Background(), list.ID)
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if result.Status != "failed" || len(result.Errors) != 1 {
+		t.Errorf("Expected a failed sync, got %+v", result)
+	}
+
+	logs, _ := repositories.NewCrawlLogRepository(db).GetByListID(300, 0)
+	if len(logs) != 1 || logs[0].Status != "failed" || logs[0].ErrorMessage == "" {
+		t.Errorf("Expected a failed list_sync log, got %+v", logs)
+	}
+	if len(logs) == 1 && (logs[0].TmdbID != 0 || logs[0].ListID == nil || *logs[0].ListID != 300) {
+		t.Errorf("Expected the list ID in list_id rather than tmdb_id, got %+v", logs[0])
+	}
+}
@@ -0,0 +1,33 @@
+package services
+
+import "github.com/xc9973/go-tmdb-crawler/models"
+
+// LocalizeShows returns copies of shows with names and overviews in lang.
+// An empty lang returns shows unchanged.
+func LocalizeShows(shows []*models.Show, lang string) []*models.Show {
+	if lang == "" {
+		return shows
+	}
+	localized := make([]*models.Show, len(shows))
+	for i, show := range shows {
+		if show != nil {
+			localized[i] = show.Localized(lang)
+		}
+	}
+	return localized
+}
+
+// LocalizeEpisodes returns copies of episodes (and their shows) with names and
+// overviews in lang. An empty lang returns episodes unchanged.
+func LocalizeEpisodes(episodes []*models.Episode, lang string) []*models.Episode {
+	if lang == "" {
+		return episodes
+	}
+	localized := make([]*models.Episode, len(episodes))
+	for i, episode := range episodes {
+		if episode != nil {
+			localized[i] = episode.Localized(lang)
+		}
+	}
+	return localized
+}
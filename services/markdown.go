@@ -16,6 +16,13 @@ type MarkdownService struct {
 	episodeRepo    repositories.EpisodeRepository
 	showRepo       repositories.ShowRepository
 	timezoneHelper *utils.TimezoneHelper
+	// lang selects the translation of names and overviews; empty uses the stored text
+	lang string
+	// movieRepo adds movie releases to the update lists when set
+	movieRepo     repositories.MovieRepository
+	releaseFilter repositories.MovieReleaseFilter
+	// templates render the Markdown when set; otherwise the built-in layout is used
+	templates *TemplateService
 }
 
 // NewMarkdownService creates a new Markdown service instance
@@ -38,45 +45,162 @@ func (s *MarkdownService) SetTimezoneHelper(tzHelper *utils.TimezoneHelper) {
 	s.timezoneHelper = tzHelper
 }
 
+// SetMovieReleases adds the movie releases matching filter to the update lists
+func (s *MarkdownService) SetMovieReleases(movieRepo repositories.MovieRepository, filter repositories.MovieReleaseFilter) {
+	s.movieRepo = movieRepo
+	s.releaseFilter = filter
+}
+
+// SetTemplates renders the update lists and show pages with templates
+func (s *MarkdownService) SetTemplates(templates *TemplateService) {
+	s.templates = templates
+}
+
+// WithLanguage returns a copy of the service that renders names and overviews in lang
+func (s *MarkdownService) WithLanguage(lang string) *MarkdownService {
+	localized := *s
+	localized.lang = lang
+	return &localized
+}
+
+// WithTimezone returns a copy of the service that computes "today", date ranges and
+// air dates in the timezone of tzHelper. A nil tzHelper keeps the configured timezone.
+func (s *MarkdownService) WithTimezone(tzHelper *utils.TimezoneHelper) *MarkdownService {
+	if tzHelper == nil {
+		return s
+	}
+	localized := *s
+	localized.timezoneHelper = tzHelper
+	localized.episodeRepo = s.episodeRepo.WithTimezone(tzHelper)
+	if s.movieRepo != nil {
+		localized.movieRepo = s.movieRepo.WithTimezone(tzHelper)
+	}
+	return &localized
+}
+
 // GenerateTodayUpdates generates Markdown content for today's updates
 func (s *MarkdownService) GenerateTodayUpdates() (string, error) {
-	episodes, err := s.episodeRepo.GetTodayUpdates()
+	episodes, releases, err := s.todayUpdates()
 	if err != nil {
-		return "", fmt.Errorf("failed to get today's episodes: %w", err)
+		return "", err
 	}
 
-	return s.GenerateUpdateList(episodes), nil
+	if s.templates != nil {
+		today := s.timezoneHelper.TodayInLocation()
+		return s.templates.Render(TodayTemplate, s.updateListData(today, today, episodes, releases))
+	}
+	return s.GenerateUpdateList(episodes, releases), nil
 }
 
 // GenerateWeeklyUpdates generates Markdown content for weekly updates
 func (s *MarkdownService) GenerateWeeklyUpdates() (string, error) {
+	startDate, endDate := s.weekRange()
+	return s.generateDateRange(WeeklyTemplate, startDate, endDate)
+}
+
+// GenerateDateRange generates Markdown content for a date range
+func (s *MarkdownService) GenerateDateRange(startDate, endDate time.Time) (string, error) {
+	return s.generateDateRange(RangeTemplate, startDate, endDate)
+}
+
+// generateDateRange generates Markdown content for a date range with the named template
+func (s *MarkdownService) generateDateRange(name string, startDate, endDate time.Time) (string, error) {
+	episodes, releases, err := s.dateRangeUpdates(startDate, endDate)
+	if err != nil {
+		return "", err
+	}
+
+	if s.templates != nil {
+		return s.templates.Render(name, s.updateListData(startDate, endDate, episodes, releases))
+	}
+	return s.GenerateDateRangeUpdates(startDate, endDate, episodes, releases), nil
+}
+
+// PreviewTemplate renders the named template against live data. A non-empty
+// source is rendered in place of the stored template without saving it.
+// The range template covers startDate to endDate and the show template showID;
+// today and weekly cover the same days as their Markdown.
+func (s *MarkdownService) PreviewTemplate(name, source string, showID uint, startDate, endDate time.Time) (string, error) {
+	if s.templates == nil {
+		return "", fmt.Errorf("templates are not configured")
+	}
+
+	var data interface{}
+	switch name {
+	case TodayTemplate:
+		episodes, releases, err := s.todayUpdates()
+		if err != nil {
+			return "", err
+		}
+		today := s.timezoneHelper.TodayInLocation()
+		data = s.updateListData(today, today, episodes, releases)
+	case WeeklyTemplate, RangeTemplate:
+		if name == WeeklyTemplate {
+			startDate, endDate = s.weekRange()
+		}
+		episodes, releases, err := s.dateRangeUpdates(startDate, endDate)
+		if err != nil {
+			return "", err
+		}
+		data = s.updateListData(startDate, endDate, episodes, releases)
+	case ShowTemplate:
+		show, episodes, err := s.showDetail(showID)
+		if err != nil {
+			return "", err
+		}
+		data = s.showDetailData(show, episodes)
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+	}
+
+	return s.templates.RenderSource(name, source, data)
+}
+
+// weekRange returns the days of the weekly update list: the last 7 days and today
+func (s *MarkdownService) weekRange() (time.Time, time.Time) {
 	today := s.timezoneHelper.TodayInLocation()
-	startDate := today.AddDate(0, 0, -7)
+	return today.AddDate(0, 0, -7), today
+}
 
-	episodes, err := s.episodeRepo.GetByDateRange(startDate, today)
+// todayUpdates loads today's episodes and movie releases
+func (s *MarkdownService) todayUpdates() ([]*models.Episode, []*models.MovieReleaseDate, error) {
+	episodes, err := s.episodeRepo.GetTodayUpdates()
 	if err != nil {
-		return "", fmt.Errorf("failed to get episodes: %w", err)
+		return nil, nil, fmt.Errorf("failed to get today's episodes: %w", err)
 	}
 
-	return s.GenerateDateRangeUpdates(startDate, today, episodes), nil
+	var releases []*models.MovieReleaseDate
+	if s.movieRepo != nil {
+		if releases, err = s.movieRepo.GetTodayReleases(s.releaseFilter); err != nil {
+			return nil, nil, fmt.Errorf("failed to get today's movie releases: %w", err)
+		}
+	}
+	return episodes, releases, nil
 }
 
-// GenerateDateRange generates Markdown content for a date range
-func (s *MarkdownService) GenerateDateRange(startDate, endDate time.Time) (string, error) {
+// dateRangeUpdates loads the episodes and movie releases of a date range
+func (s *MarkdownService) dateRangeUpdates(startDate, endDate time.Time) ([]*models.Episode, []*models.MovieReleaseDate, error) {
 	episodes, err := s.episodeRepo.GetByDateRange(startDate, endDate)
 	if err != nil {
-		return "", fmt.Errorf("failed to get episodes: %w", err)
+		return nil, nil, fmt.Errorf("failed to get episodes: %w", err)
 	}
 
-	return s.GenerateDateRangeUpdates(startDate, endDate, episodes), nil
+	var releases []*models.MovieReleaseDate
+	if s.movieRepo != nil {
+		if releases, err = s.movieRepo.GetReleasesByDateRange(startDate, endDate, s.releaseFilter); err != nil {
+			return nil, nil, fmt.Errorf("failed to get movie releases: %w", err)
+		}
+	}
+	return episodes, releases, nil
 }
 
-// GenerateUpdateList generates Markdown content for a list of episodes
-func (s *MarkdownService) GenerateUpdateList(episodes []*models.Episode) string {
+// GenerateUpdateList generates Markdown content for a list of episodes and movie releases
+func (s *MarkdownService) GenerateUpdateList(episodes []*models.Episode, releases []*models.MovieReleaseDate) string {
+	episodes = LocalizeEpisodes(episodes, s.lang)
 	var builder strings.Builder
 
 	// Header
-	today := time.Now().Format("2006年01月02日")
+	today := s.timezoneHelper.NowInLocation().Format("2006年01月02日")
 	builder.WriteString(fmt.Sprintf("# 📺 今日更新清单\n\n"))
 	builder.WriteString(fmt.Sprintf("**📅 更新日期**: %s\n\n", today))
 	builder.WriteString("---\n\n")
@@ -103,10 +227,7 @@ func (s *MarkdownService) GenerateUpdateList(episodes []*models.Episode) string
 
 		for _, ep := range showEpisodes {
 			episodeCode := ep.GetEpisodeCode()
-			airDate := ""
-			if ep.AirDate != nil {
-				airDate = ep.AirDate.Format("2006-01-02")
-			}
+			airDate := s.timezoneHelper.FormatAirDate(ep.AirAt, ep.AirDate)
 
 			builder.WriteString(fmt.Sprintf("### %s - %s\n", episodeCode, ep.Name))
 			if airDate != "" {
@@ -121,8 +242,23 @@ func (s *MarkdownService) GenerateUpdateList(episodes []*models.Episode) string
 		builder.WriteString("---\n\n")
 	}
 
+	// Movies
+	movies := groupReleasesByMovie(releases)
+	if len(movies) > 0 {
+		builder.WriteString("## 🎬 电影上映\n\n")
+		for _, movie := range movies {
+			builder.WriteString(fmt.Sprintf("### %s\n", movie.Title()))
+			builder.WriteString(fmt.Sprintf("**上映**: %s\n", formatReleases(movie.Releases)))
+			if movie.Movie != nil && movie.Movie.Overview != "" {
+				builder.WriteString(fmt.Sprintf("**简介**: %s\n", movie.Movie.Overview))
+			}
+			builder.WriteString("\n")
+		}
+		builder.WriteString("---\n\n")
+	}
+
 	// Footer
-	builder.WriteString(fmt.Sprintf("📊 **统计**: 共 %d 部剧集, %d 集更新\n\n", len(showMap), len(episodes)))
+	builder.WriteString(fmt.Sprintf("📊 **统计**: %s\n\n", updateStats(len(showMap), len(episodes), len(movies))))
 	builder.WriteString("*数据来源: TMDB*")
 
 	return builder.String()
@@ -130,21 +266,51 @@ func (s *MarkdownService) GenerateUpdateList(episodes []*models.Episode) string
 
 // GenerateShowDetail generates detailed Markdown content for a single show
 func (s *MarkdownService) GenerateShowDetail(showID uint) (string, error) {
+	show, episodes, err := s.showDetail(showID)
+	if err != nil {
+		return "", err
+	}
+
+	if s.templates != nil {
+		return s.templates.Render(ShowTemplate, s.showDetailData(show, episodes))
+	}
+	return s.GenerateShowContent(show, episodes), nil
+}
+
+// showDetail loads a show and its episodes
+func (s *MarkdownService) showDetail(showID uint) (*models.Show, []*models.Episode, error) {
 	show, err := s.showRepo.GetByID(showID)
 	if err != nil {
-		return "", fmt.Errorf("failed to get show: %w", err)
+		return nil, nil, fmt.Errorf("failed to get show: %w", err)
 	}
 
 	episodes, err := s.episodeRepo.GetByShowID(showID)
 	if err != nil {
-		return "", fmt.Errorf("failed to get episodes: %w", err)
+		return nil, nil, fmt.Errorf("failed to get episodes: %w", err)
 	}
+	return show, episodes, nil
+}
 
-	return s.GenerateShowContent(show, episodes), nil
+// showDetailData builds the data of the show template in the service's language
+func (s *MarkdownService) showDetailData(show *models.Show, episodes []*models.Episode) *ShowDetailData {
+	if s.lang != "" {
+		show = show.Localized(s.lang)
+		episodes = LocalizeEpisodes(episodes, s.lang)
+	}
+	return newShowDetailData(s.timezoneHelper, show, episodes)
+}
+
+// updateListData builds the data of an update list template in the service's language
+func (s *MarkdownService) updateListData(startDate, endDate time.Time, episodes []*models.Episode, releases []*models.MovieReleaseDate) *UpdateListData {
+	return newUpdateListData(s.timezoneHelper, startDate, endDate, LocalizeEpisodes(episodes, s.lang), releases)
 }
 
 // GenerateShowContent generates Markdown content for a show with episodes
 func (s *MarkdownService) GenerateShowContent(show *models.Show, episodes []*models.Episode) string {
+	if s.lang != "" {
+		show = show.Localized(s.lang)
+		episodes = LocalizeEpisodes(episodes, s.lang)
+	}
 	var builder strings.Builder
 
 	// Header
@@ -190,14 +356,15 @@ func (s *MarkdownService) GenerateShowContent(show *models.Show, episodes []*mod
 
 		for _, season := range seasons {
 			seasonEpisodes := seasonMap[season]
-			builder.WriteString(fmt.Sprintf("### 第%d季 (%d集)\n\n", season, len(seasonEpisodes)))
+			if season == 0 {
+				builder.WriteString(fmt.Sprintf("### 特别篇 (%d集)\n\n", len(seasonEpisodes)))
+			} else {
+				builder.WriteString(fmt.Sprintf("### 第%d季 (%d集)\n\n", season, len(seasonEpisodes)))
+			}
 
 			for _, ep := range seasonEpisodes {
 				episodeCode := ep.GetEpisodeCode()
-				airDate := ""
-				if ep.AirDate != nil {
-					airDate = ep.AirDate.Format("2006-01-02")
-				}
+				airDate := s.timezoneHelper.FormatAirDate(ep.AirAt, ep.AirDate)
 
 				builder.WriteString(fmt.Sprintf("**%s** - %s", episodeCode, ep.Name))
 				if airDate != "" {
@@ -222,8 +389,9 @@ func (s *MarkdownService) GenerateShowContent(show *models.Show, episodes []*mod
 	return builder.String()
 }
 
-// GenerateDateRangeUpdates generates Markdown content for a date range
-func (s *MarkdownService) GenerateDateRangeUpdates(startDate, endDate time.Time, episodes []*models.Episode) string {
+// GenerateDateRangeUpdates generates Markdown content for the episodes and movie releases of a date range
+func (s *MarkdownService) GenerateDateRangeUpdates(startDate, endDate time.Time, episodes []*models.Episode, releases []*models.MovieReleaseDate) string {
+	episodes = LocalizeEpisodes(episodes, s.lang)
 	var builder strings.Builder
 
 	// Header
@@ -236,15 +404,40 @@ func (s *MarkdownService) GenerateDateRangeUpdates(startDate, endDate time.Time,
 	// Group by date
 	dateMap := make(map[string][]*models.Episode)
 	for _, episode := range episodes {
-		if episode == nil || episode.AirDate == nil {
+		if episode == nil {
 			continue
 		}
-		dateStr := episode.AirDate.Format("2006-01-02")
+		// Episodes with a known air instant are listed on the day they air in the configured timezone
+		airDate := s.timezoneHelper.AirDate(episode.AirAt, episode.AirDate)
+		if airDate == nil {
+			continue
+		}
+		dateStr := airDate.Format("2006-01-02")
 		dateMap[dateStr] = append(dateMap[dateStr], episode)
 	}
+	releaseMap := make(map[string][]*models.MovieReleaseDate)
+	for _, release := range releases {
+		if release == nil {
+			continue
+		}
+		dateStr := release.ReleaseDate.Format("2006-01-02")
+		releaseMap[dateStr] = append(releaseMap[dateStr], release)
+	}
+
+	dates := make([]string, 0, len(dateMap)+len(releaseMap))
+	for dateStr := range dateMap {
+		dates = append(dates, dateStr)
+	}
+	for dateStr := range releaseMap {
+		if _, ok := dateMap[dateStr]; !ok {
+			dates = append(dates, dateStr)
+		}
+	}
+	sort.Strings(dates)
 
 	// Generate content for each date
-	for dateStr, dateEpisodes := range dateMap {
+	for _, dateStr := range dates {
+		dateEpisodes := dateMap[dateStr]
 		builder.WriteString(fmt.Sprintf("## 📅 %s\n\n", dateStr))
 
 		// Group by show
@@ -274,6 +467,12 @@ func (s *MarkdownService) GenerateDateRangeUpdates(startDate, endDate time.Time,
 			builder.WriteString("\n")
 		}
 
+		// List movies released on this date
+		for _, movie := range groupReleasesByMovie(releaseMap[dateStr]) {
+			builder.WriteString(fmt.Sprintf("### 🎬 %s\n\n", movie.Title()))
+			builder.WriteString(fmt.Sprintf("- **上映**: %s\n\n", formatReleases(movie.Releases)))
+		}
+
 		builder.WriteString("---\n\n")
 	}
 
@@ -283,12 +482,68 @@ func (s *MarkdownService) GenerateDateRangeUpdates(startDate, endDate time.Time,
 		showCount[ep.ShowID] = true
 	}
 
-	builder.WriteString(fmt.Sprintf("📊 **统计**: 共 %d 部剧集, %d 集更新\n\n", len(showCount), len(episodes)))
+	builder.WriteString(fmt.Sprintf("📊 **统计**: %s\n\n", updateStats(len(showCount), len(episodes), len(groupReleasesByMovie(releases)))))
 	builder.WriteString("*数据来源: TMDB*")
 
 	return builder.String()
 }
 
+// movieReleases is a movie with its releases in an update list
+type movieReleases struct {
+	MovieID  uint
+	Movie    *models.Movie
+	Releases []*models.MovieReleaseDate
+}
+
+// Title returns the movie title, or its ID when the movie is not loaded
+func (m *movieReleases) Title() string {
+	if m.Movie != nil && m.Movie.Title != "" {
+		return m.Movie.Title
+	}
+	return fmt.Sprintf("MovieID:%d", m.MovieID)
+}
+
+// groupReleasesByMovie groups releases by movie in the order the movies first appear
+func groupReleasesByMovie(releases []*models.MovieReleaseDate) []*movieReleases {
+	var movies []*movieReleases
+	byID := make(map[uint]*movieReleases)
+	for _, release := range releases {
+		if release == nil {
+			continue
+		}
+		movie, ok := byID[release.MovieID]
+		if !ok {
+			movie = &movieReleases{MovieID: release.MovieID, Movie: release.Movie}
+			byID[release.MovieID] = movie
+			movies = append(movies, movie)
+		}
+		movie.Releases = append(movie.Releases, release)
+	}
+	return movies
+}
+
+// formatReleases describes releases by region, type and certification, e.g. "US 院线 (PG-13), GB 数字"
+func formatReleases(releases []*models.MovieReleaseDate) string {
+	parts := make([]string, 0, len(releases))
+	for _, release := range releases {
+		part := fmt.Sprintf("%s %s", release.Region, release.GetTypeName())
+		if release.Certification != "" {
+			part += fmt.Sprintf(" (%s)", release.Certification)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// updateStats summarizes an update list, mentioning movies only when there are any
+func updateStats(shows, episodes, movies int) string {
+	stats := fmt.Sprintf("共 %d 部剧集, %d 集更新", shows, episodes)
+	if movies > 0 {
+		stats += fmt.Sprintf(", %d 部电影上映", movies)
+	}
+	return stats
+}
+
 // SaveToFile saves Markdown content to a file
 func (s *MarkdownService) SaveToFile(content, filename string) error {
 	// This would typically use os.WriteFile
@@ -0,0 +1,386 @@
+package services
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// MarkdownToNodes converts Markdown to Telegraph content nodes.
+// Inline HTML is kept, so a template can mix both; see MarkdownToHTML and HTMLToNodes.
+func MarkdownToNodes(markdown string) []Node {
+	return HTMLToNodes(MarkdownToHTML(markdown))
+}
+
+var (
+	markdownHeading   = regexp.MustCompile(`^(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+	markdownRule      = regexp.MustCompile(`^([-*_])(\s*([-*_]))*$`)
+	markdownBullet    = regexp.MustCompile(`^[-*+]\s+(.*)$`)
+	markdownNumbered  = regexp.MustCompile(`^\d+[.)]\s+(.*)$`)
+	markdownEscapable = "\\`*_[]()#+-.!~<>&"
+	// markdownSpecial are the characters escapeMarkdown escapes anywhere in text
+	markdownSpecial     = "\\`*_[]<>~&"
+	markdownLineStart   = regexp.MustCompile(`^(\d+)([.)])`)
+	markdownInlineMarks = []struct{ marker, tag string }{
+		{"**", "b"}, {"__", "b"}, {"~~", "s"}, {"*", "i"},
+	}
+)
+
+// MarkdownToHTML converts the Markdown of the templates to HTML.
+// It supports headings, paragraphs, lists, block quotes, fenced code, rules,
+// bold, italic, strikethrough, code spans, links and images. Lines that start
+// with a tag are passed through as HTML. Unlike CommonMark, line breaks within
+// a paragraph are kept, so one line per field renders as written.
+func MarkdownToHTML(markdown string) string {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	var builder strings.Builder
+
+	var paragraph []string
+	var listTag string
+	var items []string
+	var quote []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			builder.WriteString("<p>" + strings.Join(paragraph, "<br>") + "</p>\n")
+			paragraph = nil
+		}
+		if len(items) > 0 {
+			builder.WriteString("<" + listTag + ">")
+			for _, item := range items {
+				builder.WriteString("<li>" + item + "</li>")
+			}
+			builder.WriteString("</" + listTag + ">\n")
+			items = nil
+		}
+		if len(quote) > 0 {
+			builder.WriteString("<blockquote>" + strings.Join(quote, "<br>") + "</blockquote>\n")
+			quote = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "```"):
+			flush()
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			builder.WriteString("<pre>" + html.EscapeString(strings.Join(code, "\n")) + "</pre>\n")
+		case markdownRule.MatchString(trimmed) && len(strings.ReplaceAll(trimmed, " ", "")) >= 3:
+			flush()
+			builder.WriteString("<hr>\n")
+		case markdownHeading.MatchString(trimmed):
+			flush()
+			match := markdownHeading.FindStringSubmatch(trimmed)
+			tag := "h" + string(rune('0'+len(match[1])))
+			builder.WriteString("<" + tag + ">" + inlineMarkdownHTML(match[2]) + "</" + tag + ">\n")
+		case strings.HasPrefix(trimmed, ">"):
+			if len(paragraph) > 0 || len(items) > 0 {
+				flush()
+			}
+			quote = append(quote, inlineMarkdownHTML(strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))))
+		case markdownBullet.MatchString(trimmed), markdownNumbered.MatchString(trimmed):
+			tag, match := "ul", markdownBullet.FindStringSubmatch(trimmed)
+			if match == nil {
+				tag, match = "ol", markdownNumbered.FindStringSubmatch(trimmed)
+			}
+			if tag != listTag || len(paragraph) > 0 || len(quote) > 0 {
+				flush()
+			}
+			listTag = tag
+			items = append(items, inlineMarkdownHTML(match[1]))
+		case len(paragraph) == 0 && len(items) == 0 && len(quote) == 0 && isHTMLTagStart(trimmed):
+			// An HTML block runs to the next blank line
+			for ; i < len(lines) && strings.TrimSpace(lines[i]) != ""; i++ {
+				builder.WriteString(lines[i] + "\n")
+			}
+		default:
+			if len(items) > 0 || len(quote) > 0 {
+				flush()
+			}
+			paragraph = append(paragraph, inlineMarkdownHTML(trimmed))
+		}
+	}
+	flush()
+
+	return builder.String()
+}
+
+// escapeMarkdown escapes text for use in a Markdown template, so that names and
+// overviews from TMDB render as plain text: emphasis and link characters and
+// HTML tags are escaped, line breaks become spaces, and a leading "#", "-", "+"
+// or "1." cannot start a heading or list.
+func escapeMarkdown(text string) string {
+	text = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(text)
+	var builder strings.Builder
+	if match := markdownLineStart.FindStringSubmatch(text); match != nil {
+		builder.WriteString(match[1] + "\\" + match[2])
+		text = text[len(match[0]):]
+	} else if text != "" && strings.IndexByte("#-+", text[0]) >= 0 {
+		builder.WriteString("\\" + text[:1])
+		text = text[1:]
+	}
+	for i := 0; i < len(text); i++ {
+		if strings.IndexByte(markdownSpecial, text[i]) >= 0 {
+			builder.WriteByte('\\')
+		}
+		builder.WriteByte(text[i])
+	}
+	return builder.String()
+}
+
+// isSafeURL reports whether a link or image URL is http, https or relative,
+// so that javascript: and other schemes are not published
+func isSafeURL(raw string) bool {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "" || u.Scheme == "http" || u.Scheme == "https"
+}
+
+// inlineMarkdownHTML converts the inline Markdown of one line to HTML
+func inlineMarkdownHTML(text string) string {
+	var builder strings.Builder
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch rest[0] {
+		case '\\':
+			if len(rest) > 1 && strings.IndexByte(markdownEscapable, rest[1]) >= 0 {
+				builder.WriteString(html.EscapeString(rest[1:2]))
+				i += 2
+				continue
+			}
+		case '`':
+			if end := strings.IndexByte(rest[1:], '`'); end >= 0 {
+				builder.WriteString("<code>" + html.EscapeString(rest[1:1+end]) + "</code>")
+				i += end + 2
+				continue
+			}
+		case '!', '[':
+			if label, url, n := markdownLink(rest); n > 0 {
+				switch {
+				case !isSafeURL(url):
+					// Only the text of a link or image with an unsafe URL is kept
+					builder.WriteString(inlineMarkdownHTML(label))
+				case rest[0] == '!':
+					builder.WriteString(`<img src="` + html.EscapeString(url) + `" alt="` + html.EscapeString(label) + `">`)
+				default:
+					builder.WriteString(`<a href="` + html.EscapeString(url) + `">` + inlineMarkdownHTML(label) + "</a>")
+				}
+				i += n
+				continue
+			}
+		case '<':
+			if end := strings.IndexByte(rest, '>'); end > 0 && isHTMLTagStart(rest) {
+				builder.WriteString(rest[:end+1])
+				i += end + 1
+				continue
+			}
+			builder.WriteString("&lt;")
+			i++
+			continue
+		case '>':
+			builder.WriteString("&gt;")
+			i++
+			continue
+		case '*', '_', '~':
+			if tag, inner, n := markdownEmphasis(rest); n > 0 {
+				builder.WriteString("<" + tag + ">" + inlineMarkdownHTML(inner) + "</" + tag + ">")
+				i += n
+				continue
+			}
+		}
+		builder.WriteByte(rest[0])
+		i++
+	}
+	return builder.String()
+}
+
+// markdownEmphasis matches emphasis at the start of text, returning the tag,
+// the emphasized text and the length of the match
+func markdownEmphasis(text string) (string, string, int) {
+	for _, mark := range markdownInlineMarks {
+		if !strings.HasPrefix(text, mark.marker) {
+			continue
+		}
+		body := text[len(mark.marker):]
+		// The emphasized text cannot start with a space, as in "* item"
+		if body == "" || body[0] == ' ' {
+			return "", "", 0
+		}
+		if end := strings.Index(body, mark.marker); end > 0 {
+			return mark.tag, body[:end], len(mark.marker)*2 + end
+		}
+	}
+	return "", "", 0
+}
+
+// markdownLink matches [label](url) or ![alt](src) at the start of text,
+// returning the label, the URL and the length of the match.
+// Parentheses in the URL must be balanced, as in CommonMark.
+func markdownLink(text string) (string, string, int) {
+	start := 0
+	if strings.HasPrefix(text, "!") {
+		start = 1
+	}
+	if !strings.HasPrefix(text[start:], "[") {
+		return "", "", 0
+	}
+	closing := strings.Index(text[start:], "](")
+	if closing < 0 {
+		return "", "", 0
+	}
+	closing += start
+	end, depth := -1, 0
+	for i := closing + 2; i < len(text) && end < 0; i++ {
+		switch text[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				end = i
+			}
+			depth--
+		}
+	}
+	if end < 0 {
+		return "", "", 0
+	}
+	return text[start+1 : closing], strings.TrimSpace(text[closing+2 : end]), end + 1
+}
+
+// isHTMLTagStart reports whether text starts with an HTML comment or the tag of
+// a known element, so that text such as "Breaking <Bad>" is not taken for HTML
+func isHTMLTagStart(text string) bool {
+	if strings.HasPrefix(text, "<!--") {
+		return true
+	}
+	if !strings.HasPrefix(text, "<") {
+		return false
+	}
+	name := strings.TrimPrefix(text[1:], "/")
+	end := 0
+	for end < len(name) && (name[end] >= 'a' && name[end] <= 'z' || name[end] >= 'A' && name[end] <= 'Z' || name[end] >= '0' && name[end] <= '9') {
+		end++
+	}
+	if end == 0 || (end < len(name) && !strings.ContainsRune(" \t/>", rune(name[end]))) {
+		return false
+	}
+	_, known := telegraphTags[strings.ToLower(name[:end])]
+	return known || htmlContainerTags[strings.ToLower(name[:end])]
+}
+
+// Telegraph tags of HTML elements; Telegraph only has two heading levels, so
+// h1/h2 become h3 and the others h4, as ## shows and ### episodes are laid out.
+// Other elements only keep their children.
+var telegraphTags = map[string]string{
+	"a": "a", "aside": "aside", "b": "b", "blockquote": "blockquote", "br": "br",
+	"code": "code", "em": "em", "figcaption": "figcaption", "figure": "figure",
+	"h1": "h3", "h2": "h3", "h3": "h4", "h4": "h4", "h5": "h4", "h6": "h4",
+	"hr": "hr", "i": "i", "iframe": "iframe", "img": "img", "li": "li", "ol": "ol",
+	"p": "p", "pre": "pre", "s": "s", "del": "s", "strike": "s", "strong": "strong",
+	"u": "u", "ul": "ul", "video": "video",
+}
+
+// HTML elements without a Telegraph tag that templates may still use; they only keep their children
+var htmlContainerTags = map[string]bool{
+	"div": true, "span": true, "section": true, "article": true, "header": true,
+	"footer": true, "small": true, "mark": true, "sup": true, "sub": true,
+	"script": true, "style": true,
+}
+
+// Telegraph tags that can stand at the top level of a page; text and other
+// tags there are wrapped in paragraphs
+var telegraphBlockTags = map[string]bool{
+	"aside": true, "blockquote": true, "figure": true, "h3": true, "h4": true, "hr": true,
+	"iframe": true, "ol": true, "p": true, "pre": true, "ul": true, "video": true,
+}
+
+// HTMLToNodes converts HTML to Telegraph content nodes.
+// Only http, https and relative href and src attributes are kept; scripts and styles are dropped.
+func HTMLToNodes(content string) []Node {
+	body := &xhtml.Node{Type: xhtml.ElementNode, Data: "body", DataAtom: atom.Body}
+	parsed, err := xhtml.ParseFragment(strings.NewReader(content), body)
+	if err != nil {
+		return nil
+	}
+
+	var children []interface{}
+	for _, node := range parsed {
+		children = append(children, htmlChildNodes(node, false)...)
+	}
+
+	var nodes []Node
+	var inline []interface{}
+	wrapInline := func() {
+		for _, child := range inline {
+			if text, ok := child.(string); !ok || strings.TrimSpace(text) != "" {
+				nodes = append(nodes, Node{"tag": "p", "children": inline})
+				break
+			}
+		}
+		inline = nil
+	}
+	for _, child := range children {
+		if node, ok := child.(Node); ok && telegraphBlockTags[node["tag"].(string)] {
+			wrapInline()
+			nodes = append(nodes, node)
+			continue
+		}
+		inline = append(inline, child)
+	}
+	wrapInline()
+
+	return nodes
+}
+
+// htmlChildNodes converts an HTML node to Telegraph nodes and text
+func htmlChildNodes(node *xhtml.Node, pre bool) []interface{} {
+	switch node.Type {
+	case xhtml.TextNode:
+		// Whitespace between block elements is dropped, except in <pre>
+		if !pre && strings.TrimSpace(node.Data) == "" && strings.Contains(node.Data, "\n") {
+			return nil
+		}
+		return []interface{}{node.Data}
+	case xhtml.ElementNode:
+		if node.DataAtom == atom.Script || node.DataAtom == atom.Style {
+			return nil
+		}
+		var children []interface{}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			children = append(children, htmlChildNodes(child, pre || node.DataAtom == atom.Pre)...)
+		}
+
+		tag, ok := telegraphTags[node.Data]
+		if !ok {
+			return children
+		}
+		result := Node{"tag": tag}
+		attrs := make(map[string]string)
+		for _, attr := range node.Attr {
+			if (attr.Key == "href" || attr.Key == "src") && isSafeURL(attr.Val) {
+				attrs[attr.Key] = attr.Val
+			}
+		}
+		if len(attrs) > 0 {
+			result["attrs"] = attrs
+		}
+		if len(children) > 0 {
+			result["children"] = children
+		}
+		return []interface{}{result}
+	}
+	return nil
+}
@@ -232,3 +232,64 @@ func countOccurrences(s, substr string) int {
 	}
 	return count
 }
+
+func TestMarkdownService_GenerateShowContent_Specials(t *testing.T) {
+	show := &models.Show{
+		TmdbID:          1,
+		Name:            "Anime Show",
+		Status:          "Returning Series",
+		IncludeSpecials: true,
+	}
+
+	episodes := []*models.Episode{
+		{ShowID: 1, SeasonNumber: 1, EpisodeNumber: 1, Name: "Pilot"},
+		{ShowID: 1, SeasonNumber: 0, EpisodeNumber: 2, Name: "OVA"},
+	}
+
+	markdownService := &MarkdownService{}
+	markdown := markdownService.GenerateShowContent(show, episodes)
+
+	if !containsSubstring(markdown, "### 特别篇 (1集)") {
+		t.Error("Specials section not found in output")
+	}
+	if !containsSubstring(markdown, "**SP02** - OVA") {
+		t.Error("Special should use the SP episode code")
+	}
+	if containsSubstring(markdown, "第0季") {
+		t.Error("Specials should not be rendered as season 0")
+	}
+}
+
+func TestMarkdownService_GenerateShowContent_Language(t *testing.T) {
+	show := &models.Show{
+		TmdbID:   1,
+		Name:     "测试剧集",
+		Overview: "中文简介",
+		Translations: models.Translations{
+			"en-US": {Name: "Test Show", Overview: "English overview"},
+		},
+	}
+	episodes := []*models.Episode{
+		{
+			ShowID:        1,
+			SeasonNumber:  1,
+			EpisodeNumber: 1,
+			Name:          "第1集",
+			Translations:  models.Translations{"en-US": {Name: "Pilot"}},
+		},
+	}
+
+	markdownService := &MarkdownService{}
+	markdown := markdownService.WithLanguage("en").GenerateShowContent(show, episodes)
+	for _, want := range []string{"# Test Show", "English overview", "**S01E01** - Pilot"} {
+		if !containsSubstring(markdown, want) {
+			t.Errorf("Expected %q in localized markdown:\n%s", want, markdown)
+		}
+	}
+
+	// The stored text is used without a language, and the input is not modified
+	markdown = markdownService.GenerateShowContent(show, episodes)
+	if !containsSubstring(markdown, "# 测试剧集") || !containsSubstring(markdown, "第1集") {
+		t.Errorf("Expected stored text without a language:\n%s", markdown)
+	}
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/xc9973/go-tmdb-crawler/dto"
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/repositories"
+)
+
+// MovieRefreshResult summarizes a refresh of all stored movies
+type MovieRefreshResult struct {
+	Total     int      `json:"total"`
+	Refreshed int      `json:"refreshed"`
+	Failed    int      `json:"failed"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// MovieService crawls movies and their release dates from TMDB
+type MovieService struct {
+	tmdb      *TMDBService
+	movieRepo repositories.MovieRepository
+}
+
+// NewMovieService creates a new movie service instance
+func NewMovieService(tmdb *TMDBService, movieRepo repositories.MovieRepository) *MovieService {
+	return &MovieService{
+		tmdb:      tmdb,
+		movieRepo: movieRepo,
+	}
+}
+
+// GetTMDBService returns the TMDB service used for crawling
+func (s *MovieService) GetTMDBService() *TMDBService {
+	return s.tmdb
+}
+
+// CrawlMovie fetches a movie from TMDB and stores it with all its release dates
+func (s *MovieService) CrawlMovie(ctx context.Context, tmdbID int) (*models.Movie, error) {
+	movieRepo := s.movieRepo.WithContext(ctx)
+
+	tmdbMovie, err := s.tmdb.GetMovieDetails(ctx, tmdbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch movie details: %w", err)
+	}
+
+	movie, err := movieRepo.GetByTmdbID(tmdbID)
+	isNewMovie := err != nil
+	if isNewMovie {
+		movie = &models.Movie{TmdbID: tmdbMovie.ID}
+	}
+	applyMovieDetails(movie, tmdbMovie)
+	now := time.Now()
+	movie.LastCrawledAt = &now
+
+	if isNewMovie {
+		if err := movieRepo.Create(movie); err != nil {
+			return nil, fmt.Errorf("failed to create movie: %w", err)
+		}
+	} else if err := movieRepo.Update(movie); err != nil {
+		return nil, fmt.Errorf("failed to update movie: %w", err)
+	}
+
+	if err := movieRepo.ReplaceReleaseDates(movie.ID, movieReleaseDates(tmdbMovie)); err != nil {
+		return nil, fmt.Errorf("failed to save release dates: %w", err)
+	}
+
+	return movieRepo.GetByID(movie.ID)
+}
+
+// RefreshAll crawls every stored movie again.
+// Failed movies do not stop the refresh; they are reported in the result.
+func (s *MovieService) RefreshAll(ctx context.Context) (*MovieRefreshResult, error) {
+	movies, err := s.movieRepo.WithContext(ctx).ListAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list movies: %w", err)
+	}
+
+	result := &MovieRefreshResult{Total: len(movies)}
+	for _, movie := range movies {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if _, err := s.CrawlMovie(ctx, movie.TmdbID); err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("movie %d: %v", movie.TmdbID, err))
+			continue
+		}
+		result.Refreshed++
+	}
+	return result, nil
+}
+
+// applyMovieDetails copies the TMDB movie details onto the movie record
+func applyMovieDetails(movie *models.Movie, tmdbMovie *dto.TMDBMovieResponse) {
+	movie.ImdbID = tmdbMovie.ImdbID
+	movie.Title = tmdbMovie.Title
+	movie.OriginalTitle = tmdbMovie.OriginalTitle
+	movie.Status = tmdbMovie.Status
+	movie.Language = tmdbMovie.OriginalLanguage
+	movie.Runtime = tmdbMovie.Runtime
+	movie.Overview = tmdbMovie.Overview
+	movie.PosterPath = tmdbMovie.PosterPath
+	movie.BackdropPath = tmdbMovie.BackdropPath
+	movie.Popularity = tmdbMovie.Popularity
+	movie.VoteAverage = tmdbMovie.VoteAverage
+	movie.VoteCount = tmdbMovie.VoteCount
+	movie.ReleaseDate, _ = ParseDate(tmdbMovie.ReleaseDate)
+}
+
+// movieReleaseDates converts the TMDB release dates of a movie.
+// TMDB gives a timestamp per release; only its date is kept, like episode air dates.
+// Releases with an unknown type or an unparsable date are skipped.
+func movieReleaseDates(tmdbMovie *dto.TMDBMovieResponse) []*models.MovieReleaseDate {
+	if tmdbMovie.ReleaseDates == nil {
+		return nil
+	}
+
+	var releases []*models.MovieReleaseDate
+	for _, country := range tmdbMovie.ReleaseDates.Results {
+		for _, date := range country.ReleaseDates {
+			if len(date.ReleaseDate) < 10 {
+				continue
+			}
+			releaseDate, err := ParseDate(date.ReleaseDate[:10])
+			if err != nil || releaseDate == nil {
+				continue
+			}
+			release := &models.MovieReleaseDate{
+				Region:        country.ISO31661,
+				Type:          date.Type,
+				ReleaseDate:   *releaseDate,
+				Certification: date.Certification,
+				Note:          date.Note,
+			}
+			if release.Validate() != nil {
+				continue
+			}
+			releases = append(releases, release)
+		}
+	}
+	return releases
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xc9973/go-tmdb-crawler/dto"
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/repositories"
+	"github.com/xc9973/go-tmdb-crawler/utils"
+)
+
+func TestMovieService_CrawlMovie(t *testing.T) {
+	fake := newFakeTMDB()
+	fake.movies[603] = &dto.TMDBMovieResponse{
+		ID:          603,
+		ImdbID:      "tt0133093",
+		Title:       "The Matrix",
+		Status:      "Released",
+		ReleaseDate: "1999-03-30",
+		Runtime:     136,
+		ReleaseDates: &dto.TMDBReleaseDatesResponse{Results: []dto.TMDBReleaseDateCountry{
+			{ISO31661: "US", ReleaseDates: []dto.TMDBReleaseDate{
+				{Type: models.ReleaseTypeTheatrical, ReleaseDate: "1999-03-31T00:00:00.000Z", Certification: "R"},
+				{Type: models.ReleaseTypeDigital, ReleaseDate: "2001-06-01T00:00:00.000Z"},
+			}},
+			{ISO31661: "GB", ReleaseDates: []dto.TMDBReleaseDate{
+				{Type: models.ReleaseTypeTheatrical, ReleaseDate: "1999-06-11T00:00:00.000Z", Certification: "15"},
+				{Type: 9, ReleaseDate: "1999-06-12T00:00:00.000Z"},
+				{Type: models.ReleaseTypePhysical, ReleaseDate: ""},
+			}},
+		}},
+	}
+
+	crawler, db := setupCrawlerTest(t, fake)
+	if err := db.AutoMigrate(&models.Movie{}, &models.MovieReleaseDate{}); err != nil {
+		t.Fatalf("Failed to migrate movies: %v", err)
+	}
+	movieRepo := repositories.NewMovieRepository(db)
+	service := NewMovieService(crawler.GetTMDBService(), movieRepo)
+	ctx := context.Background()
+
+	movie, err := service.CrawlMovie(ctx, 603)
+	if err != nil {
+		t.Fatalf("CrawlMovie failed: %v", err)
+	}
+	if movie.Title != "The Matrix" || movie.ImdbID != "tt0133093" || movie.Runtime != 136 || movie.LastCrawledAt == nil {
+		t.Errorf("Unexpected movie: %+v", movie)
+	}
+	if movie.ReleaseDate == nil || movie.ReleaseDate.Format("2006-01-02") != "1999-03-30" {
+		t.Errorf("Unexpected release date: %v", movie.ReleaseDate)
+	}
+	// The release with an unknown type and the one without a date are skipped
+	if len(movie.ReleaseDates) != 3 {
+		t.Fatalf("Expected 3 release dates, got %d", len(movie.ReleaseDates))
+	}
+	first := movie.ReleaseDates[0]
+	if first.Region != "US" || first.Type != models.ReleaseTypeTheatrical || first.Certification != "R" ||
+		!first.ReleaseDate.Equal(time.Date(1999, 3, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected first release: %+v", first)
+	}
+
+	// A refresh replaces the release dates
+	fake.movies[603].ReleaseDates.Results = fake.movies[603].ReleaseDates.Results[:1]
+	crawler.GetTMDBService().ClearCache()
+
+	result, err := service.RefreshAll(ctx)
+	if err != nil {
+		t.Fatalf("RefreshAll failed: %v", err)
+	}
+	if result.Total != 1 || result.Refreshed != 1 || result.Failed != 0 {
+		t.Errorf("Unexpected refresh result: %+v", result)
+	}
+	stored, _ := movieRepo.GetByID(movie.ID)
+	if len(stored.ReleaseDates) != 2 {
+		t.Errorf("Expected 2 release dates after refresh, got %d", len(stored.ReleaseDates))
+	}
+	if count, _ := movieRepo.Count(); count != 1 {
+		t.Errorf("Expected the refresh to update the movie in place, got %d movies", count)
+	}
+
+	if _, err := service.CrawlMovie(ctx, 604); err == nil {
+		t.Error("Expected an error for a movie unknown to TMDB")
+	}
+}
+
+func TestMarkdownService_GenerateUpdateList_Movies(t *testing.T) {
+	service := NewMarkdownService(nil, nil)
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	movie := &models.Movie{ID: 1, Title: "Dune: Part Two", Overview: "Paul unites with the Fremen"}
+	releases := []*models.MovieReleaseDate{
+		{MovieID: 1, Movie: movie, Region: "US", Type: models.ReleaseTypeTheatrical, ReleaseDate: date, Certification: "PG-13"},
+		{MovieID: 1, Movie: movie, Region: "GB", Type: models.ReleaseTypeTheatrical, ReleaseDate: date},
+	}
+
+	content := service.GenerateUpdateList(nil, releases)
+	for _, want := range []string{"## 🎬 电影上映", "### Dune: Part Two", "US 院线 (PG-13), GB 院线", "Paul unites", "1 部电影上映"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected update list to contain %q, got:\n%s", want, content)
+		}
+	}
+
+	content = service.GenerateDateRangeUpdates(date, date.AddDate(0, 0, 7), nil, releases)
+	if !strings.Contains(content, "## 📅 2024-03-01") || !strings.Contains(content, "### 🎬 Dune: Part Two") {
+		t.Errorf("Expected the movie under its release date, got:\n%s", content)
+	}
+
+	// Without movies the stats stay as they were
+	content = service.GenerateUpdateList(nil, nil)
+	if strings.Contains(content, "电影") {
+		t.Errorf("Expected no movie section, got:\n%s", content)
+	}
+}
+
+func TestScheduler_MovieRefreshJob(t *testing.T) {
+	fake := newFakeTMDB()
+	fake.movies[604] = &dto.TMDBMovieResponse{ID: 604, Title: "The Matrix Reloaded", ReleaseDate: "2003-05-15"}
+
+	crawler, db := setupCrawlerTest(t, fake)
+	if err := db.AutoMigrate(&models.Movie{}, &models.MovieReleaseDate{}); err != nil {
+		t.Fatalf("Failed to migrate movies: %v", err)
+	}
+	movieRepo := repositories.NewMovieRepository(db)
+	service := NewMovieService(crawler.GetTMDBService(), movieRepo)
+	movie, err := service.CrawlMovie(context.Background(), 604)
+	if err != nil {
+		t.Fatalf("CrawlMovie failed: %v", err)
+	}
+
+	// TMDB adds a regional release after the movie was added
+	fake.movies[604].ReleaseDates = &dto.TMDBReleaseDatesResponse{Results: []dto.TMDBReleaseDateCountry{
+		{ISO31661: "US", ReleaseDates: []dto.TMDBReleaseDate{
+			{Type: models.ReleaseTypeTheatrical, ReleaseDate: "2003-05-15T00:00:00.000Z"},
+		}},
+	}}
+	crawler.GetTMDBService().ClearCache()
+
+	scheduler := NewScheduler(crawler, &PublisherService{}, nil, utils.NewLogger("info", ""))
+	scheduler.SetMovieRefresh(service, "0 0 4 * * *")
+	scheduler.movieRefreshJob()
+
+	stored, _ := movieRepo.GetByID(movie.ID)
+	if len(stored.ReleaseDates) != 1 {
+		t.Errorf("Expected the scheduled refresh to add the new release, got %d", len(stored.ReleaseDates))
+	}
+	if status := scheduler.GetStatus(); status["movie_refresh_enabled"] != true || status["last_movie_refresh_time"] == nil {
+		t.Errorf("Unexpected scheduler status: %v", status)
+	}
+}
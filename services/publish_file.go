@@ -0,0 +1,330 @@
+package services
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Files written to the publish directory
+const (
+	htmlIndexFileName = "index.html"
+	feedFileName      = "feed.xml"
+)
+
+// maxFeedEntries is the number of update lists kept in the feed
+const maxFeedEntries = 50
+
+// feedID is the Atom ID of the feed; entry IDs extend it with the slug
+const feedID = "urn:go-tmdb-crawler:publish"
+
+// HTMLTarget writes update lists as static HTML pages.
+// Each list is written to <slug>.html, and the latest one also to index.html.
+type HTMLTarget struct {
+	dir     string
+	baseURL string
+}
+
+// NewHTMLTarget creates an HTML target writing to dir, which is served at baseURL
+func NewHTMLTarget(dir, baseURL string) *HTMLTarget {
+	return &HTMLTarget{
+		dir:     dir,
+		baseURL: baseURL,
+	}
+}
+
+// Name returns the target name
+func (t *HTMLTarget) Name() string {
+	return PublishTargetHTML
+}
+
+// Publish writes the page of the update list
+func (t *HTMLTarget) Publish(ctx context.Context, content *PublishContent) (*TargetResult, error) {
+	if err := validateSlug(content.Slug); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(t.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create publish directory: %w", err)
+	}
+
+	page := renderHTMLPage(content)
+	name := content.Slug + ".html"
+	path := filepath.Join(t.dir, name)
+	if err := writeFileAtomic(path, page); err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(filepath.Join(t.dir, htmlIndexFileName), page); err != nil {
+		return nil, err
+	}
+	return &TargetResult{URL: publicURL(t.baseURL, name), Path: path}, nil
+}
+
+// renderHTMLPage renders a standalone HTML document of the update list
+func renderHTMLPage(content *PublishContent) []byte {
+	title := html.EscapeString(content.Title)
+	var builder strings.Builder
+	builder.WriteString("<!DOCTYPE html>\n<html>\n<head>\n")
+	builder.WriteString(`<meta charset="utf-8">` + "\n")
+	builder.WriteString(`<meta name="viewport" content="width=device-width, initial-scale=1">` + "\n")
+	builder.WriteString("<title>" + title + "</title>\n")
+	builder.WriteString(`<link rel="alternate" type="application/atom+xml" href="` + feedFileName + `">` + "\n")
+	builder.WriteString("</head>\n<body>\n<article>\n")
+	builder.WriteString("<h1>" + title + "</h1>\n")
+	builder.WriteString(renderNodesHTML(content.Content))
+	builder.WriteString("</article>\n")
+	builder.WriteString("<footer><time datetime=\"" + content.PublishedAt.UTC().Format(time.RFC3339) + "\">" +
+		content.PublishedAt.Format("2006-01-02 15:04") + "</time></footer>\n")
+	builder.WriteString("</body>\n</html>\n")
+	return []byte(builder.String())
+}
+
+// FeedTarget adds update lists to an Atom feed.
+// A list published again under the same slug replaces its entry.
+type FeedTarget struct {
+	dir     string
+	baseURL string
+}
+
+// NewFeedTarget creates a feed target writing dir/feed.xml, which is served at baseURL
+func NewFeedTarget(dir, baseURL string) *FeedTarget {
+	return &FeedTarget{
+		dir:     dir,
+		baseURL: baseURL,
+	}
+}
+
+// Name returns the target name
+func (t *FeedTarget) Name() string {
+	return PublishTargetFeed
+}
+
+// atomFeed is an Atom (RFC 4287) feed document
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomPerson  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Links      []atomLink     `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+	Content    atomContent    `xml:"content"`
+}
+
+// Publish adds the update list to the feed, keeping the latest maxFeedEntries lists
+func (t *FeedTarget) Publish(ctx context.Context, content *PublishContent) (*TargetResult, error) {
+	if err := validateSlug(content.Slug); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(t.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create publish directory: %w", err)
+	}
+
+	path := filepath.Join(t.dir, feedFileName)
+	feed, err := readAtomFeed(path)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := content.PublishedAt.UTC().Format(time.RFC3339)
+	entry := atomEntry{
+		ID:      feedID + ":" + content.Slug,
+		Title:   content.Title,
+		Updated: updated,
+		Content: atomContent{Type: "html", Body: renderNodesHTML(content.Content)},
+	}
+	if link := publicURL(t.baseURL, content.Slug+".html"); link != "" {
+		entry.Links = []atomLink{{Rel: "alternate", Type: "text/html", Href: link}}
+	}
+	for _, tag := range content.Tags {
+		entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+	}
+
+	entries := []atomEntry{entry}
+	for _, existing := range feed.Entries {
+		if existing.ID != entry.ID && len(entries) < maxFeedEntries {
+			entries = append(entries, existing)
+		}
+	}
+	feed.Entries = entries
+	feed.ID = feedID
+	feed.Title = "TMDB 剧集更新"
+	feed.Updated = updated
+	feed.Author = atomPerson{Name: "go-tmdb-crawler"}
+	feed.Links = nil
+	if self := publicURL(t.baseURL, feedFileName); self != "" {
+		feed.Links = []atomLink{{Rel: "self", Type: "application/atom+xml", Href: self}}
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal feed: %w", err)
+	}
+	if err := writeFileAtomic(path, append([]byte(xml.Header), data...)); err != nil {
+		return nil, err
+	}
+	return &TargetResult{URL: publicURL(t.baseURL, feedFileName), Path: path}, nil
+}
+
+// readAtomFeed reads the feed written by a previous publish; a missing file is an empty feed
+func readAtomFeed(path string) (*atomFeed, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &atomFeed{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feed: %w", err)
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		return nil, fmt.Errorf("failed to parse feed %s: %w", path, err)
+	}
+	return &feed, nil
+}
+
+// Tags of Telegraph content rendered as HTML elements; others only keep their children
+var htmlNodeTags = map[string]bool{
+	"a": true, "aside": true, "b": true, "blockquote": true, "br": true, "code": true,
+	"em": true, "figcaption": true, "figure": true, "h3": true, "h4": true, "hr": true,
+	"i": true, "img": true, "li": true, "ol": true, "p": true, "pre": true, "s": true,
+	"strong": true, "u": true, "ul": true,
+}
+
+// renderNodesHTML renders Telegraph content nodes as HTML.
+// Runs of top-level list items are wrapped in a <ul>.
+func renderNodesHTML(content []Node) string {
+	var builder strings.Builder
+	inList := false
+	for _, node := range content {
+		isItem := node["tag"] == "li"
+		if isItem && !inList {
+			builder.WriteString("<ul>\n")
+		} else if !isItem && inList {
+			builder.WriteString("</ul>\n")
+		}
+		inList = isItem
+		writeNodeHTML(&builder, node)
+		builder.WriteString("\n")
+	}
+	if inList {
+		builder.WriteString("</ul>\n")
+	}
+	return builder.String()
+}
+
+// writeNodeHTML writes a node, a child list or a text child
+func writeNodeHTML(builder *strings.Builder, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		builder.WriteString(html.EscapeString(v))
+	case []interface{}:
+		for _, child := range v {
+			writeNodeHTML(builder, child)
+		}
+	case Node:
+		writeNodeHTML(builder, map[string]interface{}(v))
+	case map[string]interface{}:
+		tag, _ := v["tag"].(string)
+		if !htmlNodeTags[tag] {
+			writeNodeHTML(builder, v["children"])
+			return
+		}
+		builder.WriteString("<" + tag)
+		for _, attr := range []string{"href", "src"} {
+			if value := nodeAttr(v, attr); value != "" {
+				builder.WriteString(fmt.Sprintf(` %s="%s"`, attr, html.EscapeString(value)))
+			}
+		}
+		builder.WriteString(">")
+		if tag == "br" || tag == "hr" || tag == "img" {
+			return
+		}
+		writeNodeHTML(builder, v["children"])
+		builder.WriteString("</" + tag + ">")
+	}
+}
+
+// nodeAttr returns an attribute of a node built in Go or decoded from JSON
+func nodeAttr(node map[string]interface{}, key string) string {
+	switch attrs := node["attrs"].(type) {
+	case map[string]string:
+		return attrs[key]
+	case map[string]interface{}:
+		value, _ := attrs[key].(string)
+		return value
+	}
+	return ""
+}
+
+// publicURL returns the URL of a file in the publish directory, or "" without a base URL
+func publicURL(baseURL, name string) string {
+	if baseURL == "" {
+		return ""
+	}
+	return strings.TrimRight(baseURL, "/") + "/" + name
+}
+
+// validateSlug rejects slugs that are not a plain file name
+func validateSlug(slug string) error {
+	if slug == "" || strings.ContainsAny(slug, `/\`) || strings.HasPrefix(slug, ".") {
+		return fmt.Errorf("invalid publish slug: %q", slug)
+	}
+	return nil
+}
+
+// writeFileAtomic replaces path with data, so readers never see a partial file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
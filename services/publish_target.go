@@ -0,0 +1,401 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/xc9973/go-tmdb-crawler/config"
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/repositories"
+)
+
+// Publish target names, as listed in PUBLISH_TARGETS
+const (
+	PublishTargetTelegraph = "telegraph"
+	PublishTargetTelegram  = "telegram"
+	PublishTargetWebhook   = "webhook"
+	PublishTargetHTML      = "html"
+	PublishTargetFeed      = "feed"
+)
+
+// PublishContent is an update list rendered once and handed to every publish target
+type PublishContent struct {
+	Title string
+	// Slug is the slot of the list, e.g. today-2026-01-02 or week-2026-W01.
+	// Publishing the same slot again updates what was published before: it
+	// names files and feed entries, and Telegraph edits the page of the slot.
+	Slug        string
+	Content     []Node
+	Tags        []string
+	ContentHash string
+	DateRange   string
+
+	ShowsCount    int
+	EpisodesCount int
+	MoviesCount   int
+
+	// Episodes and Releases are the data the content was rendered from
+	Episodes    []*models.Episode
+	Releases    []*models.MovieReleaseDate
+	PublishedAt time.Time
+}
+
+// PublishTarget is a destination of the update lists
+type PublishTarget interface {
+	// Name identifies the target in PUBLISH_TARGETS and in the publish records
+	Name() string
+	// Publish publishes content and returns where it can be read
+	Publish(ctx context.Context, content *PublishContent) (*TargetResult, error)
+}
+
+// TargetResult is the outcome of publishing to one target
+type TargetResult struct {
+	Target  string `json:"target"`
+	Success bool   `json:"success"`
+	URL     string `json:"url,omitempty"`
+	Path    string `json:"path,omitempty"`
+	// Duplicate is set when the same content had already been published to the target
+	Duplicate bool `json:"duplicate,omitempty"`
+	// Edited is set when an earlier publish of the slot was updated in place
+	Edited bool   `json:"edited,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// PublishTargetOptions holds the settings of the publish targets
+type PublishTargetOptions struct {
+	Telegraph      *TelegraphService
+	TelegraphPosts repositories.TelegraphPostRepository
+
+	TelegramBotToken string
+	TelegramAPIURL   string
+	TelegramChatID   string
+
+	WebhookURL string
+
+	// Dir receives the html pages and the feed
+	Dir string
+	// BaseURL is the public URL of Dir; without it pages and feed entries carry no links
+	BaseURL string
+}
+
+// NewPublishTargetOptions returns the target settings of the publish and Telegram configuration
+func NewPublishTargetOptions(cfg *config.Config, telegraph *TelegraphService, telegraphPosts repositories.TelegraphPostRepository) PublishTargetOptions {
+	return PublishTargetOptions{
+		Telegraph:        telegraph,
+		TelegraphPosts:   telegraphPosts,
+		TelegramBotToken: cfg.Telegram.BotToken,
+		TelegramAPIURL:   cfg.Telegram.APIURL,
+		TelegramChatID:   cfg.Publish.TelegramChatID,
+		WebhookURL:       cfg.Publish.WebhookURL,
+		Dir:              cfg.Publish.Dir,
+		BaseURL:          cfg.Publish.BaseURL,
+	}
+}
+
+// NewPublishTargets creates the named targets, in order
+func NewPublishTargets(names []string, opts PublishTargetOptions) ([]PublishTarget, error) {
+	targets := make([]PublishTarget, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case PublishTargetTelegraph:
+			if opts.Telegraph == nil {
+				return nil, fmt.Errorf("telegraph target needs a Telegraph service")
+			}
+			targets = append(targets, NewTelegraphTarget(opts.Telegraph, opts.TelegraphPosts))
+		case PublishTargetTelegram:
+			if opts.TelegramBotToken == "" || opts.TelegramChatID == "" {
+				return nil, fmt.Errorf("telegram target needs a bot token and a chat ID")
+			}
+			targets = append(targets, NewTelegramTarget(NewTelegramClient(opts.TelegramBotToken, opts.TelegramAPIURL), opts.TelegramChatID))
+		case PublishTargetWebhook:
+			if opts.WebhookURL == "" {
+				return nil, fmt.Errorf("webhook target needs a URL")
+			}
+			targets = append(targets, NewWebhookTarget(opts.WebhookURL))
+		case PublishTargetHTML:
+			targets = append(targets, NewHTMLTarget(opts.Dir, opts.BaseURL))
+		case PublishTargetFeed:
+			targets = append(targets, NewFeedTarget(opts.Dir, opts.BaseURL))
+		default:
+			return nil, fmt.Errorf("unknown publish target: %s", name)
+		}
+	}
+	return targets, nil
+}
+
+// TelegraphTarget publishes update lists as Telegraph pages
+type TelegraphTarget struct {
+	telegraph *TelegraphService
+	postRepo  repositories.TelegraphPostRepository
+}
+
+// NewTelegraphTarget creates a Telegraph target; postRepo may be nil
+func NewTelegraphTarget(telegraph *TelegraphService, postRepo repositories.TelegraphPostRepository) *TelegraphTarget {
+	return &TelegraphTarget{
+		telegraph: telegraph,
+		postRepo:  postRepo,
+	}
+}
+
+// Name returns the target name
+func (t *TelegraphTarget) Name() string {
+	return PublishTargetTelegraph
+}
+
+// Publish creates the Telegraph page of the content's slot, or edits it in
+// place when the slot already has a page
+func (t *TelegraphTarget) Publish(ctx context.Context, content *PublishContent) (*TargetResult, error) {
+	if t.postRepo != nil {
+		if post, err := t.postRepo.GetBySlot(content.Slug); err == nil {
+			return t.edit(post, content)
+		}
+	}
+
+	page, err := t.telegraph.CreatePage(content.Title, content.Content, content.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create page: %w", err)
+	}
+
+	if t.postRepo != nil {
+		post := &models.TelegraphPost{
+			TelegraphPath: page.Path,
+			TelegraphURL:  page.URL,
+			Slot:          content.Slug,
+		}
+		setTelegraphPostContent(post, content)
+		if err := t.postRepo.Create(post); err == nil {
+			t.addRevision(post)
+		}
+	}
+
+	return &TargetResult{URL: page.URL, Path: page.Path}, nil
+}
+
+// edit updates the existing page of a slot, unless it already shows the content
+func (t *TelegraphTarget) edit(post *models.TelegraphPost, content *PublishContent) (*TargetResult, error) {
+	if post.ContentHash == content.ContentHash {
+		return &TargetResult{URL: post.GetFullURL(), Path: post.TelegraphPath, Duplicate: true}, nil
+	}
+
+	page, err := t.telegraph.EditPage(post.TelegraphPath, content.Title, content.Content, content.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to edit page %s: %w", post.TelegraphPath, err)
+	}
+	if page.URL != "" {
+		post.TelegraphURL = page.URL
+	}
+
+	setTelegraphPostContent(post, content)
+	if err := t.postRepo.Update(post); err == nil {
+		t.addRevision(post)
+	}
+
+	return &TargetResult{URL: post.GetFullURL(), Path: post.TelegraphPath, Edited: true}, nil
+}
+
+// addRevision keeps the current version of post in its history
+func (t *TelegraphTarget) addRevision(post *models.TelegraphPost) {
+	_ = t.postRepo.CreateRevision(&models.TelegraphPostRevision{
+		PostID:        post.ID,
+		Title:         post.Title,
+		ContentHash:   post.ContentHash,
+		ShowsCount:    post.ShowsCount,
+		EpisodesCount: post.EpisodesCount,
+		MoviesCount:   post.MoviesCount,
+	})
+}
+
+// setTelegraphPostContent copies what a page shows from content to post
+func setTelegraphPostContent(post *models.TelegraphPost, content *PublishContent) {
+	post.Title = content.Title
+	post.ContentHash = content.ContentHash
+	post.ShowsCount = content.ShowsCount
+	post.EpisodesCount = content.EpisodesCount
+	post.MoviesCount = content.MoviesCount
+	post.DateRange = content.DateRange
+}
+
+// TelegramTarget posts update lists as messages to a Telegram chat or channel
+type TelegramTarget struct {
+	client *TelegramClient
+	chatID string
+}
+
+// NewTelegramTarget creates a Telegram target posting to chatID
+func NewTelegramTarget(client *TelegramClient, chatID string) *TelegramTarget {
+	return &TelegramTarget{
+		client: client,
+		chatID: chatID,
+	}
+}
+
+// Name returns the target name
+func (t *TelegramTarget) Name() string {
+	return PublishTargetTelegram
+}
+
+// Publish sends the update list as one message, cut at the message size limit
+func (t *TelegramTarget) Publish(ctx context.Context, content *PublishContent) (*TargetResult, error) {
+	text := "<b>" + html.EscapeString(content.Title) + "</b>\n\n" + telegramText(content.Content)
+	message, err := t.client.SendMessage(ctx, t.chatID, truncateTelegramText(text, telegramMessageLimit))
+	if err != nil {
+		return nil, err
+	}
+	return &TargetResult{URL: message.Link()}, nil
+}
+
+// telegramBlankLines matches the runs of empty lines left by <br> nodes
+var telegramBlankLines = regexp.MustCompile(`\n{3,}`)
+
+// telegramText renders content nodes in the HTML subset of Telegram messages
+func telegramText(content []Node) string {
+	var builder strings.Builder
+	for _, node := range content {
+		switch node["tag"] {
+		case "h3", "h4", "b", "strong":
+			builder.WriteString("<b>" + telegramInline(node["children"]) + "</b>\n")
+		case "li":
+			builder.WriteString("• " + telegramInline(node["children"]) + "\n")
+		case "hr", "br":
+			builder.WriteString("\n")
+		default:
+			builder.WriteString(telegramInline(node) + "\n")
+		}
+	}
+	return strings.TrimSpace(telegramBlankLines.ReplaceAllString(builder.String(), "\n\n"))
+}
+
+// telegramInline renders text, links and emphasis; other tags keep only their text
+func telegramInline(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return html.EscapeString(v)
+	case []interface{}:
+		var builder strings.Builder
+		for _, child := range v {
+			builder.WriteString(telegramInline(child))
+		}
+		return builder.String()
+	case Node:
+		return telegramInline(map[string]interface{}(v))
+	case map[string]interface{}:
+		children := telegramInline(v["children"])
+		switch v["tag"] {
+		case "a":
+			if href := nodeAttr(v, "href"); href != "" {
+				return `<a href="` + html.EscapeString(href) + `">` + children + "</a>"
+			}
+		case "b", "strong":
+			return "<b>" + children + "</b>"
+		case "i", "em":
+			return "<i>" + children + "</i>"
+		case "code":
+			return "<code>" + children + "</code>"
+		}
+		return children
+	}
+	return ""
+}
+
+// truncateTelegramText cuts text to at most limit characters at a line break,
+// so no HTML tag is left open
+func truncateTelegramText(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	const ellipsis = "\n…"
+	runes := []rune(text)
+	cut := string(runes[:limit-utf8.RuneCountInString(ellipsis)])
+	if i := strings.LastIndex(cut, "\n"); i > 0 {
+		cut = cut[:i]
+	}
+	return cut + ellipsis
+}
+
+// WebhookTarget posts update lists as JSON to a URL
+type WebhookTarget struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookTarget creates a webhook target posting to url
+func NewWebhookTarget(url string) *WebhookTarget {
+	return &WebhookTarget{
+		url: url,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Name returns the target name
+func (t *WebhookTarget) Name() string {
+	return PublishTargetWebhook
+}
+
+// webhookPayload is the JSON body posted by WebhookTarget
+type webhookPayload struct {
+	Event         string                     `json:"event"`
+	Title         string                     `json:"title"`
+	Slug          string                     `json:"slug"`
+	Tags          []string                   `json:"tags"`
+	ContentHash   string                     `json:"content_hash"`
+	DateRange     string                     `json:"date_range,omitempty"`
+	ShowsCount    int                        `json:"shows_count"`
+	EpisodesCount int                        `json:"episodes_count"`
+	MoviesCount   int                        `json:"movies_count"`
+	HTML          string                     `json:"html"`
+	Episodes      []*models.Episode          `json:"episodes"`
+	Releases      []*models.MovieReleaseDate `json:"movie_releases"`
+	PublishedAt   time.Time                  `json:"published_at"`
+}
+
+// Publish posts the update list; any status other than 2xx is an error
+func (t *WebhookTarget) Publish(ctx context.Context, content *PublishContent) (*TargetResult, error) {
+	body, err := json.Marshal(webhookPayload{
+		Event:         "publish",
+		Title:         content.Title,
+		Slug:          content.Slug,
+		Tags:          content.Tags,
+		ContentHash:   content.ContentHash,
+		DateRange:     content.DateRange,
+		ShowsCount:    content.ShowsCount,
+		EpisodesCount: content.EpisodesCount,
+		MoviesCount:   content.MoviesCount,
+		HTML:          renderNodesHTML(content.Content),
+		Episodes:      content.Episodes,
+		Releases:      content.Releases,
+		PublishedAt:   content.PublishedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-tmdb-crawler")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return &TargetResult{}, nil
+}
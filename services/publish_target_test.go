@@ -0,0 +1,345 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/repositories"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// fakeTarget records the content it is given and fails when err is set
+type fakeTarget struct {
+	name      string
+	err       error
+	published []*PublishContent
+}
+
+func (t *fakeTarget) Name() string { return t.name }
+
+func (t *fakeTarget) Publish(ctx context.Context, content *PublishContent) (*TargetResult, error) {
+	t.published = append(t.published, content)
+	if t.err != nil {
+		return nil, t.err
+	}
+	return &TargetResult{URL: "https://example.com/" + content.Slug}, nil
+}
+
+func testPublishContent(slug string) *PublishContent {
+	airDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	show := &models.Show{ID: 1, Name: "Breaking <Bad>"}
+	episodes := []*models.Episode{
+		{ShowID: 1, Show: show, SeasonNumber: 1, EpisodeNumber: 1, Name: "Pilot", AirDate: &airDate},
+	}
+	nodes := (&TelegraphService{}).GenerateUpdateListContent(episodes, nil)
+	return &PublishContent{
+		Title:         "今日更新 - 2024-03-01",
+		Slug:          slug,
+		Content:       nodes,
+		Tags:          []string{"剧集", "更新"},
+		ContentHash:   generateContentHash(nodes),
+		ShowsCount:    1,
+		EpisodesCount: 1,
+		Episodes:      episodes,
+		PublishedAt:   time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestPublisherService_FansOutToTargets(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "publish.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.PublishRecord{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	recordRepo := repositories.NewPublishRecordRepository(db)
+
+	working := &fakeTarget{name: "working"}
+	failing := &fakeTarget{name: "failing", err: errors.New("boom")}
+	publisher := NewPublisherService(&TelegraphService{}, nil, nil, nil, nil)
+	publisher.SetTargets([]PublishTarget{failing, working})
+	publisher.SetPublishRecords(recordRepo)
+
+	result, err := publisher.publish(testPublishContent("today-2024-03-01"))
+	if err != nil {
+		t.Fatalf("Expected a partial failure to succeed, got %v", err)
+	}
+	if !result.Success || result.URL != "https://example.com/today-2024-03-01" {
+		t.Errorf("Expected the URL of the working target, got %+v", result)
+	}
+	if len(result.Targets) != 2 || result.Targets[0].Success || result.Targets[0].Error != "boom" || !result.Targets[1].Success {
+		t.Errorf("Unexpected target results: %+v %+v", result.Targets[0], result.Targets[1])
+	}
+	if result.Error == nil {
+		t.Error("Expected the failure of the failing target to be reported")
+	}
+
+	records, err := recordRepo.ListRecent("", 10)
+	if err != nil {
+		t.Fatalf("ListRecent failed: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected one record per target, got %d", len(records))
+	}
+
+	// Publishing the same content again skips the target that already has it
+	result, err = publisher.publish(testPublishContent("today-2024-03-01"))
+	if err != nil {
+		t.Fatalf("Second publish failed: %v", err)
+	}
+	if !result.Targets[1].Duplicate || len(working.published) != 1 {
+		t.Errorf("Expected the working target to be skipped, got %+v", result.Targets[1])
+	}
+	if len(failing.published) != 2 {
+		t.Errorf("Expected the failing target to be retried, published %d times", len(failing.published))
+	}
+
+	// New content for the slot is published again, even content it had before
+	changed := testPublishContent("today-2024-03-01")
+	changed.ContentHash = "changed"
+	if _, err := publisher.publish(changed); err != nil {
+		t.Fatalf("Publishing new content failed: %v", err)
+	}
+	if _, err := publisher.publish(testPublishContent("today-2024-03-01")); err != nil {
+		t.Fatalf("Publishing the first content again failed: %v", err)
+	}
+	if len(working.published) != 3 {
+		t.Errorf("Expected each change of the slot to be published, published %d times", len(working.published))
+	}
+
+	// All targets failing is an error
+	publisher.SetTargets([]PublishTarget{failing})
+	if result, err := publisher.publish(testPublishContent("today-2024-03-02")); err == nil || result.Success {
+		t.Errorf("Expected an error when every target fails, got %+v", result)
+	}
+}
+
+func TestTelegraphTarget_EditsThePageOfASlot(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, strings.TrimPrefix(r.URL.Path, "/"))
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"path":"Today-03-01","url":"https://telegra.ph/Today-03-01"}}`))
+	}))
+	defer server.Close()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "telegraph.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.TelegraphPost{}, &models.TelegraphPostRevision{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	postRepo := repositories.NewTelegraphPostRepository(db)
+	telegraph := &TelegraphService{apiURL: server.URL, accessToken: "token", httpClient: server.Client()}
+	target := NewTelegraphTarget(telegraph, postRepo)
+	ctx := context.Background()
+
+	content := testPublishContent("today-2024-03-01")
+	if result, err := target.Publish(ctx, content); err != nil || result.Edited || result.Path != "Today-03-01" {
+		t.Fatalf("Expected a new page, got %+v, %v", result, err)
+	}
+
+	changed := testPublishContent("today-2024-03-01")
+	changed.ContentHash = "changed"
+	changed.EpisodesCount = 2
+	result, err := target.Publish(ctx, changed)
+	if err != nil || !result.Edited || result.URL != "https://telegra.ph/Today-03-01" {
+		t.Fatalf("Expected the page to be edited, got %+v, %v", result, err)
+	}
+
+	if result, err := target.Publish(ctx, changed); err != nil || !result.Duplicate {
+		t.Fatalf("Expected the unchanged content to be skipped, got %+v, %v", result, err)
+	}
+	if strings.Join(methods, ",") != "createPage,editPage" {
+		t.Errorf("Expected one page created and edited once, got %v", methods)
+	}
+
+	post, err := postRepo.GetBySlot("today-2024-03-01")
+	if err != nil {
+		t.Fatalf("GetBySlot failed: %v", err)
+	}
+	if post.ContentHash != "changed" || post.EpisodesCount != 2 {
+		t.Errorf("Expected the post to show the edited content, got %+v", post)
+	}
+	revisions, err := postRepo.ListRevisions(post.ID)
+	if err != nil {
+		t.Fatalf("ListRevisions failed: %v", err)
+	}
+	if len(revisions) != 2 || revisions[0].ContentHash != content.ContentHash || revisions[1].ContentHash != "changed" {
+		t.Errorf("Expected the created and the edited version, got %+v", revisions)
+	}
+}
+
+func TestNewPublishTargets(t *testing.T) {
+	targets, err := NewPublishTargets([]string{"telegraph", "html", "feed"}, PublishTargetOptions{
+		Telegraph: &TelegraphService{},
+		Dir:       t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("NewPublishTargets failed: %v", err)
+	}
+	if len(targets) != 3 || targets[0].Name() != "telegraph" || targets[2].Name() != "feed" {
+		t.Errorf("Unexpected targets: %v", targets)
+	}
+
+	for _, names := range [][]string{{"telegram"}, {"webhook"}, {"carrier-pigeon"}} {
+		if _, err := NewPublishTargets(names, PublishTargetOptions{}); err == nil {
+			t.Errorf("Expected an error for %v without settings", names)
+		}
+	}
+}
+
+func TestTelegramTarget_Publish(t *testing.T) {
+	var request map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bot123:abc/sendMessage" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":42,"chat":{"id":-100,"type":"channel","username":"tvupdates"}}}`))
+	}))
+	defer server.Close()
+
+	target := NewTelegramTarget(NewTelegramClient("123:abc", server.URL), "@tvupdates")
+	result, err := target.Publish(context.Background(), testPublishContent("today-2024-03-01"))
+	if err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if result.URL != "https://t.me/tvupdates/42" {
+		t.Errorf("Expected the message link, got %q", result.URL)
+	}
+
+	text, _ := request["text"].(string)
+	if request["chat_id"] != "@tvupdates" || request["parse_mode"] != "HTML" {
+		t.Errorf("Unexpected request %v", request)
+	}
+	for _, want := range []string{"<b>今日更新 - 2024-03-01</b>", "<b>Breaking &lt;Bad&gt;</b>", "• S01E01 - Pilot"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected the message to contain %q, got:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "\n\n\n") {
+		t.Errorf("Expected blank lines to be collapsed, got:\n%s", text)
+	}
+}
+
+func TestTelegramClient_ReportsAPIErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewTelegramClient("123:abc", server.URL).SendMessage(context.Background(), "@missing", "hi")
+	if err == nil || !strings.Contains(err.Error(), "chat not found") {
+		t.Errorf("Expected the API error description, got %v", err)
+	}
+}
+
+func TestTruncateTelegramText(t *testing.T) {
+	text := strings.Repeat("• S01E01 - 一集\n", 1000)
+	truncated := truncateTelegramText(text, telegramMessageLimit)
+	if utf8.RuneCountInString(truncated) > telegramMessageLimit {
+		t.Errorf("Expected at most %d characters, got %d", telegramMessageLimit, utf8.RuneCountInString(truncated))
+	}
+	if !strings.HasSuffix(truncated, "一集\n…") {
+		t.Errorf("Expected the text to be cut at a line break, got suffix %q", truncated[len(truncated)-20:])
+	}
+	if short := "short"; truncateTelegramText(short, telegramMessageLimit) != short {
+		t.Error("Expected short text to be unchanged")
+	}
+}
+
+func TestWebhookTarget_Publish(t *testing.T) {
+	status := http.StatusNoContent
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	target := NewWebhookTarget(server.URL)
+	if _, err := target.Publish(context.Background(), testPublishContent("today-2024-03-01")); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if payload["event"] != "publish" || payload["slug"] != "today-2024-03-01" || payload["episodes_count"] != float64(1) {
+		t.Errorf("Unexpected payload %v", payload)
+	}
+	if episodes, _ := payload["episodes"].([]interface{}); len(episodes) != 1 {
+		t.Errorf("Expected the episodes in the payload, got %v", payload["episodes"])
+	}
+
+	status = http.StatusInternalServerError
+	if _, err := target.Publish(context.Background(), testPublishContent("today-2024-03-01")); err == nil {
+		t.Error("Expected an error for a 500 response")
+	}
+}
+
+func TestHTMLAndFeedTargets(t *testing.T) {
+	dir := t.TempDir()
+	htmlTarget := NewHTMLTarget(dir, "https://tv.example.com/updates/")
+	feedTarget := NewFeedTarget(dir, "https://tv.example.com/updates/")
+	ctx := context.Background()
+
+	result, err := htmlTarget.Publish(ctx, testPublishContent("today-2024-03-01"))
+	if err != nil {
+		t.Fatalf("HTML publish failed: %v", err)
+	}
+	if result.URL != "https://tv.example.com/updates/today-2024-03-01.html" {
+		t.Errorf("Unexpected page URL %q", result.URL)
+	}
+	page, err := os.ReadFile(filepath.Join(dir, "today-2024-03-01.html"))
+	if err != nil {
+		t.Fatalf("Page not written: %v", err)
+	}
+	for _, want := range []string{"<title>今日更新 - 2024-03-01</title>", "<b>Breaking &lt;Bad&gt;</b>", "<ul>\n<li>S01E01 - Pilot</li>\n</ul>"} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("Expected the page to contain %q, got:\n%s", want, page)
+		}
+	}
+	if index, _ := os.ReadFile(filepath.Join(dir, "index.html")); string(index) != string(page) {
+		t.Error("Expected index.html to be the latest page")
+	}
+
+	// The same slug replaces its entry, a new slug is added in front
+	for _, slug := range []string{"today-2024-03-01", "today-2024-03-01", "today-2024-03-02"} {
+		if _, err := feedTarget.Publish(ctx, testPublishContent(slug)); err != nil {
+			t.Fatalf("Feed publish failed: %v", err)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "feed.xml"))
+	if err != nil {
+		t.Fatalf("Feed not written: %v", err)
+	}
+	var feed atomFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		t.Fatalf("Feed is not valid XML: %v", err)
+	}
+	if len(feed.Entries) != 2 || feed.Entries[0].ID != feedID+":today-2024-03-02" {
+		t.Errorf("Unexpected feed entries: %+v", feed.Entries)
+	}
+	if links := feed.Entries[1].Links; len(links) != 1 || links[0].Href != "https://tv.example.com/updates/today-2024-03-01.html" {
+		t.Errorf("Unexpected entry links: %+v", links)
+	}
+	if !strings.Contains(string(data), `<feed xmlns="http://www.w3.org/2005/Atom">`) {
+		t.Errorf("Expected an Atom feed, got:\n%s", data)
+	}
+
+	if _, err := htmlTarget.Publish(ctx, testPublishContent("../escape")); err == nil {
+		t.Error("Expected an error for a slug with a path separator")
+	}
+}
@@ -1,9 +1,11 @@
 package services
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -12,13 +14,26 @@ import (
 	"github.com/xc9973/go-tmdb-crawler/utils"
 )
 
-// PublisherService handles publishing to Telegraph
+// PublisherService renders update lists and publishes them to every configured target
 type PublisherService struct {
 	telegraph         *TelegraphService
 	showRepo          repositories.ShowRepository
 	episodeRepo       repositories.EpisodeRepository
 	telegraphPostRepo repositories.TelegraphPostRepository
 	timezoneHelper    *utils.TimezoneHelper
+	// lang selects the translation of names and overviews; empty uses the stored text
+	lang string
+	// movieRepo adds movie releases to the update lists when set
+	movieRepo     repositories.MovieRepository
+	releaseFilter repositories.MovieReleaseFilter
+	// targets receive every update list, in order
+	targets []PublishTarget
+	// recordRepo keeps the result of each target when set
+	recordRepo repositories.PublishRecordRepository
+	// events receives publish.completed when set
+	events EventEmitter
+	// templates render the content as Markdown when set; otherwise the built-in layout is used
+	templates *TemplateService
 }
 
 // NewPublisherService creates a new publisher service instance
@@ -35,9 +50,54 @@ func NewPublisherService(
 		episodeRepo:       episodeRepo,
 		telegraphPostRepo: telegraphPostRepo,
 		timezoneHelper:    timezoneHelper,
+		targets:           []PublishTarget{NewTelegraphTarget(telegraph, telegraphPostRepo)},
 	}
 }
 
+// SetTargets replaces the publish targets; the default is Telegraph only
+func (s *PublisherService) SetTargets(targets []PublishTarget) {
+	s.targets = targets
+}
+
+// SetPublishRecords records the result of every target in repo.
+// Content already published successfully to a target is not published to it again.
+func (s *PublisherService) SetPublishRecords(repo repositories.PublishRecordRepository) {
+	s.recordRepo = repo
+}
+
+// SetEventEmitter reports every publish that changed at least one target to events
+func (s *PublisherService) SetEventEmitter(events EventEmitter) {
+	s.events = events
+}
+
+// SetTemplates renders the update lists and show pages with the same templates
+// as the Markdown, converting them to Telegraph nodes
+func (s *PublisherService) SetTemplates(templates *TemplateService) {
+	s.templates = templates
+}
+
+// TargetNames returns the names of the publish targets, in order
+func (s *PublisherService) TargetNames() []string {
+	names := make([]string, 0, len(s.targets))
+	for _, target := range s.targets {
+		names = append(names, target.Name())
+	}
+	return names
+}
+
+// SetMovieReleases adds the movie releases matching filter to the update lists
+func (s *PublisherService) SetMovieReleases(movieRepo repositories.MovieRepository, filter repositories.MovieReleaseFilter) {
+	s.movieRepo = movieRepo
+	s.releaseFilter = filter
+}
+
+// WithLanguage returns a copy of the service that publishes names and overviews in lang
+func (s *PublisherService) WithLanguage(lang string) *PublisherService {
+	localized := *s
+	localized.lang = lang
+	return &localized
+}
+
 // generateContentHash generates a SHA256 hash from content nodes
 func generateContentHash(content []Node) string {
 	data, err := json.Marshal(content)
@@ -49,7 +109,8 @@ func generateContentHash(content []Node) string {
 	return hex.EncodeToString(hash[:])
 }
 
-// PublishResult represents the result of a publish operation
+// PublishResult represents the result of a publish operation.
+// URL and Path are those of the first target that succeeded.
 type PublishResult struct {
 	Success       bool
 	URL           string
@@ -57,10 +118,27 @@ type PublishResult struct {
 	Title         string
 	ShowsCount    int
 	EpisodesCount int
+	MoviesCount   int
 	Error         error
+	// Targets holds the result of each target, in order
+	Targets []*TargetResult
 }
 
-// PublishTodayUpdates publishes today's episode updates to Telegraph
+// IsDuplicate reports whether every target that succeeded already had the content
+func (r *PublishResult) IsDuplicate() bool {
+	duplicate := false
+	for _, target := range r.Targets {
+		if target.Success {
+			if !target.Duplicate {
+				return false
+			}
+			duplicate = true
+		}
+	}
+	return duplicate
+}
+
+// PublishTodayUpdates publishes today's episode updates to all targets
 func (s *PublisherService) PublishTodayUpdates() (*PublishResult, error) {
 	// Get today's episodes
 	episodes, err := s.episodeRepo.GetTodayUpdates()
@@ -71,82 +149,44 @@ func (s *PublisherService) PublishTodayUpdates() (*PublishResult, error) {
 		}, err
 	}
 
-	if len(episodes) == 0 {
-		return &PublishResult{
-			Success: false,
-			Error:   fmt.Errorf("no episodes found for today"),
-		}, fmt.Errorf("no episodes to publish")
-	}
-
-	// Generate title using configured timezone
-	today := s.timezoneHelper.NowInLocation().Format("2006-01-02")
-	title := fmt.Sprintf("今日更新 - %s", today)
-
-	// Generate content
-	content := s.telegraph.GenerateUpdateListContent(episodes)
-
-	// Generate content hash for deduplication
-	contentHash := generateContentHash(content)
-
-	// Check if same content already exists
-	if s.telegraphPostRepo != nil {
-		existingPost, err := s.telegraphPostRepo.GetByContentHash(contentHash)
-		if err == nil && existingPost != nil {
-			// Return existing post
+	var releases []*models.MovieReleaseDate
+	if s.movieRepo != nil {
+		if releases, err = s.movieRepo.GetTodayReleases(s.releaseFilter); err != nil {
 			return &PublishResult{
-				Success:       true,
-				URL:           existingPost.TelegraphURL,
-				Path:          existingPost.TelegraphPath,
-				Title:         existingPost.Title,
-				ShowsCount:    existingPost.ShowsCount,
-				EpisodesCount: existingPost.EpisodesCount,
-			}, nil
+				Success: false,
+				Error:   fmt.Errorf("failed to get today's movie releases: %w", err),
+			}, err
 		}
 	}
 
-	// Generate tags
-	tags := []string{"剧集", "更新", "TV Shows", today}
-
-	// Create page
-	page, err := s.telegraph.CreatePage(title, content, tags)
-	if err != nil {
+	if len(episodes) == 0 && len(releases) == 0 {
 		return &PublishResult{
 			Success: false,
-			Error:   fmt.Errorf("failed to create page: %w", err),
-		}, err
-	}
-
-	// Count unique shows
-	showMap := make(map[uint]bool)
-	for _, ep := range episodes {
-		showMap[ep.ShowID] = true
+			Error:   fmt.Errorf("no episodes or movies found for today"),
+		}, fmt.Errorf("no episodes to publish")
 	}
 
-	// Save to database if repository is available
-	if s.telegraphPostRepo != nil {
-		post := &models.TelegraphPost{
-			TelegraphPath: page.Path,
-			TelegraphURL:  page.URL,
-			Title:         title,
-			ContentHash:   contentHash,
-			ShowsCount:    len(showMap),
-			EpisodesCount: len(episodes),
-		}
-		_ = s.telegraphPostRepo.Create(post)
+	// Generate title using configured timezone
+	today := s.timezoneHelper.NowInLocation().Format("2006-01-02")
+	todayDate := s.timezoneHelper.TodayInLocation()
+	content, err := s.updateListContent(TodayTemplate, fmt.Sprintf("今日更新 - %s", today), "today-"+today,
+		todayDate, todayDate, episodes, releases, []string{"剧集", "更新", "TV Shows", today})
+	if err != nil {
+		return &PublishResult{Success: false, Error: err}, err
 	}
+	content.DateRange = today
 
-	return &PublishResult{
-		Success:       true,
-		URL:           page.URL,
-		Path:          page.Path,
-		Title:         title,
-		ShowsCount:    len(showMap),
-		EpisodesCount: len(episodes),
-	}, nil
+	return s.publish(content)
 }
 
 // PublishDateRange publishes episodes for a date range
 func (s *PublisherService) PublishDateRange(startDate, endDate time.Time) (*PublishResult, error) {
+	slot := fmt.Sprintf("range-%s-%s", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	return s.publishDateRange(RangeTemplate, startDate, endDate, slot)
+}
+
+// publishDateRange publishes episodes for a date range to the given slot, rendered with the named template
+func (s *PublisherService) publishDateRange(name string, startDate, endDate time.Time, slot string) (*PublishResult, error) {
 	// Get episodes in date range
 	episodes, err := s.episodeRepo.GetByDateRange(startDate, endDate)
 	if err != nil {
@@ -156,79 +196,33 @@ func (s *PublisherService) PublishDateRange(startDate, endDate time.Time) (*Publ
 		}, err
 	}
 
-	if len(episodes) == 0 {
-		return &PublishResult{
-			Success: false,
-			Error:   fmt.Errorf("no episodes found in date range"),
-		}, fmt.Errorf("no episodes to publish")
-	}
-
-	// Generate title
-	title := fmt.Sprintf("更新清单 - %s 至 %s",
-		startDate.Format("2006-01-02"),
-		endDate.Format("2006-01-02"))
-
-	// Generate content
-	content := s.telegraph.GenerateUpdateListContent(episodes)
-
-	// Generate content hash for deduplication
-	contentHash := generateContentHash(content)
-
-	// Check if same content already exists
-	if s.telegraphPostRepo != nil {
-		existingPost, err := s.telegraphPostRepo.GetByContentHash(contentHash)
-		if err == nil && existingPost != nil {
-			// Return existing post
+	var releases []*models.MovieReleaseDate
+	if s.movieRepo != nil {
+		if releases, err = s.movieRepo.GetReleasesByDateRange(startDate, endDate, s.releaseFilter); err != nil {
 			return &PublishResult{
-				Success:       true,
-				URL:           existingPost.TelegraphURL,
-				Path:          existingPost.TelegraphPath,
-				Title:         existingPost.Title,
-				ShowsCount:    existingPost.ShowsCount,
-				EpisodesCount: existingPost.EpisodesCount,
-			}, nil
+				Success: false,
+				Error:   fmt.Errorf("failed to get movie releases: %w", err),
+			}, err
 		}
 	}
 
-	// Generate tags
-	tags := []string{"剧集", "更新", "TV Shows"}
-
-	// Create page
-	page, err := s.telegraph.CreatePage(title, content, tags)
-	if err != nil {
+	if len(episodes) == 0 && len(releases) == 0 {
 		return &PublishResult{
 			Success: false,
-			Error:   fmt.Errorf("failed to create page: %w", err),
-		}, err
-	}
-
-	// Count unique shows
-	showMap := make(map[uint]bool)
-	for _, ep := range episodes {
-		showMap[ep.ShowID] = true
+			Error:   fmt.Errorf("no episodes or movies found in date range"),
+		}, fmt.Errorf("no episodes to publish")
 	}
 
-	// Save to database if repository is available
-	if s.telegraphPostRepo != nil {
-		post := &models.TelegraphPost{
-			TelegraphPath: page.Path,
-			TelegraphURL:  page.URL,
-			Title:         title,
-			ContentHash:   contentHash,
-			ShowsCount:    len(showMap),
-			EpisodesCount: len(episodes),
-		}
-		_ = s.telegraphPostRepo.Create(post)
+	start := startDate.Format("2006-01-02")
+	end := endDate.Format("2006-01-02")
+	content, err := s.updateListContent(name, fmt.Sprintf("更新清单 - %s 至 %s", start, end), slot,
+		startDate, endDate, episodes, releases, []string{"剧集", "更新", "TV Shows"})
+	if err != nil {
+		return &PublishResult{Success: false, Error: err}, err
 	}
+	content.DateRange = fmt.Sprintf("%s to %s", start, end)
 
-	return &PublishResult{
-		Success:       true,
-		URL:           page.URL,
-		Path:          page.Path,
-		Title:         title,
-		ShowsCount:    len(showMap),
-		EpisodesCount: len(episodes),
-	}, nil
+	return s.publish(content)
 }
 
 // PublishShow publishes a single show with all its episodes
@@ -258,83 +252,188 @@ func (s *PublisherService) PublishShow(showID uint) (*PublishResult, error) {
 		}, fmt.Errorf("no episodes to publish")
 	}
 
-	// Generate title
-	title := fmt.Sprintf("%s - 剧集列表", show.Name)
-
-	// Generate content
-	content := s.telegraph.GenerateShowContent(show, episodes)
-
-	// Generate content hash for deduplication
-	contentHash := generateContentHash(content)
-
-	// Check if same content already exists
-	if s.telegraphPostRepo != nil {
-		existingPost, err := s.telegraphPostRepo.GetByContentHash(contentHash)
-		if err == nil && existingPost != nil {
-			// Return existing post
-			return &PublishResult{
-				Success:       true,
-				URL:           existingPost.TelegraphURL,
-				Path:          existingPost.TelegraphPath,
-				Title:         existingPost.Title,
-				ShowsCount:    existingPost.ShowsCount,
-				EpisodesCount: existingPost.EpisodesCount,
-			}, nil
-		}
+	if s.lang != "" {
+		show = show.Localized(s.lang)
+		episodes = LocalizeEpisodes(episodes, s.lang)
 	}
 
-	// Generate tags
 	tags := []string{"剧集", show.Name, "TV Shows"}
 	if show.Status != "" {
 		tags = append(tags, show.Status)
 	}
 
-	// Create page
-	page, err := s.telegraph.CreatePage(title, content, tags)
-	if err != nil {
-		return &PublishResult{
-			Success: false,
-			Error:   fmt.Errorf("failed to create page: %w", err),
-		}, err
+	var nodes []Node
+	if s.templates != nil {
+		markdown, err := s.templates.Render(ShowTemplate, newShowDetailData(s.timezoneHelper, show, episodes))
+		if err != nil {
+			return &PublishResult{Success: false, Error: err}, err
+		}
+		nodes = MarkdownToNodes(markdown)
+	} else {
+		nodes = s.telegraph.GenerateShowContent(show, episodes)
 	}
+	content := &PublishContent{
+		Title:         fmt.Sprintf("%s - 剧集列表", show.Name),
+		Slug:          fmt.Sprintf("show-%d", show.ID),
+		Content:       nodes,
+		Tags:          tags,
+		ContentHash:   generateContentHash(nodes),
+		ShowsCount:    1,
+		EpisodesCount: len(episodes),
+		Episodes:      episodes,
+		PublishedAt:   time.Now(),
+	}
+
+	return s.publish(content)
+}
 
-	// Save to database if repository is available
-	if s.telegraphPostRepo != nil {
-		post := &models.TelegraphPost{
-			TelegraphPath: page.Path,
-			TelegraphURL:  page.URL,
-			Title:         title,
-			ContentHash:   contentHash,
-			ShowsCount:    1,
-			EpisodesCount: len(episodes),
+// updateListContent renders an update list of episodes and movie releases with the named template
+func (s *PublisherService) updateListContent(name, title, slug string, startDate, endDate time.Time, episodes []*models.Episode, releases []*models.MovieReleaseDate, tags []string) (*PublishContent, error) {
+	episodes = LocalizeEpisodes(episodes, s.lang)
+	var nodes []Node
+	if s.templates != nil {
+		markdown, err := s.templates.Render(name, newUpdateListData(s.timezoneHelper, startDate, endDate, episodes, releases))
+		if err != nil {
+			return nil, err
 		}
-		_ = s.telegraphPostRepo.Create(post)
+		nodes = MarkdownToNodes(markdown)
+	} else {
+		nodes = s.telegraph.GenerateUpdateListContent(episodes, releases)
 	}
 
-	return &PublishResult{
-		Success:       true,
-		URL:           page.URL,
-		Path:          page.Path,
+	if len(releases) > 0 {
+		tags = append(tags, "电影")
+	}
+
+	// Count unique shows
+	showMap := make(map[uint]bool)
+	for _, ep := range episodes {
+		showMap[ep.ShowID] = true
+	}
+
+	return &PublishContent{
 		Title:         title,
-		ShowsCount:    1,
+		Slug:          slug,
+		Content:       nodes,
+		Tags:          tags,
+		ContentHash:   generateContentHash(nodes),
+		ShowsCount:    len(showMap),
 		EpisodesCount: len(episodes),
+		MoviesCount:   len(groupReleasesByMovie(releases)),
+		Episodes:      episodes,
+		Releases:      releases,
+		PublishedAt:   time.Now(),
 	}, nil
 }
 
+// publish hands content to every target and records each result.
+// The publish succeeds when at least one target succeeds; the failures of
+// the others are reported in Error and Targets.
+func (s *PublisherService) publish(content *PublishContent) (*PublishResult, error) {
+	result := &PublishResult{
+		Title:         content.Title,
+		ShowsCount:    content.ShowsCount,
+		EpisodesCount: content.EpisodesCount,
+		MoviesCount:   content.MoviesCount,
+	}
+	if len(s.targets) == 0 {
+		result.Error = fmt.Errorf("no publish targets configured")
+		return result, result.Error
+	}
+
+	ctx := context.Background()
+	var errs []error
+	for _, target := range s.targets {
+		targetResult := s.publishTo(ctx, target, content)
+		result.Targets = append(result.Targets, targetResult)
+		if !targetResult.Success {
+			errs = append(errs, fmt.Errorf("%s: %s", targetResult.Target, targetResult.Error))
+			continue
+		}
+		if !result.Success {
+			result.Success = true
+			result.URL = targetResult.URL
+			result.Path = targetResult.Path
+		}
+	}
+
+	if len(errs) > 0 {
+		result.Error = errors.Join(errs...)
+	}
+	if !result.Success {
+		return result, fmt.Errorf("failed to publish: %w", result.Error)
+	}
+	if s.events != nil && !result.IsDuplicate() {
+		s.events.Emit(models.WebhookEventPublishCompleted, PublishEvent{
+			Title:         content.Title,
+			Slug:          content.Slug,
+			URL:           result.URL,
+			ShowsCount:    content.ShowsCount,
+			EpisodesCount: content.EpisodesCount,
+			MoviesCount:   content.MoviesCount,
+			Targets:       result.Targets,
+		})
+	}
+	return result, nil
+}
+
+// publishTo publishes content to one target, unless the latest publish of
+// the slot to the target already had the same content
+func (s *PublisherService) publishTo(ctx context.Context, target PublishTarget, content *PublishContent) *TargetResult {
+	name := target.Name()
+	if s.recordRepo != nil {
+		record, err := s.recordRepo.WithContext(ctx).GetLatestSuccessful(name, content.Slug)
+		if err == nil && record.ContentHash == content.ContentHash {
+			return &TargetResult{Target: name, Success: true, URL: record.URL, Path: record.Path, Duplicate: true}
+		}
+	}
+
+	targetResult, err := target.Publish(ctx, content)
+	if targetResult == nil {
+		targetResult = &TargetResult{}
+	}
+	targetResult.Target = name
+	targetResult.Success = err == nil
+	if err != nil {
+		targetResult.Error = err.Error()
+	}
+
+	if s.recordRepo != nil && !targetResult.Duplicate {
+		record := &models.PublishRecord{
+			Target:        name,
+			Title:         content.Title,
+			Slug:          content.Slug,
+			ContentHash:   content.ContentHash,
+			Success:       targetResult.Success,
+			URL:           targetResult.URL,
+			Path:          targetResult.Path,
+			Error:         targetResult.Error,
+			ShowsCount:    content.ShowsCount,
+			EpisodesCount: content.EpisodesCount,
+			MoviesCount:   content.MoviesCount,
+		}
+		_ = s.recordRepo.WithContext(ctx).Create(record)
+	}
+	return targetResult
+}
+
 // PublishWeeklyUpdates publishes the last 7 days of updates
-// Uses the configured timezone for date calculations
+// Uses the configured timezone for date calculations; every run in the same
+// ISO week updates the same slot
 func (s *PublisherService) PublishWeeklyUpdates() (*PublishResult, error) {
 	today := s.timezoneHelper.TodayInLocation()
 	startDate := today.AddDate(0, 0, -7)
+	year, week := today.ISOWeek()
 
-	return s.PublishDateRange(startDate, today)
+	return s.publishDateRange(WeeklyTemplate, startDate, today, fmt.Sprintf("week-%d-W%02d", year, week))
 }
 
 // PublishMonthlyUpdates publishes the last 30 days of updates
-// Uses the configured timezone for date calculations
+// Uses the configured timezone for date calculations; every run in the same
+// month updates the same slot
 func (s *PublisherService) PublishMonthlyUpdates() (*PublishResult, error) {
 	today := s.timezoneHelper.TodayInLocation()
 	startDate := today.AddDate(0, 0, -30)
 
-	return s.PublishDateRange(startDate, today)
+	return s.publishDateRange(RangeTemplate, startDate, today, "month-"+today.Format("2006-01"))
 }
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_AllowsBurst(t *testing.T) {
+	limiter := NewRateLimiter(10, 5)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		limiter.Wait(context.Background())
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Burst of 5 should not block, took %v", elapsed)
+	}
+}
+
+func TestRateLimiter_LimitsRate(t *testing.T) {
+	limiter := NewRateLimiter(20, 1)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			limiter.Wait(context.Background())
+		}()
+	}
+	wg.Wait()
+
+	// 1 token up front, 4 more at 20/s => ~200ms
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("Expected concurrent waits to be rate limited, took only %v", elapsed)
+	}
+}
+
+func TestRateLimiter_Disabled(t *testing.T) {
+	limiter := NewRateLimiter(0, 0)
+
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		limiter.Wait(context.Background())
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Disabled limiter should not block, took %v", elapsed)
+	}
+
+	var nilLimiter *RateLimiter
+	nilLimiter.Wait(context.Background())
+}
+
+func TestRateLimiter_WaitCancelled(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+	limiter.Wait(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := limiter.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Cancelled wait should return early, took %v", elapsed)
+	}
+}
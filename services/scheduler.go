@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
@@ -16,11 +17,21 @@ type Scheduler struct {
 	crawler         *CrawlerService
 	publisher       *PublisherService
 	correction      *correction.Service
+	discovery       *DiscoveryService
+	discoveryCron   string
+	listSync        *ListSyncService
+	listSyncCron    string
+	movies          *MovieService
+	movieRefreshCron string
+	telegramBot     *TelegramBot
 	logger          *utils.Logger
 	mu              sync.RWMutex
 	running         bool
 	lastCrawlTime   time.Time
 	lastPublishTime time.Time
+	lastDiscoveryTime time.Time
+	lastListSyncTime  time.Time
+	lastMovieRefreshTime time.Time
 
 	// Concurrency control
 	crawlJobRunning     bool
@@ -29,6 +40,9 @@ type Scheduler struct {
 	crawlJobMutex       sync.Mutex
 	publishJobMutex     sync.Mutex
 	correctionJobMutex  sync.Mutex
+	discoveryJobMutex   sync.Mutex
+	listSyncJobMutex    sync.Mutex
+	movieRefreshJobMutex sync.Mutex
 
 	// Timeout settings
 	crawlTimeout   time.Duration
@@ -86,6 +100,24 @@ func (s *Scheduler) Start() error {
 		return fmt.Errorf("failed to add daily correction job: %w", err)
 	}
 
+	if s.discovery != nil {
+		if _, err := s.cron.AddFunc(s.discoveryCron, s.discoveryJob); err != nil {
+			return fmt.Errorf("failed to add discovery job: %w", err)
+		}
+	}
+
+	if s.listSync != nil {
+		if _, err := s.cron.AddFunc(s.listSyncCron, s.listSyncJob); err != nil {
+			return fmt.Errorf("failed to add list sync job: %w", err)
+		}
+	}
+
+	if s.movies != nil {
+		if _, err := s.cron.AddFunc(s.movieRefreshCron, s.movieRefreshJob); err != nil {
+			return fmt.Errorf("failed to add movie refresh job: %w", err)
+		}
+	}
+
 	s.cron.Start()
 	s.running = true
 
@@ -124,19 +156,20 @@ func (s *Scheduler) dailyCrawlJob() {
 	}
 	defer s.crawlJobMutex.Unlock()
 
-	s.logger.Info("Starting daily crawl job...")
-	startTime := time.Now()
-
-	// Refresh all returning shows
-	if err := s.crawler.RefreshAll(); err != nil {
-		s.logger.Errorf("Daily crawl failed: %v", err)
-	} else {
+	// Incremental refresh: only changed seasons are re-fetched,
+	// shows without a change cursor fall back to a full crawl
+	_ = s.runJobWithTimeout("Daily crawl", s.getCrawlTimeout(), func(ctx context.Context) error {
+		startTime := time.Now()
+		summary, err := s.crawler.RefreshIncremental(ctx)
+		if err != nil {
+			return err
+		}
 		s.mu.Lock()
 		s.lastCrawlTime = time.Now()
 		s.mu.Unlock()
-		duration := time.Since(startTime)
-		s.logger.Infof("Daily crawl completed in %v", duration)
-	}
+		s.logCrawlSummary("Daily crawl", summary, time.Since(startTime))
+		return nil
+	})
 }
 
 // dailyPublishJob performs daily publish task
@@ -153,6 +186,9 @@ func (s *Scheduler) dailyPublishJob() {
 
 	// Publish today's updates
 	result, err := s.publisher.PublishTodayUpdates()
+	if result != nil {
+		s.logPublishTargets("Daily publish", result)
+	}
 	if err != nil {
 		s.logger.Errorf("Daily publish failed: %v", err)
 	} else if result.Success {
@@ -160,11 +196,13 @@ func (s *Scheduler) dailyPublishJob() {
 		s.lastPublishTime = time.Now()
 		s.mu.Unlock()
 		duration := time.Since(startTime)
-		s.logger.Infof("Daily publish completed: %s (%d shows, %d episodes) in %v",
+		s.logger.Infof("Daily publish completed: %s (%d shows, %d episodes, %d movies) in %v",
 			result.URL,
 			result.ShowsCount,
 			result.EpisodesCount,
+			result.MoviesCount,
 			duration)
+		s.notifyPublish("Daily publish", result)
 	} else {
 		s.logger.Warnf("Daily publish skipped: %v", result.Error)
 	}
@@ -179,18 +217,51 @@ func (s *Scheduler) weeklyCrawlJob() {
 	}
 	defer s.crawlJobMutex.Unlock()
 
-	s.logger.Info("Starting weekly crawl job...")
-	startTime := time.Now()
-
 	// Refresh all shows
-	if err := s.crawler.RefreshAll(); err != nil {
-		s.logger.Errorf("Weekly crawl failed: %v", err)
-	} else {
+	_ = s.runJobWithTimeout("Weekly crawl", s.getCrawlTimeout(), func(ctx context.Context) error {
+		startTime := time.Now()
+		summary, err := s.crawler.RefreshAll(ctx)
+		if err != nil {
+			return err
+		}
 		s.mu.Lock()
 		s.lastCrawlTime = time.Now()
 		s.mu.Unlock()
-		duration := time.Since(startTime)
-		s.logger.Infof("Weekly crawl completed in %v", duration)
+		s.logCrawlSummary("Weekly crawl", summary, time.Since(startTime))
+		return nil
+	})
+}
+
+// getCrawlTimeout returns the time limit for scheduled crawl jobs
+func (s *Scheduler) getCrawlTimeout() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.crawlTimeout
+}
+
+// logCrawlSummary logs the outcome of a batch crawl job
+func (s *Scheduler) logCrawlSummary(job string, summary *CrawlSummary, duration time.Duration) {
+	s.logger.Infof("%s completed in %v: %d succeeded, %d failed, %d skipped (%d episodes added, %d updated)",
+		job, duration, summary.Succeeded, summary.Failed, summary.Skipped,
+		summary.EpisodesAdded, summary.EpisodesUpdated)
+	for _, show := range summary.Shows {
+		if show.Status == showCrawlFailed {
+			s.logger.Warnf("%s: show %d (%s) failed: %s", job, show.TmdbID, show.Name, show.Error)
+		}
+	}
+}
+
+// logPublishTargets logs the outcome of each publish target
+func (s *Scheduler) logPublishTargets(job string, result *PublishResult) {
+	for _, target := range result.Targets {
+		switch {
+		case !target.Success:
+			s.logger.Warnf("%s to %s failed: %s", job, target.Target, target.Error)
+		case target.Duplicate:
+			s.logger.Infof("%s to %s skipped, content already published: %s", job, target.Target, target.URL)
+		default:
+			s.logger.Infof("%s to %s completed: %s", job, target.Target, target.URL)
+		}
 	}
 }
 
@@ -208,6 +279,9 @@ func (s *Scheduler) weeklyPublishJob() {
 
 	// Publish weekly updates
 	result, err := s.publisher.PublishWeeklyUpdates()
+	if result != nil {
+		s.logPublishTargets("Weekly publish", result)
+	}
 	if err != nil {
 		s.logger.Errorf("Weekly publish failed: %v", err)
 	} else if result.Success {
@@ -215,22 +289,25 @@ func (s *Scheduler) weeklyPublishJob() {
 		s.lastPublishTime = time.Now()
 		s.mu.Unlock()
 		duration := time.Since(startTime)
-		s.logger.Infof("Weekly publish completed: %s (%d shows, %d episodes) in %v",
+		s.logger.Infof("Weekly publish completed: %s (%d shows, %d episodes, %d movies) in %v",
 			result.URL,
 			result.ShowsCount,
 			result.EpisodesCount,
+			result.MoviesCount,
 			duration)
+		s.notifyPublish("Weekly publish", result)
 	} else {
 		s.logger.Warnf("Weekly publish skipped: %v", result.Error)
 	}
 }
 
-// RunCrawlNow triggers an immediate crawl job
-func (s *Scheduler) RunCrawlNow() error {
+// RunCrawlNow triggers an immediate crawl job, stopping early if ctx is cancelled
+func (s *Scheduler) RunCrawlNow(ctx context.Context) error {
 	s.logger.Info("Triggering immediate crawl...")
 	startTime := time.Now()
 
-	if err := s.crawler.RefreshAll(); err != nil {
+	summary, err := s.crawler.RefreshAll(ctx)
+	if err != nil {
 		return fmt.Errorf("crawl failed: %w", err)
 	}
 
@@ -239,8 +316,8 @@ func (s *Scheduler) RunCrawlNow() error {
 	s.mu.Unlock()
 
 	duration := time.Since(startTime)
-	s.logger.Infof("Immediate crawl completed in %v", duration)
-	return nil
+	s.logCrawlSummary("Immediate crawl", summary, duration)
+	return summary.Err()
 }
 
 // RunPublishNow triggers an immediate publish job
@@ -249,6 +326,9 @@ func (s *Scheduler) RunPublishNow() (*PublishResult, error) {
 	startTime := time.Now()
 
 	result, err := s.publisher.PublishTodayUpdates()
+	if result != nil {
+		s.logPublishTargets("Immediate publish", result)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("publish failed: %w", err)
 	}
@@ -259,10 +339,11 @@ func (s *Scheduler) RunPublishNow() (*PublishResult, error) {
 		s.mu.Unlock()
 
 		duration := time.Since(startTime)
-		s.logger.Infof("Immediate publish completed: %s (%d shows, %d episodes) in %v",
+		s.logger.Infof("Immediate publish completed: %s (%d shows, %d episodes, %d movies) in %v",
 			result.URL,
 			result.ShowsCount,
 			result.EpisodesCount,
+			result.MoviesCount,
 			duration)
 	}
 
@@ -291,6 +372,124 @@ func (s *Scheduler) dailyCorrectionJob() {
 	}
 }
 
+// SetDiscovery enables the discovery job with a 6-field cron spec.
+// It must be called before Start.
+func (s *Scheduler) SetDiscovery(discovery *DiscoveryService, spec string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.discovery = discovery
+	s.discoveryCron = spec
+}
+
+// discoveryJob pulls the TMDB lists and queues or follows new shows
+func (s *Scheduler) discoveryJob() {
+	if !s.discoveryJobMutex.TryLock() {
+		s.logger.Warn("Discovery job already running, skipping")
+		return
+	}
+	defer s.discoveryJobMutex.Unlock()
+
+	_ = s.runJobWithTimeout("Discovery", s.getCrawlTimeout(), func(ctx context.Context) error {
+		result, err := s.discovery.Run(ctx)
+		if err != nil {
+			return err
+		}
+		s.mu.Lock()
+		s.lastDiscoveryTime = time.Now()
+		s.mu.Unlock()
+		s.logger.Infof("Discovery: %d shows fetched, %d matched, %d queued, %d followed",
+			result.Fetched, result.Matched, result.Queued, result.Followed)
+		for _, msg := range result.Errors {
+			s.logger.Warnf("Discovery: %s", msg)
+		}
+		return nil
+	})
+}
+
+// SetTelegramBot posts a summary of each scheduled publish to the bot's chats
+func (s *Scheduler) SetTelegramBot(bot *TelegramBot) {
+	s.telegramBot = bot
+}
+
+// notifyPublish posts the summary of a publish through the Telegram bot
+func (s *Scheduler) notifyPublish(job string, result *PublishResult) {
+	if s.telegramBot == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := s.telegramBot.NotifyPublish(ctx, result); err != nil {
+		s.logger.Warnf("%s Telegram notification failed: %v", job, err)
+	}
+}
+
+// SetListSync enables the TMDB list sync job with a 6-field cron spec.
+// It must be called before Start.
+func (s *Scheduler) SetListSync(listSync *ListSyncService, spec string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listSync = listSync
+	s.listSyncCron = spec
+}
+
+// listSyncJob crawls the shows of every enabled TMDB list
+func (s *Scheduler) listSyncJob() {
+	if !s.listSyncJobMutex.TryLock() {
+		s.logger.Warn("List sync job already running, skipping")
+		return
+	}
+	defer s.listSyncJobMutex.Unlock()
+
+	_ = s.runJobWithTimeout("List sync", s.getCrawlTimeout(), func(ctx context.Context) error {
+		results, err := s.listSync.SyncAll(ctx)
+		if err != nil {
+			return err
+		}
+		s.mu.Lock()
+		s.lastListSyncTime = time.Now()
+		s.mu.Unlock()
+		for _, result := range results {
+			s.logger.Infof("List sync %d (%s): %s, %d shows, %d crawled, %d failed, %d archived, %d restored",
+				result.ListID, result.Name, result.Status, result.Shows, result.Crawled, result.Failed, result.Archived, result.Restored)
+		}
+		return nil
+	})
+}
+
+// SetMovieRefresh enables the movie refresh job with a 6-field cron spec.
+// It must be called before Start.
+func (s *Scheduler) SetMovieRefresh(movies *MovieService, spec string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.movies = movies
+	s.movieRefreshCron = spec
+}
+
+// movieRefreshJob refetches the details and release dates of every movie
+func (s *Scheduler) movieRefreshJob() {
+	if !s.movieRefreshJobMutex.TryLock() {
+		s.logger.Warn("Movie refresh job already running, skipping")
+		return
+	}
+	defer s.movieRefreshJobMutex.Unlock()
+
+	_ = s.runJobWithTimeout("Movie refresh", s.getCrawlTimeout(), func(ctx context.Context) error {
+		result, err := s.movies.RefreshAll(ctx)
+		if err != nil {
+			return err
+		}
+		s.mu.Lock()
+		s.lastMovieRefreshTime = time.Now()
+		s.mu.Unlock()
+		s.logger.Infof("Movie refresh: %d movies, %d refreshed, %d failed",
+			result.Total, result.Refreshed, result.Failed)
+		for _, msg := range result.Errors {
+			s.logger.Warnf("Movie refresh: %s", msg)
+		}
+		return nil
+	})
+}
+
 // GetStatus returns the scheduler status
 func (s *Scheduler) GetStatus() map[string]interface{} {
 	s.mu.RLock()
@@ -313,6 +512,23 @@ func (s *Scheduler) GetStatus() map[string]interface{} {
 		status["time_since_last_publish"] = time.Since(s.lastPublishTime).String()
 	}
 
+	status["discovery_enabled"] = s.discovery != nil
+	if !s.lastDiscoveryTime.IsZero() {
+		status["last_discovery_time"] = s.lastDiscoveryTime
+	}
+
+	status["publish_targets"] = s.publisher.TargetNames()
+
+	status["list_sync_enabled"] = s.listSync != nil
+	if !s.lastListSyncTime.IsZero() {
+		status["last_list_sync_time"] = s.lastListSyncTime
+	}
+
+	status["movie_refresh_enabled"] = s.movies != nil
+	if !s.lastMovieRefreshTime.IsZero() {
+		status["last_movie_refresh_time"] = s.lastMovieRefreshTime
+	}
+
 	return status
 }
 
@@ -358,10 +574,10 @@ func (s *Scheduler) GetTimeouts() map[string]string {
 }
 
 // RunManualCrawl runs a manual crawl task
-func (s *Scheduler) RunManualCrawl(showID int) error {
+func (s *Scheduler) RunManualCrawl(ctx context.Context, showID int) error {
 	s.logger.Infof("Running manual crawl for show %d", showID)
 
-	if err := s.crawler.CrawlShow(showID); err != nil {
+	if err := s.crawler.CrawlShow(ctx, showID); err != nil {
 		return fmt.Errorf("manual crawl failed: %w", err)
 	}
 
@@ -419,16 +635,20 @@ func GetDefaultCronSpecs() map[string]string {
 }
 
 // Helper function to run job with timeout
-func (s *Scheduler) runJobWithTimeout(jobName string, timeout time.Duration, job func() error) error {
+// The job's context is cancelled when the timeout is reached so it can stop its work
+func (s *Scheduler) runJobWithTimeout(jobName string, timeout time.Duration, job func(ctx context.Context) error) error {
 	s.logger.Infof("Starting %s (timeout: %v)", jobName, timeout)
 	startTime := time.Now()
 
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
 	// Create a channel to receive job result
 	done := make(chan error, 1)
 
 	// Run job in goroutine
 	go func() {
-		done <- job()
+		done <- job(ctx)
 	}()
 
 	// Wait for job completion or timeout
@@ -441,7 +661,7 @@ func (s *Scheduler) runJobWithTimeout(jobName string, timeout time.Duration, job
 		}
 		s.logger.Infof("%s completed in %v", jobName, duration)
 		return nil
-	case <-time.After(timeout):
+	case <-ctx.Done():
 		duration := time.Since(startTime)
 		s.logger.Errorf("%s timed out after %v (limit: %v)", jobName, duration, timeout)
 		return fmt.Errorf("%s timed out after %v", jobName, timeout)
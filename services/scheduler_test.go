@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -309,7 +310,7 @@ func TestScheduler_ConcurrencyControl(t *testing.T) {
 	crawler := &CrawlerService{}
 	publisher := &PublisherService{}
 
-	scheduler := NewScheduler(crawler, publisher, logger)
+	scheduler := NewScheduler(crawler, publisher, nil, logger)
 
 	// Test that mutex prevents concurrent execution
 	t.Run("CrawlJobMutex", func(t *testing.T) {
@@ -352,7 +353,7 @@ func TestScheduler_TimeoutSettings(t *testing.T) {
 	crawler := &CrawlerService{}
 	publisher := &PublisherService{}
 
-	scheduler := NewScheduler(crawler, publisher, logger)
+	scheduler := NewScheduler(crawler, publisher, nil, logger)
 
 	// Test default timeouts
 	timeouts := scheduler.GetTimeouts()
@@ -379,10 +380,10 @@ func TestScheduler_RunJobWithTimeout(t *testing.T) {
 	crawler := &CrawlerService{}
 	publisher := &PublisherService{}
 
-	scheduler := NewScheduler(crawler, publisher, logger)
+	scheduler := NewScheduler(crawler, publisher, nil, logger)
 
 	t.Run("JobCompletesWithinTimeout", func(t *testing.T) {
-		job := func() error {
+		job := func(ctx context.Context) error {
 			time.Sleep(100 * time.Millisecond)
 			return nil
 		}
@@ -394,20 +395,32 @@ func TestScheduler_RunJobWithTimeout(t *testing.T) {
 	})
 
 	t.Run("JobTimesOut", func(t *testing.T) {
-		job := func() error {
-			time.Sleep(2 * time.Second)
-			return nil
+		cancelled := make(chan struct{})
+		job := func(ctx context.Context) error {
+			select {
+			case <-time.After(2 * time.Second):
+				return nil
+			case <-ctx.Done():
+				close(cancelled)
+				return ctx.Err()
+			}
 		}
 
 		err := scheduler.runJobWithTimeout("test_job", 100*time.Millisecond, job)
 		if err == nil {
 			t.Error("Job should timeout")
 		}
+
+		select {
+		case <-cancelled:
+		case <-time.After(time.Second):
+			t.Error("Job context should be cancelled on timeout")
+		}
 	})
 
 	t.Run("JobReturnsError", func(t *testing.T) {
 		expectedErr := fmt.Errorf("job failed")
-		job := func() error {
+		job := func(ctx context.Context) error {
 			return expectedErr
 		}
 
@@ -423,7 +436,7 @@ func TestScheduler_StatusIncludesConcurrencyInfo(t *testing.T) {
 	crawler := &CrawlerService{}
 	publisher := &PublisherService{}
 
-	scheduler := NewScheduler(crawler, publisher, logger)
+	scheduler := NewScheduler(crawler, publisher, nil, logger)
 
 	status := scheduler.GetStatus()
 
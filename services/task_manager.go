@@ -1,8 +1,11 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/xc9973/go-tmdb-crawler/models"
@@ -10,16 +13,55 @@ import (
 )
 
 const (
-	taskStatusQueued  = "queued"
-	taskStatusRunning = "running"
-	taskStatusSuccess = "success"
-	taskStatusFailed  = "failed"
+	taskStatusQueued      = "queued"
+	taskStatusRunning     = "running"
+	taskStatusSuccess     = "success"
+	taskStatusFailed      = "failed"
+	taskStatusCancelled   = "cancelled"
+	taskStatusInterrupted = "interrupted"
 )
 
+// Types of the tasks run by TaskManager; other task types, such as correction tasks,
+// belong to other services and are left alone by RecoverInterrupted
+const (
+	taskTypeRefreshAll    = "refresh_all"
+	taskTypeCrawlByStatus = "crawl_by_status"
+)
+
+// ErrTaskNotRunning is returned when cancelling a task that is not running in this process.
+var ErrTaskNotRunning = errors.New("task is not running")
+
+// taskParams are the inputs of a task, stored as JSON in CrawlTask.Params.
+// RemainingTmdbIDs is the checkpoint of a batch task: the shows not crawled yet.
+type taskParams struct {
+	Status           string `json:"status,omitempty"`
+	RemainingTmdbIDs []int  `json:"remaining_tmdb_ids,omitempty"`
+}
+
+// parseTaskParams decodes the params of a task; empty params decode to zero values
+func parseTaskParams(raw string) (taskParams, error) {
+	var params taskParams
+	if raw == "" {
+		return params, nil
+	}
+	err := json.Unmarshal([]byte(raw), &params)
+	return params, err
+}
+
 // TaskManager manages async crawl tasks and persists status.
 type TaskManager struct {
 	tasks   repositories.CrawlTaskRepository
 	crawler *CrawlerService
+
+	// running holds the tasks started by this manager that have not finished yet
+	running map[uint]*runningTask
+	mu      sync.Mutex
+}
+
+// runningTask is a task executing in this process
+type runningTask struct {
+	cancel   context.CancelFunc
+	progress *taskProgress
 }
 
 // NewTaskManager creates a task manager instance.
@@ -27,30 +69,110 @@ func NewTaskManager(tasks repositories.CrawlTaskRepository, crawler *CrawlerServ
 	return &TaskManager{
 		tasks:   tasks,
 		crawler: crawler,
+		running: make(map[uint]*runningTask),
 	}
 }
 
 // StartRefreshAll starts a refresh-all task in background.
 func (m *TaskManager) StartRefreshAll() (*models.CrawlTask, error) {
-	return m.startTask("refresh_all", nil, func() error {
-		return m.crawler.RefreshAll()
+	return m.startTask(taskTypeRefreshAll, nil, func(ctx context.Context) (*CrawlSummary, error) {
+		return m.crawler.RefreshAll(ctx)
 	})
 }
 
 // StartCrawlByStatus starts a status crawl task in background.
 func (m *TaskManager) StartCrawlByStatus(status string) (*models.CrawlTask, error) {
-	params := map[string]string{"status": status}
-	return m.startTask("crawl_by_status", params, func() error {
-		return m.crawler.CrawlByStatus(status)
+	params := &taskParams{Status: status}
+	return m.startTask(taskTypeCrawlByStatus, params, func(ctx context.Context) (*CrawlSummary, error) {
+		return m.crawler.CrawlByStatus(ctx, status)
 	})
 }
 
 // GetTask returns task status by id.
+// Tasks running in this process are returned with their live progress.
 func (m *TaskManager) GetTask(id uint) (*models.CrawlTask, error) {
+	m.mu.Lock()
+	running, ok := m.running[id]
+	m.mu.Unlock()
+	if ok {
+		return running.progress.snapshot(), nil
+	}
 	return m.tasks.GetByID(id)
 }
 
-func (m *TaskManager) startTask(taskType string, params map[string]string, runner func() error) (*models.CrawlTask, error) {
+// ListRunning returns the tasks currently marked running, with live progress where available.
+func (m *TaskManager) ListRunning() ([]*models.CrawlTask, error) {
+	tasks, err := m.tasks.GetRunning()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, task := range tasks {
+		if running, ok := m.running[task.ID]; ok {
+			tasks[i] = running.progress.snapshot()
+		}
+	}
+	return tasks, nil
+}
+
+// CancelTask stops a queued or running task. Shows already crawled are kept
+// and the task finishes with the cancelled status and a partial summary.
+func (m *TaskManager) CancelTask(id uint) error {
+	m.mu.Lock()
+	running, ok := m.running[id]
+	m.mu.Unlock()
+	if !ok {
+		return ErrTaskNotRunning
+	}
+	running.cancel()
+	return nil
+}
+
+// RecoverInterrupted handles the refresh and status crawl tasks left queued or running by a previous process.
+// With resume set, tasks that have a checkpoint are resumed for their remaining
+// shows; all other orphaned tasks, including every queued one, are marked interrupted.
+func (m *TaskManager) RecoverInterrupted(resume bool) (resumed, interrupted int, err error) {
+	tasks, err := m.tasks.GetUnfinished([]string{taskTypeRefreshAll, taskTypeCrawlByStatus})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list unfinished tasks: %w", err)
+	}
+
+	for _, task := range tasks {
+		m.mu.Lock()
+		_, own := m.running[task.ID]
+		m.mu.Unlock()
+		if own {
+			continue
+		}
+
+		params, parseErr := parseTaskParams(task.Params)
+		if resume && parseErr == nil && len(params.RemainingTmdbIDs) > 0 {
+			remaining := params.RemainingTmdbIDs
+			m.runTask(task, func(ctx context.Context) (*CrawlSummary, error) {
+				return m.crawler.CrawlShowsByTmdbID(ctx, remaining)
+			})
+			resumed++
+			continue
+		}
+
+		finishedAt := time.Now()
+		task.Status = taskStatusInterrupted
+		task.FinishedAt = &finishedAt
+		task.CurrentShow = ""
+		task.EstimatedFinishAt = nil
+		task.ErrorMessage = "task interrupted by server restart"
+		if err := m.tasks.Update(task); err != nil {
+			return resumed, interrupted, fmt.Errorf("failed to mark task %d interrupted: %w", task.ID, err)
+		}
+		interrupted++
+	}
+
+	return resumed, interrupted, nil
+}
+
+func (m *TaskManager) startTask(taskType string, params *taskParams, runner func(ctx context.Context) (*CrawlSummary, error)) (*models.CrawlTask, error) {
 	paramsJSON := ""
 	if params != nil {
 		b, err := json.Marshal(params)
@@ -72,23 +194,187 @@ func (m *TaskManager) startTask(taskType string, params map[string]string, runne
 		return nil, fmt.Errorf("failed to create task: %w", err)
 	}
 
-	go func(t *models.CrawlTask) {
-		startedAt := time.Now()
-		t.Status = taskStatusRunning
-		t.StartedAt = &startedAt
-		_ = m.tasks.Update(t)
+	return m.runTask(task, runner), nil
+}
 
-		err := runner()
-		finishedAt := time.Now()
-		t.FinishedAt = &finishedAt
-		if err != nil {
-			t.Status = taskStatusFailed
-			t.ErrorMessage = err.Error()
-		} else {
-			t.Status = taskStatusSuccess
+// runTask runs a stored task in background and returns a snapshot of it.
+// A resumed task keeps its original start time and processed count, and the
+// shows it finished before the interruption stay in its summary.
+func (m *TaskManager) runTask(task *models.CrawlTask, runner func(ctx context.Context) (*CrawlSummary, error)) *models.CrawlTask {
+	// The summary checkpointed by an interrupted run, empty for a new task
+	previous := NewCrawlSummary()
+	if task.Summary != "" {
+		if err := json.Unmarshal([]byte(task.Summary), previous); err != nil {
+			previous = NewCrawlSummary()
 		}
-		_ = m.tasks.Update(t)
-	}(task)
+	}
+
+	// The goroutine owns the task; callers and readers get snapshots
+	progress := &taskProgress{task: task, tasks: m.tasks, names: make(map[int]string)}
+	progress.summary = NewCrawlSummary()
+	progress.summary.Merge(previous)
+	response := progress.snapshot()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.mu.Lock()
+	m.running[task.ID] = &runningTask{cancel: cancel, progress: progress}
+	m.mu.Unlock()
+
+	go func() {
+		defer func() {
+			m.mu.Lock()
+			delete(m.running, task.ID)
+			m.mu.Unlock()
+			cancel()
+		}()
+
+		progress.update(func(t *models.CrawlTask) {
+			if t.StartedAt == nil {
+				startedAt := time.Now()
+				t.StartedAt = &startedAt
+			}
+			t.Status = taskStatusRunning
+		})
+
+		summary, err := runner(WithCrawlProgress(ctx, progress))
+
+		progress.update(func(t *models.CrawlTask) {
+			finishedAt := time.Now()
+			t.FinishedAt = &finishedAt
+			t.CurrentShow = ""
+			t.EstimatedFinishAt = nil
+			if summary != nil {
+				merged := NewCrawlSummary()
+				merged.Merge(previous)
+				merged.Merge(summary)
+				if b, marshalErr := json.Marshal(merged); marshalErr == nil {
+					t.Summary = string(b)
+				}
+			}
+			if ctx.Err() != nil {
+				// Shows crawled before the cancellation are kept in the summary
+				t.Status = taskStatusCancelled
+				t.ErrorMessage = "task cancelled"
+				return
+			}
+			if err == nil {
+				// Individual show failures mark the task failed; the summary has the details
+				err = summary.Err()
+			}
+			if err != nil {
+				t.Status = taskStatusFailed
+				t.ErrorMessage = err.Error()
+			} else {
+				t.Status = taskStatusSuccess
+			}
+		})
+	}()
+
+	return response
+}
+
+// taskProgress records the progress of a running task and persists every change.
+// It implements CrawlProgress for the batch crawls run by the task and keeps
+// the remaining TMDB IDs in the task params as a checkpoint for resuming.
+type taskProgress struct {
+	mu    sync.Mutex
+	task  *models.CrawlTask
+	tasks repositories.CrawlTaskRepository
 
-	return task, nil
+	// remaining is the checkpoint: shows of the batch not crawled yet
+	remaining []int
+	// summary is checkpointed with remaining: the shows finished so far,
+	// including those of the runs before a resume
+	summary *CrawlSummary
+	// names are the names of the started shows, for the summary
+	names map[int]string
+	// batchStart and batchProcessed measure this run for the ETA
+	batchStart     time.Time
+	batchProcessed int
+}
+
+// update applies fn to the task and persists it
+func (p *taskProgress) update(fn func(t *models.CrawlTask)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fn(p.task)
+	_ = p.tasks.Update(p.task)
+}
+
+// snapshot returns a copy of the task's current state
+func (p *taskProgress) snapshot() *models.CrawlTask {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	task := *p.task
+	return &task
+}
+
+// saveCheckpoint stores the remaining TMDB IDs in the task params and the shows finished so far in its summary
+// Must be called with p.mu held.
+func (p *taskProgress) saveCheckpoint(t *models.CrawlTask) {
+	params, err := parseTaskParams(t.Params)
+	if err != nil {
+		return
+	}
+	params.RemainingTmdbIDs = p.remaining
+	if b, err := json.Marshal(params); err == nil {
+		t.Params = string(b)
+	}
+	if b, err := json.Marshal(p.summary); err == nil {
+		t.Summary = string(b)
+	}
+}
+
+// BatchStarted implements CrawlProgress
+// Shows processed before a resume stay counted in the total.
+func (p *taskProgress) BatchStarted(tmdbIDs []int) {
+	p.update(func(t *models.CrawlTask) {
+		p.remaining = append([]int(nil), tmdbIDs...)
+		p.batchStart = time.Now()
+		p.batchProcessed = 0
+		t.TotalShows = t.ProcessedShows + len(tmdbIDs)
+		p.saveCheckpoint(t)
+	})
+}
+
+// ShowStarted implements CrawlProgress
+func (p *taskProgress) ShowStarted(tmdbID int, name string) {
+	p.update(func(t *models.CrawlTask) {
+		if name == "" {
+			name = fmt.Sprintf("TMDB %d", tmdbID)
+		}
+		t.CurrentShow = name
+		p.names[tmdbID] = name
+	})
+}
+
+// ShowFinished implements CrawlProgress
+// Cancelled shows stay in the checkpoint. The estimated finish time assumes
+// the remaining shows take as long as the average of this run so far.
+func (p *taskProgress) ShowFinished(result *CrawlResult) {
+	p.update(func(t *models.CrawlTask) {
+		if result.Cancelled {
+			return
+		}
+
+		for i, tmdbID := range p.remaining {
+			if tmdbID == result.TmdbID {
+				p.remaining = append(p.remaining[:i], p.remaining[i+1:]...)
+				break
+			}
+		}
+		p.summary.AddResult(&models.Show{TmdbID: result.TmdbID, Name: p.names[result.TmdbID]}, result)
+		p.saveCheckpoint(t)
+
+		t.ProcessedShows++
+		p.batchProcessed++
+		if t.ProcessedShows >= t.TotalShows {
+			t.EstimatedFinishAt = nil
+			return
+		}
+		elapsed := time.Since(p.batchStart)
+		remaining := elapsed / time.Duration(p.batchProcessed) * time.Duration(t.TotalShows-t.ProcessedShows)
+		finishAt := time.Now().Add(remaining)
+		t.EstimatedFinishAt = &finishAt
+	})
 }
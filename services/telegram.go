@@ -0,0 +1,178 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultTelegramAPIURL is the Telegram Bot API server
+const DefaultTelegramAPIURL = "https://api.telegram.org"
+
+// telegramMessageLimit is the longest message text the Bot API accepts, in characters
+const telegramMessageLimit = 4096
+
+// telegramPollTimeout is how long getUpdates waits for new updates; it must stay
+// below the HTTP client timeout
+const telegramPollTimeout = 25 * time.Second
+
+// TelegramClient calls the Telegram Bot API
+type TelegramClient struct {
+	token  string
+	apiURL string
+	client *http.Client
+}
+
+// NewTelegramClient creates a Bot API client for the bot token.
+// An empty apiURL uses DefaultTelegramAPIURL.
+func NewTelegramClient(token, apiURL string) *TelegramClient {
+	if apiURL == "" {
+		apiURL = DefaultTelegramAPIURL
+	}
+	return &TelegramClient{
+		token:  token,
+		apiURL: strings.TrimRight(apiURL, "/"),
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// TelegramChat is the chat a message was sent to
+type TelegramChat struct {
+	ID       int64  `json:"id"`
+	Type     string `json:"type"`
+	Username string `json:"username,omitempty"`
+}
+
+// TelegramUser is the sender of a message
+type TelegramUser struct {
+	ID        int64  `json:"id"`
+	IsBot     bool   `json:"is_bot"`
+	FirstName string `json:"first_name"`
+	Username  string `json:"username,omitempty"`
+}
+
+// TelegramMessage is a message returned by the Bot API
+type TelegramMessage struct {
+	MessageID int           `json:"message_id"`
+	From      *TelegramUser `json:"from,omitempty"`
+	Chat      TelegramChat  `json:"chat"`
+	Text      string        `json:"text,omitempty"`
+}
+
+// TelegramUpdate is an incoming update; only messages are used
+type TelegramUpdate struct {
+	UpdateID int64            `json:"update_id"`
+	Message  *TelegramMessage `json:"message,omitempty"`
+}
+
+// Link returns the t.me link of the message, or "" when the chat has no public username
+func (m *TelegramMessage) Link() string {
+	if m.Chat.Username == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://t.me/%s/%d", m.Chat.Username, m.MessageID)
+}
+
+// telegramResponse is the envelope of every Bot API response
+type telegramResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+}
+
+// SendMessage sends an HTML formatted message to chatID (a numeric ID or @channelusername)
+func (c *TelegramClient) SendMessage(ctx context.Context, chatID, text string) (*TelegramMessage, error) {
+	params := map[string]interface{}{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"parse_mode":               "HTML",
+		"disable_web_page_preview": true,
+	}
+
+	var message TelegramMessage
+	if err := c.call(ctx, "sendMessage", params, &message); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// GetMe returns the bot's own user
+func (c *TelegramClient) GetMe(ctx context.Context) (*TelegramUser, error) {
+	var user TelegramUser
+	if err := c.call(ctx, "getMe", map[string]interface{}{}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetUpdates long polls for the updates from offset on, waiting up to timeout for new ones
+func (c *TelegramClient) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]TelegramUpdate, error) {
+	params := map[string]interface{}{
+		"offset":          offset,
+		"timeout":         int(timeout.Seconds()),
+		"allowed_updates": []string{"message"},
+	}
+
+	var updates []TelegramUpdate
+	if err := c.call(ctx, "getUpdates", params, &updates); err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+// call invokes a Bot API method and decodes its result into result
+func (c *TelegramClient) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/bot%s/%s", c.apiURL, c.token, method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// The error contains the URL, and with it the bot token
+		return fmt.Errorf("telegram %s request failed: %w", method, redactToken(err, c.token))
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var apiResp telegramResponse
+	if err := json.Unmarshal(data, &apiResp); err != nil {
+		return fmt.Errorf("telegram %s returned status %d", method, resp.StatusCode)
+	}
+	if !apiResp.OK {
+		return fmt.Errorf("telegram %s failed: %d %s", method, apiResp.ErrorCode, apiResp.Description)
+	}
+	if result != nil {
+		if err := json.Unmarshal(apiResp.Result, result); err != nil {
+			return fmt.Errorf("failed to decode telegram %s result: %w", method, err)
+		}
+	}
+	return nil
+}
+
+// redactToken removes the bot token from an error message
+func redactToken(err error, token string) error {
+	if token == "" {
+		return err
+	}
+	return fmt.Errorf("%s", strings.ReplaceAll(err.Error(), token, "<token>"))
+}
@@ -0,0 +1,406 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/repositories"
+	"github.com/xc9973/go-tmdb-crawler/utils"
+)
+
+// telegramRetryDelay is the pause after a failed getUpdates before polling again
+const telegramRetryDelay = 5 * time.Second
+
+// telegramHelp is the reply to /start and /help
+const telegramHelp = `<b>剧集更新助手</b>
+
+/today - 今日更新
+/week - 最近 7 天的更新
+/show &lt;剧名&gt; - 剧集信息和下一集
+/follow &lt;tmdb_id&gt; - 追踪新剧集 (仅管理员)`
+
+// TelegramBot posts a summary to its chats after each scheduled publish and
+// answers commands sent to it
+type TelegramBot struct {
+	client         *TelegramClient
+	showRepo       repositories.ShowRepository
+	episodeRepo    repositories.EpisodeRepository
+	crawler        *CrawlerService
+	timezoneHelper *utils.TimezoneHelper
+	logger         *utils.Logger
+	// notifyChatIDs receive the publish summaries
+	notifyChatIDs []string
+	// adminUserIDs may use /follow
+	adminUserIDs map[int64]bool
+	// username is the bot's own username, looked up when Run starts.
+	// Commands addressed to another bot ("/today@OtherBot") are ignored.
+	username string
+}
+
+// NewTelegramBot creates a new Telegram bot instance
+func NewTelegramBot(
+	client *TelegramClient,
+	showRepo repositories.ShowRepository,
+	episodeRepo repositories.EpisodeRepository,
+	crawler *CrawlerService,
+	timezoneHelper *utils.TimezoneHelper,
+	logger *utils.Logger,
+) *TelegramBot {
+	return &TelegramBot{
+		client:         client,
+		showRepo:       showRepo,
+		episodeRepo:    episodeRepo,
+		crawler:        crawler,
+		timezoneHelper: timezoneHelper,
+		logger:         logger,
+		adminUserIDs:   make(map[int64]bool),
+	}
+}
+
+// SetNotifyChats sets the chats (numeric IDs or @channelusername) that receive publish summaries
+func (b *TelegramBot) SetNotifyChats(chatIDs []string) {
+	b.notifyChatIDs = chatIDs
+}
+
+// SetAdminUsers sets the Telegram user IDs allowed to follow shows
+func (b *TelegramBot) SetAdminUsers(userIDs []int) {
+	b.adminUserIDs = make(map[int64]bool, len(userIDs))
+	for _, id := range userIDs {
+		b.adminUserIDs[int64(id)] = true
+	}
+}
+
+// NotifyPublish posts the link and a short summary of a publish to every notify chat.
+// Publishes whose content every target already had are not posted again.
+func (b *TelegramBot) NotifyPublish(ctx context.Context, result *PublishResult) error {
+	if result == nil || !result.Success || result.IsDuplicate() || len(b.notifyChatIDs) == 0 {
+		return nil
+	}
+
+	text := formatPublishSummary(result)
+	var errs []error
+	for _, chatID := range b.notifyChatIDs {
+		if _, err := b.client.SendMessage(ctx, chatID, text); err != nil {
+			errs = append(errs, fmt.Errorf("chat %s: %w", chatID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// formatPublishSummary renders the notification of a publish
+func formatPublishSummary(result *PublishResult) string {
+	var builder strings.Builder
+	builder.WriteString("<b>" + html.EscapeString(result.Title) + "</b>\n")
+	summary := fmt.Sprintf("📺 %d 部剧集, %d 集", result.ShowsCount, result.EpisodesCount)
+	if result.MoviesCount > 0 {
+		summary += fmt.Sprintf(", 🎬 %d 部电影", result.MoviesCount)
+	}
+	builder.WriteString(summary + "\n")
+	if result.URL != "" {
+		builder.WriteString("\n" + html.EscapeString(result.URL))
+	}
+	return builder.String()
+}
+
+// Run answers commands by long polling until ctx is cancelled.
+// Each update is handled in its own goroutine so a slow /follow crawl does not hold up other commands.
+func (b *TelegramBot) Run(ctx context.Context) {
+	if !b.lookupUsername(ctx) {
+		b.logger.Info("Telegram bot stopped")
+		return
+	}
+	b.logger.Infof("Telegram bot @%s started", b.username)
+
+	var handlers sync.WaitGroup
+	defer handlers.Wait()
+
+	var offset int64
+	for {
+		updates, err := b.client.GetUpdates(ctx, offset, telegramPollTimeout)
+		if err != nil {
+			if ctx.Err() != nil {
+				b.logger.Info("Telegram bot stopped")
+				return
+			}
+			b.logger.Warnf("Telegram getUpdates failed: %v", err)
+			select {
+			case <-ctx.Done():
+				b.logger.Info("Telegram bot stopped")
+				return
+			case <-time.After(telegramRetryDelay):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			if update.Message == nil {
+				continue
+			}
+			handlers.Add(1)
+			go func(message *TelegramMessage) {
+				defer handlers.Done()
+				b.reply(ctx, message)
+			}(update.Message)
+		}
+	}
+}
+
+// lookupUsername fetches the bot's username, retrying until it succeeds or ctx is cancelled
+func (b *TelegramBot) lookupUsername(ctx context.Context) bool {
+	for {
+		me, err := b.client.GetMe(ctx)
+		if err == nil {
+			b.username = me.Username
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		b.logger.Warnf("Telegram getMe failed: %v", err)
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(telegramRetryDelay):
+		}
+	}
+}
+
+// reply sends the reply to a command, if there is one
+func (b *TelegramBot) reply(ctx context.Context, message *TelegramMessage) {
+	reply := b.HandleCommand(ctx, message)
+	if reply == "" {
+		return
+	}
+	chatID := strconv.FormatInt(message.Chat.ID, 10)
+	if _, err := b.client.SendMessage(ctx, chatID, truncateTelegramText(reply, telegramMessageLimit)); err != nil {
+		b.logger.Warnf("Telegram reply to chat %s failed: %v", chatID, err)
+	}
+}
+
+// HandleCommand returns the reply to a message, or "" when it is not a command for this bot.
+// Unknown commands are only answered in private chats, where no other bot can be meant.
+func (b *TelegramBot) HandleCommand(ctx context.Context, message *TelegramMessage) string {
+	command, botName, args := parseTelegramCommand(message.Text)
+	if botName != "" && !strings.EqualFold(botName, b.username) {
+		return ""
+	}
+	switch command {
+	case "":
+		return ""
+	case "start", "help":
+		return telegramHelp
+	case "today":
+		return b.todayReply(ctx)
+	case "week":
+		return b.weekReply(ctx)
+	case "show":
+		return b.showReply(ctx, args)
+	case "follow":
+		if message.From == nil || !b.adminUserIDs[message.From.ID] {
+			return "⛔ 只有管理员可以追踪新剧集"
+		}
+		return b.followReply(ctx, args)
+	default:
+		if message.Chat.Type != "private" {
+			return ""
+		}
+		return "未知命令, 发送 /help 查看可用命令"
+	}
+}
+
+// parseTelegramCommand splits "/show@MyBot Breaking Bad" into "show", "MyBot" and "Breaking Bad"
+func parseTelegramCommand(text string) (string, string, string) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "/") {
+		return "", "", ""
+	}
+	command, args, _ := strings.Cut(text[1:], " ")
+	command, botName, _ := strings.Cut(command, "@")
+	return strings.ToLower(command), botName, strings.TrimSpace(args)
+}
+
+// todayReply lists today's episodes
+func (b *TelegramBot) todayReply(ctx context.Context) string {
+	episodes, err := b.episodeRepo.WithContext(ctx).GetTodayUpdates()
+	if err != nil {
+		return "❌ 获取今日更新失败: " + html.EscapeString(err.Error())
+	}
+	today := b.timezoneHelper.NowInLocation().Format("2006-01-02")
+	if len(episodes) == 0 {
+		return fmt.Sprintf("📺 今日 (%s) 没有剧集更新", today)
+	}
+	return fmt.Sprintf("<b>📺 今日更新 - %s</b>\n\n", today) + b.formatEpisodes(episodes, false)
+}
+
+// weekReply lists the episodes of the last 7 days, like the weekly publish
+func (b *TelegramBot) weekReply(ctx context.Context) string {
+	today := b.timezoneHelper.TodayInLocation()
+	startDate := today.AddDate(0, 0, -7)
+	episodes, err := b.episodeRepo.WithContext(ctx).GetByDateRange(startDate, today)
+	if err != nil {
+		return "❌ 获取本周更新失败: " + html.EscapeString(err.Error())
+	}
+	title := fmt.Sprintf("%s 至 %s", startDate.Format("2006-01-02"), today.Format("2006-01-02"))
+	if len(episodes) == 0 {
+		return fmt.Sprintf("📺 %s 没有剧集更新", title)
+	}
+	return fmt.Sprintf("<b>📺 本周更新 - %s</b>\n\n", title) + b.formatEpisodes(episodes, true)
+}
+
+// formatEpisodes groups episodes by show, in the order the shows first appear
+func (b *TelegramBot) formatEpisodes(episodes []*models.Episode, withDate bool) string {
+	var order []uint
+	byShow := make(map[uint][]*models.Episode)
+	for _, episode := range episodes {
+		if _, ok := byShow[episode.ShowID]; !ok {
+			order = append(order, episode.ShowID)
+		}
+		byShow[episode.ShowID] = append(byShow[episode.ShowID], episode)
+	}
+
+	var builder strings.Builder
+	for _, showID := range order {
+		showEpisodes := byShow[showID]
+		showName := fmt.Sprintf("ShowID:%d", showID)
+		if show := showEpisodes[0].Show; show != nil && show.Name != "" {
+			showName = show.Name
+		}
+		builder.WriteString("<b>" + html.EscapeString(showName) + "</b>\n")
+		for _, episode := range showEpisodes {
+			line := "• " + episode.GetEpisodeCode()
+			if episode.Name != "" {
+				line += " - " + html.EscapeString(episode.Name)
+			}
+			if withDate {
+				if date := b.timezoneHelper.FormatAirDate(episode.AirAt, episode.AirDate); date != "" {
+					line += " (" + date + ")"
+				}
+			}
+			builder.WriteString(line + "\n")
+		}
+		builder.WriteString("\n")
+	}
+	builder.WriteString(fmt.Sprintf("共 %d 部剧集, %d 集", len(order), len(episodes)))
+	return builder.String()
+}
+
+// showReply describes the best match of a show name, with its latest and next episode
+func (b *TelegramBot) showReply(ctx context.Context, name string) string {
+	if name == "" {
+		return "用法: /show &lt;剧名&gt;"
+	}
+	shows, _, err := b.showRepo.WithContext(ctx).Search(name, 1, 5)
+	if err != nil {
+		return "❌ 搜索失败: " + html.EscapeString(err.Error())
+	}
+	if len(shows) == 0 {
+		return "未找到剧集: " + html.EscapeString(name)
+	}
+
+	show := shows[0]
+	for _, candidate := range shows {
+		if strings.EqualFold(candidate.Name, name) || strings.EqualFold(candidate.OriginalName, name) {
+			show = candidate
+			break
+		}
+	}
+
+	var builder strings.Builder
+	builder.WriteString("<b>📺 " + html.EscapeString(show.Name) + "</b>\n")
+	if show.OriginalName != "" && show.OriginalName != show.Name {
+		builder.WriteString("原名: " + html.EscapeString(show.OriginalName) + "\n")
+	}
+	builder.WriteString("状态: " + html.EscapeString(show.GetDisplayStatus()) + "\n")
+	if show.VoteCount > 0 {
+		builder.WriteString(fmt.Sprintf("评分: %.1f/10 (%d票)\n", show.VoteAverage, show.VoteCount))
+	}
+
+	episodes, err := b.episodeRepo.WithContext(ctx).GetByShowID(show.ID)
+	if err == nil {
+		latest, next := b.latestAndNext(episodes)
+		if latest != nil {
+			builder.WriteString("最新: " + b.formatEpisodeLine(latest) + "\n")
+		}
+		if next != nil {
+			builder.WriteString("下一集: " + b.formatEpisodeLine(next) + "\n")
+		}
+	}
+	builder.WriteString(fmt.Sprintf("https://www.themoviedb.org/tv/%d", show.TmdbID))
+
+	if len(shows) > 1 {
+		builder.WriteString("\n\n其他结果:")
+		for _, other := range shows {
+			if other.ID != show.ID {
+				builder.WriteString("\n• " + html.EscapeString(other.Name))
+			}
+		}
+	}
+	return builder.String()
+}
+
+// latestAndNext returns the last episode aired before today and the first one airing today or later
+func (b *TelegramBot) latestAndNext(episodes []*models.Episode) (*models.Episode, *models.Episode) {
+	today := b.timezoneHelper.DateOf(time.Now())
+	var latest, next *models.Episode
+	var latestDate, nextDate time.Time
+	for _, episode := range episodes {
+		airDate := b.timezoneHelper.AirDate(episode.AirAt, episode.AirDate)
+		if airDate == nil {
+			continue
+		}
+		if airDate.Before(today) {
+			if latest == nil || !airDate.Before(latestDate) {
+				latest, latestDate = episode, *airDate
+			}
+		} else if next == nil || airDate.Before(nextDate) {
+			next, nextDate = episode, *airDate
+		}
+	}
+	return latest, next
+}
+
+// formatEpisodeLine renders "S01E02 - Name (2026-01-02)"
+func (b *TelegramBot) formatEpisodeLine(episode *models.Episode) string {
+	line := episode.GetEpisodeCode()
+	if episode.Name != "" {
+		line += " - " + html.EscapeString(episode.Name)
+	}
+	if date := b.timezoneHelper.FormatAirDate(episode.AirAt, episode.AirDate); date != "" {
+		line += " (" + date + ")"
+	}
+	return line
+}
+
+// followReply crawls a show by TMDB ID unless it is already followed
+func (b *TelegramBot) followReply(ctx context.Context, args string) string {
+	tmdbID, err := strconv.Atoi(args)
+	if err != nil || tmdbID <= 0 {
+		return "用法: /follow &lt;tmdb_id&gt;"
+	}
+
+	showRepo := b.showRepo.WithContext(ctx)
+	if show, err := showRepo.GetByTmdbID(tmdbID); err == nil {
+		if show.IsArchived() {
+			return fmt.Sprintf("ℹ️ %s 已归档", html.EscapeString(show.Name))
+		}
+		return fmt.Sprintf("ℹ️ 已在追踪 %s", html.EscapeString(show.Name))
+	}
+
+	if err := b.crawler.CrawlShow(ctx, tmdbID); err != nil {
+		return "❌ 追踪失败: " + html.EscapeString(err.Error())
+	}
+	show, err := showRepo.GetByTmdbID(tmdbID)
+	if err != nil {
+		return "❌ 追踪失败: " + html.EscapeString(err.Error())
+	}
+	b.logger.Infof("Telegram bot followed show %d (%s)", show.TmdbID, show.Name)
+	return fmt.Sprintf("✅ 已追踪 %s (TMDB ID: %d)", html.EscapeString(show.Name), show.TmdbID)
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xc9973/go-tmdb-crawler/repositories"
+	"github.com/xc9973/go-tmdb-crawler/utils"
+)
+
+// fakeBotAPI serves getUpdates from a queue and records sendMessage calls
+type fakeBotAPI struct {
+	mu       sync.Mutex
+	updates  []TelegramUpdate
+	messages []map[string]interface{}
+	sent     chan struct{}
+}
+
+func newFakeBotAPI() *fakeBotAPI {
+	return &fakeBotAPI{sent: make(chan struct{}, 10)}
+}
+
+func (f *fakeBotAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var params map[string]interface{}
+	_ = json.NewDecoder(r.Body).Decode(&params)
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/getMe"):
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"id":123,"is_bot":true,"first_name":"TV","username":"TvBot"}}`))
+	case strings.HasSuffix(r.URL.Path, "/getUpdates"):
+		f.mu.Lock()
+		updates := f.updates
+		f.updates = nil
+		f.mu.Unlock()
+		if len(updates) == 0 {
+			// Long poll until the bot gives up
+			<-r.Context().Done()
+			return
+		}
+		result, _ := json.Marshal(updates)
+		_, _ = w.Write([]byte(`{"ok":true,"result":` + string(result) + `}`))
+	case strings.HasSuffix(r.URL.Path, "/sendMessage"):
+		f.mu.Lock()
+		f.messages = append(f.messages, params)
+		f.mu.Unlock()
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":1,"chat":{"id":1,"type":"private"}}}`))
+		f.sent <- struct{}{}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func setupTelegramBotTest(t *testing.T, api *fakeBotAPI) *TelegramBot {
+	fake := newFakeTMDB()
+	fake.addShow(1396, 2, 3)
+	return setupTelegramBotWithTMDB(t, api, fake)
+}
+
+func setupTelegramBotWithTMDB(t *testing.T, api *fakeBotAPI, fake *fakeTMDB) *TelegramBot {
+	crawler, db := setupCrawlerTest(t, fake)
+
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	bot := NewTelegramBot(
+		NewTelegramClient("123:abc", server.URL),
+		repositories.NewShowRepository(db),
+		repositories.NewEpisodeRepository(db),
+		crawler,
+		utils.NewTimezoneHelper(time.UTC),
+		utils.NewLogger("info", ""),
+	)
+	bot.SetAdminUsers([]int{42})
+	return bot
+}
+
+func TestTelegramBot_HandleCommand(t *testing.T) {
+	bot := setupTelegramBotTest(t, newFakeBotAPI())
+	bot.username = "TvBot"
+	ctx := context.Background()
+	message := func(userID int64, text string) *TelegramMessage {
+		return &TelegramMessage{From: &TelegramUser{ID: userID}, Chat: TelegramChat{ID: 1, Type: "private"}, Text: text}
+	}
+	groupMessage := func(text string) *TelegramMessage {
+		return &TelegramMessage{From: &TelegramUser{ID: 7}, Chat: TelegramChat{ID: -100, Type: "supergroup"}, Text: text}
+	}
+
+	tests := []struct {
+		name string
+		msg  *TelegramMessage
+		want string
+	}{
+		{"Not a command", message(42, "hello"), ""},
+		{"Help with bot name", message(7, "/help@TvBot"), "/follow"},
+		{"Command for another bot", groupMessage("/today@OtherBot"), ""},
+		{"Command for this bot in a group", groupMessage("/help@tvbot"), "/follow"},
+		{"Follow needs an admin", message(7, "/follow 1396"), "只有管理员"},
+		{"Follow needs an ID", message(42, "/follow abc"), "用法"},
+		{"Follow", message(42, "/follow 1396"), "已追踪 Show 1396"},
+		{"Follow again", message(42, "/FOLLOW 1396"), "已在追踪 Show 1396"},
+		{"Show", message(7, "/show show 1396"), "最新: S02E03 - Episode 3 (2024-02-03)"},
+		{"Show not found", message(7, "/show nothing"), "未找到剧集"},
+		{"Today", message(7, "/today"), "没有剧集更新"},
+		{"Unknown", message(7, "/dance"), "/help"},
+		{"Unknown in a group", groupMessage("/dance"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reply := bot.HandleCommand(ctx, tt.msg)
+			if tt.want == "" {
+				if reply != "" {
+					t.Errorf("Expected no reply, got %q", reply)
+				}
+				return
+			}
+			if !strings.Contains(reply, tt.want) {
+				t.Errorf("Expected the reply to contain %q, got %q", tt.want, reply)
+			}
+		})
+	}
+}
+
+func TestTelegramBot_RunRepliesToCommands(t *testing.T) {
+	api := newFakeBotAPI()
+	api.updates = []TelegramUpdate{
+		{UpdateID: 10, Message: &TelegramMessage{MessageID: 1, Chat: TelegramChat{ID: -100}, Text: "/start"}},
+	}
+	bot := setupTelegramBotTest(t, api)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		bot.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-api.sent:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the reply")
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not stop after the context was cancelled")
+	}
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	if len(api.messages) != 1 || api.messages[0]["chat_id"] != "-100" {
+		t.Fatalf("Expected one reply to chat -100, got %v", api.messages)
+	}
+	if text, _ := api.messages[0]["text"].(string); !strings.Contains(text, "/today") {
+		t.Errorf("Expected the help text, got %q", text)
+	}
+}
+
+func TestTelegramBot_RunDoesNotWaitForFollow(t *testing.T) {
+	api := newFakeBotAPI()
+	api.updates = []TelegramUpdate{
+		{UpdateID: 10, Message: &TelegramMessage{MessageID: 1, From: &TelegramUser{ID: 42}, Chat: TelegramChat{ID: 1, Type: "private"}, Text: "/follow 1396"}},
+		{UpdateID: 11, Message: &TelegramMessage{MessageID: 2, Chat: TelegramChat{ID: 2, Type: "private"}, Text: "/help"}},
+	}
+	fake := newFakeTMDB()
+	fake.addShow(1396, 2, 3)
+	fake.delay = 500 * time.Millisecond
+	bot := setupTelegramBotWithTMDB(t, api, fake)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		bot.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-api.sent:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the reply")
+	}
+	api.mu.Lock()
+	first := api.messages[0]["chat_id"]
+	api.mu.Unlock()
+	if first != "2" {
+		t.Errorf("Expected /help to be answered while the follow crawl runs, first reply went to %v", first)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not stop after the context was cancelled")
+	}
+}
+
+func TestTelegramBot_NotifyPublish(t *testing.T) {
+	api := newFakeBotAPI()
+	bot := setupTelegramBotTest(t, api)
+	bot.SetNotifyChats([]string{"@tvupdates", "-100"})
+	ctx := context.Background()
+
+	result := &PublishResult{
+		Success:       true,
+		URL:           "https://telegra.ph/Today-01-02",
+		Title:         "今日更新 - 2026-01-02",
+		ShowsCount:    2,
+		EpisodesCount: 3,
+		Targets:       []*TargetResult{{Target: "telegraph", Success: true, URL: "https://telegra.ph/Today-01-02"}},
+	}
+	if err := bot.NotifyPublish(ctx, result); err != nil {
+		t.Fatalf("NotifyPublish failed: %v", err)
+	}
+
+	// Content every target already had is not announced again
+	result.Targets[0].Duplicate = true
+	if err := bot.NotifyPublish(ctx, result); err != nil {
+		t.Fatalf("NotifyPublish failed: %v", err)
+	}
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	if len(api.messages) != 2 {
+		t.Fatalf("Expected one message per chat, got %d", len(api.messages))
+	}
+	text, _ := api.messages[0]["text"].(string)
+	for _, want := range []string{"<b>今日更新 - 2026-01-02</b>", "2 部剧集, 3 集", "https://telegra.ph/Today-01-02"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected the summary to contain %q, got %q", want, text)
+		}
+	}
+	if api.messages[1]["chat_id"] != "-100" {
+		t.Errorf("Expected the second message to go to -100, got %v", api.messages[1]["chat_id"])
+	}
+}
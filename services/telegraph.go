@@ -232,8 +232,8 @@ func (s *TelegraphService) doRequest(method string, data interface{}) ([]byte, e
 	return body, nil
 }
 
-// GenerateUpdateListContent generates content for update list
-func (s *TelegraphService) GenerateUpdateListContent(episodes []*models.Episode) []Node {
+// GenerateUpdateListContent generates content for an update list of episodes and movie releases
+func (s *TelegraphService) GenerateUpdateListContent(episodes []*models.Episode, releases []*models.MovieReleaseDate) []Node {
 	content := []Node{}
 
 	// Title
@@ -278,9 +278,23 @@ func (s *TelegraphService) GenerateUpdateListContent(episodes []*models.Episode)
 		content = append(content, NewBrNode())
 	}
 
+	// Movies
+	movies := groupReleasesByMovie(releases)
+	if len(movies) > 0 {
+		content = append(content, NewBoldNode("🎬 电影上映"))
+		for _, movie := range movies {
+			content = append(content, NewListNode(fmt.Sprintf("%s - %s", movie.Title(), formatReleases(movie.Releases))))
+		}
+		content = append(content, NewBrNode())
+	}
+
 	// Footer
+	footer := fmt.Sprintf("📊 共 %d 部剧集更新", len(showMap))
+	if len(movies) > 0 {
+		footer += fmt.Sprintf(", %d 部电影上映", len(movies))
+	}
 	content = append(content, NewHrNode())
-	content = append(content, NewTextNode(fmt.Sprintf("📊 共 %d 部剧集更新", len(showMap))))
+	content = append(content, NewTextNode(footer))
 	content = append(content, NewBrNode())
 	content = append(content, NewTextNode("数据来源: TMDB"))
 
@@ -0,0 +1,416 @@
+package services
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/utils"
+)
+
+// Names of the update list and show templates
+const (
+	TodayTemplate  = "today"
+	WeeklyTemplate = "weekly"
+	RangeTemplate  = "range"
+	ShowTemplate   = "show"
+)
+
+// TemplateNames lists the templates, in the order they are documented
+var TemplateNames = []string{TodayTemplate, WeeklyTemplate, RangeTemplate, ShowTemplate}
+
+var (
+	// ErrUnknownTemplate is returned for a name that is not one of TemplateNames
+	ErrUnknownTemplate = errors.New("unknown template")
+	// ErrInvalidTemplate is returned for a template that does not parse or execute
+	ErrInvalidTemplate = errors.New("invalid template")
+)
+
+// defaultTemplates are used for the templates without a file in the templates directory
+//
+//go:embed templates/*.md
+var defaultTemplates embed.FS
+
+// templateFuncs are the functions available to templates besides the text/template builtins
+var templateFuncs = template.FuncMap{
+	// date formats a time with a Go layout, e.g. {{date .Today "2006-01-02"}}
+	"date": func(t time.Time, layout string) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(layout)
+	},
+	// escape escapes a raw field for Markdown, e.g. {{escape .Show.Homepage}}
+	"escape": escapeMarkdown,
+}
+
+// TemplateService renders update lists and show pages from text/template Markdown.
+// Each template is read from <dir>/<name>.md on every render, so edits apply
+// without a restart; a template without a file uses the built-in default.
+// The templates share one set, so one can include another with {{template "range" .}}.
+type TemplateService struct {
+	dir string
+}
+
+// NewTemplateService creates a template service reading templates from dir
+func NewTemplateService(dir string) *TemplateService {
+	return &TemplateService{dir: dir}
+}
+
+// TemplateInfo describes a template and where it is read from
+type TemplateInfo struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Custom bool   `json:"custom"`
+	Source string `json:"source,omitempty"`
+}
+
+// List returns the templates without their source
+func (s *TemplateService) List() ([]*TemplateInfo, error) {
+	infos := make([]*TemplateInfo, 0, len(TemplateNames))
+	for _, name := range TemplateNames {
+		info, err := s.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		info.Source = ""
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// Get returns a template with its source: the file when there is one, the default otherwise
+func (s *TemplateService) Get(name string) (*TemplateInfo, error) {
+	if !isTemplateName(name) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+	}
+
+	info := &TemplateInfo{Name: name, Path: s.path(name)}
+	data, err := os.ReadFile(info.Path)
+	switch {
+	case err == nil:
+		info.Custom = true
+		info.Source = string(data)
+	case errors.Is(err, os.ErrNotExist):
+		data, err := defaultTemplates.ReadFile("templates/" + name + ".md")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read default template %s: %w", name, err)
+		}
+		info.Source = string(data)
+	default:
+		return nil, fmt.Errorf("failed to read template %s: %w", name, err)
+	}
+	return info, nil
+}
+
+// Save writes the source of a template after checking that it parses
+func (s *TemplateService) Save(name, source string) error {
+	if _, err := s.parse(name, source); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create templates directory: %w", err)
+	}
+	if err := os.WriteFile(s.path(name), []byte(source), 0o644); err != nil {
+		return fmt.Errorf("failed to write template %s: %w", name, err)
+	}
+	return nil
+}
+
+// Reset deletes the file of a template, restoring the default
+func (s *TemplateService) Reset(name string) error {
+	if !isTemplateName(name) {
+		return fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+	}
+	if err := os.Remove(s.path(name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete template %s: %w", name, err)
+	}
+	return nil
+}
+
+// Render renders the named template with data
+func (s *TemplateService) Render(name string, data interface{}) (string, error) {
+	return s.RenderSource(name, "", data)
+}
+
+// RenderSource renders data with source in place of the named template,
+// leaving the stored template unchanged. An empty source renders the stored one.
+func (s *TemplateService) RenderSource(name, source string, data interface{}) (string, error) {
+	set, err := s.parse(name, source)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := set.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return strings.TrimRight(buf.String(), "\n"), nil
+}
+
+// parse parses every template into one set, with source in place of the named one when set
+func (s *TemplateService) parse(name, source string) (*template.Template, error) {
+	if !isTemplateName(name) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+	}
+
+	set := template.New("").Funcs(templateFuncs)
+	for _, templateName := range TemplateNames {
+		text := source
+		if templateName != name || source == "" {
+			info, err := s.Get(templateName)
+			if err != nil {
+				return nil, err
+			}
+			text = info.Source
+		}
+		if _, err := set.New(templateName).Parse(text); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+		}
+	}
+	return set, nil
+}
+
+// path returns the file of a template
+func (s *TemplateService) path(name string) string {
+	return filepath.Join(s.dir, name+".md")
+}
+
+// isTemplateName reports whether name is one of TemplateNames
+func isTemplateName(name string) bool {
+	for _, templateName := range TemplateNames {
+		if templateName == name {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateListData is the data of the today, weekly and range templates.
+// Shows and Movies list everything in the range, Days the same grouped by air date.
+// Names, titles and overviews are escaped for Markdown, so TMDB data renders as
+// plain text; the Show, Episode and Movie records are not, use {{escape}} on their text.
+type UpdateListData struct {
+	// Today is the current time in the configured timezone
+	Today     time.Time
+	StartDate time.Time
+	EndDate   time.Time
+	Shows     []*TemplateShow
+	Movies    []*TemplateMovie
+	Days      []*TemplateDay
+	// Stats summarizes the counts, e.g. "共 2 部剧集, 3 集更新"
+	Stats         string
+	ShowsCount    int
+	EpisodesCount int
+	MoviesCount   int
+}
+
+// TemplateDay is one air date of an update list
+type TemplateDay struct {
+	Date   string
+	Shows  []*TemplateShow
+	Movies []*TemplateMovie
+}
+
+// TemplateShow is a show with its episodes in an update list
+type TemplateShow struct {
+	ID       uint
+	Name     string
+	Show     *models.Show
+	Episodes []*TemplateEpisode
+}
+
+// TemplateEpisode is an episode with its code and air date formatted
+type TemplateEpisode struct {
+	Code     string
+	Name     string
+	Overview string
+	// AirDate is the air date in the configured timezone, empty when unknown
+	AirDate string
+	Episode *models.Episode
+}
+
+// TemplateMovie is a movie with its releases in an update list
+type TemplateMovie struct {
+	Title    string
+	Overview string
+	// Releases describes the releases, e.g. "US 院线 (PG-13), GB 数字"
+	Releases string
+	Movie    *models.Movie
+}
+
+// ShowDetailData is the data of the show template.
+// Like UpdateListData, its text fields are escaped for Markdown and Show is not.
+type ShowDetailData struct {
+	Show         *models.Show
+	Name         string
+	OriginalName string
+	Overview     string
+	Language     string
+	Status       string
+	Type         string
+	FirstAirDate string
+	Seasons      []*TemplateSeason
+	// Now is the time the page is rendered; the default template shows only the date,
+	// so republishing an unchanged show on the same day is recognized as a duplicate
+	Now           time.Time
+	EpisodesCount int
+}
+
+// TemplateSeason is a season of a show, in ascending order; season 0 holds the specials
+type TemplateSeason struct {
+	Number   int
+	Name     string
+	Episodes []*TemplateEpisode
+}
+
+// newUpdateListData builds the data of an update list from already localized episodes
+func newUpdateListData(tzHelper *utils.TimezoneHelper, startDate, endDate time.Time, episodes []*models.Episode, releases []*models.MovieReleaseDate) *UpdateListData {
+	data := &UpdateListData{
+		Today:     tzHelper.NowInLocation(),
+		StartDate: startDate,
+		EndDate:   endDate,
+	}
+
+	var nonNil []*models.Episode
+	episodesByDate := make(map[string][]*models.Episode)
+	for _, episode := range episodes {
+		if episode == nil {
+			continue
+		}
+		nonNil = append(nonNil, episode)
+		if airDate := tzHelper.AirDate(episode.AirAt, episode.AirDate); airDate != nil {
+			date := airDate.Format("2006-01-02")
+			episodesByDate[date] = append(episodesByDate[date], episode)
+		}
+	}
+	releasesByDate := make(map[string][]*models.MovieReleaseDate)
+	for _, release := range releases {
+		if release != nil {
+			date := release.ReleaseDate.Format("2006-01-02")
+			releasesByDate[date] = append(releasesByDate[date], release)
+		}
+	}
+
+	data.Shows = templateShows(tzHelper, nonNil)
+	data.Movies = templateMovies(releases)
+	dates := make([]string, 0, len(episodesByDate)+len(releasesByDate))
+	for date := range episodesByDate {
+		dates = append(dates, date)
+	}
+	for date := range releasesByDate {
+		if _, ok := episodesByDate[date]; !ok {
+			dates = append(dates, date)
+		}
+	}
+	sort.Strings(dates)
+	for _, date := range dates {
+		data.Days = append(data.Days, &TemplateDay{
+			Date:   date,
+			Shows:  templateShows(tzHelper, episodesByDate[date]),
+			Movies: templateMovies(releasesByDate[date]),
+		})
+	}
+
+	data.ShowsCount = len(data.Shows)
+	data.EpisodesCount = len(nonNil)
+	data.MoviesCount = len(data.Movies)
+	data.Stats = updateStats(data.ShowsCount, data.EpisodesCount, data.MoviesCount)
+	return data
+}
+
+// templateShows groups episodes by show in the order the shows first appear
+func templateShows(tzHelper *utils.TimezoneHelper, episodes []*models.Episode) []*TemplateShow {
+	var shows []*TemplateShow
+	byID := make(map[uint]*TemplateShow)
+	for _, episode := range episodes {
+		show, ok := byID[episode.ShowID]
+		if !ok {
+			show = &TemplateShow{ID: episode.ShowID, Name: fmt.Sprintf("ShowID:%d", episode.ShowID), Show: episode.Show}
+			if episode.Show != nil && episode.Show.Name != "" {
+				show.Name = escapeMarkdown(episode.Show.Name)
+			}
+			byID[episode.ShowID] = show
+			shows = append(shows, show)
+		}
+		show.Episodes = append(show.Episodes, templateEpisode(tzHelper, episode))
+	}
+	return shows
+}
+
+// templateEpisode formats an episode for the templates
+func templateEpisode(tzHelper *utils.TimezoneHelper, episode *models.Episode) *TemplateEpisode {
+	return &TemplateEpisode{
+		Code:     episode.GetEpisodeCode(),
+		Name:     escapeMarkdown(episode.Name),
+		Overview: escapeMarkdown(episode.Overview),
+		AirDate:  tzHelper.FormatAirDate(episode.AirAt, episode.AirDate),
+		Episode:  episode,
+	}
+}
+
+// templateMovies groups releases by movie in the order the movies first appear
+func templateMovies(releases []*models.MovieReleaseDate) []*TemplateMovie {
+	var movies []*TemplateMovie
+	for _, movie := range groupReleasesByMovie(releases) {
+		templateMovie := &TemplateMovie{
+			Title:    escapeMarkdown(movie.Title()),
+			Releases: escapeMarkdown(formatReleases(movie.Releases)),
+			Movie:    movie.Movie,
+		}
+		if movie.Movie != nil {
+			templateMovie.Overview = escapeMarkdown(movie.Movie.Overview)
+		}
+		movies = append(movies, templateMovie)
+	}
+	return movies
+}
+
+// newShowDetailData builds the data of the show template from an already localized show
+func newShowDetailData(tzHelper *utils.TimezoneHelper, show *models.Show, episodes []*models.Episode) *ShowDetailData {
+	data := &ShowDetailData{
+		Show:         show,
+		Name:         escapeMarkdown(show.Name),
+		OriginalName: escapeMarkdown(show.OriginalName),
+		Overview:     escapeMarkdown(show.Overview),
+		Language:     escapeMarkdown(show.Language),
+		Status:       escapeMarkdown(show.GetDisplayStatus()),
+		Type:         escapeMarkdown(show.GetDisplayType()),
+		Now:          time.Now(),
+	}
+	if show.FirstAirDate != nil {
+		data.FirstAirDate = show.FirstAirDate.Format("2006-01-02")
+	}
+
+	bySeason := make(map[int]*TemplateSeason)
+	for _, episode := range episodes {
+		if episode == nil {
+			continue
+		}
+		number := int(episode.SeasonNumber)
+		season, ok := bySeason[number]
+		if !ok {
+			season = &TemplateSeason{Number: number, Name: fmt.Sprintf("第%d季", number)}
+			if number == 0 {
+				season.Name = "特别篇"
+			}
+			bySeason[number] = season
+			data.Seasons = append(data.Seasons, season)
+		}
+		season.Episodes = append(season.Episodes, templateEpisode(tzHelper, episode))
+		data.EpisodesCount++
+	}
+	sort.Slice(data.Seasons, func(i, j int) bool {
+		return data.Seasons[i].Number < data.Seasons[j].Number
+	})
+
+	return data
+}
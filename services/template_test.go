@@ -0,0 +1,211 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/utils"
+)
+
+func TestMarkdownToNodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{"headings", "# Title\n### S01E01 - Pilot\n##### Note", "<h3>Title</h3>\n<h4>S01E01 - Pilot</h4>\n<h4>Note</h4>\n"},
+		{"paragraph keeps line breaks", "**播出日期**: 2024-03-01\n**简介**: *Walt* ~~cooks~~", "<p><b>播出日期</b>: 2024-03-01<br><b>简介</b>: <i>Walt</i> <s>cooks</s></p>\n"},
+		{"lists", "- **S01E01** - Pilot\n- S01E02\n\n1. one", "<ul><li><b>S01E01</b> - Pilot</li><li>S01E02</li></ul>\n<ol><li>one</li></ol>\n"},
+		{"quote and rule", "> Overview\n---", "<blockquote>Overview</blockquote>\n<hr>\n"},
+		{"links, images and code", "[TMDB](https://www.themoviedb.org/tv/1) ![poster](https://image.tmdb.org/p.jpg) `a<b`", `<p><a href="https://www.themoviedb.org/tv/1">TMDB</a> <img src="https://image.tmdb.org/p.jpg"> <code>a&lt;b</code></p>` + "\n"},
+		{"text is escaped", "Breaking <Bad> & 1 < 2", "<p>Breaking &lt;Bad&gt; &amp; 1 &lt; 2</p>\n"},
+		{"inline html", "Airs <u>tonight</u> at <b>9pm</b>", "<p>Airs <u>tonight</u> at <b>9pm</b></p>\n"},
+		{"html blocks", "<h2>Week</h2>\n<div><span>Plain</span> <em>text</em></div><script>alert(1)</script>", "<h3>Week</h3>\n<p>Plain <em>text</em></p>\n"},
+		{"parentheses in link URLs", "[Film](https://en.wikipedia.org/wiki/Dune_(2021_film)) (2021)", `<p><a href="https://en.wikipedia.org/wiki/Dune_(2021_film)">Film</a> (2021)</p>` + "\n"},
+		{"unsafe markdown links", "[click](javascript:alert(1)) ![x](data:image/png;base64,AA)", "<p>click x</p>\n"},
+		{"unsafe html links", `<a href="javascript:alert(1)">click</a> <img src="/poster.jpg">`, `<p><a>click</a> <img src="/poster.jpg"></p>` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderNodesHTML(MarkdownToNodes(tt.markdown)); got != tt.want {
+				t.Errorf("MarkdownToNodes(%q) rendered\n%q, want\n%q", tt.markdown, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeMarkdown(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"M*A*S*H", "<p>M*A*S*H</p>\n"},
+		{"snake_case_name __init__", "<p>snake_case_name __init__</p>\n"},
+		{"[x](a(b))", "<p>[x](a(b))</p>\n"},
+		{"# Not a heading", "<p># Not a heading</p>\n"},
+		{"1. Not a list", "<p>1. Not a list</p>\n"},
+		{"- Not a list", "<p>- Not a list</p>\n"},
+		{"AT&amp;T <b>bold</b>\n\n> quote", "<p>AT&amp;amp;T &lt;b&gt;bold&lt;/b&gt;  &gt; quote</p>\n"},
+	}
+	for _, tt := range tests {
+		if got := renderNodesHTML(MarkdownToNodes(escapeMarkdown(tt.text))); got != tt.want {
+			t.Errorf("escapeMarkdown(%q) rendered\n%q, want\n%q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTemplateService_EscapesData(t *testing.T) {
+	tzHelper := utils.NewTimezoneHelper(time.UTC)
+	templates := NewTemplateService(t.TempDir())
+	episodes, releases := templateTestData()
+	episodes[0].Overview = `Great show <a href="javascript:alert(1)">click</a>`
+	episodes[0].Show.Name = "M*A*S*H"
+
+	markdown, err := templates.Render(TodayTemplate, newUpdateListData(tzHelper, time.Now(), time.Now(), episodes, releases))
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	got := renderNodesHTML(MarkdownToNodes(markdown))
+	for _, want := range []string{"<h3>M*A*S*H</h3>", `<b>简介</b>: Great show &lt;a href=&#34;javascript:alert(1)&#34;&gt;click&lt;/a&gt;`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected the page to contain %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "<a") {
+		t.Errorf("Expected no link from the overview, got:\n%s", got)
+	}
+
+	show := &models.Show{Name: "<i>Severance</i>", Overview: `<iframe src="https://evil.example"></iframe>`}
+	markdown, err = templates.Render(ShowTemplate, newShowDetailData(tzHelper, show, nil))
+	if err != nil {
+		t.Fatalf("Render(show) failed: %v", err)
+	}
+	got = renderNodesHTML(MarkdownToNodes(markdown))
+	if strings.Contains(got, "<i>Severance") || strings.Contains(got, "<iframe") {
+		t.Errorf("Expected the show page to escape the show's name and overview, got:\n%s", got)
+	}
+}
+
+// templateTestData returns the episodes of two days of one show and a movie release
+func templateTestData() ([]*models.Episode, []*models.MovieReleaseDate) {
+	show := &models.Show{ID: 1, Name: "Severance"}
+	first := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	second := first.AddDate(0, 0, 1)
+	episodes := []*models.Episode{
+		{ShowID: 1, Show: show, SeasonNumber: 2, EpisodeNumber: 1, Name: "Hello, Ms. Cobel", Overview: "Mark returns", AirDate: &first},
+		{ShowID: 1, Show: show, SeasonNumber: 2, EpisodeNumber: 2, Name: "Goodbye, Mrs. Selvig", AirDate: &second},
+	}
+	movie := &models.Movie{ID: 7, Title: "Dune: Part Two", Overview: "Paul unites with the Fremen"}
+	releases := []*models.MovieReleaseDate{
+		{MovieID: 7, Movie: movie, Region: "US", Type: models.ReleaseTypeTheatrical, ReleaseDate: second, Certification: "PG-13"},
+	}
+	return episodes, releases
+}
+
+func TestTemplateService_DefaultsMatchBuiltInLayout(t *testing.T) {
+	tzHelper := utils.NewTimezoneHelper(time.UTC)
+	markdown := &MarkdownService{timezoneHelper: tzHelper}
+	templates := NewTemplateService(t.TempDir())
+	episodes, releases := templateTestData()
+	start, end := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC)
+	data := newUpdateListData(tzHelper, start, end, episodes, releases)
+
+	for _, name := range []string{TodayTemplate, WeeklyTemplate, RangeTemplate} {
+		got, err := templates.Render(name, data)
+		if err != nil {
+			t.Fatalf("Render(%s) failed: %v", name, err)
+		}
+		want := markdown.GenerateDateRangeUpdates(start, end, episodes, releases)
+		if name == TodayTemplate {
+			want = markdown.GenerateUpdateList(episodes, releases)
+		}
+		if got != want {
+			t.Errorf("Default %s template rendered\n%s\nwant\n%s", name, got, want)
+		}
+	}
+
+	show := &models.Show{TmdbID: 95396, Name: "Severance", Status: "Returning Series", VoteAverage: 8.4, VoteCount: 2000, Language: "en"}
+	got, err := templates.Render(ShowTemplate, newShowDetailData(tzHelper, show, episodes))
+	if err != nil {
+		t.Fatalf("Render(show) failed: %v", err)
+	}
+	for _, want := range []string{"# Severance", "- **评分**: 8.4/10 (2000票)", "### 第2季 (2集)", "**S02E01** - Hello, Ms. Cobel *(2024-03-01)*\n> Mark returns", "*TMDB ID: 95396 | 最后更新: "} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected show page to contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestTemplateService_UserTemplates(t *testing.T) {
+	dir := t.TempDir()
+	templates := NewTemplateService(dir)
+	episodes, releases := templateTestData()
+	data := newUpdateListData(utils.NewTimezoneHelper(time.UTC), time.Now(), time.Now(), episodes, releases)
+
+	// A file in the directory replaces the default, and weekly follows range
+	source := "{{range .Shows}}{{.Name}}: {{len .Episodes}}{{end}}, {{.MoviesCount}}\n"
+	if err := os.WriteFile(filepath.Join(dir, "range.md"), []byte(source), 0o644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	for _, name := range []string{RangeTemplate, WeeklyTemplate} {
+		if got, err := templates.Render(name, data); err != nil || got != "Severance: 2, 1" {
+			t.Errorf("Render(%s) = %q, %v", name, got, err)
+		}
+	}
+
+	// A preview does not save its source
+	if got, err := templates.RenderSource(RangeTemplate, "{{.Stats}}", data); err != nil || got != "共 1 部剧集, 2 集更新, 1 部电影上映" {
+		t.Errorf("RenderSource = %q, %v", got, err)
+	}
+	if info, _ := templates.Get(RangeTemplate); !info.Custom || info.Source != source {
+		t.Errorf("Expected the stored template to be unchanged, got %+v", info)
+	}
+
+	// Templates that do not parse or execute are rejected
+	if err := templates.Save(TodayTemplate, "{{range .Shows}}"); !errors.Is(err, ErrInvalidTemplate) {
+		t.Errorf("Expected ErrInvalidTemplate for an unclosed range, got %v", err)
+	}
+	if _, err := templates.RenderSource(TodayTemplate, "{{.Missing}}", data); !errors.Is(err, ErrInvalidTemplate) {
+		t.Errorf("Expected ErrInvalidTemplate for an unknown field, got %v", err)
+	}
+	if err := templates.Save("monthly", "x"); !errors.Is(err, ErrUnknownTemplate) {
+		t.Errorf("Expected ErrUnknownTemplate, got %v", err)
+	}
+
+	// Reset restores the default
+	if err := templates.Reset(RangeTemplate); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	if got, _ := templates.Render(WeeklyTemplate, data); !strings.HasPrefix(got, "# 📺 更新清单") {
+		t.Errorf("Expected the default layout after a reset, got:\n%s", got)
+	}
+}
+
+func TestPublisherService_RendersTemplates(t *testing.T) {
+	templates := NewTemplateService(t.TempDir())
+	if err := templates.Save(TodayTemplate, "## {{date .Today \"2006\"}}\n{{range .Shows}}- **{{.Name}}** {{range .Episodes}}{{.Code}} {{end}}\n{{end}}"); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	publisher := NewPublisherService(&TelegraphService{}, nil, nil, nil, utils.NewTimezoneHelper(time.UTC))
+	publisher.SetTemplates(templates)
+
+	episodes, releases := templateTestData()
+	today := time.Now().UTC()
+	content, err := publisher.updateListContent(TodayTemplate, "今日更新", "today", today, today, episodes, releases, nil)
+	if err != nil {
+		t.Fatalf("updateListContent failed: %v", err)
+	}
+	want := "<h3>" + today.Format("2006") + "</h3>\n<ul><li><b>Severance</b> S02E01 S02E02</li></ul>\n"
+	if got := renderNodesHTML(content.Content); got != want {
+		t.Errorf("Expected the template as Telegraph nodes, got %q", got)
+	}
+	if content.ShowsCount != 1 || content.EpisodesCount != 2 || content.MoviesCount != 1 {
+		t.Errorf("Unexpected counts: %+v", content)
+	}
+}
@@ -1,101 +1,49 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
 	"sync"
 	"time"
 
-	"github.com/parnurzeal/gorequest"
 	"github.com/xc9973/go-tmdb-crawler/dto"
+	"github.com/xc9973/go-tmdb-crawler/models"
 )
 
 // TMDBService handles all TMDB API interactions
 type TMDBService struct {
-	apiKey     string
-	baseURL    string
-	lang       string
-	client     *gorequest.SuperAgent
-	timeout    time.Duration
-	maxRetries int
-	cache      *TMDBCache
-	mu         sync.RWMutex
+	apiKey  string
+	baseURL string
+	// accessToken is the optional v4 read access token, sent as a bearer token
+	accessToken string
+	lang        string
+	timeout     time.Duration
+	maxRetries  int
+	cache       TMDBCacheStore
+	cacheTTLs   TMDBCacheTTLs
+	limiter     *RateLimiter
+	mode        string
+	fixtures    *TMDBFixtures
+	mu          sync.RWMutex
 }
 
-// TMDBCache provides simple in-memory caching for TMDB responses
-type TMDBCache struct {
-	data map[string]cacheEntry
-	mu   sync.RWMutex
-	ttl  time.Duration
-}
-
-type cacheEntry struct {
-	data      []byte
-	timestamp time.Time
-}
-
-// NewTMDBCache creates a new cache instance
-func NewTMDBCache(ttl time.Duration) *TMDBCache {
-	cache := &TMDBCache{
-		data: make(map[string]cacheEntry),
-		ttl:  ttl,
-	}
-	// Start cleanup goroutine
-	go cache.cleanup()
-	return cache
-}
-
-// Get retrieves data from cache
-func (c *TMDBCache) Get(key string) ([]byte, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	entry, exists := c.data[key]
-	if !exists {
-		return nil, false
-	}
-
-	if time.Since(entry.timestamp) > c.ttl {
-		return nil, false
-	}
-
-	return entry.data, true
-}
-
-// Set stores data in cache
-func (c *TMDBCache) Set(key string, data []byte) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	c.data[key] = cacheEntry{
-		data:      data,
-		timestamp: time.Now(),
-	}
-}
+// External ID sources accepted by TMDB's /find endpoint
+const (
+	ExternalSourceIMDb = "imdb_id"
+	ExternalSourceTVDB = "tvdb_id"
+)
 
-// cleanup removes expired cache entries
-func (c *TMDBCache) cleanup() {
-	ticker := time.NewTicker(5 * time.Minute)
-	defer ticker.Stop()
+// ErrExternalIDNotFound is returned when TMDB has no show for an external ID
+var ErrExternalIDNotFound = errors.New("no TMDB show found for external ID")
 
-	for range ticker.C {
-		c.mu.Lock()
-		now := time.Now()
-		for key, entry := range c.data {
-			if now.Sub(entry.timestamp) > c.ttl {
-				delete(c.data, key)
-			}
-		}
-		c.mu.Unlock()
-	}
-}
-
-// Clear clears all cache entries
-func (c *TMDBCache) Clear() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.data = make(map[string]cacheEntry)
-}
+// DefaultTMDBRateLimit is the default number of TMDB requests allowed per second
+const DefaultTMDBRateLimit = 40
 
 // NewTMDBService creates a new TMDB service instance
 func NewTMDBService(apiKey, baseURL, lang string) *TMDBService {
@@ -103,23 +51,27 @@ func NewTMDBService(apiKey, baseURL, lang string) *TMDBService {
 		apiKey:     apiKey,
 		baseURL:    baseURL,
 		lang:       lang,
-		client:     gorequest.New(),
 		timeout:    10 * time.Second,
 		maxRetries: 3,
-		cache:      NewTMDBCache(5 * time.Minute),
+		cache:      NewTMDBCache(tmdbCacheRetention),
+		cacheTTLs:  DefaultTMDBCacheTTLs(),
+		limiter:    NewRateLimiter(DefaultTMDBRateLimit, DefaultTMDBRateLimit),
+		mode:       TMDBModeLive,
 	}
 }
 
-// NewTMDBServiceWithCache creates a new TMDB service instance with custom cache TTL
+// NewTMDBServiceWithCache creates a new TMDB service instance with the same cache TTL for every endpoint
 func NewTMDBServiceWithCache(apiKey, baseURL, lang string, cacheTTL time.Duration) *TMDBService {
 	return &TMDBService{
 		apiKey:     apiKey,
 		baseURL:    baseURL,
 		lang:       lang,
-		client:     gorequest.New(),
 		timeout:    10 * time.Second,
 		maxRetries: 3,
-		cache:      NewTMDBCache(cacheTTL),
+		cache:      NewTMDBCache(tmdbCacheRetention),
+		cacheTTLs:  TMDBCacheTTLs{Show: cacheTTL, Season: cacheTTL, Search: cacheTTL, Changes: cacheTTL},
+		limiter:    NewRateLimiter(DefaultTMDBRateLimit, DefaultTMDBRateLimit),
+		mode:       TMDBModeLive,
 	}
 }
 
@@ -145,9 +97,72 @@ func (s *TMDBService) SetMaxRetries(maxRetries int) {
 	s.maxRetries = maxRetries
 }
 
+// SetRateLimit sets the maximum number of TMDB requests per second.
+// The limiter is shared by all goroutines using this service; a
+// non-positive value disables rate limiting.
+func (s *TMDBService) SetRateLimit(requestsPerSecond int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limiter = NewRateLimiter(requestsPerSecond, requestsPerSecond)
+}
+
+// SetMode switches between live, record and replay mode.
+// Record and replay use fixtureDir to store the raw responses.
+func (s *TMDBService) SetMode(mode, fixtureDir string) error {
+	switch mode {
+	case "", TMDBModeLive:
+		mode = TMDBModeLive
+	case TMDBModeRecord, TMDBModeReplay:
+		if fixtureDir == "" {
+			return fmt.Errorf("TMDB %s mode requires a fixture directory", mode)
+		}
+	default:
+		return fmt.Errorf("unknown TMDB mode: %s", mode)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mode = mode
+	s.fixtures = NewTMDBFixtures(fixtureDir)
+	return nil
+}
+
+// SetAccessToken sets the v4 read access token sent with every request.
+// TMDB accepts the API key for public v4 lists; private lists need the token.
+func (s *TMDBService) SetAccessToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accessToken = token
+}
+
+// GetMode returns the current TMDB mode
+func (s *TMDBService) GetMode() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.mode
+}
+
+// SetCacheStore replaces the response cache, e.g. with a disk or SQL store
+// shared by several processes
+func (s *TMDBService) SetCacheStore(store TMDBCacheStore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache = store
+}
+
+// SetCacheTTLs sets the cache lifetime per endpoint type
+func (s *TMDBService) SetCacheTTLs(ttls TMDBCacheTTLs) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cacheTTLs = ttls
+}
+
 // ClearCache clears the TMDB cache
 func (s *TMDBService) ClearCache() {
-	s.cache.Clear()
+	s.mu.RLock()
+	cache := s.cache
+	s.mu.RUnlock()
+	_ = cache.Clear()
 }
 
 // GetCacheStats returns cache statistics
@@ -156,17 +171,34 @@ func (s *TMDBService) GetCacheStats() map[string]interface{} {
 	defer s.mu.RUnlock()
 
 	return map[string]interface{}{
-		"entries": len(s.cache.data),
-		"ttl":     s.cache.ttl.String(),
+		"backend": s.cache.Backend(),
+		"entries": s.cache.Len(),
+		"ttl": map[string]string{
+			"show":    s.cacheTTLs.Show.String(),
+			"season":  s.cacheTTLs.Season.String(),
+			"search":  s.cacheTTLs.Search.String(),
+			"changes": s.cacheTTLs.Changes.String(),
+		},
 	}
 }
 
+// Language returns the primary language requested from TMDB
+func (s *TMDBService) Language() string {
+	return s.lang
+}
+
 // GetShowDetails fetches show details from TMDB
-func (s *TMDBService) GetShowDetails(tmdbID int) (*dto.TMDBShowResponse, error) {
+func (s *TMDBService) GetShowDetails(ctx context.Context, tmdbID int) (*dto.TMDBShowResponse, error) {
+	return s.GetShowDetailsInLanguage(ctx, tmdbID, s.lang)
+}
+
+// GetShowDetailsInLanguage fetches show details from TMDB in the given language
+func (s *TMDBService) GetShowDetailsInLanguage(ctx context.Context, tmdbID int, lang string) (*dto.TMDBShowResponse, error) {
 	url := fmt.Sprintf("%s/tv/%d", s.baseURL, tmdbID)
 
 	var response dto.TMDBShowResponse
-	if err := s.makeRequest(url, &response); err != nil {
+	params := map[string]string{"language": lang, "append_to_response": "external_ids"}
+	if err := s.makeRequest(ctx, tmdbEndpointShow, url, &response, params); err != nil {
 		return nil, err
 	}
 
@@ -174,21 +206,54 @@ func (s *TMDBService) GetShowDetails(tmdbID int) (*dto.TMDBShowResponse, error)
 }
 
 // GetSeasonEpisodes fetches episodes for a specific season
-func (s *TMDBService) GetSeasonEpisodes(tmdbID, seasonNumber int) (*dto.TMDBSeasonResponse, error) {
+func (s *TMDBService) GetSeasonEpisodes(ctx context.Context, tmdbID, seasonNumber int) (*dto.TMDBSeasonResponse, error) {
+	return s.GetSeasonEpisodesInLanguage(ctx, tmdbID, seasonNumber, s.lang)
+}
+
+// GetSeasonEpisodesInLanguage fetches episodes for a specific season in the given language
+func (s *TMDBService) GetSeasonEpisodesInLanguage(ctx context.Context, tmdbID, seasonNumber int, lang string) (*dto.TMDBSeasonResponse, error) {
 	url := fmt.Sprintf("%s/tv/%d/season/%d", s.baseURL, tmdbID, seasonNumber)
 
 	var response dto.TMDBSeasonResponse
-	if err := s.makeRequest(url, &response); err != nil {
+	if err := s.makeRequest(ctx, tmdbEndpointSeason, url, &response, map[string]string{"language": lang}); err != nil {
 		return nil, err
 	}
 
 	return &response, nil
 }
 
+// GetEpisodeExternalIDs fetches the IMDb and TVDB IDs of an episode
+func (s *TMDBService) GetEpisodeExternalIDs(ctx context.Context, tmdbID, seasonNumber, episodeNumber int) (*dto.TMDBExternalIDs, error) {
+	url := fmt.Sprintf("%s/tv/%d/season/%d/episode/%d/external_ids", s.baseURL, tmdbID, seasonNumber, episodeNumber)
+
+	var response dto.TMDBExternalIDs
+	if err := s.makeRequest(ctx, tmdbEndpointSeason, url, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// FindShowByExternalID resolves an IMDb or TVDB ID to a TMDB show.
+// source is ExternalSourceIMDb or ExternalSourceTVDB.
+func (s *TMDBService) FindShowByExternalID(ctx context.Context, source, externalID string) (*dto.TMDBShowResult, error) {
+	url := fmt.Sprintf("%s/find/%s", s.baseURL, externalID)
+
+	var response dto.TMDBFindResponse
+	if err := s.makeRequest(ctx, tmdbEndpointSearch, url, &response, map[string]string{"external_source": source}); err != nil {
+		return nil, err
+	}
+	if len(response.TVResults) == 0 {
+		return nil, fmt.Errorf("%w: %s %s", ErrExternalIDNotFound, source, externalID)
+	}
+
+	return &response.TVResults[0], nil
+}
+
 // GetAllSeasons fetches all seasons for a show
-func (s *TMDBService) GetAllSeasons(tmdbID int) ([]*dto.TMDBSeasonResponse, error) {
+func (s *TMDBService) GetAllSeasons(ctx context.Context, tmdbID int) ([]*dto.TMDBSeasonResponse, error) {
 	// First get show details to know how many seasons there are
-	show, err := s.GetShowDetails(tmdbID)
+	show, err := s.GetShowDetails(ctx, tmdbID)
 	if err != nil {
 		return nil, err
 	}
@@ -200,7 +265,7 @@ func (s *TMDBService) GetAllSeasons(tmdbID int) ([]*dto.TMDBSeasonResponse, erro
 			continue
 		}
 
-		season, err := s.GetSeasonEpisodes(tmdbID, seasonInfo.SeasonNumber)
+		season, err := s.GetSeasonEpisodes(ctx, tmdbID, seasonInfo.SeasonNumber)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get season %d: %w", seasonInfo.SeasonNumber, err)
 		}
@@ -211,13 +276,13 @@ func (s *TMDBService) GetAllSeasons(tmdbID int) ([]*dto.TMDBSeasonResponse, erro
 }
 
 // GetShowWithAllSeasons fetches show details and all seasons
-func (s *TMDBService) GetShowWithAllSeasons(tmdbID int) (*dto.TMDBShowResponse, []*dto.TMDBSeasonResponse, error) {
-	show, err := s.GetShowDetails(tmdbID)
+func (s *TMDBService) GetShowWithAllSeasons(ctx context.Context, tmdbID int) (*dto.TMDBShowResponse, []*dto.TMDBSeasonResponse, error) {
+	show, err := s.GetShowDetails(ctx, tmdbID)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	seasons, err := s.GetAllSeasons(tmdbID)
+	seasons, err := s.GetAllSeasons(ctx, tmdbID)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -226,11 +291,38 @@ func (s *TMDBService) GetShowWithAllSeasons(tmdbID int) (*dto.TMDBShowResponse,
 }
 
 // SearchShow searches for shows by query
-func (s *TMDBService) SearchShow(query string, page int) (*dto.TMDBSearchResponse, error) {
+func (s *TMDBService) SearchShow(ctx context.Context, query string, page int) (*dto.TMDBSearchResponse, error) {
 	url := fmt.Sprintf("%s/search/tv", s.baseURL)
 
 	var response dto.TMDBSearchResponse
-	if err := s.makeRequest(url, &response, map[string]string{
+	if err := s.makeRequest(ctx, tmdbEndpointSearch, url, &response, map[string]string{
+		"query": query,
+		"page":  fmt.Sprintf("%d", page),
+	}); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// GetMovieDetails fetches movie details from TMDB, including the release dates per country
+func (s *TMDBService) GetMovieDetails(ctx context.Context, tmdbID int) (*dto.TMDBMovieResponse, error) {
+	url := fmt.Sprintf("%s/movie/%d", s.baseURL, tmdbID)
+
+	var response dto.TMDBMovieResponse
+	if err := s.makeRequest(ctx, tmdbEndpointShow, url, &response, map[string]string{"append_to_response": "release_dates"}); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// SearchMovie searches for movies by query
+func (s *TMDBService) SearchMovie(ctx context.Context, query string, page int) (*dto.TMDBMovieSearchResponse, error) {
+	url := fmt.Sprintf("%s/search/movie", s.baseURL)
+
+	var response dto.TMDBMovieSearchResponse
+	if err := s.makeRequest(ctx, tmdbEndpointSearch, url, &response, map[string]string{
 		"query": query,
 		"page":  fmt.Sprintf("%d", page),
 	}); err != nil {
@@ -240,52 +332,227 @@ func (s *TMDBService) SearchShow(query string, page int) (*dto.TMDBSearchRespons
 	return &response, nil
 }
 
-// makeRequest makes an HTTP request to TMDB API with caching and retry logic
-func (s *TMDBService) makeRequest(url string, result interface{}, queryParams ...map[string]string) error {
+// TMDB TV lists used for discovery
+const (
+	TMDBListOnTheAir    = "on_the_air"
+	TMDBListAiringToday = "airing_today"
+	TMDBListTrending    = "trending"
+)
+
+// tmdbListPaths maps a TV list to its endpoint; trending uses the daily window
+var tmdbListPaths = map[string]string{
+	TMDBListOnTheAir:    "/tv/on_the_air",
+	TMDBListAiringToday: "/tv/airing_today",
+	TMDBListTrending:    "/trending/tv/day",
+}
+
+// GetShowList fetches one page of a TMDB TV list (TMDBListOnTheAir, TMDBListAiringToday or TMDBListTrending)
+func (s *TMDBService) GetShowList(ctx context.Context, list string, page int) (*dto.TMDBSearchResponse, error) {
+	path, ok := tmdbListPaths[list]
+	if !ok {
+		return nil, fmt.Errorf("unknown TMDB list: %s", list)
+	}
+
+	var response dto.TMDBSearchResponse
+	if err := s.makeRequest(ctx, tmdbEndpointSearch, s.baseURL+path, &response, map[string]string{
+		"page": fmt.Sprintf("%d", page),
+	}); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// v4BaseURL returns the base URL of TMDB API v4, derived from the v3 base URL
+func (s *TMDBService) v4BaseURL() string {
+	return strings.TrimSuffix(s.baseURL, "/3") + "/4"
+}
+
+// GetList fetches one page of a TMDB v4 list
+func (s *TMDBService) GetList(ctx context.Context, listID, page int) (*dto.TMDBListResponse, error) {
+	url := fmt.Sprintf("%s/list/%d", s.v4BaseURL(), listID)
+
+	var response dto.TMDBListResponse
+	if err := s.makeRequest(ctx, tmdbEndpointSearch, url, &response, map[string]string{
+		"page": fmt.Sprintf("%d", page),
+	}); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// TMDBChangesMaxRange is the longest period the TMDB changes endpoints accept
+const TMDBChangesMaxRange = 14 * 24 * time.Hour
+
+// GetChangedShowIDs fetches the IDs of all TV shows changed between start and end
+func (s *TMDBService) GetChangedShowIDs(ctx context.Context, start, end time.Time) ([]int, error) {
+	url := fmt.Sprintf("%s/tv/changes", s.baseURL)
+
+	var ids []int
+	for page := 1; ; page++ {
+		var response dto.TMDBChangesResponse
+		if err := s.makeRequest(ctx, tmdbEndpointChanges, url, &response, map[string]string{
+			"start_date": start.UTC().Format("2006-01-02"),
+			"end_date":   end.UTC().Format("2006-01-02"),
+			"page":       fmt.Sprintf("%d", page),
+		}); err != nil {
+			return nil, err
+		}
+
+		for _, item := range response.Results {
+			ids = append(ids, item.ID)
+		}
+
+		if page >= response.TotalPages {
+			break
+		}
+	}
+
+	return ids, nil
+}
+
+// GetShowChanges fetches the change history of a show between start and end
+func (s *TMDBService) GetShowChanges(ctx context.Context, tmdbID int, start, end time.Time) (*dto.TMDBShowChangesResponse, error) {
+	url := fmt.Sprintf("%s/tv/%d/changes", s.baseURL, tmdbID)
+
+	var response dto.TMDBShowChangesResponse
+	if err := s.makeRequest(ctx, tmdbEndpointChanges, url, &response, map[string]string{
+		"start_date": start.UTC().Format("2006-01-02"),
+		"end_date":   end.UTC().Format("2006-01-02"),
+	}); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// makeRequest makes an HTTP request to TMDB API with caching and retry logic.
+// Fresh cache entries are served directly; expired entries with an ETag or
+// Last-Modified are revalidated with a conditional request.
+// In replay mode the response comes from the fixtures only; in record mode
+// every response returned to the caller is also saved as a fixture.
+// The request and any backoff between retries are aborted once ctx is done.
+func (s *TMDBService) makeRequest(ctx context.Context, endpointType tmdbEndpoint, endpoint string, result interface{}, queryParams ...map[string]string) error {
 	s.mu.RLock()
 	timeout := s.timeout
 	maxRetries := s.maxRetries
+	limiter := s.limiter
+	cache := s.cache
+	ttl := s.cacheTTLs.forEndpoint(endpointType)
+	mode := s.mode
+	fixtures := s.fixtures
+	accessToken := s.accessToken
 	s.mu.RUnlock()
 
-	// Generate cache key
-	cacheKey := s.generateCacheKey(url, queryParams...)
+	// Build query
+	query := url.Values{}
+	query.Set("language", s.lang)
+	if len(queryParams) > 0 {
+		for key, value := range queryParams[0] {
+			query.Set(key, value)
+		}
+	}
 
-	// Try to get from cache
-	if cachedData, found := s.cache.Get(cacheKey); found {
-		if err := json.Unmarshal(cachedData, result); err == nil {
+	// The cache key leaves out the API key so caches can be shared
+	cacheKey := s.generateCacheKey(endpoint, query)
+
+	// Fixtures are keyed by the path below the base URL; v4 paths keep their /4 prefix
+	fixturePath := strings.TrimPrefix(endpoint, s.baseURL)
+	if v4Base := s.v4BaseURL(); strings.HasPrefix(endpoint, v4Base) {
+		fixturePath = "/4" + strings.TrimPrefix(endpoint, v4Base)
+	}
+	fixtureQuery := query.Encode()
+	if mode == TMDBModeReplay {
+		body, err := fixtures.Load(fixturePath, fixtureQuery)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(body, result); err != nil {
+			return fmt.Errorf("failed to parse fixture: %w", err)
+		}
+		return nil
+	}
+	record := func(body []byte) error {
+		if mode != TMDBModeRecord {
 			return nil
 		}
+		return fixtures.Save(fixturePath, fixtureQuery, body)
 	}
 
-	// Build request
+	// Try to get from cache
+	cached, found := cache.Get(cacheKey)
+	if found && ttl > 0 && cached.IsFresh(time.Now()) {
+		if err := json.Unmarshal(cached.Body, result); err == nil {
+			return record(cached.Body)
+		}
+	}
+
+	query.Set("api_key", s.apiKey)
+	requestURL := endpoint + "?" + query.Encode()
+	client := &http.Client{Timeout: timeout}
+
 	var lastErr error
 	for attempt := 0; attempt < maxRetries; attempt++ {
 		if attempt > 0 {
 			// Exponential backoff
-			time.Sleep(time.Duration(attempt) * time.Second)
+			if err := sleepContext(ctx, time.Duration(attempt)*time.Second); err != nil {
+				return err
+			}
 		}
 
-		request := s.client.Get(url).
-			Query("api_key=" + s.apiKey).
-			Query("language=" + s.lang)
+		// Every attempt that reaches TMDB counts against the quota
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
 
-		// Add custom query params if provided
-		if len(queryParams) > 0 {
-			for key, value := range queryParams[0] {
-				request = request.Query(fmt.Sprintf("%s=%s", key, value))
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+		if err != nil {
+			return fmt.Errorf("failed to build request: %w", err)
+		}
+		if accessToken != "" {
+			req.Header.Set("Authorization", "Bearer "+accessToken)
+		}
+		if found && cached.CanRevalidate() {
+			if cached.ETag != "" {
+				req.Header.Set("If-None-Match", cached.ETag)
+			}
+			if cached.LastModified != "" {
+				req.Header.Set("If-Modified-Since", cached.LastModified)
 			}
 		}
 
-		// Set timeout
-		request.Timeout(timeout)
-
 		// Send request
-		resp, body, errs := request.EndBytes()
-		if len(errs) > 0 {
-			lastErr = fmt.Errorf("request failed: %v", errs[0])
+		resp, err := client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			lastErr = fmt.Errorf("request failed: %v", err)
+			continue
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			lastErr = fmt.Errorf("failed to read response: %w", err)
 			continue
 		}
 
+		// Cached entry is still valid, extend it
+		if resp.StatusCode == http.StatusNotModified && found {
+			if err := json.Unmarshal(cached.Body, result); err != nil {
+				return fmt.Errorf("failed to parse cached response: %w", err)
+			}
+			if ttl > 0 {
+				cached.ExpiresAt = time.Now().Add(ttl)
+				_ = cache.Set(cached)
+			}
+			return record(cached.Body)
+		}
+
 		// Check status code
 		if resp.StatusCode != 200 {
 			var errResp dto.TMDBErrorResponse
@@ -308,24 +575,39 @@ func (s *TMDBService) makeRequest(url string, result interface{}, queryParams ..
 			continue
 		}
 
-		// Cache the successful response
-		s.cache.Set(cacheKey, body)
+		// Cache the successful response with its validators
+		if ttl > 0 {
+			_ = cache.Set(&models.TMDBCacheEntry{
+				Key:          cacheKey,
+				Body:         body,
+				ETag:         resp.Header.Get("ETag"),
+				LastModified: resp.Header.Get("Last-Modified"),
+				ExpiresAt:    time.Now().Add(ttl),
+			})
+		}
 
-		return nil
+		return record(body)
 	}
 
 	return lastErr
 }
 
-// generateCacheKey generates a unique cache key for the request
-func (s *TMDBService) generateCacheKey(url string, queryParams ...map[string]string) string {
-	key := url
-	if len(queryParams) > 0 {
-		for k, v := range queryParams[0] {
-			key += fmt.Sprintf(":%s=%s", k, v)
-		}
+// sleepContext sleeps for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	return key
+}
+
+// generateCacheKey generates a unique cache key for the request.
+// Query parameters are encoded in sorted order so the key is stable across processes.
+func (s *TMDBService) generateCacheKey(endpoint string, query url.Values) string {
+	return endpoint + "?" + query.Encode()
 }
 
 // GetImageURL returns the full image URL
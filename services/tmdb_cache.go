@@ -0,0 +1,391 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/repositories"
+)
+
+// TMDB cache backends
+const (
+	TMDBCacheBackendMemory = "memory"
+	TMDBCacheBackendDisk   = "disk"
+	TMDBCacheBackendSQL    = "sql"
+)
+
+// tmdbCacheRetention is how long expired entries are kept for revalidation
+const tmdbCacheRetention = 7 * 24 * time.Hour
+
+// TMDBCacheStore stores TMDB responses.
+// Expired entries are still returned by Get so they can be revalidated with TMDB.
+type TMDBCacheStore interface {
+	Get(key string) (*models.TMDBCacheEntry, bool)
+	Set(entry *models.TMDBCacheEntry) error
+	Clear() error
+	Len() int
+	Backend() string
+}
+
+// TMDBCacheTTLs holds the cache lifetime per TMDB endpoint type.
+// A non-positive TTL disables caching for that endpoint type.
+type TMDBCacheTTLs struct {
+	Show    time.Duration // /tv/{id}
+	Season  time.Duration // /tv/{id}/season/{n}
+	Search  time.Duration // /search/tv and the TV lists
+	Changes time.Duration // /tv/changes and /tv/{id}/changes
+}
+
+// DefaultTMDBCacheTTLs returns the default cache lifetimes
+func DefaultTMDBCacheTTLs() TMDBCacheTTLs {
+	return TMDBCacheTTLs{
+		Show:    5 * time.Minute,
+		Season:  5 * time.Minute,
+		Search:  5 * time.Minute,
+		Changes: 5 * time.Minute,
+	}
+}
+
+// tmdbEndpoint is the type of a TMDB endpoint, used to pick its cache TTL
+type tmdbEndpoint int
+
+const (
+	tmdbEndpointShow tmdbEndpoint = iota
+	tmdbEndpointSeason
+	tmdbEndpointSearch
+	tmdbEndpointChanges
+)
+
+// forEndpoint returns the TTL for an endpoint type
+func (t TMDBCacheTTLs) forEndpoint(endpoint tmdbEndpoint) time.Duration {
+	switch endpoint {
+	case tmdbEndpointSeason:
+		return t.Season
+	case tmdbEndpointSearch:
+		return t.Search
+	case tmdbEndpointChanges:
+		return t.Changes
+	default:
+		return t.Show
+	}
+}
+
+// NewTMDBCacheStore creates the cache store for a backend.
+// The disk backend stores entries under dataDir/tmdb_cache; the sql backend uses repo.
+func NewTMDBCacheStore(backend, dataDir string, repo repositories.TMDBCacheRepository) (TMDBCacheStore, error) {
+	switch backend {
+	case "", TMDBCacheBackendMemory:
+		return NewTMDBCache(tmdbCacheRetention), nil
+	case TMDBCacheBackendDisk:
+		return NewDiskTMDBCache(filepath.Join(dataDir, "tmdb_cache"))
+	case TMDBCacheBackendSQL:
+		if repo == nil {
+			return nil, fmt.Errorf("sql TMDB cache requires a repository")
+		}
+		return NewSQLTMDBCache(repo), nil
+	default:
+		return nil, fmt.Errorf("unknown TMDB cache backend: %s", backend)
+	}
+}
+
+// TMDBCache provides in-memory caching for TMDB responses
+type TMDBCache struct {
+	data      map[string]*models.TMDBCacheEntry
+	mu        sync.RWMutex
+	retention time.Duration
+}
+
+// NewTMDBCache creates a new in-memory cache.
+// Entries are dropped once they have been expired for longer than retention.
+func NewTMDBCache(retention time.Duration) *TMDBCache {
+	cache := &TMDBCache{
+		data:      make(map[string]*models.TMDBCacheEntry),
+		retention: retention,
+	}
+	// Start cleanup goroutine
+	go cache.cleanup()
+	return cache
+}
+
+// Get retrieves an entry from cache
+func (c *TMDBCache) Get(key string) (*models.TMDBCacheEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, exists := c.data[key]
+	if !exists {
+		return nil, false
+	}
+	copied := *entry
+	return &copied, true
+}
+
+// Set stores an entry in cache
+func (c *TMDBCache) Set(entry *models.TMDBCacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	copied := *entry
+	copied.UpdatedAt = time.Now()
+	c.data[entry.Key] = &copied
+	return nil
+}
+
+// cleanup removes entries past their retention
+func (c *TMDBCache) cleanup() {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		c.mu.Lock()
+		cutoff := time.Now().Add(-c.retention)
+		for key, entry := range c.data {
+			if entry.ExpiresAt.Before(cutoff) {
+				delete(c.data, key)
+			}
+		}
+		c.mu.Unlock()
+	}
+}
+
+// Clear clears all cache entries
+func (c *TMDBCache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data = make(map[string]*models.TMDBCacheEntry)
+	return nil
+}
+
+// Len returns the number of cache entries
+func (c *TMDBCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.data)
+}
+
+// Backend returns the backend name
+func (c *TMDBCache) Backend() string {
+	return TMDBCacheBackendMemory
+}
+
+// DiskTMDBCache stores TMDB responses as JSON files, one file per request
+type DiskTMDBCache struct {
+	dir string
+}
+
+// diskCacheFile is the on-disk format of a cache entry
+type diskCacheFile struct {
+	Key          string          `json:"key"`
+	Body         json.RawMessage `json:"body"`
+	ETag         string          `json:"etag,omitempty"`
+	LastModified string          `json:"last_modified,omitempty"`
+	ExpiresAt    time.Time       `json:"expires_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+// NewDiskTMDBCache creates a disk cache in dir, creating the directory if needed.
+// Entries expired for longer than the retention are pruned periodically.
+func NewDiskTMDBCache(dir string) (*DiskTMDBCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create TMDB cache directory: %w", err)
+	}
+	cache := &DiskTMDBCache{dir: dir}
+	go cache.cleanup()
+	return cache, nil
+}
+
+// cleanup removes entries past their retention
+func (c *DiskTMDBCache) cleanup() {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		_ = c.DeleteExpiredBefore(time.Now().Add(-tmdbCacheRetention))
+	}
+}
+
+// DeleteExpiredBefore removes the files of entries that expired before cutoff.
+// Files that cannot be decoded are removed too; they are never read back.
+func (c *DiskTMDBCache) DeleteExpiredBefore(cutoff time.Time) error {
+	files, err := filepath.Glob(filepath.Join(c.dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		var entry diskCacheFile
+		if err := json.Unmarshal(data, &entry); err == nil && !entry.ExpiresAt.Before(cutoff) {
+			continue
+		}
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// path returns the file path of a key
+func (c *DiskTMDBCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get reads an entry from disk
+func (c *DiskTMDBCache) Get(key string) (*models.TMDBCacheEntry, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var file diskCacheFile
+	if err := json.Unmarshal(data, &file); err != nil || file.Key != key {
+		return nil, false
+	}
+
+	return &models.TMDBCacheEntry{
+		Key:          file.Key,
+		Body:         file.Body,
+		ETag:         file.ETag,
+		LastModified: file.LastModified,
+		ExpiresAt:    file.ExpiresAt,
+		UpdatedAt:    file.UpdatedAt,
+	}, true
+}
+
+// Set writes an entry to disk; the file is replaced atomically so
+// concurrent readers in other processes never see a partial entry
+func (c *DiskTMDBCache) Set(entry *models.TMDBCacheEntry) error {
+	if !json.Valid(entry.Body) {
+		return fmt.Errorf("TMDB cache body is not valid JSON")
+	}
+
+	data, err := json.Marshal(diskCacheFile{
+		Key:          entry.Key,
+		Body:         entry.Body,
+		ETag:         entry.ETag,
+		LastModified: entry.LastModified,
+		ExpiresAt:    entry.ExpiresAt,
+		UpdatedAt:    time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(entry.Key))
+}
+
+// Clear removes all cached files
+func (c *DiskTMDBCache) Clear() error {
+	files, err := filepath.Glob(filepath.Join(c.dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Len returns the number of cached files
+func (c *DiskTMDBCache) Len() int {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			count++
+		}
+	}
+	return count
+}
+
+// Backend returns the backend name
+func (c *DiskTMDBCache) Backend() string {
+	return TMDBCacheBackendDisk
+}
+
+// SQLTMDBCache stores TMDB responses in the tmdb_cache table
+type SQLTMDBCache struct {
+	repo repositories.TMDBCacheRepository
+}
+
+// NewSQLTMDBCache creates a cache backed by the database.
+// Entries expired for longer than the retention are pruned periodically.
+func NewSQLTMDBCache(repo repositories.TMDBCacheRepository) *SQLTMDBCache {
+	cache := &SQLTMDBCache{repo: repo}
+	go cache.cleanup()
+	return cache
+}
+
+// cleanup removes entries past their retention
+func (c *SQLTMDBCache) cleanup() {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		_ = c.repo.DeleteExpiredBefore(time.Now().Add(-tmdbCacheRetention))
+	}
+}
+
+// Get retrieves an entry from the database
+func (c *SQLTMDBCache) Get(key string) (*models.TMDBCacheEntry, bool) {
+	entry, err := c.repo.Get(key)
+	if err != nil {
+		return nil, false
+	}
+	return entry, true
+}
+
+// Set stores an entry in the database
+func (c *SQLTMDBCache) Set(entry *models.TMDBCacheEntry) error {
+	copied := *entry
+	copied.UpdatedAt = time.Now()
+	return c.repo.Upsert(&copied)
+}
+
+// Clear removes all entries from the database
+func (c *SQLTMDBCache) Clear() error {
+	return c.repo.DeleteAll()
+}
+
+// Len returns the number of entries in the database
+func (c *SQLTMDBCache) Len() int {
+	count, err := c.repo.Count()
+	if err != nil {
+		return 0
+	}
+	return int(count)
+}
+
+// Backend returns the backend name
+func (c *SQLTMDBCache) Backend() string {
+	return TMDBCacheBackendSQL
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/xc9973/go-tmdb-crawler/models"
+)
+
+func testCacheStoreRoundTrip(t *testing.T, store TMDBCacheStore) {
+	t.Helper()
+
+	if _, found := store.Get("/tv/1"); found {
+		t.Fatal("Expected empty cache")
+	}
+
+	entry := &models.TMDBCacheEntry{
+		Key:       "/tv/1",
+		Body:      []byte(`{"id":1}`),
+		ETag:      `"abc"`,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	if err := store.Set(entry); err != nil {
+		t.Fatalf("Failed to set entry: %v", err)
+	}
+
+	got, found := store.Get("/tv/1")
+	if !found {
+		t.Fatal("Expected cached entry")
+	}
+	if string(got.Body) != `{"id":1}` || got.ETag != `"abc"` {
+		t.Errorf("Unexpected entry: body=%s etag=%s", got.Body, got.ETag)
+	}
+	if !got.IsFresh(time.Now()) {
+		t.Error("Expected entry to be fresh")
+	}
+	if store.Len() != 1 {
+		t.Errorf("Expected 1 entry, got %d", store.Len())
+	}
+
+	if err := store.Clear(); err != nil {
+		t.Fatalf("Failed to clear cache: %v", err)
+	}
+	if store.Len() != 0 {
+		t.Errorf("Expected empty cache after clear, got %d", store.Len())
+	}
+}
+
+func TestTMDBCache_Memory(t *testing.T) {
+	testCacheStoreRoundTrip(t, NewTMDBCache(time.Hour))
+}
+
+func TestTMDBCache_Disk(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewTMDBCacheStore(TMDBCacheBackendDisk, dir, nil)
+	if err != nil {
+		t.Fatalf("Failed to create disk cache: %v", err)
+	}
+	if store.Backend() != TMDBCacheBackendDisk {
+		t.Errorf("Expected disk backend, got %s", store.Backend())
+	}
+	testCacheStoreRoundTrip(t, store)
+
+	// Entries survive a new store on the same directory
+	entry := &models.TMDBCacheEntry{Key: "/tv/2", Body: []byte(`{"id":2}`), ExpiresAt: time.Now().Add(time.Minute)}
+	if err := store.Set(entry); err != nil {
+		t.Fatalf("Failed to set entry: %v", err)
+	}
+	reopened, err := NewDiskTMDBCache(filepath.Join(dir, "tmdb_cache"))
+	if err != nil {
+		t.Fatalf("Failed to reopen disk cache: %v", err)
+	}
+	if _, found := reopened.Get("/tv/2"); !found {
+		t.Error("Expected entry to persist on disk")
+	}
+
+	// Only entries expired for longer than the retention are pruned
+	expired := &models.TMDBCacheEntry{Key: "/tv/3", Body: []byte(`{"id":3}`), ExpiresAt: time.Now().Add(-tmdbCacheRetention - time.Hour)}
+	if err := store.Set(expired); err != nil {
+		t.Fatalf("Failed to set entry: %v", err)
+	}
+	if err := reopened.DeleteExpiredBefore(time.Now().Add(-tmdbCacheRetention)); err != nil {
+		t.Fatalf("DeleteExpiredBefore failed: %v", err)
+	}
+	if _, found := reopened.Get("/tv/3"); found {
+		t.Error("Expected the entry past its retention to be pruned")
+	}
+	if _, found := reopened.Get("/tv/2"); !found || reopened.Len() != 1 {
+		t.Errorf("Expected the fresh entry to be kept, got %d entries", reopened.Len())
+	}
+}
+
+func TestNewTMDBCacheStore_UnknownBackend(t *testing.T) {
+	if _, err := NewTMDBCacheStore("redis", t.TempDir(), nil); err == nil {
+		t.Error("Expected error for unknown backend")
+	}
+	if _, err := NewTMDBCacheStore(TMDBCacheBackendSQL, t.TempDir(), nil); err == nil {
+		t.Error("Expected error for sql backend without repository")
+	}
+}
+
+func TestTMDBService_CacheRevalidation(t *testing.T) {
+	var requests, notModified int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"id": 42, "name": "Cached Show"})
+	}))
+	defer server.Close()
+
+	tmdb := NewTMDBServiceWithCache("test-key", server.URL, "en-US", time.Hour)
+
+	ctx := context.Background()
+	show, err := tmdb.GetShowDetails(ctx, 42)
+	if err != nil {
+		t.Fatalf("First request failed: %v", err)
+	}
+	if show.Name != "Cached Show" {
+		t.Errorf("Expected 'Cached Show', got %s", show.Name)
+	}
+
+	// A fresh entry is served without asking TMDB
+	if _, err := tmdb.GetShowDetails(ctx, 42); err != nil {
+		t.Fatalf("Cached request failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("Expected 1 request while fresh, got %d", got)
+	}
+
+	// Expire the entry; the next request revalidates with the ETag
+	entry, found := tmdb.cache.Get(server.URL + "/tv/42?append_to_response=external_ids&language=en-US")
+	if !found {
+		t.Fatal("Expected cache entry for the show")
+	}
+	entry.ExpiresAt = time.Now().Add(-time.Minute)
+	_ = tmdb.cache.Set(entry)
+
+	show, err = tmdb.GetShowDetails(ctx, 42)
+	if err != nil {
+		t.Fatalf("Revalidated request failed: %v", err)
+	}
+	if show.Name != "Cached Show" {
+		t.Errorf("Expected cached body after 304, got %s", show.Name)
+	}
+	if got := atomic.LoadInt32(&notModified); got != 1 {
+		t.Errorf("Expected 1 conditional request answered with 304, got %d", got)
+	}
+}
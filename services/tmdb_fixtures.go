@@ -0,0 +1,76 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TMDB modes
+const (
+	// TMDBModeLive talks to the TMDB API
+	TMDBModeLive = "live"
+	// TMDBModeRecord talks to the TMDB API and saves every response as a fixture
+	TMDBModeRecord = "record"
+	// TMDBModeReplay serves responses from fixtures without network access
+	TMDBModeReplay = "replay"
+)
+
+// ErrTMDBFixtureNotFound is returned in replay mode when a request has no fixture
+var ErrTMDBFixtureNotFound = errors.New("TMDB fixture not found")
+
+// TMDBFixtures stores raw TMDB responses as JSON files.
+// A request for /tv/42 with language=zh-CN is stored as
+// tv/42__language=zh-CN.json. When replaying, tv/42.json is used as a
+// fallback for any query, so hand-written fixtures can skip the query part.
+type TMDBFixtures struct {
+	dir string
+}
+
+// NewTMDBFixtures creates a fixture store in dir
+func NewTMDBFixtures(dir string) *TMDBFixtures {
+	return &TMDBFixtures{dir: dir}
+}
+
+// Dir returns the fixture directory
+func (f *TMDBFixtures) Dir() string {
+	return f.dir
+}
+
+// path returns the fixture file of an endpoint path and encoded query.
+// url.Values.Encode escapes "/" so the query never adds directories.
+func (f *TMDBFixtures) path(endpointPath, query string) string {
+	name := filepath.Join(f.dir, filepath.FromSlash(strings.Trim(endpointPath, "/")))
+	if query != "" {
+		name += "__" + query
+	}
+	return name + ".json"
+}
+
+// Load reads the fixture for a request
+func (f *TMDBFixtures) Load(endpointPath, query string) ([]byte, error) {
+	for _, name := range []string{f.path(endpointPath, query), f.path(endpointPath, "")} {
+		body, err := os.ReadFile(name)
+		if err == nil {
+			return body, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read TMDB fixture: %w", err)
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrTMDBFixtureNotFound, f.path(endpointPath, query))
+}
+
+// Save writes the fixture for a request, replacing any previous recording
+func (f *TMDBFixtures) Save(endpointPath, query string, body []byte) error {
+	name := f.path(endpointPath, query)
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	if err := os.WriteFile(name, body, 0644); err != nil {
+		return fmt.Errorf("failed to write TMDB fixture: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xc9973/go-tmdb-crawler/models"
+)
+
+func TestTMDBService_RecordAndReplay(t *testing.T) {
+	fake := newFakeTMDB()
+	fake.addShow(501, 2, 3)
+	crawler, db := setupCrawlerTest(t, fake)
+	tmdb := crawler.GetTMDBService()
+	fixtureDir := t.TempDir()
+
+	if err := tmdb.SetMode(TMDBModeRecord, fixtureDir); err != nil {
+		t.Fatalf("Failed to enable record mode: %v", err)
+	}
+	if err := crawler.CrawlShow(context.Background(), 501); err != nil {
+		t.Fatalf("Recorded crawl failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(fixtureDir, "tv", "501__append_to_response=external_ids&language=zh-CN.json")); err != nil {
+		t.Fatalf("Expected show fixture to be recorded: %v", err)
+	}
+
+	// Replay serves the recorded responses without touching the server
+	if err := tmdb.SetMode(TMDBModeReplay, fixtureDir); err != nil {
+		t.Fatalf("Failed to enable replay mode: %v", err)
+	}
+	tmdb.ClearCache()
+	fake.mu.Lock()
+	requests := fake.requests
+	fake.mu.Unlock()
+	db.Where("1 = 1").Delete(&models.Episode{})
+
+	if err := crawler.CrawlShow(context.Background(), 501); err != nil {
+		t.Fatalf("Replayed crawl failed: %v", err)
+	}
+	fake.mu.Lock()
+	if fake.requests != requests {
+		t.Errorf("Expected no requests in replay mode, got %d", fake.requests-requests)
+	}
+	fake.mu.Unlock()
+
+	var episodes int64
+	db.Model(&models.Episode{}).Count(&episodes)
+	if episodes != 6 {
+		t.Errorf("Expected 6 replayed episodes, got %d", episodes)
+	}
+
+	// Requests that were never recorded fail instead of going to the network
+	if _, err := tmdb.GetShowDetails(context.Background(), 999); !errors.Is(err, ErrTMDBFixtureNotFound) {
+		t.Errorf("Expected ErrTMDBFixtureNotFound, got %v", err)
+	}
+}
+
+func TestTMDBFixtures_FallbackWithoutQuery(t *testing.T) {
+	fixtures := NewTMDBFixtures(t.TempDir())
+	if err := fixtures.Save("/tv/changes", "", []byte(`{"results":[]}`)); err != nil {
+		t.Fatalf("Failed to save fixture: %v", err)
+	}
+
+	body, err := fixtures.Load("/tv/changes", "end_date=2026-10-16&language=zh-CN&page=1&start_date=2026-10-15")
+	if err != nil {
+		t.Fatalf("Expected fallback fixture, got %v", err)
+	}
+	if string(body) != `{"results":[]}` {
+		t.Errorf("Unexpected fixture body: %s", body)
+	}
+}
+
+func TestTMDBService_SetMode_Invalid(t *testing.T) {
+	tmdb := NewTMDBService("", "http://localhost", "zh-CN")
+	if err := tmdb.SetMode("offline", t.TempDir()); err == nil {
+		t.Error("Expected error for unknown mode")
+	}
+	if err := tmdb.SetMode(TMDBModeReplay, ""); err == nil {
+		t.Error("Expected error for replay mode without fixture directory")
+	}
+	if tmdb.GetMode() != TMDBModeLive {
+		t.Errorf("Expected mode to stay live, got %s", tmdb.GetMode())
+	}
+}
@@ -0,0 +1,360 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/repositories"
+	"github.com/xc9973/go-tmdb-crawler/utils"
+)
+
+// ErrInvalidWebhook is returned for webhook endpoints that fail validation
+var ErrInvalidWebhook = errors.New("invalid webhook")
+
+// EventEmitter receives the events of crawls, publishes and corrections
+type EventEmitter interface {
+	Emit(event string, data interface{})
+}
+
+// Webhook delivery defaults
+const (
+	DefaultWebhookMaxAttempts  = 5
+	DefaultWebhookRetryBackoff = 30 * time.Second
+)
+
+// Headers of every webhook delivery
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// WebhookPayload is the JSON body of every webhook delivery
+type WebhookPayload struct {
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// EpisodeEvent is the data of episode.added and episode.air_date_changed.
+// OldValue and NewValue are the air dates (YYYY-MM-DD); for added episodes NewValue is the name.
+type EpisodeEvent struct {
+	ShowID        uint   `json:"show_id"`
+	TmdbID        int    `json:"tmdb_id"`
+	ShowName      string `json:"show_name"`
+	SeasonNumber  int    `json:"season_number"`
+	EpisodeNumber int    `json:"episode_number"`
+	OldValue      string `json:"old_value,omitempty"`
+	NewValue      string `json:"new_value,omitempty"`
+}
+
+// ShowStatusEvent is the data of show.status_changed
+type ShowStatusEvent struct {
+	ShowID    uint   `json:"show_id"`
+	TmdbID    int    `json:"tmdb_id"`
+	ShowName  string `json:"show_name"`
+	OldStatus string `json:"old_status"`
+	NewStatus string `json:"new_status"`
+}
+
+// CrawlFailedEvent is the data of crawl.failed
+type CrawlFailedEvent struct {
+	CrawlLogID uint   `json:"crawl_log_id,omitempty"`
+	ShowID     *uint  `json:"show_id,omitempty"`
+	TmdbID     int    `json:"tmdb_id"`
+	Action     string `json:"action"`
+	Error      string `json:"error"`
+}
+
+// PublishEvent is the data of publish.completed
+type PublishEvent struct {
+	Title         string          `json:"title"`
+	Slug          string          `json:"slug"`
+	URL           string          `json:"url,omitempty"`
+	ShowsCount    int             `json:"shows_count"`
+	EpisodesCount int             `json:"episodes_count"`
+	MoviesCount   int             `json:"movies_count"`
+	Targets       []*TargetResult `json:"targets"`
+}
+
+// WebhookService delivers events to the registered webhook endpoints.
+// Deliveries run in the background and every one is recorded. Failed
+// deliveries are retried with exponential backoff while the process runs;
+// pending retries are dropped on restart.
+type WebhookService struct {
+	repo   repositories.WebhookRepository
+	client *http.Client
+	logger *utils.Logger
+
+	maxAttempts int
+	backoff     time.Duration
+	mu          sync.RWMutex
+
+	// ctx is cancelled by Close to stop the pending retries
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewWebhookService creates a new webhook service instance
+func NewWebhookService(repo repositories.WebhookRepository, logger *utils.Logger) *WebhookService {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WebhookService{
+		repo: repo,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		logger:      logger,
+		maxAttempts: DefaultWebhookMaxAttempts,
+		backoff:     DefaultWebhookRetryBackoff,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// SetRetry sets how often a delivery is attempted and the wait before the
+// first retry; the wait doubles with every further retry
+func (s *WebhookService) SetRetry(maxAttempts int, backoff time.Duration) {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxAttempts = maxAttempts
+	s.backoff = backoff
+}
+
+// retryPolicy returns the attempt limit and the first retry wait
+func (s *WebhookService) retryPolicy() (int, time.Duration) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.maxAttempts, s.backoff
+}
+
+// Emit delivers event to every enabled endpoint subscribed to it.
+// It returns at once; data is encoded before Emit returns.
+func (s *WebhookService) Emit(event string, data interface{}) {
+	body, err := json.Marshal(WebhookPayload{Event: event, CreatedAt: time.Now(), Data: data})
+	if err != nil {
+		s.logger.Warnf("Failed to encode webhook event %s: %v", event, err)
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.dispatch(event, body)
+	}()
+}
+
+// dispatch records and sends a delivery to every endpoint subscribed to event
+func (s *WebhookService) dispatch(event string, body []byte) {
+	repo := s.repo.WithContext(s.ctx)
+	endpoints, err := repo.ListEnabledEndpoints()
+	if err != nil {
+		s.logger.Warnf("Failed to load webhook endpoints for %s: %v", event, err)
+		return
+	}
+
+	for _, endpoint := range endpoints {
+		if !endpoint.Subscribes(event) {
+			continue
+		}
+		delivery := &models.WebhookDelivery{
+			EndpointID: endpoint.ID,
+			Event:      event,
+			Payload:    string(body),
+		}
+		if err := repo.CreateDelivery(delivery); err != nil {
+			s.logger.Warnf("Failed to record webhook delivery of %s to %s: %v", event, endpoint.Name, err)
+			continue
+		}
+
+		s.wg.Add(1)
+		go func(endpoint *models.WebhookEndpoint) {
+			defer s.wg.Done()
+			s.deliver(s.ctx, endpoint, delivery)
+		}(endpoint)
+	}
+}
+
+// deliver sends a delivery until it succeeds, fails permanently or runs out of attempts
+func (s *WebhookService) deliver(ctx context.Context, endpoint *models.WebhookEndpoint, delivery *models.WebhookDelivery) {
+	maxAttempts, backoff := s.retryPolicy()
+	for {
+		retry := s.attempt(ctx, endpoint, delivery)
+		if delivery.Success {
+			return
+		}
+		if !retry || delivery.Attempts >= maxAttempts {
+			s.logger.Warnf("Webhook delivery %d of %s to %s failed after %d attempts: %s",
+				delivery.ID, delivery.Event, endpoint.Name, delivery.Attempts, delivery.Error)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff << (delivery.Attempts - 1)):
+		}
+	}
+}
+
+// attempt sends a delivery once and records the outcome.
+// It reports whether a failure is worth retrying.
+func (s *WebhookService) attempt(ctx context.Context, endpoint *models.WebhookEndpoint, delivery *models.WebhookDelivery) bool {
+	startTime := time.Now()
+	statusCode, err := s.send(ctx, endpoint, delivery)
+
+	delivery.Attempts++
+	delivery.StatusCode = statusCode
+	delivery.DurationMs = int(time.Since(startTime).Milliseconds())
+	delivery.Success = err == nil
+	delivery.Error = ""
+	if err != nil {
+		delivery.Error = err.Error()
+	} else {
+		now := time.Now()
+		delivery.DeliveredAt = &now
+	}
+	// The record is written without ctx so the outcome of an interrupted attempt is kept
+	_ = s.repo.UpdateDelivery(delivery)
+
+	// Other client errors will not go away by sending the same request again
+	return statusCode == 0 || statusCode >= 500 ||
+		statusCode == http.StatusRequestTimeout || statusCode == http.StatusTooManyRequests
+}
+
+// send posts the payload of a delivery, signed with the endpoint secret.
+// Any status other than 2xx is an error.
+func (s *WebhookService) send(ctx context.Context, endpoint *models.WebhookEndpoint, delivery *models.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-tmdb-crawler")
+	req.Header.Set(WebhookEventHeader, delivery.Event)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(endpoint.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// SignWebhookPayload returns the signature header value of a delivery:
+// "sha256=" and the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with secret
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// CreateEndpoint registers an endpoint for events (all events when empty).
+// An empty secret is replaced by a random one.
+func (s *WebhookService) CreateEndpoint(ctx context.Context, name, url string, events []string, secret string) (*models.WebhookEndpoint, error) {
+	if secret == "" {
+		var err error
+		if secret, err = generateWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	endpoint := &models.WebhookEndpoint{
+		Name:    strings.TrimSpace(name),
+		URL:     strings.TrimSpace(url),
+		Secret:  secret,
+		Enabled: true,
+	}
+	endpoint.SetEventList(events)
+	if err := endpoint.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWebhook, err)
+	}
+	if err := s.repo.WithContext(ctx).CreateEndpoint(endpoint); err != nil {
+		return nil, fmt.Errorf("failed to create webhook endpoint: %w", err)
+	}
+	return endpoint, nil
+}
+
+// RotateSecret replaces the secret of an endpoint with a random one
+func (s *WebhookService) RotateSecret(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return err
+	}
+	endpoint.Secret = secret
+	return s.repo.WithContext(ctx).UpdateEndpoint(endpoint)
+}
+
+// Ping sends a ping event to an endpoint once, whether or not it is enabled,
+// and returns the recorded delivery
+func (s *WebhookService) Ping(ctx context.Context, endpoint *models.WebhookEndpoint) (*models.WebhookDelivery, error) {
+	body, err := json.Marshal(WebhookPayload{
+		Event:     models.WebhookEventPing,
+		CreatedAt: time.Now(),
+		Data:      map[string]interface{}{"endpoint_id": endpoint.ID, "events": endpoint.EventList()},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode ping: %w", err)
+	}
+
+	delivery := &models.WebhookDelivery{
+		EndpointID: endpoint.ID,
+		Event:      models.WebhookEventPing,
+		Payload:    string(body),
+	}
+	if err := s.repo.WithContext(ctx).CreateDelivery(delivery); err != nil {
+		return nil, fmt.Errorf("failed to record webhook delivery: %w", err)
+	}
+	s.attempt(ctx, endpoint, delivery)
+	return delivery, nil
+}
+
+// Wait waits until the emitted events are delivered or given up on
+func (s *WebhookService) Wait() {
+	s.wg.Wait()
+}
+
+// Close stops the pending retries and waits for the running deliveries
+func (s *WebhookService) Close() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// generateWebhookSecret returns a random secret for signing deliveries
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xc9973/go-tmdb-crawler/dto"
+	"github.com/xc9973/go-tmdb-crawler/models"
+	"github.com/xc9973/go-tmdb-crawler/repositories"
+	"github.com/xc9973/go-tmdb-crawler/utils"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// recordingEmitter keeps the emitted events in order
+type recordingEmitter struct {
+	mu     sync.Mutex
+	events []string
+	data   []interface{}
+}
+
+func (e *recordingEmitter) Emit(event string, data interface{}) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.events = append(e.events, event)
+	e.data = append(e.data, data)
+}
+
+func (e *recordingEmitter) find(event string) []interface{} {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var found []interface{}
+	for i, name := range e.events {
+		if name == event {
+			found = append(found, e.data[i])
+		}
+	}
+	return found
+}
+
+func TestCrawlerService_EmitsEvents(t *testing.T) {
+	fake := newFakeTMDB()
+	fake.addShow(901, 1, 2)
+	fake.addShow(902, 1, 1)
+	crawler, _ := setupCrawlerTest(t, fake)
+	events := &recordingEmitter{}
+	crawler.SetEventEmitter(events)
+
+	// The first crawl of a show has no history to compare against
+	crawler.BatchCrawl(context.Background(), []int{901, 902})
+	if len(events.events) != 0 {
+		t.Fatalf("Expected no events for new shows, got %v", events.events)
+	}
+
+	// 901 ends, gains an episode and moves another; 902 disappears from TMDB
+	fake.mu.Lock()
+	fake.shows[901].Status = "Ended"
+	season := fake.seasons["901/1"]
+	season.Episodes[1].AirDate = "2024-02-01"
+	season.Episodes = append(season.Episodes, dto.TMDBEpisode{SeasonNumber: 1, EpisodeNumber: 3, Name: "Finale", AirDate: "2024-02-08"})
+	delete(fake.shows, 902)
+	fake.mu.Unlock()
+	crawler.GetTMDBService().ClearCache()
+
+	if _, err := crawler.RefreshAll(context.Background()); err != nil {
+		t.Fatalf("RefreshAll failed: %v", err)
+	}
+
+	added := events.find(models.WebhookEventEpisodeAdded)
+	if len(added) != 1 || added[0].(EpisodeEvent).EpisodeNumber != 3 || added[0].(EpisodeEvent).ShowName != "Show 901" {
+		t.Errorf("Unexpected episode.added events: %+v", added)
+	}
+	moved := events.find(models.WebhookEventEpisodeAirDateChanged)
+	if len(moved) != 1 || moved[0].(EpisodeEvent).OldValue != "2024-01-02" || moved[0].(EpisodeEvent).NewValue != "2024-02-01" {
+		t.Errorf("Unexpected episode.air_date_changed events: %+v", moved)
+	}
+	status := events.find(models.WebhookEventShowStatusChanged)
+	if len(status) != 1 || status[0].(ShowStatusEvent).OldStatus != "Returning Series" || status[0].(ShowStatusEvent).NewStatus != "Ended" {
+		t.Errorf("Unexpected show.status_changed events: %+v", status)
+	}
+	failed := events.find(models.WebhookEventCrawlFailed)
+	if len(failed) != 1 || failed[0].(CrawlFailedEvent).TmdbID != 902 || failed[0].(CrawlFailedEvent).Error == "" {
+		t.Errorf("Unexpected crawl.failed events: %+v", failed)
+	}
+}
+
+func setupWebhookTest(t *testing.T) (*WebhookService, repositories.WebhookRepository) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "webhook.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.WebhookEndpoint{}, &models.WebhookDelivery{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	repo := repositories.NewWebhookRepository(db)
+	webhooks := NewWebhookService(repo, utils.NewLogger("info", ""))
+	webhooks.SetRetry(3, time.Millisecond)
+	t.Cleanup(webhooks.Close)
+	return webhooks, repo
+}
+
+func TestWebhookService_DeliversSignedEvents(t *testing.T) {
+	var mu sync.Mutex
+	var requests []*http.Request
+	var bodies [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r)
+		bodies = append(bodies, body)
+		// The first attempt fails, the retry succeeds
+		if len(requests) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	webhooks, repo := setupWebhookTest(t)
+	ctx := context.Background()
+	endpoint, err := webhooks.CreateEndpoint(ctx, "Bot", server.URL, []string{models.WebhookEventEpisodeAdded}, "")
+	if err != nil {
+		t.Fatalf("CreateEndpoint failed: %v", err)
+	}
+	if len(endpoint.Secret) < 32 {
+		t.Errorf("Expected a generated secret, got %q", endpoint.Secret)
+	}
+
+	webhooks.Emit(models.WebhookEventCrawlFailed, CrawlFailedEvent{TmdbID: 1})
+	webhooks.Emit(models.WebhookEventEpisodeAdded, EpisodeEvent{ShowID: 1, ShowName: "Show", EpisodeNumber: 3})
+	webhooks.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requests) != 2 {
+		t.Fatalf("Expected one failed attempt and one retry of the subscribed event, got %d requests", len(requests))
+	}
+	req, body := requests[1], bodies[1]
+	timestamp, _ := strconv.ParseInt(req.Header.Get(WebhookTimestampHeader), 10, 64)
+	if want := SignWebhookPayload(endpoint.Secret, timestamp, body); req.Header.Get(WebhookSignatureHeader) != want {
+		t.Errorf("Expected signature %s, got %s", want, req.Header.Get(WebhookSignatureHeader))
+	}
+	if req.Header.Get(WebhookEventHeader) != models.WebhookEventEpisodeAdded {
+		t.Errorf("Unexpected event header %q", req.Header.Get(WebhookEventHeader))
+	}
+
+	var payload struct {
+		Event string       `json:"event"`
+		Data  EpisodeEvent `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("Payload is not JSON: %v", err)
+	}
+	if payload.Event != models.WebhookEventEpisodeAdded || payload.Data.EpisodeNumber != 3 {
+		t.Errorf("Unexpected payload %s", body)
+	}
+
+	deliveries, err := repo.ListDeliveries(endpoint.ID, 10)
+	if err != nil {
+		t.Fatalf("ListDeliveries failed: %v", err)
+	}
+	if len(deliveries) != 1 || !deliveries[0].Success || deliveries[0].Attempts != 2 || deliveries[0].DeliveredAt == nil {
+		t.Errorf("Expected one delivery that succeeded on the second attempt, got %+v", deliveries)
+	}
+}
+
+func TestWebhookService_StopsRetrying(t *testing.T) {
+	status := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	webhooks, repo := setupWebhookTest(t)
+	ctx := context.Background()
+	endpoint, err := webhooks.CreateEndpoint(ctx, "Broken", server.URL, nil, "secret")
+	if err != nil {
+		t.Fatalf("CreateEndpoint failed: %v", err)
+	}
+
+	// Server errors are retried up to the attempt limit
+	webhooks.Emit(models.WebhookEventPublishCompleted, PublishEvent{Title: "今日更新"})
+	webhooks.Wait()
+
+	// Other client errors are not retried
+	status = http.StatusGone
+	webhooks.Emit(models.WebhookEventPublishCompleted, PublishEvent{Title: "今日更新"})
+	webhooks.Wait()
+
+	deliveries, err := repo.ListDeliveries(endpoint.ID, 10)
+	if err != nil {
+		t.Fatalf("ListDeliveries failed: %v", err)
+	}
+	if len(deliveries) != 2 {
+		t.Fatalf("Expected 2 deliveries, got %d", len(deliveries))
+	}
+	if d := deliveries[1]; d.Success || d.Attempts != 3 || d.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Expected 3 failed attempts, got %+v", d)
+	}
+	if d := deliveries[0]; d.Success || d.Attempts != 1 || d.StatusCode != http.StatusGone {
+		t.Errorf("Expected a single attempt, got %+v", d)
+	}
+
+	if _, err := webhooks.CreateEndpoint(ctx, "Bad", "ftp://example.com", nil, ""); err == nil {
+		t.Error("Expected an error for a non-HTTP URL")
+	}
+	if _, err := webhooks.CreateEndpoint(ctx, "Bad", server.URL, []string{"episode.deleted"}, ""); err == nil {
+		t.Error("Expected an error for an unknown event")
+	}
+}
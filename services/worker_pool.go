@@ -0,0 +1,36 @@
+package services
+
+import "sync"
+
+// runWorkerPool calls fn for every index in [0, n) using at most workers
+// goroutines. It returns once all calls have finished. Callers that need
+// ordered results should write into a slice indexed by i.
+func runWorkerPool(workers, n int, fn func(i int)) {
+	if n <= 0 {
+		return
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
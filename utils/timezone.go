@@ -51,6 +51,16 @@ func (h *TimezoneHelper) DateRange(startDate, endDate time.Time) (time.Time, tim
 	return start, end
 }
 
+// DayRange returns the start and end of the calendar days startDate through endDate
+// in the configured timezone. The dates are taken as written (year, month and day in
+// their own location), so dates parsed from "2006-01-02" keep their day in any timezone.
+// The range is [start, end) - start is inclusive, end is exclusive
+func (h *TimezoneHelper) DayRange(startDate, endDate time.Time) (time.Time, time.Time) {
+	start := time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, h.location)
+	end := time.Date(endDate.Year(), endDate.Month(), endDate.Day()+1, 0, 0, 0, 0, h.location)
+	return start, end
+}
+
 // IsToday checks if the given time is today in the configured timezone
 func (h *TimezoneHelper) IsToday(t time.Time) bool {
 	if t.IsZero() {
@@ -88,3 +98,30 @@ func (h *TimezoneHelper) ParseInLocation(layout, value string) (time.Time, error
 func (h *TimezoneHelper) FormatInLocation(t time.Time, layout string) string {
 	return t.In(h.location).Format(layout)
 }
+
+// DateOf returns the calendar date of t in the configured timezone as midnight UTC,
+// the form in which date-only values such as episode air dates are stored
+func (h *TimezoneHelper) DateOf(t time.Time) time.Time {
+	localTime := t.In(h.location)
+	return time.Date(localTime.Year(), localTime.Month(), localTime.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// AirDate returns the date an episode airs on in the configured timezone.
+// airAt is the exact broadcast instant when known; otherwise the date-only airDate is returned as is.
+func (h *TimezoneHelper) AirDate(airAt, airDate *time.Time) *time.Time {
+	if airAt == nil {
+		return airDate
+	}
+	date := h.DateOf(*airAt)
+	return &date
+}
+
+// FormatAirDate formats the date an episode airs on in the configured timezone as
+// 2006-01-02; see AirDate. Returns an empty string when the air date is unknown.
+func (h *TimezoneHelper) FormatAirDate(airAt, airDate *time.Time) string {
+	date := h.AirDate(airAt, airDate)
+	if date == nil {
+		return ""
+	}
+	return date.Format("2006-01-02")
+}
@@ -248,3 +248,44 @@ func BenchmarkTimezoneHelper_IsToday(b *testing.B) {
 		_ = helper.IsToday(now)
 	}
 }
+
+func TestTimezoneHelper_AirDate(t *testing.T) {
+	shanghai, _ := time.LoadLocation("Asia/Shanghai")
+	helper := NewTimezoneHelper(shanghai)
+
+	// 21:00 in New York on March 1st is the morning of March 2nd in Shanghai
+	airDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	airAt := time.Date(2024, 3, 2, 2, 0, 0, 0, time.UTC)
+
+	got := helper.AirDate(&airAt, &airDate)
+	if got == nil || !got.Equal(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected 2024-03-02, got %v", got)
+	}
+
+	// Without an air instant the stored date is used as is
+	if got := helper.AirDate(nil, &airDate); got != &airDate {
+		t.Errorf("Expected the air date, got %v", got)
+	}
+	if got := helper.AirDate(nil, nil); got != nil {
+		t.Errorf("Expected nil, got %v", got)
+	}
+}
+
+func TestTimezoneHelper_DayRange(t *testing.T) {
+	newYork, _ := time.LoadLocation("America/New_York")
+	helper := NewTimezoneHelper(newYork)
+
+	// Dates parsed as UTC keep their calendar day
+	startDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	start, end := helper.DayRange(startDate, startDate.AddDate(0, 0, 1))
+
+	if !start.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, newYork)) {
+		t.Errorf("Expected start 2024-03-01 00:00 in New York, got %v", start)
+	}
+	if !end.Equal(time.Date(2024, 3, 3, 0, 0, 0, 0, newYork)) {
+		t.Errorf("Expected end 2024-03-03 00:00 in New York, got %v", end)
+	}
+	if got := helper.FormatAirDate(nil, &startDate); got != "2024-03-01" {
+		t.Errorf("Expected 2024-03-01, got %q", got)
+	}
+}
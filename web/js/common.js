@@ -512,6 +512,20 @@ class APIClient {
         return this.get('/crawler/logs', params);
     }
 
+    /**
+     * 获取运行中的爬取任务 (含进度)
+     */
+    async getRunningTasks() {
+        return this.get('/crawler/tasks/running');
+    }
+
+    /**
+     * 订阅任务进度事件流 (Server-Sent Events)
+     */
+    subscribeTaskEvents(taskId) {
+        return new EventSource(`${this.baseURL}/crawler/tasks/${taskId}/events`);
+    }
+
     /**
      * 获取爬取状态
      */
@@ -12,12 +12,14 @@ class LogsPage {
         this.status = '';
         this.operation = '';
         this.logs = [];
+        this.taskStreams = new Map();
         this.init();
     }
 
     init() {
         this.bindEvents();
         this.loadLogs();
+        this.loadRunningTasks();
     }
 
     bindEvents() {
@@ -45,6 +47,12 @@ class LogsPage {
         // 刷新按钮
         document.getElementById('refreshLogsBtn').addEventListener('click', () => {
             this.loadLogs();
+            this.loadRunningTasks();
+        });
+
+        // 离开页面时关闭事件流
+        window.addEventListener('beforeunload', () => {
+            this.taskStreams.forEach(stream => stream.close());
         });
 
         // 导出日志
@@ -81,6 +89,103 @@ class LogsPage {
         }
     }
 
+    async loadRunningTasks() {
+        try {
+            const response = await api.getRunningTasks();
+            if (response.code === 0) {
+                (response.data || []).forEach(task => this.watchTask(task));
+            }
+        } catch (error) {
+            // 任务进度是辅助信息, 加载失败不影响日志列表
+            console.warn('加载运行中的任务失败:', error);
+        }
+    }
+
+    watchTask(task) {
+        this.renderTaskProgress(task);
+        if (this.taskStreams.has(task.id)) {
+            return;
+        }
+
+        const stream = api.subscribeTaskEvents(task.id);
+        stream.addEventListener('progress', (e) => {
+            this.renderTaskProgress(JSON.parse(e.data));
+        });
+        stream.addEventListener('done', (e) => {
+            const finished = JSON.parse(e.data);
+            this.renderTaskProgress(finished);
+            this.closeTaskStream(finished.id);
+            this.loadLogs();
+        });
+        stream.addEventListener('error', () => {
+            // 服务端结束流后浏览器会自动重连, 这里只在连接关闭时清理
+            if (stream.readyState === EventSource.CLOSED) {
+                this.closeTaskStream(task.id);
+            }
+        });
+        this.taskStreams.set(task.id, stream);
+    }
+
+    closeTaskStream(taskId) {
+        const stream = this.taskStreams.get(taskId);
+        if (stream) {
+            stream.close();
+            this.taskStreams.delete(taskId);
+        }
+    }
+
+    renderTaskProgress(task) {
+        const section = document.getElementById('runningTasksSection');
+        const container = document.getElementById('runningTasks');
+        section.style.display = 'block';
+
+        let row = document.getElementById(`task-${task.id}`);
+        if (!row) {
+            row = document.createElement('div');
+            row.id = `task-${task.id}`;
+            row.className = 'mb-2';
+            container.appendChild(row);
+        }
+
+        const finished = ['success', 'failed', 'cancelled', 'interrupted'].includes(task.status);
+        const barClass = {
+            'success': 'bg-success',
+            'failed': 'bg-danger',
+            'cancelled': 'bg-secondary',
+            'interrupted': 'bg-warning'
+        }[task.status] || 'progress-bar-striped progress-bar-animated';
+        const details = finished
+            ? this.renderTaskStatus(task.status)
+            : `${this.escapeHtml(task.current_show || '')}${task.eta_seconds != null ? ' · 预计剩余 ' + this.formatDuration(task.eta_seconds) : ''}`;
+
+        row.innerHTML = `
+            <div class="d-flex justify-content-between small mb-1">
+                <span>#${task.id} ${this.escapeHtml(task.type)} (${task.processed_shows || 0}/${task.total_shows || 0})</span>
+                <span>${details}</span>
+            </div>
+            <div class="progress" style="height: 8px;">
+                <div class="progress-bar ${barClass}" role="progressbar" style="width: ${task.progress || 0}%"></div>
+            </div>
+        `;
+    }
+
+    renderTaskStatus(status) {
+        const badges = {
+            'success': '<span class="badge bg-success">完成</span>',
+            'failed': '<span class="badge bg-danger">失败</span>',
+            'cancelled': '<span class="badge bg-secondary">已取消</span>',
+            'interrupted': '<span class="badge bg-warning text-dark">已中断</span>'
+        };
+        return badges[status] || '';
+    }
+
+    formatDuration(seconds) {
+        if (seconds < 60) return `${seconds}秒`;
+        const minutes = Math.floor(seconds / 60);
+        if (minutes < 60) return `${minutes}分${seconds % 60}秒`;
+        return `${Math.floor(minutes / 60)}小时${minutes % 60}分`;
+    }
+
     renderTable() {
         const tbody = document.getElementById('logsTableBody');
         tbody.innerHTML = '';
@@ -95,7 +95,8 @@ class ShowDetailPage {
         document.getElementById('showName').textContent = this.show.name;
         document.getElementById('showOriginalName').textContent = this.show.original_name || '';
         document.getElementById('showFirstAirDate').textContent = this.formatDate(this.show.first_air_date);
-        document.getElementById('showGenres').textContent = this.show.genres || '-';
+        const genres = (this.show.genres || []).map(genre => genre.name).join(' / ');
+        document.getElementById('showGenres').textContent = genres || '-';
         document.getElementById('showTmdbId').textContent = this.show.tmdb_id;
         document.getElementById('showOverview').textContent = this.show.overview || '暂无简介';
 
@@ -148,7 +149,7 @@ class ShowDetailPage {
                         data-bs-toggle="tab"
                         data-bs-target="#season-${season.season_number}"
                         type="button">
-                    第${season.season_number}季 <span class="badge bg-secondary">${season.episode_count}</span>
+                    ${season.season_number === 0 ? '特别篇' : `第${season.season_number}季`} <span class="badge bg-secondary">${season.episode_count}</span>
                 </button>
             `;
             seasonTabs.appendChild(tabItem);
@@ -175,7 +176,9 @@ class ShowDetailPage {
 
             if (season.episodes && season.episodes.length > 0) {
                 season.episodes.forEach(ep => {
-                    const episodeCode = `S${season.season_number}E${ep.episode_number}`;
+                    const episodeCode = season.season_number === 0
+                        ? `SP${String(ep.episode_number).padStart(2, '0')}`
+                        : `S${season.season_number}E${ep.episode_number}`;
                     tableHTML += `
                         <tr>
                             <td><strong>${episodeCode}</strong></td>
@@ -158,7 +158,7 @@ class TodayPage {
             if (show.episodes && show.episodes.length > 0) {
                 episodesHTML = '<div class="episodes-list">';
                 show.episodes.forEach(ep => {
-                    const episodeCode = `S${ep.season_number}E${ep.episode_number}`;
+                    const episodeCode = ep.episode_code || `S${ep.season_number}E${ep.episode_number}`;
                     const isUploaded = ep.uploaded || false;
                     const checkBtnClass = isUploaded ? 'uploaded' : '';
                     const btnTitle = isUploaded ? '已上传 - 点击取消' : '标记已上传';